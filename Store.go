@@ -1,28 +1,58 @@
 package shopstore
 
 import (
-	"context"
 	"database/sql"
 	"log/slog"
+	"time"
 
 	"github.com/dracory/neat"
-	contractsschema "github.com/dracory/neat/contracts/database/schema"
 )
 
 var _ StoreInterface = (*Store)(nil) // verify it extends the interface
 
 type Store struct {
-	categoryTableName      string
-	discountTableName      string
-	mediaTableName         string
-	orderTableName         string
-	orderLineItemTableName string
-	productTableName       string
-	db                     *neat.Database
-	timeoutSeconds         int64
-	automigrateEnabled     bool
-	debugEnabled           bool
-	sqlLogger              *slog.Logger
+	addressTableName            string
+	cartTableName               string
+	cartItemTableName           string
+	categoryTableName           string
+	customerTableName           string
+	discountTableName           string
+	mediaTableName              string
+	orderTableName              string
+	orderLineItemTableName      string
+	productTableName            string
+	shippingMethodTableName     string
+	shippingZoneTableName       string
+	taxRateTableName            string
+	migrationTableName          string
+	outboxTableName             string
+	auditLogTableName           string
+	db                          *neat.Database
+	dialect                     dialect
+	operationTimeout            time.Duration
+	automigrateEnabled          bool
+	referentialIntegrityEnabled bool
+	foreignKeysEnabled          bool
+	outboxEnabled               bool
+	auditLogEnabled             bool
+	lowStockThreshold           int
+	pricesIncludeTax            bool
+	taxCalculator               TaxCalculator
+	debugEnabled                bool
+	sqlLogger                   *slog.Logger
+
+	addressHooks        Hooks[AddressInterface]
+	cartHooks           Hooks[CartInterface]
+	categoryHooks       Hooks[CategoryInterface]
+	customerHooks       Hooks[CustomerInterface]
+	discountHooks       Hooks[DiscountInterface]
+	mediaHooks          Hooks[MediaInterface]
+	orderHooks          Hooks[OrderInterface]
+	orderLineItemHooks  Hooks[OrderLineItemInterface]
+	productHooks        Hooks[ProductInterface]
+	shippingMethodHooks Hooks[ShippingMethodInterface]
+	shippingZoneHooks   Hooks[ShippingZoneInterface]
+	taxRateHooks        Hooks[TaxRateInterface]
 }
 
 // logSql logs sql to the sql logger
@@ -36,49 +66,6 @@ func (store *Store) logSql(sqlOperationType string, sql string, params ...interf
 	}
 }
 
-// MigrateUp creates or updates database tables to match the current schema
-func (store *Store) MigrateUp(ctx context.Context, tx ...*sql.Tx) error {
-	if err := store.categoryTableCreate(); err != nil {
-		return err
-	}
-	if err := store.discountTableCreate(); err != nil {
-		return err
-	}
-	if err := store.mediaTableCreate(); err != nil {
-		return err
-	}
-	if err := store.orderTableCreate(); err != nil {
-		return err
-	}
-	if err := store.orderLineItemTableCreate(); err != nil {
-		return err
-	}
-	if err := store.productTableCreate(); err != nil {
-		return err
-	}
-
-	if err := migration_001_product_table_add_parent_id(store); err != nil {
-		return err
-	}
-
-	if err := migration_002_product_table_add_variant_dimensions(store); err != nil {
-		return err
-	}
-
-	return nil
-}
-
-// MigrateDown drops the shop store tables
-func (store *Store) MigrateDown(ctx context.Context, tx ...*sql.Tx) error {
-	_ = store.db.Schema().DropIfExists(store.categoryTableName)
-	_ = store.db.Schema().DropIfExists(store.discountTableName)
-	_ = store.db.Schema().DropIfExists(store.mediaTableName)
-	_ = store.db.Schema().DropIfExists(store.orderLineItemTableName)
-	_ = store.db.Schema().DropIfExists(store.orderTableName)
-	_ = store.db.Schema().DropIfExists(store.productTableName)
-	return nil
-}
-
 func (store *Store) DB() *sql.DB {
 	db, _ := store.db.DB()
 	return db
@@ -98,10 +85,30 @@ func (store *Store) EnableDebug(debug bool, sqlLogger ...*slog.Logger) {
 	}
 }
 
+func (store *Store) AddressTableName() string {
+	return store.addressTableName
+}
+
+func (store *Store) AuditLogTableName() string {
+	return store.auditLogTableName
+}
+
+func (store *Store) CartTableName() string {
+	return store.cartTableName
+}
+
+func (store *Store) CartItemTableName() string {
+	return store.cartItemTableName
+}
+
 func (store *Store) CategoryTableName() string {
 	return store.categoryTableName
 }
 
+func (store *Store) CustomerTableName() string {
+	return store.customerTableName
+}
+
 func (store *Store) DiscountTableName() string {
 	return store.discountTableName
 }
@@ -118,134 +125,22 @@ func (store *Store) OrderLineItemTableName() string {
 	return store.orderLineItemTableName
 }
 
+func (store *Store) OutboxTableName() string {
+	return store.outboxTableName
+}
+
 func (store *Store) ProductTableName() string {
 	return store.productTableName
 }
 
-func (store *Store) categoryTableCreate() error {
-	if store.db.Schema().HasTable(store.categoryTableName) {
-		return nil
-	}
-	return store.db.Schema().Create(store.categoryTableName, func(table contractsschema.Blueprint) {
-		table.String(COLUMN_ID, 40)
-		table.Primary(COLUMN_ID)
-		table.String(COLUMN_STATUS, 20)
-		table.String(COLUMN_PARENT_ID, 40)
-		table.String(COLUMN_TITLE, 255)
-		table.Text(COLUMN_DESCRIPTION)
-		table.Text(COLUMN_METAS)
-		table.Text(COLUMN_MEMO)
-		table.DateTime(COLUMN_CREATED_AT)
-		table.DateTime(COLUMN_UPDATED_AT)
-		table.DateTime(COLUMN_SOFT_DELETED_AT)
-	})
-}
-
-func (store *Store) discountTableCreate() error {
-	if store.db.Schema().HasTable(store.discountTableName) {
-		return nil
-	}
-	return store.db.Schema().Create(store.discountTableName, func(table contractsschema.Blueprint) {
-		table.String(COLUMN_ID, 40)
-		table.Primary(COLUMN_ID)
-		table.String(COLUMN_STATUS, 20)
-		table.String(COLUMN_TITLE, 255)
-		table.Text(COLUMN_DESCRIPTION)
-		table.String(COLUMN_TYPE, 20)
-		table.Decimal(COLUMN_AMOUNT)
-		table.String(COLUMN_CODE, 100)
-		table.DateTime(COLUMN_STARTS_AT)
-		table.DateTime(COLUMN_ENDS_AT)
-		table.Text(COLUMN_METAS)
-		table.Text(COLUMN_MEMO)
-		table.DateTime(COLUMN_CREATED_AT)
-		table.DateTime(COLUMN_UPDATED_AT)
-		table.DateTime(COLUMN_SOFT_DELETED_AT)
-	})
-}
-
-func (store *Store) mediaTableCreate() error {
-	if store.db.Schema().HasTable(store.mediaTableName) {
-		return nil
-	}
-	return store.db.Schema().Create(store.mediaTableName, func(table contractsschema.Blueprint) {
-		table.String(COLUMN_ID, 40)
-		table.Primary(COLUMN_ID)
-		table.String(COLUMN_STATUS, 20)
-		table.String(COLUMN_ENTITY_ID, 40)
-		table.Integer(COLUMN_SEQUENCE)
-		table.String(COLUMN_MEDIA_TYPE, 50)
-		table.String(COLUMN_MEDIA_URL, 510)
-		table.String(COLUMN_TITLE, 255)
-		table.Text(COLUMN_DESCRIPTION)
-		table.Text(COLUMN_MEMO)
-		table.Text(COLUMN_METAS)
-		table.DateTime(COLUMN_CREATED_AT)
-		table.DateTime(COLUMN_UPDATED_AT)
-		table.DateTime(COLUMN_SOFT_DELETED_AT)
-	})
-}
-
-func (store *Store) orderTableCreate() error {
-	if store.db.Schema().HasTable(store.orderTableName) {
-		return nil
-	}
-	return store.db.Schema().Create(store.orderTableName, func(table contractsschema.Blueprint) {
-		table.String(COLUMN_ID, 40)
-		table.Primary(COLUMN_ID)
-		table.String(COLUMN_STATUS, 20)
-		table.String(COLUMN_CUSTOMER_ID, 40)
-		table.Integer(COLUMN_QUANTITY)
-		table.Decimal(COLUMN_PRICE)
-		table.Text(COLUMN_METAS)
-		table.Text(COLUMN_MEMO)
-		table.DateTime(COLUMN_CREATED_AT)
-		table.DateTime(COLUMN_UPDATED_AT)
-		table.DateTime(COLUMN_SOFT_DELETED_AT)
-	})
-}
-
-func (store *Store) orderLineItemTableCreate() error {
-	if store.db.Schema().HasTable(store.orderLineItemTableName) {
-		return nil
-	}
-	return store.db.Schema().Create(store.orderLineItemTableName, func(table contractsschema.Blueprint) {
-		table.String(COLUMN_ID, 40)
-		table.Primary(COLUMN_ID)
-		table.String(COLUMN_STATUS, 20)
-		table.String(COLUMN_ORDER_ID, 40)
-		table.String(COLUMN_PRODUCT_ID, 40)
-		table.String(COLUMN_TITLE, 255)
-		table.Integer(COLUMN_QUANTITY)
-		table.Decimal(COLUMN_PRICE)
-		table.Text(COLUMN_METAS)
-		table.Text(COLUMN_MEMO)
-		table.DateTime(COLUMN_CREATED_AT)
-		table.DateTime(COLUMN_UPDATED_AT)
-		table.DateTime(COLUMN_SOFT_DELETED_AT)
-	})
-}
-
-func (store *Store) productTableCreate() error {
-	if store.db.Schema().HasTable(store.productTableName) {
-		return nil
-	}
-	return store.db.Schema().Create(store.productTableName, func(table contractsschema.Blueprint) {
-		table.String(COLUMN_ID, 40)
-		table.Primary(COLUMN_ID)
-		table.String(COLUMN_STATUS, 20)
-		table.String(COLUMN_PARENT_ID, 40)
-		table.String(COLUMN_TITLE, 255)
-		table.Text(COLUMN_DESCRIPTION)
-		table.Text(COLUMN_SHORT_DESCRIPTION)
-		table.Integer(COLUMN_QUANTITY)
-		table.Decimal(COLUMN_PRICE)
-		table.Text(COLUMN_VARIANT_MATRIX_SCHEMA)
-		table.Text(COLUMN_VARIANT_MATRIX_VALUES)
-		table.Text(COLUMN_METAS)
-		table.Text(COLUMN_MEMO)
-		table.DateTime(COLUMN_CREATED_AT)
-		table.DateTime(COLUMN_UPDATED_AT)
-		table.DateTime(COLUMN_SOFT_DELETED_AT)
-	})
+func (store *Store) ShippingMethodTableName() string {
+	return store.shippingMethodTableName
+}
+
+func (store *Store) ShippingZoneTableName() string {
+	return store.shippingZoneTableName
+}
+
+func (store *Store) TaxRateTableName() string {
+	return store.taxRateTableName
 }
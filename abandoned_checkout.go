@@ -0,0 +1,395 @@
+package shopstore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+	"github.com/dromara/carbon/v2"
+	"github.com/samber/lo"
+)
+
+// AbandonedCheckoutOptions select the checkouts left unfinished: the
+// pending orders placed, and the active carts last changed, at least
+// OlderThan ago
+type AbandonedCheckoutOptions struct {
+	// OlderThan is the age from which a checkout counts as abandoned. Required.
+	OlderThan time.Duration
+
+	// CustomerID restricts the checkouts to those of the customer, when set
+	CustomerID string
+
+	// Limit caps the number of orders, and the number of carts, selected.
+	// 0 selects them all.
+	Limit int
+}
+
+// AbandonedCheckoutReport lists the abandoned checkouts, oldest first
+type AbandonedCheckoutReport struct {
+	Orders []AbandonedOrder
+	Carts  []AbandonedCart
+
+	// OrdersValue is the total price of the orders
+	OrdersValue float64
+
+	// CartsValue is the total value of the carts
+	CartsValue float64
+}
+
+// AbandonedOrder is a pending order left unfinished
+type AbandonedOrder struct {
+	Order     OrderInterface
+	LineItems []OrderLineItemInterface
+
+	// Customer is nil for guests and for customers not in the customer table
+	Customer CustomerInterface
+
+	// StockReserved is true for an order checked out from a cart, which took
+	// the ordered quantities off the product stock
+	StockReserved bool
+}
+
+// AbandonedCart is an active cart left unfinished
+type AbandonedCart struct {
+	Cart  CartInterface
+	Items []CartItemInterface
+
+	// Customer is nil for guest carts
+	Customer CustomerInterface
+
+	// Value is the total of the items, before any discount
+	Value float64
+}
+
+// AbandonedCheckoutExpireResult lists the checkouts expired by AbandonedCheckoutExpire
+type AbandonedCheckoutExpireResult struct {
+	// OrderIDs are the IDs of the orders cancelled
+	OrderIDs []string
+
+	// CartIDs are the IDs of the carts expired
+	CartIDs []string
+}
+
+// AbandonedCheckoutReport lists the abandoned checkouts selected by the
+// options, with the line items or cart items, the customer and the value
+// of each, to follow them up or review them before AbandonedCheckoutExpire
+func (store *Store) AbandonedCheckoutReport(ctx context.Context, options AbandonedCheckoutOptions) (AbandonedCheckoutReport, error) {
+	if err := options.validate(); err != nil {
+		return AbandonedCheckoutReport{}, err
+	}
+
+	orders, err := store.OrderList(ctx, options.orderQuery())
+	if err != nil {
+		return AbandonedCheckoutReport{}, err
+	}
+
+	carts, err := store.CartList(ctx, options.cartQuery())
+	if err != nil {
+		return AbandonedCheckoutReport{}, err
+	}
+
+	orderIDs := lo.Map(orders, func(order OrderInterface, _ int) string {
+		return order.GetID()
+	})
+
+	cartIDs := lo.Map(carts, func(cart CartInterface, _ int) string {
+		return cart.GetID()
+	})
+
+	customerIDs := append(
+		lo.Map(orders, func(order OrderInterface, _ int) string { return order.GetCustomerID() }),
+		lo.Map(carts, func(cart CartInterface, _ int) string { return cart.GetCustomerID() })...,
+	)
+
+	customers, err := store.abandonedCheckoutCustomers(ctx, customerIDs)
+	if err != nil {
+		return AbandonedCheckoutReport{}, err
+	}
+
+	lineItems := map[string][]OrderLineItemInterface{}
+	for _, batch := range lo.Chunk(orderIDs, bulkBatchSize) {
+		list, err := store.OrderLineItemList(ctx, NewOrderLineItemQuery().SetOrderIDIn(batch))
+		if err != nil {
+			return AbandonedCheckoutReport{}, err
+		}
+
+		for _, lineItem := range list {
+			lineItems[lineItem.GetOrderID()] = append(lineItems[lineItem.GetOrderID()], lineItem)
+		}
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	reserved, err := store.checkedOutOrderIDs(store.query(ctx), orderIDs)
+	if err != nil {
+		return AbandonedCheckoutReport{}, store.operationError("abandoned checkout report", err)
+	}
+
+	cartItems := map[string][]CartItemInterface{}
+	for _, batch := range lo.Chunk(cartIDs, bulkBatchSize) {
+		var results []map[string]any
+		err := store.query(ctx).
+			Table(store.cartItemTableName).
+			WhereIn(COLUMN_CART_ID, lo.ToAnySlice(batch)).
+			OrderBy(COLUMN_CREATED_AT).
+			OrderBy(COLUMN_ID).
+			Get(&results)
+		if err != nil {
+			return AbandonedCheckoutReport{}, store.operationError("abandoned checkout report", err)
+		}
+
+		for _, result := range results {
+			item := NewCartItemFromExistingData(mapAnyToString(result))
+			cartItems[item.GetCartID()] = append(cartItems[item.GetCartID()], item)
+		}
+	}
+
+	report := AbandonedCheckoutReport{
+		Orders: make([]AbandonedOrder, 0, len(orders)),
+		Carts:  make([]AbandonedCart, 0, len(carts)),
+	}
+
+	for _, order := range orders {
+		_, stockReserved := reserved[order.GetID()]
+
+		report.Orders = append(report.Orders, AbandonedOrder{
+			Order:         order,
+			LineItems:     lineItems[order.GetID()],
+			Customer:      customers[order.GetCustomerID()],
+			StockReserved: stockReserved,
+		})
+
+		report.OrdersValue += order.GetPriceFloat()
+	}
+
+	for _, cart := range carts {
+		value := roundPrice(lo.SumBy(cartItems[cart.GetID()], func(item CartItemInterface) float64 {
+			return item.GetSubtotalFloat()
+		}))
+
+		report.Carts = append(report.Carts, AbandonedCart{
+			Cart:     cart,
+			Items:    cartItems[cart.GetID()],
+			Customer: customers[cart.GetCustomerID()],
+			Value:    value,
+		})
+
+		report.CartsValue += value
+	}
+
+	report.OrdersValue = roundPrice(report.OrdersValue)
+	report.CartsValue = roundPrice(report.CartsValue)
+
+	return report, nil
+}
+
+// AbandonedCheckoutExpire cancels the abandoned pending orders and expires
+// the abandoned carts selected by the options, in a single transaction.
+// The orders checked out from a cart give their line item quantities back
+// to the product stock, as the checkout took them off. Orders created
+// otherwise never reserved stock, so the stock is left as is for them.
+//
+// The status changes are recorded like those of OrderUpdateMany, with an
+// order.status_changed event per order. Once committed, the AfterUpdate
+// hooks run on the orders, carts and products changed, but not the
+// BeforeUpdate hooks, as the rows are not loaded.
+func (store *Store) AbandonedCheckoutExpire(ctx context.Context, options AbandonedCheckoutOptions) (AbandonedCheckoutExpireResult, error) {
+	if err := options.validate(); err != nil {
+		return AbandonedCheckoutExpireResult{}, err
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	result := AbandonedCheckoutExpireResult{}
+	var orders, carts, products []map[string]string
+	err := store.query(ctx).Transaction(func(tx contractsorm.Query) error {
+		orderIDs, records, err := store.updateManyOn(ctx, tx, store.orderBulkTable(options.orderQuery()), map[string]string{
+			COLUMN_STATUS: ORDER_STATUS_CANCELLED,
+		})
+		if err != nil {
+			return err
+		}
+
+		productIDs := []string{}
+
+		reserved, err := store.checkedOutOrderIDs(tx, orderIDs)
+		if err != nil {
+			return err
+		}
+
+		for _, batch := range lo.Chunk(lo.Keys(reserved), bulkBatchSize) {
+			var results []map[string]any
+			err := statement(tx).
+				Table(store.orderLineItemTableName).
+				Select([]string{COLUMN_PRODUCT_ID, COLUMN_QUANTITY}).
+				WhereIn(COLUMN_ORDER_ID, lo.ToAnySlice(batch)).
+				Where(COLUMN_SOFT_DELETED_AT+" = ?", MAX_DATETIME).
+				Get(&results)
+			if err != nil {
+				return err
+			}
+
+			for _, result := range results {
+				lineItem := NewOrderLineItemFromExistingData(mapAnyToString(result))
+
+				_, released, err := store.productQuantityAdjust(ctx, tx, lineItem.GetProductID(), lineItem.GetQuantityInt(), ProductQuantityAdjustOptions{})
+				if errors.Is(err, ErrProductNotFound) {
+					continue // nothing to give the stock back to
+				}
+				if err != nil {
+					return err
+				}
+
+				productIDs = append(productIDs, lineItem.GetProductID())
+				records.auditLogs = append(records.auditLogs, released.auditLogs...)
+				records.events = append(records.events, released.events...)
+			}
+		}
+
+		cartIDs, cartRecords, err := store.updateManyOn(ctx, tx, store.cartBulkTable(options.cartQuery()), map[string]string{
+			COLUMN_STATUS: CART_STATUS_EXPIRED,
+		})
+		if err != nil {
+			return err
+		}
+
+		records.auditLogs = append(records.auditLogs, cartRecords.auditLogs...)
+		records.events = append(records.events, cartRecords.events...)
+
+		if store.orderHooks.hasAfterUpdate() {
+			if orders, err = rowsByID(tx, store.orderTableName, orderIDs); err != nil {
+				return err
+			}
+		}
+
+		if store.cartHooks.hasAfterUpdate() {
+			if carts, err = rowsByID(tx, store.cartTableName, cartIDs); err != nil {
+				return err
+			}
+		}
+
+		if store.productHooks.hasAfterUpdate() {
+			if products, err = rowsByID(tx, store.productTableName, lo.Uniq(productIDs)); err != nil {
+				return err
+			}
+		}
+
+		result = AbandonedCheckoutExpireResult{OrderIDs: orderIDs, CartIDs: cartIDs}
+
+		return store.recordChanges(tx, records)
+	})
+	if err != nil {
+		return AbandonedCheckoutExpireResult{}, store.operationError("abandoned checkout expire", err)
+	}
+
+	runAfterUpdateRows(ctx, &store.orderHooks, orders, NewOrderFromExistingData, func(map[string]string) map[string]string {
+		return map[string]string{COLUMN_STATUS: ORDER_STATUS_CANCELLED}
+	})
+	runAfterUpdateRows(ctx, &store.cartHooks, carts, NewCartFromExistingData, func(map[string]string) map[string]string {
+		return map[string]string{COLUMN_STATUS: CART_STATUS_EXPIRED}
+	})
+	runAfterUpdateRows(ctx, &store.productHooks, products, NewProductFromExistingData, productQuantityChanged)
+
+	return result, nil
+}
+
+// abandonedCheckoutCustomers returns the live customers with the IDs, by ID
+func (store *Store) abandonedCheckoutCustomers(ctx context.Context, ids []string) (map[string]CustomerInterface, error) {
+	ids = lo.Uniq(lo.Compact(ids))
+
+	customers := map[string]CustomerInterface{}
+	for _, batch := range lo.Chunk(ids, bulkBatchSize) {
+		list, err := store.CustomerList(ctx, NewCustomerQuery().SetIDIn(batch))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, customer := range list {
+			customers[customer.GetID()] = customer
+		}
+	}
+
+	return customers, nil
+}
+
+// checkedOutOrderIDs returns the IDs, among the order IDs, of the orders a
+// cart was checked out as, reading on q, which may be a transaction
+func (store *Store) checkedOutOrderIDs(q contractsorm.Query, orderIDs []string) (map[string]struct{}, error) {
+	ids := map[string]struct{}{}
+
+	for _, batch := range lo.Chunk(orderIDs, bulkBatchSize) {
+		var results []map[string]any
+		err := statement(q).
+			Table(store.cartTableName).
+			Select([]string{COLUMN_ORDER_ID}).
+			WhereIn(COLUMN_ORDER_ID, lo.ToAnySlice(batch)).
+			Where(COLUMN_STATUS+" = ?", CART_STATUS_CHECKED_OUT).
+			Get(&results)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, result := range results {
+			ids[mapAnyToString(result)[COLUMN_ORDER_ID]] = struct{}{}
+		}
+	}
+
+	return ids, nil
+}
+
+// validate checks the options
+func (options AbandonedCheckoutOptions) validate() error {
+	if options.OlderThan <= 0 {
+		return errors.New("abandoned checkout. older than must be positive")
+	}
+
+	if options.Limit < 0 {
+		return errors.New("abandoned checkout. limit cannot be negative")
+	}
+
+	return nil
+}
+
+// cutoff returns the datetime abandoned checkouts were last active at or before
+func (options AbandonedCheckoutOptions) cutoff() string {
+	return carbon.CreateFromStdTime(time.Now().Add(-options.OlderThan)).ToDateTimeString(carbon.UTC)
+}
+
+// orderQuery returns the query of the abandoned pending orders, oldest first
+func (options AbandonedCheckoutOptions) orderQuery() OrderQueryInterface {
+	query := NewOrderQuery().
+		SetStatus(ORDER_STATUS_PENDING).
+		SetCreatedAtLte(options.cutoff()).
+		AddSort(COLUMN_CREATED_AT, SORT_DIRECTION_ASC)
+
+	if options.CustomerID != "" {
+		query.SetCustomerID(options.CustomerID)
+	}
+
+	if options.Limit > 0 {
+		query.SetLimit(options.Limit)
+	}
+
+	return query
+}
+
+// cartQuery returns the query of the abandoned active carts, oldest first
+func (options AbandonedCheckoutOptions) cartQuery() CartQueryInterface {
+	query := NewCartQuery().
+		SetStatus(CART_STATUS_ACTIVE).
+		SetUpdatedAtLte(options.cutoff()).
+		AddSort(COLUMN_UPDATED_AT, SORT_DIRECTION_ASC)
+
+	if options.CustomerID != "" {
+		query.SetCustomerID(options.CustomerID)
+	}
+
+	if options.Limit > 0 {
+		query.SetLimit(options.Limit)
+	}
+
+	return query
+}
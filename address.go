@@ -0,0 +1,411 @@
+package shopstore
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/dracory/dataobject"
+	"github.com/dromara/carbon/v2"
+	"github.com/spf13/cast"
+)
+
+// == CONSTANTS ==============================================================
+
+// DEFAULT_ADDRESS_TABLE_NAME is the table the addresses are stored in
+// when NewStoreOptions.AddressTableName is not set
+const DEFAULT_ADDRESS_TABLE_NAME = "shop_address"
+
+// == CLASS ==================================================================
+
+// Address represents an address in the address book of a customer. An
+// address can be the default shipping and/or billing address of its
+// customer. Orders keep a snapshot of their addresses (see OrderAddress),
+// so editing the address book never alters past orders.
+type Address struct {
+	dataobject.DataObject
+}
+
+// == INTERFACES =============================================================
+
+// Compile-time interface compliance check
+var _ AddressInterface = (*Address)(nil)
+
+// == CONSTRUCTORS ===========================================================
+
+// NewAddress creates a new address with default values:
+// - CustomerID: empty
+// - Name, Line1, Line2, City, Region, PostalCode, CountryCode, Phone: empty
+// - IsDefaultShipping: false
+// - IsDefaultBilling: false
+// - Memo: empty
+// - CreatedAt: current UTC time
+// - UpdatedAt: current UTC time
+// - SoftDeletedAt: max datetime (not deleted)
+// - Metas: empty map
+func NewAddress() AddressInterface {
+	o := (&Address{}).
+		SetID(GenerateShortID()).
+		SetCustomerID("").
+		SetName("").
+		SetLine1("").
+		SetLine2("").
+		SetCity("").
+		SetRegion("").
+		SetPostalCode("").
+		SetCountryCode("").
+		SetPhone("").
+		SetIsDefaultShipping(false).
+		SetIsDefaultBilling(false).
+		SetMemo("").
+		SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
+		SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
+		SetSoftDeletedAt(MAX_DATETIME).
+		SetVersion(1)
+
+	_ = o.SetMetas(map[string]string{})
+
+	return o
+}
+
+// NewAddressFromExistingData creates an address from existing data map.
+// Used when hydrating from database or external sources.
+func NewAddressFromExistingData(data map[string]string) AddressInterface {
+	o := &Address{}
+	o.Hydrate(data)
+	return o
+}
+
+// == METHODS ================================================================
+
+// IsSoftDeleted returns true if the address is soft deleted.
+func (address *Address) IsSoftDeleted() bool {
+	return address.GetSoftDeletedAt() != MAX_DATETIME
+}
+
+// ToOrderAddress returns the snapshot of the address to store on an order.
+func (address *Address) ToOrderAddress() OrderAddress {
+	return OrderAddress{
+		Name:        address.GetName(),
+		Line1:       address.GetLine1(),
+		Line2:       address.GetLine2(),
+		City:        address.GetCity(),
+		Region:      address.GetRegion(),
+		PostalCode:  address.GetPostalCode(),
+		CountryCode: address.GetCountryCode(),
+		Phone:       address.GetPhone(),
+	}
+}
+
+// == SETTERS AND GETTERS ====================================================
+
+// GetCity returns the city.
+func (address *Address) GetCity() string {
+	return address.Get(COLUMN_CITY)
+}
+
+// SetCity sets the city.
+func (address *Address) SetCity(city string) AddressInterface {
+	address.Set(COLUMN_CITY, city)
+	return address
+}
+
+// GetCountryCode returns the ISO 3166-1 alpha-2 country code, like "GB".
+func (address *Address) GetCountryCode() string {
+	return address.Get(COLUMN_COUNTRY_CODE)
+}
+
+// SetCountryCode sets the ISO 3166-1 alpha-2 country code, trimmed and uppercased.
+func (address *Address) SetCountryCode(countryCode string) AddressInterface {
+	address.Set(COLUMN_COUNTRY_CODE, strings.ToUpper(strings.TrimSpace(countryCode)))
+	return address
+}
+
+// GetCreatedAt returns the creation timestamp as a string.
+func (address *Address) GetCreatedAt() string {
+	return address.Get(COLUMN_CREATED_AT)
+}
+
+// GetCreatedAtCarbon returns the creation timestamp as a Carbon instance.
+func (address *Address) GetCreatedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(address.GetCreatedAt())
+}
+
+// SetCreatedAt sets the creation timestamp.
+func (address *Address) SetCreatedAt(createdAt string) AddressInterface {
+	address.Set(COLUMN_CREATED_AT, createdAt)
+	return address
+}
+
+// GetCustomerID returns the ID of the customer the address belongs to.
+func (address *Address) GetCustomerID() string {
+	return address.Get(COLUMN_CUSTOMER_ID)
+}
+
+// SetCustomerID sets the ID of the customer the address belongs to.
+func (address *Address) SetCustomerID(customerID string) AddressInterface {
+	address.Set(COLUMN_CUSTOMER_ID, customerID)
+	return address
+}
+
+// GetID returns the unique identifier.
+func (address *Address) GetID() string {
+	return address.Get(COLUMN_ID)
+}
+
+// SetID sets the unique identifier.
+func (address *Address) SetID(id string) AddressInterface {
+	address.Set(COLUMN_ID, id)
+	return address
+}
+
+// IsDefaultBilling returns true if this is the default billing address of the customer.
+func (address *Address) IsDefaultBilling() bool {
+	return cast.ToBool(address.Get(COLUMN_IS_DEFAULT_BILLING))
+}
+
+// SetIsDefaultBilling sets whether this is the default billing address of the customer.
+func (address *Address) SetIsDefaultBilling(isDefault bool) AddressInterface {
+	address.Set(COLUMN_IS_DEFAULT_BILLING, flagValue(isDefault))
+	return address
+}
+
+// IsDefaultShipping returns true if this is the default shipping address of the customer.
+func (address *Address) IsDefaultShipping() bool {
+	return cast.ToBool(address.Get(COLUMN_IS_DEFAULT_SHIPPING))
+}
+
+// SetIsDefaultShipping sets whether this is the default shipping address of the customer.
+func (address *Address) SetIsDefaultShipping(isDefault bool) AddressInterface {
+	address.Set(COLUMN_IS_DEFAULT_SHIPPING, flagValue(isDefault))
+	return address
+}
+
+// GetLine1 returns the first address line.
+func (address *Address) GetLine1() string {
+	return address.Get(COLUMN_LINE1)
+}
+
+// SetLine1 sets the first address line.
+func (address *Address) SetLine1(line1 string) AddressInterface {
+	address.Set(COLUMN_LINE1, line1)
+	return address
+}
+
+// GetLine2 returns the second address line.
+func (address *Address) GetLine2() string {
+	return address.Get(COLUMN_LINE2)
+}
+
+// SetLine2 sets the second address line.
+func (address *Address) SetLine2(line2 string) AddressInterface {
+	address.Set(COLUMN_LINE2, line2)
+	return address
+}
+
+// GetMemo returns the internal memo.
+func (address *Address) GetMemo() string {
+	return address.Get(COLUMN_MEMO)
+}
+
+// SetMemo sets the internal memo.
+func (address *Address) SetMemo(memo string) AddressInterface {
+	address.Set(COLUMN_MEMO, memo)
+	return address
+}
+
+// GetMeta returns a specific metadata value by name. Returns empty string if not found.
+func (address *Address) GetMeta(name string) string {
+	metas, err := address.GetMetas()
+
+	if err != nil {
+		return ""
+	}
+
+	if value, exists := metas[name]; exists {
+		return value
+	}
+
+	return ""
+}
+
+// MetaRemove removes a single metadata entry.
+func (address *Address) MetaRemove(name string) error {
+	metas, err := address.GetMetas()
+
+	if err != nil {
+		return err
+	}
+
+	delete(metas, name)
+
+	return address.SetMetas(metas)
+}
+
+// SetMeta sets a single metadata value.
+func (address *Address) SetMeta(name string, value string) error {
+	return address.MetasUpsert(map[string]string{name: value})
+}
+
+// GetMetas returns all metadata as a map. Returns empty map if no metas stored.
+func (address *Address) GetMetas() (map[string]string, error) {
+	metasStr := address.Get(COLUMN_METAS)
+
+	if metasStr == "" {
+		metasStr = "{}"
+	}
+
+	metasJson := map[string]string{}
+	errJson := json.Unmarshal([]byte(metasStr), &metasJson)
+	if errJson != nil {
+		return map[string]string{}, errJson
+	}
+
+	if metasJson == nil {
+		metasJson = map[string]string{}
+	}
+
+	return metasJson, nil
+}
+
+// MetasRemove removes multiple metadata entries.
+func (address *Address) MetasRemove(names []string) error {
+	for _, name := range names {
+		err := address.MetaRemove(name)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MetasUpsert merges the provided metadata with existing values.
+func (address *Address) MetasUpsert(metas map[string]string) error {
+	currentMetas, err := address.GetMetas()
+
+	if err != nil {
+		return err
+	}
+
+	for k, v := range metas {
+		currentMetas[k] = v
+	}
+
+	return address.SetMetas(currentMetas)
+}
+
+// SetMetas replaces all metadata with the provided map.
+// Warning: this overwrites any existing metadata.
+func (address *Address) SetMetas(metas map[string]string) error {
+	mapString, err := json.Marshal(metas)
+
+	if err != nil {
+		return err
+	}
+
+	address.Set(COLUMN_METAS, string(mapString))
+
+	return nil
+}
+
+// GetName returns the name of the recipient.
+func (address *Address) GetName() string {
+	return address.Get(COLUMN_NAME)
+}
+
+// SetName sets the name of the recipient.
+func (address *Address) SetName(name string) AddressInterface {
+	address.Set(COLUMN_NAME, name)
+	return address
+}
+
+// GetPhone returns the phone number of the recipient.
+func (address *Address) GetPhone() string {
+	return address.Get(COLUMN_PHONE)
+}
+
+// SetPhone sets the phone number of the recipient.
+func (address *Address) SetPhone(phone string) AddressInterface {
+	address.Set(COLUMN_PHONE, phone)
+	return address
+}
+
+// GetPostalCode returns the postal code.
+func (address *Address) GetPostalCode() string {
+	return address.Get(COLUMN_POSTAL_CODE)
+}
+
+// SetPostalCode sets the postal code.
+func (address *Address) SetPostalCode(postalCode string) AddressInterface {
+	address.Set(COLUMN_POSTAL_CODE, postalCode)
+	return address
+}
+
+// GetRegion returns the region, like a state, province or county.
+func (address *Address) GetRegion() string {
+	return address.Get(COLUMN_REGION)
+}
+
+// SetRegion sets the region, like a state, province or county.
+func (address *Address) SetRegion(region string) AddressInterface {
+	address.Set(COLUMN_REGION, region)
+	return address
+}
+
+// GetSoftDeletedAt returns the soft deletion timestamp.
+func (address *Address) GetSoftDeletedAt() string {
+	return address.Get(COLUMN_SOFT_DELETED_AT)
+}
+
+// GetSoftDeletedAtCarbon returns the soft deletion timestamp as a Carbon instance.
+func (address *Address) GetSoftDeletedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(address.GetSoftDeletedAt())
+}
+
+// SetSoftDeletedAt sets the soft deletion timestamp.
+func (address *Address) SetSoftDeletedAt(deletedAt string) AddressInterface {
+	address.Set(COLUMN_SOFT_DELETED_AT, deletedAt)
+	return address
+}
+
+// GetUpdatedAt returns the last update timestamp.
+func (address *Address) GetUpdatedAt() string {
+	return address.Get(COLUMN_UPDATED_AT)
+}
+
+// GetUpdatedAtCarbon returns the last update timestamp as a Carbon instance.
+func (address *Address) GetUpdatedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(address.GetUpdatedAt())
+}
+
+// SetUpdatedAt sets the last update timestamp.
+func (address *Address) SetUpdatedAt(updatedAt string) AddressInterface {
+	address.Set(COLUMN_UPDATED_AT, updatedAt)
+	return address
+}
+
+// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+func (address *Address) GetVersion() int64 {
+	return cast.ToInt64(address.Get(COLUMN_VERSION))
+}
+
+// SetVersion sets the version of the row the entity was loaded at.
+func (address *Address) SetVersion(version int64) AddressInterface {
+	address.Set(COLUMN_VERSION, cast.ToString(version))
+	return address
+}
+
+// MarkAsNotDirty resets the dirty state, clearing all change tracking.
+func (address *Address) MarkAsNotDirty() {
+	address.DataObject.MarkAsNotDirty()
+}
+
+// flagValue returns the stored value of a boolean flag column
+func flagValue(flag bool) string {
+	if flag {
+		return "1"
+	}
+
+	return "0"
+}
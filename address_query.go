@@ -0,0 +1,435 @@
+package shopstore
+
+import "errors"
+
+type AddressQueryInterface interface {
+	Validate() error
+
+	Columns() []string
+	SetColumns(columns []string) AddressQueryInterface
+
+	HasCountOnly() bool
+	IsCountOnly() bool
+	SetCountOnly(countOnly bool) AddressQueryInterface
+
+	HasCreatedAtGte() bool
+	CreatedAtGte() string
+	SetCreatedAtGte(createdAtGte string) AddressQueryInterface
+
+	HasCreatedAtLte() bool
+	CreatedAtLte() string
+	SetCreatedAtLte(createdAtLte string) AddressQueryInterface
+
+	HasCustomerID() bool
+	CustomerID() string
+	SetCustomerID(customerID string) AddressQueryInterface
+
+	HasID() bool
+	ID() string
+	SetID(id string) AddressQueryInterface
+
+	HasIDIn() bool
+	IDIn() []string
+	SetIDIn(idIn []string) AddressQueryInterface
+
+	HasLimit() bool
+	Limit() int
+	SetLimit(limit int) AddressQueryInterface
+
+	HasOffset() bool
+	Offset() int
+	SetOffset(offset int) AddressQueryInterface
+
+	HasOrderBy() bool
+	OrderBy() string
+	SetOrderBy(orderBy string) AddressQueryInterface
+
+	HasSortDirection() bool
+	SortDirection() string
+	SetSortDirection(sortDirection string) AddressQueryInterface
+
+	// AddSort adds a sort key. Keys are applied in the order they are added,
+	// after the order_by column if one is set. Only the entity's sortable
+	// columns and the asc/desc directions are accepted by Validate.
+	HasSorts() bool
+	Sorts() []QuerySort
+	AddSort(column string, direction string) AddressQueryInterface
+
+	// AfterCursor continues a keyset paginated list right after the row the
+	// cursor points at. Cursors are returned as NextCursor by the *ListPage methods.
+	HasAfterCursor() bool
+	AfterCursor() string
+	SetAfterCursor(cursor string) AddressQueryInterface
+
+	HasSoftDeletedIncluded() bool
+	SoftDeletedIncluded() bool
+	SetSoftDeletedIncluded(softDeletedIncluded bool) AddressQueryInterface
+
+	HasIsDefaultBilling() bool
+	IsDefaultBilling() bool
+	SetIsDefaultBilling(isDefaultBilling bool) AddressQueryInterface
+
+	HasIsDefaultShipping() bool
+	IsDefaultShipping() bool
+	SetIsDefaultShipping(isDefaultShipping bool) AddressQueryInterface
+
+	hasProperty(name string) bool
+}
+
+func NewAddressQuery() AddressQueryInterface {
+	return &addressQueryImplementation{
+		properties: make(map[string]any),
+	}
+}
+
+type addressQueryImplementation struct {
+	properties map[string]any
+}
+
+func (c *addressQueryImplementation) Validate() error {
+
+	if c.HasCreatedAtGte() && c.CreatedAtGte() == "" {
+		return errors.New("address query. created_at_gte cannot be empty")
+	}
+
+	if c.HasCreatedAtLte() && c.CreatedAtLte() == "" {
+		return errors.New("address query. created_at_lte cannot be empty")
+	}
+
+	if c.HasCustomerID() && c.CustomerID() == "" {
+		return errors.New("address query. customer_id cannot be empty")
+	}
+
+	if c.HasID() && c.ID() == "" {
+		return errors.New("address query. id cannot be empty")
+	}
+
+	if c.HasIDIn() && len(c.IDIn()) == 0 {
+		return errors.New("address query. id_in cannot be empty")
+	}
+
+	if c.HasSortDirection() && c.SortDirection() == "" {
+		return errors.New("address query. sort_direction cannot be empty")
+	}
+
+	if c.HasLimit() && c.Limit() <= 0 {
+		return errors.New("address query. limit must be greater than 0")
+	}
+
+	if c.HasOffset() && c.Offset() < 0 {
+		return errors.New("address query. offset must be greater than or equal to 0")
+	}
+
+	if c.HasOrderBy() && c.OrderBy() == "" {
+		return errors.New("address query. order_by cannot be empty")
+	}
+
+	if err := validateSort(c, addressSortableColumns); err != nil {
+		return errors.New("address query. " + err.Error())
+	}
+
+	if c.HasAfterCursor() {
+		if c.AfterCursor() == "" {
+			return errors.New("address query. after_cursor cannot be empty")
+		}
+
+		if c.HasOffset() {
+			return errors.New("address query. after_cursor cannot be combined with offset")
+		}
+
+		if err := validateCursor(c.AfterCursor(), keysetSort(c)); err != nil {
+			return errors.New("address query. after_cursor " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (c *addressQueryImplementation) Columns() []string {
+	if !c.hasProperty("columns") {
+		return []string{}
+	}
+
+	return c.properties["columns"].([]string)
+}
+
+func (c *addressQueryImplementation) SetColumns(columns []string) AddressQueryInterface {
+	c.properties["columns"] = columns
+
+	return c
+}
+
+func (c *addressQueryImplementation) HasCountOnly() bool {
+	return c.hasProperty("count_only")
+}
+
+func (c *addressQueryImplementation) IsCountOnly() bool {
+	if !c.HasCountOnly() {
+		return false
+	}
+
+	return c.properties["count_only"].(bool)
+}
+
+func (c *addressQueryImplementation) SetCountOnly(countOnly bool) AddressQueryInterface {
+	c.properties["count_only"] = countOnly
+
+	return c
+}
+
+func (c *addressQueryImplementation) HasCustomerID() bool {
+	return c.hasProperty("customer_id")
+}
+
+func (c *addressQueryImplementation) CustomerID() string {
+	if !c.HasCustomerID() {
+		return ""
+	}
+
+	return c.properties["customer_id"].(string)
+}
+
+func (c *addressQueryImplementation) SetCustomerID(customerID string) AddressQueryInterface {
+	c.properties["customer_id"] = customerID
+
+	return c
+}
+
+func (c *addressQueryImplementation) HasCreatedAtGte() bool {
+	return c.hasProperty("created_at_gte")
+}
+
+func (c *addressQueryImplementation) CreatedAtGte() string {
+	if !c.HasCreatedAtGte() {
+		return ""
+	}
+
+	return c.properties["created_at_gte"].(string)
+}
+
+func (c *addressQueryImplementation) SetCreatedAtGte(createdAtGte string) AddressQueryInterface {
+	c.properties["created_at_gte"] = createdAtGte
+
+	return c
+}
+
+func (c *addressQueryImplementation) HasCreatedAtLte() bool {
+	return c.hasProperty("created_at_lte")
+}
+
+func (c *addressQueryImplementation) CreatedAtLte() string {
+	if !c.HasCreatedAtLte() {
+		return ""
+	}
+
+	return c.properties["created_at_lte"].(string)
+}
+
+func (c *addressQueryImplementation) SetCreatedAtLte(createdAtLte string) AddressQueryInterface {
+	c.properties["created_at_lte"] = createdAtLte
+
+	return c
+}
+
+func (c *addressQueryImplementation) HasID() bool {
+	return c.hasProperty("id")
+}
+
+func (c *addressQueryImplementation) ID() string {
+	if !c.HasID() {
+		return ""
+	}
+
+	return c.properties["id"].(string)
+}
+
+func (c *addressQueryImplementation) SetID(id string) AddressQueryInterface {
+	c.properties["id"] = id
+
+	return c
+}
+
+func (c *addressQueryImplementation) HasIDIn() bool {
+	return c.hasProperty("id_in")
+}
+
+func (c *addressQueryImplementation) IDIn() []string {
+	if !c.HasIDIn() {
+		return []string{}
+	}
+
+	return c.properties["id_in"].([]string)
+}
+
+func (c *addressQueryImplementation) SetIDIn(idIn []string) AddressQueryInterface {
+	c.properties["id_in"] = idIn
+
+	return c
+}
+
+func (c *addressQueryImplementation) HasLimit() bool {
+	return c.hasProperty("limit")
+}
+
+func (c *addressQueryImplementation) Limit() int {
+	if !c.HasLimit() {
+		return 0
+	}
+
+	return c.properties["limit"].(int)
+}
+
+func (c *addressQueryImplementation) SetLimit(limit int) AddressQueryInterface {
+	c.properties["limit"] = limit
+
+	return c
+}
+
+func (c *addressQueryImplementation) HasOffset() bool {
+	return c.hasProperty("offset")
+}
+
+func (c *addressQueryImplementation) Offset() int {
+	if !c.HasOffset() {
+		return 0
+	}
+
+	return c.properties["offset"].(int)
+}
+
+func (c *addressQueryImplementation) SetOffset(offset int) AddressQueryInterface {
+	c.properties["offset"] = offset
+
+	return c
+}
+
+func (c *addressQueryImplementation) HasOrderBy() bool {
+	return c.hasProperty("order_by")
+}
+
+func (c *addressQueryImplementation) OrderBy() string {
+	if !c.HasOrderBy() {
+		return ""
+	}
+
+	return c.properties["order_by"].(string)
+}
+
+func (c *addressQueryImplementation) SetOrderBy(orderBy string) AddressQueryInterface {
+	c.properties["order_by"] = orderBy
+
+	return c
+}
+
+func (c *addressQueryImplementation) HasSortDirection() bool {
+	return c.hasProperty("sort_direction")
+}
+
+func (c *addressQueryImplementation) SortDirection() string {
+	if !c.HasSortDirection() {
+		return ""
+	}
+
+	return c.properties["sort_direction"].(string)
+}
+
+func (c *addressQueryImplementation) SetSortDirection(sortDirection string) AddressQueryInterface {
+	c.properties["sort_direction"] = sortDirection
+
+	return c
+}
+
+func (c *addressQueryImplementation) HasSorts() bool {
+	return c.hasProperty("sorts")
+}
+
+func (c *addressQueryImplementation) Sorts() []QuerySort {
+	if !c.HasSorts() {
+		return []QuerySort{}
+	}
+
+	return c.properties["sorts"].([]QuerySort)
+}
+
+func (c *addressQueryImplementation) AddSort(column string, direction string) AddressQueryInterface {
+	c.properties["sorts"] = appendSort(c.Sorts(), column, direction)
+
+	return c
+}
+
+func (c *addressQueryImplementation) HasSoftDeletedIncluded() bool {
+	return c.hasProperty("soft_deleted_included")
+}
+
+func (c *addressQueryImplementation) SoftDeletedIncluded() bool {
+	if !c.HasSoftDeletedIncluded() {
+		return false
+	}
+
+	return c.properties["soft_deleted_included"].(bool)
+}
+
+func (c *addressQueryImplementation) SetSoftDeletedIncluded(softDeletedIncluded bool) AddressQueryInterface {
+	c.properties["soft_deleted_included"] = softDeletedIncluded
+
+	return c
+}
+
+func (c *addressQueryImplementation) HasIsDefaultBilling() bool {
+	return c.hasProperty("is_default_billing")
+}
+
+func (c *addressQueryImplementation) IsDefaultBilling() bool {
+	if !c.HasIsDefaultBilling() {
+		return false
+	}
+
+	return c.properties["is_default_billing"].(bool)
+}
+
+func (c *addressQueryImplementation) SetIsDefaultBilling(isDefaultBilling bool) AddressQueryInterface {
+	c.properties["is_default_billing"] = isDefaultBilling
+
+	return c
+}
+
+func (c *addressQueryImplementation) HasIsDefaultShipping() bool {
+	return c.hasProperty("is_default_shipping")
+}
+
+func (c *addressQueryImplementation) IsDefaultShipping() bool {
+	if !c.HasIsDefaultShipping() {
+		return false
+	}
+
+	return c.properties["is_default_shipping"].(bool)
+}
+
+func (c *addressQueryImplementation) SetIsDefaultShipping(isDefaultShipping bool) AddressQueryInterface {
+	c.properties["is_default_shipping"] = isDefaultShipping
+
+	return c
+}
+
+func (c *addressQueryImplementation) HasAfterCursor() bool {
+	return c.hasProperty("after_cursor")
+}
+
+func (c *addressQueryImplementation) AfterCursor() string {
+	if !c.HasAfterCursor() {
+		return ""
+	}
+
+	return c.properties["after_cursor"].(string)
+}
+
+func (c *addressQueryImplementation) SetAfterCursor(cursor string) AddressQueryInterface {
+	c.properties["after_cursor"] = cursor
+
+	return c
+}
+
+func (c *addressQueryImplementation) hasProperty(name string) bool {
+	_, ok := c.properties[name]
+	return ok
+}
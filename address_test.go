@@ -0,0 +1,97 @@
+package shopstore
+
+import "testing"
+
+func TestNewAddressDefaults(t *testing.T) {
+	address := NewAddress()
+	if address == nil {
+		t.Fatal("NewAddress returned nil")
+	}
+
+	if address.GetID() == "" {
+		t.Fatal("expected generated ID to be non-empty")
+	}
+
+	if address.IsDefaultShipping() || address.IsDefaultBilling() {
+		t.Fatal("expected a new address not to be a default address")
+	}
+
+	if address.GetSoftDeletedAt() != MAX_DATETIME {
+		t.Fatalf("expected soft deleted at to be %q, got %q", MAX_DATETIME, address.GetSoftDeletedAt())
+	}
+
+	if address.GetVersion() != 1 {
+		t.Fatalf("expected version 1, got %d", address.GetVersion())
+	}
+
+	if !address.ToOrderAddress().IsEmpty() {
+		t.Fatalf("expected an empty order address, got %+v", address.ToOrderAddress())
+	}
+}
+
+func TestAddressSetCountryCodeNormalizes(t *testing.T) {
+	address := NewAddress().SetCountryCode(" gb ")
+
+	if address.GetCountryCode() != "GB" {
+		t.Fatalf("expected the country code trimmed and uppercased, got %q", address.GetCountryCode())
+	}
+}
+
+func TestAddressToOrderAddress(t *testing.T) {
+	address := NewAddress().
+		SetName("Jane Doe").
+		SetLine1("1 High Street").
+		SetLine2("Flat 2").
+		SetCity("London").
+		SetRegion("Greater London").
+		SetPostalCode("SW1A 1AA").
+		SetCountryCode("GB").
+		SetPhone("+44 20 7946 0000")
+
+	expected := OrderAddress{
+		Name:        "Jane Doe",
+		Line1:       "1 High Street",
+		Line2:       "Flat 2",
+		City:        "London",
+		Region:      "Greater London",
+		PostalCode:  "SW1A 1AA",
+		CountryCode: "GB",
+		Phone:       "+44 20 7946 0000",
+	}
+
+	if address.ToOrderAddress() != expected {
+		t.Fatalf("unexpected order address %+v", address.ToOrderAddress())
+	}
+}
+
+func TestOrderAddressRoundTrip(t *testing.T) {
+	order := NewOrder()
+
+	shipping, err := order.GetShippingAddress()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !shipping.IsEmpty() {
+		t.Fatalf("expected no shipping address by default, got %+v", shipping)
+	}
+
+	if err := order.SetBillingAddress(OrderAddress{City: "Paris", CountryCode: "FR"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	billing, err := order.GetBillingAddress()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if billing.City != "Paris" || billing.CountryCode != "FR" {
+		t.Fatalf("unexpected billing address %+v", billing)
+	}
+
+	// orders stored before the address columns were added
+	billing, err = NewOrderFromExistingData(map[string]string{}).GetBillingAddress()
+	if err != nil || !billing.IsEmpty() {
+		t.Fatalf("expected an empty billing address, got %+v, %v", billing, err)
+	}
+}
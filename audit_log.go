@@ -0,0 +1,182 @@
+package shopstore
+
+import (
+	"encoding/json"
+
+	"github.com/dracory/dataobject"
+	"github.com/dromara/carbon/v2"
+)
+
+// == TYPE ====================================================================
+
+// AuditLog records a single change of a store entity: who (the actor from
+// the context, see WithActorID) did what (the operation) to which entity,
+// with the values of the changed fields before and after the change.
+type AuditLog struct {
+	dataobject.DataObject
+}
+
+// == INTERFACES ===============================================================
+
+// Compile-time interface compliance check
+var _ AuditLogInterface = (*AuditLog)(nil)
+
+// == CONSTRUCTORS =============================================================
+
+// NewAuditLog creates a new audit log entry:
+// - ValuesBefore, ValuesAfter: empty JSON objects
+// - CreatedAt: current UTC time
+func NewAuditLog(entityType string, entityID string, operation string) AuditLogInterface {
+	o := (&AuditLog{}).
+		SetID(GenerateShortID()).
+		SetEntityType(entityType).
+		SetEntityID(entityID).
+		SetOperation(operation).
+		SetActorID("").
+		SetValuesBefore("{}").
+		SetValuesAfter("{}").
+		SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+
+	return o
+}
+
+// NewAuditLogFromExistingData creates an audit log entry from existing data map.
+// Used when hydrating from database or external sources.
+func NewAuditLogFromExistingData(data map[string]string) AuditLogInterface {
+	o := &AuditLog{}
+	o.Hydrate(data)
+	return o
+}
+
+// == SETTESR AND GETTERS =====================================================
+
+// GetActorID returns the ID of the actor who made the change, empty if unknown.
+func (o *AuditLog) GetActorID() string {
+	return o.Get(COLUMN_ACTOR_ID)
+}
+
+// SetActorID sets the ID of the actor who made the change.
+func (o *AuditLog) SetActorID(actorID string) AuditLogInterface {
+	o.Set(COLUMN_ACTOR_ID, actorID)
+	return o
+}
+
+// GetCreatedAt returns the time of the change as a string.
+func (o *AuditLog) GetCreatedAt() string {
+	return o.Get(COLUMN_CREATED_AT)
+}
+
+// SetCreatedAt sets the time of the change.
+func (o *AuditLog) SetCreatedAt(createdAt string) AuditLogInterface {
+	o.Set(COLUMN_CREATED_AT, createdAt)
+	return o
+}
+
+// GetEntityID returns the ID of the changed entity.
+func (o *AuditLog) GetEntityID() string {
+	return o.Get(COLUMN_ENTITY_ID)
+}
+
+// SetEntityID sets the ID of the changed entity.
+func (o *AuditLog) SetEntityID(entityID string) AuditLogInterface {
+	o.Set(COLUMN_ENTITY_ID, entityID)
+	return o
+}
+
+// GetEntityType returns the type of the changed entity (e.g. "product").
+func (o *AuditLog) GetEntityType() string {
+	return o.Get(COLUMN_ENTITY_TYPE)
+}
+
+// SetEntityType sets the type of the changed entity.
+func (o *AuditLog) SetEntityType(entityType string) AuditLogInterface {
+	o.Set(COLUMN_ENTITY_TYPE, entityType)
+	return o
+}
+
+// GetID returns the unique identifier.
+func (o *AuditLog) GetID() string {
+	return o.Get(COLUMN_ID)
+}
+
+// SetID sets the unique identifier.
+func (o *AuditLog) SetID(id string) AuditLogInterface {
+	o.Set(COLUMN_ID, id)
+	return o
+}
+
+// GetOperation returns the operation, one of the AUDIT_OPERATION_ constants.
+func (o *AuditLog) GetOperation() string {
+	return o.Get(COLUMN_OPERATION)
+}
+
+// SetOperation sets the operation.
+func (o *AuditLog) SetOperation(operation string) AuditLogInterface {
+	o.Set(COLUMN_OPERATION, operation)
+	return o
+}
+
+// GetValuesAfter returns the changed fields after the change as a JSON string.
+func (o *AuditLog) GetValuesAfter() string {
+	return o.Get(COLUMN_VALUES_AFTER)
+}
+
+// GetValuesAfterMap returns the changed fields after the change decoded as a map.
+func (o *AuditLog) GetValuesAfterMap() (map[string]string, error) {
+	return auditValuesDecode(o.GetValuesAfter())
+}
+
+// SetValuesAfter sets the changed fields after the change as a JSON string.
+func (o *AuditLog) SetValuesAfter(values string) AuditLogInterface {
+	o.Set(COLUMN_VALUES_AFTER, values)
+	return o
+}
+
+// SetValuesAfterMap sets the changed fields after the change from a map, stored as JSON.
+func (o *AuditLog) SetValuesAfterMap(values map[string]string) error {
+	jsonBytes, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	o.SetValuesAfter(string(jsonBytes))
+	return nil
+}
+
+// GetValuesBefore returns the changed fields before the change as a JSON string.
+func (o *AuditLog) GetValuesBefore() string {
+	return o.Get(COLUMN_VALUES_BEFORE)
+}
+
+// GetValuesBeforeMap returns the changed fields before the change decoded as a map.
+func (o *AuditLog) GetValuesBeforeMap() (map[string]string, error) {
+	return auditValuesDecode(o.GetValuesBefore())
+}
+
+// SetValuesBefore sets the changed fields before the change as a JSON string.
+func (o *AuditLog) SetValuesBefore(values string) AuditLogInterface {
+	o.Set(COLUMN_VALUES_BEFORE, values)
+	return o
+}
+
+// SetValuesBeforeMap sets the changed fields before the change from a map, stored as JSON.
+func (o *AuditLog) SetValuesBeforeMap(values map[string]string) error {
+	jsonBytes, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	o.SetValuesBefore(string(jsonBytes))
+	return nil
+}
+
+// MarkAsNotDirty resets the dirty state, clearing all change tracking.
+func (o *AuditLog) MarkAsNotDirty() {
+	o.DataObject.MarkAsNotDirty()
+}
+
+func auditValuesDecode(values string) (map[string]string, error) {
+	decoded := map[string]string{}
+	if err := json.Unmarshal([]byte(values), &decoded); err != nil {
+		return map[string]string{}, err
+	}
+	return decoded, nil
+}
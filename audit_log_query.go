@@ -0,0 +1,366 @@
+package shopstore
+
+import "errors"
+
+type AuditLogQueryInterface interface {
+	Validate() error
+
+	HasActorID() bool
+	ActorID() string
+	SetActorID(actorID string) AuditLogQueryInterface
+
+	HasCountOnly() bool
+	IsCountOnly() bool
+	SetCountOnly(countOnly bool) AuditLogQueryInterface
+
+	// CreatedAtGte and CreatedAtLte bound the time of the change, inclusive
+	HasCreatedAtGte() bool
+	CreatedAtGte() string
+	SetCreatedAtGte(createdAtGte string) AuditLogQueryInterface
+
+	HasCreatedAtLte() bool
+	CreatedAtLte() string
+	SetCreatedAtLte(createdAtLte string) AuditLogQueryInterface
+
+	HasEntityID() bool
+	EntityID() string
+	SetEntityID(entityID string) AuditLogQueryInterface
+
+	HasEntityType() bool
+	EntityType() string
+	SetEntityType(entityType string) AuditLogQueryInterface
+
+	HasID() bool
+	ID() string
+	SetID(id string) AuditLogQueryInterface
+
+	HasLimit() bool
+	Limit() int
+	SetLimit(limit int) AuditLogQueryInterface
+
+	HasOffset() bool
+	Offset() int
+	SetOffset(offset int) AuditLogQueryInterface
+
+	HasOperation() bool
+	Operation() string
+	SetOperation(operation string) AuditLogQueryInterface
+
+	HasOrderBy() bool
+	OrderBy() string
+	SetOrderBy(orderBy string) AuditLogQueryInterface
+
+	HasSortDirection() bool
+	SortDirection() string
+	SetSortDirection(sortDirection string) AuditLogQueryInterface
+
+	// AddSort adds a sort key. Keys are applied in the order they are added,
+	// after the order_by column if one is set. Without any sort the entries
+	// are listed oldest first.
+	HasSorts() bool
+	Sorts() []QuerySort
+	AddSort(column string, direction string) AuditLogQueryInterface
+
+	hasProperty(name string) bool
+}
+
+func NewAuditLogQuery() AuditLogQueryInterface {
+	return &auditLogQueryImplementation{
+		properties: make(map[string]any),
+	}
+}
+
+type auditLogQueryImplementation struct {
+	properties map[string]any
+}
+
+func (c *auditLogQueryImplementation) Validate() error {
+	if c.HasActorID() && c.ActorID() == "" {
+		return errors.New("audit log query. actor_id cannot be empty")
+	}
+
+	if c.HasCreatedAtGte() && c.CreatedAtGte() == "" {
+		return errors.New("audit log query. created_at_gte cannot be empty")
+	}
+
+	if c.HasCreatedAtLte() && c.CreatedAtLte() == "" {
+		return errors.New("audit log query. created_at_lte cannot be empty")
+	}
+
+	if c.HasEntityID() && c.EntityID() == "" {
+		return errors.New("audit log query. entity_id cannot be empty")
+	}
+
+	if c.HasEntityType() && c.EntityType() == "" {
+		return errors.New("audit log query. entity_type cannot be empty")
+	}
+
+	if c.HasID() && c.ID() == "" {
+		return errors.New("audit log query. id cannot be empty")
+	}
+
+	if c.HasOperation() && c.Operation() == "" {
+		return errors.New("audit log query. operation cannot be empty")
+	}
+
+	if c.HasOrderBy() && c.OrderBy() == "" {
+		return errors.New("audit log query. order_by cannot be empty")
+	}
+
+	if c.HasSortDirection() && c.SortDirection() == "" {
+		return errors.New("audit log query. sort_direction cannot be empty")
+	}
+
+	if c.HasOffset() && c.Offset() < 0 {
+		return errors.New("audit log query. offset cannot be negative")
+	}
+
+	if c.HasLimit() && c.Limit() < 0 {
+		return errors.New("audit log query. limit cannot be negative")
+	}
+
+	if err := validateSort(c, auditLogSortableColumns); err != nil {
+		return errors.New("audit log query. " + err.Error())
+	}
+
+	return nil
+}
+
+func (c *auditLogQueryImplementation) HasActorID() bool {
+	return c.hasProperty("actor_id")
+}
+
+func (c *auditLogQueryImplementation) ActorID() string {
+	if !c.HasActorID() {
+		return ""
+	}
+
+	return c.properties["actor_id"].(string)
+}
+
+func (c *auditLogQueryImplementation) SetActorID(actorID string) AuditLogQueryInterface {
+	c.properties["actor_id"] = actorID
+
+	return c
+}
+
+func (c *auditLogQueryImplementation) HasCountOnly() bool {
+	return c.hasProperty("count_only")
+}
+
+func (c *auditLogQueryImplementation) IsCountOnly() bool {
+	if !c.HasCountOnly() {
+		return false
+	}
+
+	return c.properties["count_only"].(bool)
+}
+
+func (c *auditLogQueryImplementation) SetCountOnly(countOnly bool) AuditLogQueryInterface {
+	c.properties["count_only"] = countOnly
+
+	return c
+}
+
+func (c *auditLogQueryImplementation) HasCreatedAtGte() bool {
+	return c.hasProperty("created_at_gte")
+}
+
+func (c *auditLogQueryImplementation) CreatedAtGte() string {
+	if !c.HasCreatedAtGte() {
+		return ""
+	}
+
+	return c.properties["created_at_gte"].(string)
+}
+
+func (c *auditLogQueryImplementation) SetCreatedAtGte(createdAtGte string) AuditLogQueryInterface {
+	c.properties["created_at_gte"] = createdAtGte
+
+	return c
+}
+
+func (c *auditLogQueryImplementation) HasCreatedAtLte() bool {
+	return c.hasProperty("created_at_lte")
+}
+
+func (c *auditLogQueryImplementation) CreatedAtLte() string {
+	if !c.HasCreatedAtLte() {
+		return ""
+	}
+
+	return c.properties["created_at_lte"].(string)
+}
+
+func (c *auditLogQueryImplementation) SetCreatedAtLte(createdAtLte string) AuditLogQueryInterface {
+	c.properties["created_at_lte"] = createdAtLte
+
+	return c
+}
+
+func (c *auditLogQueryImplementation) HasEntityID() bool {
+	return c.hasProperty("entity_id")
+}
+
+func (c *auditLogQueryImplementation) EntityID() string {
+	if !c.HasEntityID() {
+		return ""
+	}
+
+	return c.properties["entity_id"].(string)
+}
+
+func (c *auditLogQueryImplementation) SetEntityID(entityID string) AuditLogQueryInterface {
+	c.properties["entity_id"] = entityID
+
+	return c
+}
+
+func (c *auditLogQueryImplementation) HasEntityType() bool {
+	return c.hasProperty("entity_type")
+}
+
+func (c *auditLogQueryImplementation) EntityType() string {
+	if !c.HasEntityType() {
+		return ""
+	}
+
+	return c.properties["entity_type"].(string)
+}
+
+func (c *auditLogQueryImplementation) SetEntityType(entityType string) AuditLogQueryInterface {
+	c.properties["entity_type"] = entityType
+
+	return c
+}
+
+func (c *auditLogQueryImplementation) HasID() bool {
+	return c.hasProperty("id")
+}
+
+func (c *auditLogQueryImplementation) ID() string {
+	if !c.HasID() {
+		return ""
+	}
+
+	return c.properties["id"].(string)
+}
+
+func (c *auditLogQueryImplementation) SetID(id string) AuditLogQueryInterface {
+	c.properties["id"] = id
+
+	return c
+}
+
+func (c *auditLogQueryImplementation) HasLimit() bool {
+	return c.hasProperty("limit")
+}
+
+func (c *auditLogQueryImplementation) Limit() int {
+	if !c.HasLimit() {
+		return 0
+	}
+
+	return c.properties["limit"].(int)
+}
+
+func (c *auditLogQueryImplementation) SetLimit(limit int) AuditLogQueryInterface {
+	c.properties["limit"] = limit
+
+	return c
+}
+
+func (c *auditLogQueryImplementation) HasOffset() bool {
+	return c.hasProperty("offset")
+}
+
+func (c *auditLogQueryImplementation) Offset() int {
+	if !c.HasOffset() {
+		return 0
+	}
+
+	return c.properties["offset"].(int)
+}
+
+func (c *auditLogQueryImplementation) SetOffset(offset int) AuditLogQueryInterface {
+	c.properties["offset"] = offset
+
+	return c
+}
+
+func (c *auditLogQueryImplementation) HasOperation() bool {
+	return c.hasProperty("operation")
+}
+
+func (c *auditLogQueryImplementation) Operation() string {
+	if !c.HasOperation() {
+		return ""
+	}
+
+	return c.properties["operation"].(string)
+}
+
+func (c *auditLogQueryImplementation) SetOperation(operation string) AuditLogQueryInterface {
+	c.properties["operation"] = operation
+
+	return c
+}
+
+func (c *auditLogQueryImplementation) HasOrderBy() bool {
+	return c.hasProperty("order_by")
+}
+
+func (c *auditLogQueryImplementation) OrderBy() string {
+	if !c.HasOrderBy() {
+		return ""
+	}
+
+	return c.properties["order_by"].(string)
+}
+
+func (c *auditLogQueryImplementation) SetOrderBy(orderBy string) AuditLogQueryInterface {
+	c.properties["order_by"] = orderBy
+
+	return c
+}
+
+func (c *auditLogQueryImplementation) HasSortDirection() bool {
+	return c.hasProperty("sort_direction")
+}
+
+func (c *auditLogQueryImplementation) SortDirection() string {
+	if !c.HasSortDirection() {
+		return ""
+	}
+
+	return c.properties["sort_direction"].(string)
+}
+
+func (c *auditLogQueryImplementation) SetSortDirection(sortDirection string) AuditLogQueryInterface {
+	c.properties["sort_direction"] = sortDirection
+
+	return c
+}
+
+func (c *auditLogQueryImplementation) HasSorts() bool {
+	return c.hasProperty("sorts")
+}
+
+func (c *auditLogQueryImplementation) Sorts() []QuerySort {
+	if !c.HasSorts() {
+		return []QuerySort{}
+	}
+
+	return c.properties["sorts"].([]QuerySort)
+}
+
+func (c *auditLogQueryImplementation) AddSort(column string, direction string) AuditLogQueryInterface {
+	c.properties["sorts"] = appendSort(c.Sorts(), column, direction)
+
+	return c
+}
+
+func (c *auditLogQueryImplementation) hasProperty(name string) bool {
+	_, ok := c.properties[name]
+	return ok
+}
@@ -0,0 +1,401 @@
+package shopstore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+	neatquery "github.com/dracory/neat/database/query"
+	"github.com/dromara/carbon/v2"
+	"github.com/samber/lo"
+)
+
+// bulkBatchSize is the number of rows written, or IDs matched, by a single
+// statement of the bulk operations. Keeps the bind parameters of a
+// statement well below the limits of the supported databases.
+const bulkBatchSize = 500
+
+// Updatable columns, per entity. Only these can be set with the UpdateMany
+// operations, so that arbitrary strings never reach the SET clause. IDs,
+// timestamps and versions are maintained by the store. Discount codes and
+// customer emails are unique, and a customer has a single default shipping
+// and billing address, so these (and the customer of addresses) cannot be
+// set on many rows at once.
+var addressUpdatableColumns = []string{
+	COLUMN_CITY,
+	COLUMN_COUNTRY_CODE,
+	COLUMN_LINE1,
+	COLUMN_LINE2,
+	COLUMN_MEMO,
+	COLUMN_METAS,
+	COLUMN_NAME,
+	COLUMN_PHONE,
+	COLUMN_POSTAL_CODE,
+	COLUMN_REGION,
+}
+
+var categoryUpdatableColumns = []string{
+	COLUMN_DESCRIPTION,
+	COLUMN_MEMO,
+	COLUMN_METAS,
+	COLUMN_PARENT_ID,
+	COLUMN_STATUS,
+	COLUMN_TITLE,
+}
+
+var customerUpdatableColumns = []string{
+	COLUMN_MEMO,
+	COLUMN_METAS,
+	COLUMN_NAME,
+	COLUMN_PHONE,
+	COLUMN_STATUS,
+}
+
+var discountUpdatableColumns = []string{
+	COLUMN_AMOUNT,
+	COLUMN_DESCRIPTION,
+	COLUMN_ENDS_AT,
+	COLUMN_MEMO,
+	COLUMN_METAS,
+	COLUMN_STARTS_AT,
+	COLUMN_STATUS,
+	COLUMN_TITLE,
+	COLUMN_TYPE,
+}
+
+var mediaUpdatableColumns = []string{
+	COLUMN_DESCRIPTION,
+	COLUMN_ENTITY_ID,
+	COLUMN_MEDIA_TYPE,
+	COLUMN_MEDIA_URL,
+	COLUMN_MEMO,
+	COLUMN_METAS,
+	COLUMN_SEQUENCE,
+	COLUMN_STATUS,
+	COLUMN_TITLE,
+}
+
+var orderUpdatableColumns = []string{
+	COLUMN_CUSTOMER_ID,
+	COLUMN_MEMO,
+	COLUMN_METAS,
+	COLUMN_PRICE,
+	COLUMN_QUANTITY,
+	COLUMN_STATUS,
+}
+
+var orderLineItemUpdatableColumns = []string{
+	COLUMN_MEMO,
+	COLUMN_METAS,
+	COLUMN_ORDER_ID,
+	COLUMN_PRICE,
+	COLUMN_PRODUCT_ID,
+	COLUMN_QUANTITY,
+	COLUMN_STATUS,
+	COLUMN_TITLE,
+}
+
+var productUpdatableColumns = []string{
+	COLUMN_CATEGORY_ID,
+	COLUMN_DESCRIPTION,
+	COLUMN_HEIGHT,
+	COLUMN_LENGTH,
+	COLUMN_MEMO,
+	COLUMN_METAS,
+	COLUMN_PARENT_ID,
+	COLUMN_PRICE,
+	COLUMN_QUANTITY,
+	COLUMN_SHORT_DESCRIPTION,
+	COLUMN_STATUS,
+	COLUMN_TAX_CLASS,
+	COLUMN_TITLE,
+	COLUMN_VARIANT_MATRIX_SCHEMA,
+	COLUMN_VARIANT_MATRIX_VALUES,
+	COLUMN_WEIGHT,
+	COLUMN_WIDTH,
+}
+
+var shippingMethodUpdatableColumns = []string{
+	COLUMN_AMOUNT,
+	COLUMN_FREE_OVER_AMOUNT,
+	COLUMN_MEMO,
+	COLUMN_METAS,
+	COLUMN_NAME,
+	COLUMN_RATE_RULES,
+	COLUMN_RATE_TYPE,
+	COLUMN_SHIPPING_ZONE_ID,
+	COLUMN_STATUS,
+}
+
+var shippingZoneUpdatableColumns = []string{
+	COLUMN_COUNTRY_CODES,
+	COLUMN_MEMO,
+	COLUMN_METAS,
+	COLUMN_NAME,
+	COLUMN_REGIONS,
+	COLUMN_STATUS,
+}
+
+var taxRateUpdatableColumns = []string{
+	COLUMN_COUNTRY_CODE,
+	COLUMN_MEMO,
+	COLUMN_METAS,
+	COLUMN_NAME,
+	COLUMN_RATE,
+	COLUMN_REGION,
+	COLUMN_TAX_CLASS,
+}
+
+// bulkTable describes the rows of an entity table selected by a bulk
+// update or soft delete
+type bulkTable struct {
+	entityType string
+	tableName  string
+
+	// selectRows applies the query options of the operation to q
+	selectRows func(q contractsorm.Query) (contractsorm.Query, error)
+
+	// events returns the outbox events of a row change, nil when none
+	events func(id string, before map[string]string, changed map[string]string) ([]OutboxEventInterface, error)
+
+	// dependents are the rows keeping the selected rows from being soft deleted
+	dependents []dependentCheck
+}
+
+// dependentCheck is a column of a table referencing the rows of a bulk
+// soft delete. A live row referencing one of them fails the soft delete
+// with err, unless the referencing row is soft deleted along.
+type dependentCheck struct {
+	tableName string
+	column    string
+	err       error
+}
+
+// assertUpdatable checks that fields only sets updatable columns
+func assertUpdatable(fields map[string]string, updatableColumns []string) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("%w: no fields to update", ErrInvalidBulkUpdate)
+	}
+
+	for column := range fields {
+		if !slices.Contains(updatableColumns, column) {
+			return fmt.Errorf("%w: column %q cannot be updated", ErrInvalidBulkUpdate, column)
+		}
+	}
+
+	return nil
+}
+
+// createMany inserts the data of new entities in a single transaction,
+// with batched multi-row statements, and records their creation. The
+// references of the entities may point at entities of the same batch.
+func (store *Store) createMany(ctx context.Context, entityType string, tableName string, references []referenceCheck, rows []map[string]string) error {
+	if err := store.assertManyReferencesExist(ctx, tableName, rows, references); err != nil {
+		return err
+	}
+
+	return store.query(ctx).Transaction(func(tx contractsorm.Query) error {
+		if err := bulkInsert(tx, tableName, rows); err != nil {
+			return err
+		}
+
+		records := changeRecords{}
+		for _, row := range rows {
+			records.auditLogs = append(records.auditLogs, store.auditLogEntries(ctx, entityType, row[COLUMN_ID], AUDIT_OPERATION_CREATE, nil, row)...)
+		}
+
+		return store.recordChanges(tx, records)
+	})
+}
+
+// updateMany sets the fields of all the rows selected from the table, in a
+// single transaction with batched statements, and records the change of
+// every row. The rows are locked and their previous values read first.
+// Setting soft_deleted_at soft deletes the selected live rows, failing
+// with the error of the first dependent check still referencing them.
+//
+// Versions are incremented without being checked, as the rows are
+// selected by query rather than loaded. Returns the IDs of the rows set.
+func (store *Store) updateMany(ctx context.Context, table bulkTable, fields map[string]string) ([]string, error) {
+	ids, _, err := store.updateManyLoaded(ctx, table, fields, false)
+	return ids, err
+}
+
+// updateManyLoaded is updateMany also returning, when load is set, the rows
+// set as read back within the transaction, for the AfterUpdate hooks to
+// run on
+func (store *Store) updateManyLoaded(ctx context.Context, table bulkTable, fields map[string]string, load bool) ([]string, []map[string]string, error) {
+	var ids []string
+	var rows []map[string]string
+	err := store.query(ctx).Transaction(func(tx contractsorm.Query) error {
+		var records changeRecords
+		var err error
+
+		ids, records, err = store.updateManyOn(ctx, tx, table, fields)
+		if err != nil {
+			return err
+		}
+
+		if load {
+			if rows, err = rowsByID(tx, table.tableName, ids); err != nil {
+				return err
+			}
+		}
+
+		return store.recordChanges(tx, records)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ids, rows, nil
+}
+
+// rowsByID reads the rows of the table with the IDs within the transaction tx
+func rowsByID(tx contractsorm.Query, tableName string, ids []string) ([]map[string]string, error) {
+	rows := []map[string]string{}
+
+	for _, batch := range lo.Chunk(ids, bulkBatchSize) {
+		var results []map[string]any
+		err := statement(tx).Table(tableName).WhereIn(COLUMN_ID, lo.ToAnySlice(batch)).Get(&results)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, result := range results {
+			rows = append(rows, mapAnyToString(result))
+		}
+	}
+
+	return rows, nil
+}
+
+// updateManyOn is updateMany within the transaction tx, returning the IDs
+// of the rows set and the records of the change to record
+func (store *Store) updateManyOn(ctx context.Context, tx contractsorm.Query, table bulkTable, fields map[string]string) ([]string, changeRecords, error) {
+	changed := lo.Assign(fields, map[string]string{
+		COLUMN_UPDATED_AT: carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC),
+	})
+
+	_, softDelete := fields[COLUMN_SOFT_DELETED_AT]
+
+	q, err := table.selectRows(statement(tx))
+	if err != nil {
+		return nil, changeRecords{}, err
+	}
+
+	if softDelete {
+		q = q.Where(COLUMN_SOFT_DELETED_AT+" = ?", MAX_DATETIME)
+	}
+
+	var results []map[string]any
+	err = q.Select(append([]string{COLUMN_ID}, lo.Keys(changed)...)).LockForUpdate().Get(&results)
+	if err != nil {
+		return nil, changeRecords{}, err
+	}
+
+	rows := lo.Map(results, func(result map[string]any, _ int) map[string]string {
+		return mapAnyToString(result)
+	})
+
+	ids := lo.Map(rows, func(row map[string]string, _ int) string {
+		return row[COLUMN_ID]
+	})
+
+	if softDelete {
+		if err := assertNoDependents(tx, ids, table.dependents); err != nil {
+			return nil, changeRecords{}, err
+		}
+	}
+
+	for _, batch := range lo.Chunk(ids, bulkBatchSize) {
+		row := map[string]any{COLUMN_VERSION: neatquery.RawExpr(COLUMN_VERSION + " + 1")}
+		for k, v := range changed {
+			row[k] = rowValue(k, v)
+		}
+
+		_, err := statement(tx).Table(table.tableName).WhereIn(COLUMN_ID, lo.ToAnySlice(batch)).Update(row)
+		if err != nil {
+			return nil, changeRecords{}, err
+		}
+	}
+
+	records := changeRecords{}
+	for _, before := range rows {
+		id := before[COLUMN_ID]
+		delete(before, COLUMN_ID)
+
+		records.auditLogs = append(records.auditLogs, store.auditLogEntries(ctx, table.entityType, id, updateOperation(changed), before, changed)...)
+
+		if table.events == nil {
+			continue
+		}
+
+		events, err := table.events(id, before, changed)
+		if err != nil {
+			return nil, changeRecords{}, err
+		}
+
+		records.events = append(records.events, events...)
+	}
+
+	return ids, records, nil
+}
+
+// assertNoDependents checks within the transaction tx that no live row
+// outside of ids references one of the ids
+func assertNoDependents(tx contractsorm.Query, ids []string, dependents []dependentCheck) error {
+	selected := lo.Keyify(ids)
+
+	for _, dependent := range dependents {
+		for _, batch := range lo.Chunk(ids, bulkBatchSize) {
+			var results []map[string]any
+			err := statement(tx).
+				Table(dependent.tableName).
+				Select([]string{COLUMN_ID}).
+				WhereIn(dependent.column, lo.ToAnySlice(batch)).
+				Where(COLUMN_SOFT_DELETED_AT+" = ?", MAX_DATETIME).
+				Get(&results)
+			if err != nil {
+				return err
+			}
+
+			for _, result := range results {
+				if _, ok := selected[mapAnyToString(result)[COLUMN_ID]]; !ok {
+					return dependent.err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// bulkInsert inserts the rows in the table with multi-row statements of up
+// to bulkBatchSize rows. A statement has a single column list, so rows are
+// grouped by the columns they set.
+func bulkInsert(tx contractsorm.Query, tableName string, rows []map[string]string) error {
+	groups := lo.GroupBy(rows, func(row map[string]string) string {
+		columns := lo.Keys(row)
+		slices.Sort(columns)
+		return strings.Join(columns, ",")
+	})
+
+	for _, columns := range lo.Keys(groups) {
+		for _, batch := range lo.Chunk(groups[columns], bulkBatchSize) {
+			values := lo.Map(batch, func(row map[string]string, _ int) map[string]any {
+				return lo.MapValues(row, func(value string, column string) any {
+					return rowValue(column, value)
+				})
+			})
+
+			if err := statement(tx).Table(tableName).Create(values); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,358 @@
+package shopstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestStoreProductCreateMany(t *testing.T) {
+	store := initIntegrityStore(t)
+	ctx := context.Background()
+
+	created := 0
+	store.ProductHooks().AfterCreate(func(ctx context.Context, product ProductInterface) {
+		created++
+	})
+
+	parent := NewProduct().SetStatus(PRODUCT_STATUS_ACTIVE).SetTitle("Shirt")
+	small := NewProduct().SetStatus(PRODUCT_STATUS_ACTIVE).SetTitle("Shirt S").SetParentID(parent.GetID())
+	large := NewProduct().SetStatus(PRODUCT_STATUS_ACTIVE).SetTitle("Shirt L").SetParentID(parent.GetID())
+
+	if err := store.ProductCreateMany(ctx, []ProductInterface{small, parent, large}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if created != 3 {
+		t.Fatalf("expected the create hooks to run for 3 products, got %d", created)
+	}
+
+	variants, err := store.ProductVariantList(ctx, parent.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(variants) != 2 {
+		t.Fatalf("expected 2 variants, got %d", len(variants))
+	}
+
+	if small.GetCreatedAt() == "" || len(small.DataChanged()) != 0 {
+		t.Fatal("expected the created products to be stamped and not dirty")
+	}
+
+	orphan := NewProduct().SetStatus(PRODUCT_STATUS_ACTIVE).SetTitle("Orphan").SetParentID("MISSING")
+	other := NewProduct().SetStatus(PRODUCT_STATUS_ACTIVE).SetTitle("Other")
+
+	err = store.ProductCreateMany(ctx, []ProductInterface{other, orphan})
+	if !errors.Is(err, ErrReferenceNotFound) {
+		t.Fatalf("expected ErrReferenceNotFound, got %v", err)
+	}
+
+	count, err := store.ProductCount(ctx, NewProductQuery())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if count != 3 {
+		t.Fatalf("expected none of the failed batch to be created, got %d products", count)
+	}
+}
+
+func TestStoreMediaCreateMany_Batches(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	total := bulkBatchSize*2 + 1
+	list := make([]MediaInterface, total)
+	for i := range list {
+		list[i] = NewMedia().
+			SetStatus(MEDIA_STATUS_ACTIVE).
+			SetEntityID("PRODUCT01").
+			SetType("image/png").
+			SetURL("https://example.com/image.png").
+			SetSequence(i)
+	}
+
+	if err := store.MediaCreateMany(ctx, list); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	count, err := store.MediaCount(ctx, NewMediaQuery().SetEntityID("PRODUCT01"))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if count != int64(total) {
+		t.Fatalf("expected %d media, got %d", total, count)
+	}
+
+	updated, err := store.MediaUpdateMany(ctx, NewMediaQuery().SetEntityID("PRODUCT01"), map[string]string{
+		COLUMN_STATUS: MEDIA_STATUS_INACTIVE,
+	})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if updated != int64(total) {
+		t.Fatalf("expected %d media updated, got %d", total, updated)
+	}
+
+	count, err = store.MediaCount(ctx, NewMediaQuery().SetStatus(MEDIA_STATUS_INACTIVE))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if count != int64(total) {
+		t.Fatalf("expected %d inactive media, got %d", total, count)
+	}
+}
+
+func TestStoreOrderLineItemCreateMany(t *testing.T) {
+	store := initIntegrityStore(t)
+	ctx := context.Background()
+
+	order := NewOrder().SetCustomerID("CUSTOMER01_ID").SetStatus(ORDER_STATUS_PENDING)
+	if err := store.OrderCreate(ctx, order); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	product := NewProduct().SetStatus(PRODUCT_STATUS_ACTIVE).SetTitle("Widget")
+	if err := store.ProductCreate(ctx, product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	items := []OrderLineItemInterface{
+		NewOrderLineItem().SetOrderID(order.GetID()).SetProductID(product.GetID()).SetQuantityInt(1),
+		NewOrderLineItem().SetOrderID(order.GetID()).SetProductID(product.GetID()).SetQuantityInt(2),
+	}
+
+	if err := store.OrderLineItemCreateMany(ctx, items); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	count, err := store.OrderLineItemCount(ctx, NewOrderLineItemQuery().SetOrderID(order.GetID()))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if count != 2 {
+		t.Fatalf("expected 2 line items, got %d", count)
+	}
+
+	if err := store.OrderLineItemCreateMany(ctx, []OrderLineItemInterface{nil}); err == nil {
+		t.Fatal("expected an error for a nil line item")
+	}
+}
+
+func TestStoreProductUpdateMany(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	drafts := []ProductInterface{
+		NewProduct().SetStatus(PRODUCT_STATUS_DRAFT).SetTitle("One"),
+		NewProduct().SetStatus(PRODUCT_STATUS_DRAFT).SetTitle("Two"),
+	}
+	active := NewProduct().SetStatus(PRODUCT_STATUS_ACTIVE).SetTitle("Three")
+
+	if err := store.ProductCreateMany(ctx, append(drafts, active)); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	updated, err := store.ProductUpdateMany(ctx, NewProductQuery().SetStatus(PRODUCT_STATUS_DRAFT), map[string]string{
+		COLUMN_STATUS: PRODUCT_STATUS_ACTIVE,
+		COLUMN_MEMO:   "published",
+	})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if updated != 2 {
+		t.Fatalf("expected 2 products updated, got %d", updated)
+	}
+
+	found, err := store.ProductFindByID(ctx, drafts[0].GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found.GetStatus() != PRODUCT_STATUS_ACTIVE || found.GetMemo() != "published" || found.GetVersion() != 2 {
+		t.Fatalf("unexpected product %s %q at version %d", found.GetStatus(), found.GetMemo(), found.GetVersion())
+	}
+
+	untouched, err := store.ProductFindByID(ctx, active.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if untouched.GetMemo() != "" || untouched.GetVersion() != 1 {
+		t.Fatal("expected the product not matching the query to be left alone")
+	}
+
+	// loaded before the bulk update, the copy is stale now
+	drafts[1].SetTitle("Stale")
+	if err := store.ProductUpdate(ctx, drafts[1]); !errors.Is(err, ErrConcurrentModification) {
+		t.Fatalf("expected ErrConcurrentModification, got %v", err)
+	}
+
+	for _, fields := range []map[string]string{
+		{},
+		{COLUMN_ID: "NEW_ID"},
+		{COLUMN_VERSION: "10"},
+		{"title = 'x', memo": "injected"},
+	} {
+		_, err := store.ProductUpdateMany(ctx, NewProductQuery(), fields)
+		if !errors.Is(err, ErrInvalidBulkUpdate) {
+			t.Fatalf("expected ErrInvalidBulkUpdate for %v, got %v", fields, err)
+		}
+	}
+}
+
+func TestStoreProductSoftDeleteMany(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	parent := NewProduct().SetStatus(PRODUCT_STATUS_ACTIVE).SetTitle("Shirt")
+	variant := NewProduct().SetStatus(PRODUCT_STATUS_ACTIVE).SetTitle("Shirt S").SetParentID(parent.GetID())
+	other := NewProduct().SetStatus(PRODUCT_STATUS_DISABLED).SetTitle("Other")
+
+	if err := store.ProductCreateMany(ctx, []ProductInterface{parent, variant, other}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	softDeleted := []string{}
+	store.ProductHooks().AfterSoftDelete(func(ctx context.Context, id string) {
+		softDeleted = append(softDeleted, id)
+	})
+
+	_, err = store.ProductSoftDeleteMany(ctx, NewProductQuery().SetStatus(PRODUCT_STATUS_ACTIVE).SetID(parent.GetID()))
+	if !errors.Is(err, ErrProductHasActiveVariants) {
+		t.Fatalf("expected ErrProductHasActiveVariants, got %v", err)
+	}
+
+	deleted, err := store.ProductSoftDeleteMany(ctx, NewProductQuery().SetStatus(PRODUCT_STATUS_ACTIVE))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if deleted != 2 || len(softDeleted) != 2 {
+		t.Fatalf("expected the parent and its variant soft deleted, got %d (%d hooks)", deleted, len(softDeleted))
+	}
+
+	count, err := store.ProductCount(ctx, NewProductQuery())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if count != 1 {
+		t.Fatalf("expected 1 live product, got %d", count)
+	}
+
+	// soft deleted products are not soft deleted again
+	deleted, err = store.ProductSoftDeleteMany(ctx, NewProductQuery().SetSoftDeletedIncluded(true))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if deleted != 1 {
+		t.Fatalf("expected only the live product soft deleted, got %d", deleted)
+	}
+}
+
+func TestStoreOrderUpdateMany_RecordsChanges(t *testing.T) {
+	db, err := initDB(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	options := testStoreOptions(db)
+	options.AuditLogEnabled = true
+	options.OutboxEnabled = true
+
+	store, err := NewStore(options)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := WithActorID(context.Background(), "ADMIN01")
+
+	for range 3 {
+		order := NewOrder().SetCustomerID("CUSTOMER01_ID").SetStatus(ORDER_STATUS_PENDING)
+		if err := store.OrderCreate(ctx, order); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	updated, err := store.OrderUpdateMany(ctx, NewOrderQuery().SetStatus(ORDER_STATUS_PENDING), map[string]string{
+		COLUMN_STATUS: ORDER_STATUS_CANCELLED,
+	})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if updated != 3 {
+		t.Fatalf("expected 3 orders updated, got %d", updated)
+	}
+
+	entries, err := store.AuditLogList(ctx, NewAuditLogQuery().
+		SetEntityType(ENTITY_TYPE_ORDER).
+		SetOperation(AUDIT_OPERATION_UPDATE).
+		SetActorID("ADMIN01"))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(entries) != 3 {
+		t.Fatalf("expected an audit entry per order, got %d", len(entries))
+	}
+
+	before, err := entries[0].GetValuesBeforeMap()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if before[COLUMN_STATUS] != ORDER_STATUS_PENDING {
+		t.Fatalf("expected the previous status recorded, got %v", before)
+	}
+
+	events, err := store.OutboxFetchPending(ctx, 10)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	changed := 0
+	for _, event := range events {
+		if event.GetType() == OUTBOX_EVENT_ORDER_STATUS_CHANGED {
+			changed++
+		}
+	}
+
+	if changed != 3 {
+		t.Fatalf("expected a status changed event per order, got %d", changed)
+	}
+
+	deleted, err := store.OrderSoftDeleteMany(ctx, NewOrderQuery())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	count, err := store.AuditLogCount(ctx, NewAuditLogQuery().SetOperation(AUDIT_OPERATION_SOFT_DELETE))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if deleted != 3 || count != 3 {
+		t.Fatalf("expected 3 orders soft deleted and audited, got %d and %d", deleted, count)
+	}
+}
@@ -0,0 +1,333 @@
+package shopstore
+
+import (
+	"encoding/json"
+
+	"github.com/dracory/dataobject"
+	"github.com/dromara/carbon/v2"
+	"github.com/spf13/cast"
+)
+
+// == CONSTANTS ==============================================================
+
+// DEFAULT_CART_TABLE_NAME is the table the carts are stored in when
+// NewStoreOptions.CartTableName is not set
+const DEFAULT_CART_TABLE_NAME = "shop_cart"
+
+const CART_STATUS_ACTIVE = "active"
+const CART_STATUS_CHECKED_OUT = "checked_out"
+const CART_STATUS_EXPIRED = "expired"
+const CART_STATUS_MERGED = "merged"
+
+// == CLASS ==================================================================
+
+// Cart represents the shopping cart of a customer, or of a guest while its
+// customer ID is empty. The items of an active cart can be changed, and
+// CartCheckout converts it into an order.
+type Cart struct {
+	dataobject.DataObject
+}
+
+// == INTERFACES =============================================================
+
+// Compile-time interface compliance check
+var _ CartInterface = (*Cart)(nil)
+
+// == CONSTRUCTORS ===========================================================
+
+// NewCart creates a new cart with default values:
+// - Status: active
+// - CustomerID: empty (guest cart)
+// - DiscountID: empty
+// - OrderID: empty
+// - Memo: empty
+// - CreatedAt: current UTC time
+// - UpdatedAt: current UTC time
+// - SoftDeletedAt: max datetime (not deleted)
+// - Metas: empty map
+func NewCart() CartInterface {
+	o := (&Cart{}).
+		SetID(GenerateShortID()).
+		SetStatus(CART_STATUS_ACTIVE).
+		SetCustomerID("").
+		SetDiscountID("").
+		SetOrderID("").
+		SetMemo("").
+		SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
+		SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
+		SetSoftDeletedAt(MAX_DATETIME).
+		SetVersion(1)
+
+	_ = o.SetMetas(map[string]string{})
+
+	return o
+}
+
+// NewCartFromExistingData creates a cart from existing data map.
+// Used when hydrating from database or external sources.
+func NewCartFromExistingData(data map[string]string) CartInterface {
+	o := &Cart{}
+	o.Hydrate(data)
+	return o
+}
+
+// == METHODS ================================================================
+
+// IsActive returns true if the cart status is active.
+func (cart *Cart) IsActive() bool {
+	return cart.GetStatus() == CART_STATUS_ACTIVE
+}
+
+// IsCheckedOut returns true if the cart was converted into an order.
+func (cart *Cart) IsCheckedOut() bool {
+	return cart.GetStatus() == CART_STATUS_CHECKED_OUT
+}
+
+// IsExpired returns true if the cart was abandoned and expired.
+func (cart *Cart) IsExpired() bool {
+	return cart.GetStatus() == CART_STATUS_EXPIRED
+}
+
+// IsGuest returns true if the cart does not belong to a customer.
+func (cart *Cart) IsGuest() bool {
+	return cart.GetCustomerID() == ""
+}
+
+// IsMerged returns true if the cart was merged into the cart of a customer.
+func (cart *Cart) IsMerged() bool {
+	return cart.GetStatus() == CART_STATUS_MERGED
+}
+
+// IsSoftDeleted returns true if the cart is soft deleted.
+func (cart *Cart) IsSoftDeleted() bool {
+	return cart.GetSoftDeletedAt() != MAX_DATETIME
+}
+
+// == SETTERS AND GETTERS ====================================================
+
+// GetCreatedAt returns the creation timestamp as a string.
+func (cart *Cart) GetCreatedAt() string {
+	return cart.Get(COLUMN_CREATED_AT)
+}
+
+// GetCreatedAtCarbon returns the creation timestamp as a Carbon instance.
+func (cart *Cart) GetCreatedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(cart.GetCreatedAt())
+}
+
+// SetCreatedAt sets the creation timestamp.
+func (cart *Cart) SetCreatedAt(createdAt string) CartInterface {
+	cart.Set(COLUMN_CREATED_AT, createdAt)
+	return cart
+}
+
+// GetCustomerID returns the ID of the customer, empty for a guest cart.
+func (cart *Cart) GetCustomerID() string {
+	return cart.Get(COLUMN_CUSTOMER_ID)
+}
+
+// SetCustomerID sets the ID of the customer, empty for a guest cart.
+func (cart *Cart) SetCustomerID(customerID string) CartInterface {
+	cart.Set(COLUMN_CUSTOMER_ID, customerID)
+	return cart
+}
+
+// GetDiscountID returns the ID of the discount applied to the cart.
+func (cart *Cart) GetDiscountID() string {
+	return cart.Get(COLUMN_DISCOUNT_ID)
+}
+
+// SetDiscountID sets the ID of the discount applied to the cart.
+func (cart *Cart) SetDiscountID(discountID string) CartInterface {
+	cart.Set(COLUMN_DISCOUNT_ID, discountID)
+	return cart
+}
+
+// GetID returns the unique identifier.
+func (cart *Cart) GetID() string {
+	return cart.Get(COLUMN_ID)
+}
+
+// SetID sets the unique identifier.
+func (cart *Cart) SetID(id string) CartInterface {
+	cart.Set(COLUMN_ID, id)
+	return cart
+}
+
+// GetMemo returns the internal memo.
+func (cart *Cart) GetMemo() string {
+	return cart.Get(COLUMN_MEMO)
+}
+
+// SetMemo sets the internal memo.
+func (cart *Cart) SetMemo(memo string) CartInterface {
+	cart.Set(COLUMN_MEMO, memo)
+	return cart
+}
+
+// GetMeta returns a specific metadata value by name. Returns empty string if not found.
+func (cart *Cart) GetMeta(name string) string {
+	metas, err := cart.GetMetas()
+
+	if err != nil {
+		return ""
+	}
+
+	if value, exists := metas[name]; exists {
+		return value
+	}
+
+	return ""
+}
+
+// MetaRemove removes a single metadata entry.
+func (cart *Cart) MetaRemove(name string) error {
+	metas, err := cart.GetMetas()
+
+	if err != nil {
+		return err
+	}
+
+	delete(metas, name)
+
+	return cart.SetMetas(metas)
+}
+
+// SetMeta sets a single metadata value.
+func (cart *Cart) SetMeta(name string, value string) error {
+	return cart.MetasUpsert(map[string]string{name: value})
+}
+
+// GetMetas returns all metadata as a map. Returns empty map if no metas stored.
+func (cart *Cart) GetMetas() (map[string]string, error) {
+	metasStr := cart.Get(COLUMN_METAS)
+
+	if metasStr == "" {
+		metasStr = "{}"
+	}
+
+	metasJson := map[string]string{}
+	errJson := json.Unmarshal([]byte(metasStr), &metasJson)
+	if errJson != nil {
+		return map[string]string{}, errJson
+	}
+
+	if metasJson == nil {
+		metasJson = map[string]string{}
+	}
+
+	return metasJson, nil
+}
+
+// MetasRemove removes multiple metadata entries.
+func (cart *Cart) MetasRemove(names []string) error {
+	for _, name := range names {
+		err := cart.MetaRemove(name)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MetasUpsert merges the provided metadata with existing values.
+func (cart *Cart) MetasUpsert(metas map[string]string) error {
+	currentMetas, err := cart.GetMetas()
+
+	if err != nil {
+		return err
+	}
+
+	for k, v := range metas {
+		currentMetas[k] = v
+	}
+
+	return cart.SetMetas(currentMetas)
+}
+
+// SetMetas replaces all metadata with the provided map.
+// Warning: this overwrites any existing metadata.
+func (cart *Cart) SetMetas(metas map[string]string) error {
+	mapString, err := json.Marshal(metas)
+
+	if err != nil {
+		return err
+	}
+
+	cart.Set(COLUMN_METAS, string(mapString))
+
+	return nil
+}
+
+// GetOrderID returns the ID of the order the cart was checked out as.
+func (cart *Cart) GetOrderID() string {
+	return cart.Get(COLUMN_ORDER_ID)
+}
+
+// SetOrderID sets the ID of the order the cart was checked out as.
+func (cart *Cart) SetOrderID(orderID string) CartInterface {
+	cart.Set(COLUMN_ORDER_ID, orderID)
+	return cart
+}
+
+// GetSoftDeletedAt returns the soft deletion timestamp.
+func (cart *Cart) GetSoftDeletedAt() string {
+	return cart.Get(COLUMN_SOFT_DELETED_AT)
+}
+
+// GetSoftDeletedAtCarbon returns the soft deletion timestamp as a Carbon instance.
+func (cart *Cart) GetSoftDeletedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(cart.GetSoftDeletedAt())
+}
+
+// SetSoftDeletedAt sets the soft deletion timestamp.
+func (cart *Cart) SetSoftDeletedAt(deletedAt string) CartInterface {
+	cart.Set(COLUMN_SOFT_DELETED_AT, deletedAt)
+	return cart
+}
+
+// GetStatus returns the current status.
+func (cart *Cart) GetStatus() string {
+	return cart.Get(COLUMN_STATUS)
+}
+
+// SetStatus sets the current status.
+func (cart *Cart) SetStatus(status string) CartInterface {
+	cart.Set(COLUMN_STATUS, status)
+	return cart
+}
+
+// GetUpdatedAt returns the last update timestamp. Changing the items of
+// the cart updates it too, so it tells when the cart was last used.
+func (cart *Cart) GetUpdatedAt() string {
+	return cart.Get(COLUMN_UPDATED_AT)
+}
+
+// GetUpdatedAtCarbon returns the last update timestamp as a Carbon instance.
+func (cart *Cart) GetUpdatedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(cart.GetUpdatedAt())
+}
+
+// SetUpdatedAt sets the last update timestamp.
+func (cart *Cart) SetUpdatedAt(updatedAt string) CartInterface {
+	cart.Set(COLUMN_UPDATED_AT, updatedAt)
+	return cart
+}
+
+// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+func (cart *Cart) GetVersion() int64 {
+	return cast.ToInt64(cart.Get(COLUMN_VERSION))
+}
+
+// SetVersion sets the version of the row the entity was loaded at.
+func (cart *Cart) SetVersion(version int64) CartInterface {
+	cart.Set(COLUMN_VERSION, cast.ToString(version))
+	return cart
+}
+
+// MarkAsNotDirty resets the dirty state, clearing all change tracking.
+func (cart *Cart) MarkAsNotDirty() {
+	cart.DataObject.MarkAsNotDirty()
+}
@@ -0,0 +1,311 @@
+package shopstore
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/dracory/dataobject"
+	"github.com/dromara/carbon/v2"
+	"github.com/spf13/cast"
+)
+
+// == CONSTANTS ==============================================================
+
+// DEFAULT_CART_ITEM_TABLE_NAME is the table the cart items are stored in
+// when NewStoreOptions.CartItemTableName is not set
+const DEFAULT_CART_ITEM_TABLE_NAME = "shop_cart_item"
+
+// == CLASS ==================================================================
+
+// CartItem represents a product, or a product variant, in a cart. The title
+// and unit price of the product are copied when the item is added, so the
+// cart keeps the price the customer was shown. Cart items are managed
+// through the Cart* store operations and deleted, not soft deleted.
+type CartItem struct {
+	dataobject.DataObject
+}
+
+// == INTERFACES =============================================================
+
+// Compile-time interface compliance check
+var _ CartItemInterface = (*CartItem)(nil)
+
+// == CONSTRUCTORS ===========================================================
+
+// NewCartItem creates a new cart item with default values:
+// - CartID, ProductID, Title: empty
+// - Quantity: 1
+// - Price: 0.00 (free)
+// - CreatedAt: current UTC time
+// - UpdatedAt: current UTC time
+// - Metas: empty map
+func NewCartItem() CartItemInterface {
+	o := (&CartItem{}).
+		SetID(GenerateShortID()).
+		SetCartID("").
+		SetProductID("").
+		SetTitle("").
+		SetQuantityInt(1).
+		SetPriceFloat(0).
+		SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
+		SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
+		SetVersion(1)
+
+	_ = o.SetMetas(map[string]string{})
+
+	return o
+}
+
+// NewCartItemFromExistingData creates a cart item from existing data map.
+// Used when hydrating from database or external sources.
+func NewCartItemFromExistingData(data map[string]string) CartItemInterface {
+	o := &CartItem{}
+	o.Hydrate(data)
+	return o
+}
+
+// == METHODS ================================================================
+
+// GetSubtotalFloat returns the unit price times the quantity, rounded to cents.
+func (item *CartItem) GetSubtotalFloat() float64 {
+	return roundPrice(item.GetPriceFloat() * float64(item.GetQuantityInt()))
+}
+
+// == SETTERS AND GETTERS ====================================================
+
+// GetCartID returns the ID of the cart the item belongs to.
+func (item *CartItem) GetCartID() string {
+	return item.Get(COLUMN_CART_ID)
+}
+
+// SetCartID sets the ID of the cart the item belongs to.
+func (item *CartItem) SetCartID(cartID string) CartItemInterface {
+	item.Set(COLUMN_CART_ID, cartID)
+	return item
+}
+
+// GetCreatedAt returns the creation timestamp as a string.
+func (item *CartItem) GetCreatedAt() string {
+	return item.Get(COLUMN_CREATED_AT)
+}
+
+// GetCreatedAtCarbon returns the creation timestamp as a Carbon instance.
+func (item *CartItem) GetCreatedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(item.GetCreatedAt())
+}
+
+// SetCreatedAt sets the creation timestamp.
+func (item *CartItem) SetCreatedAt(createdAt string) CartItemInterface {
+	item.Set(COLUMN_CREATED_AT, createdAt)
+	return item
+}
+
+// GetID returns the unique identifier.
+func (item *CartItem) GetID() string {
+	return item.Get(COLUMN_ID)
+}
+
+// SetID sets the unique identifier.
+func (item *CartItem) SetID(id string) CartItemInterface {
+	item.Set(COLUMN_ID, id)
+	return item
+}
+
+// GetMeta returns a specific metadata value by name. Returns empty string if not found.
+func (item *CartItem) GetMeta(name string) string {
+	metas, err := item.GetMetas()
+
+	if err != nil {
+		return ""
+	}
+
+	if value, exists := metas[name]; exists {
+		return value
+	}
+
+	return ""
+}
+
+// MetaRemove removes a single metadata entry.
+func (item *CartItem) MetaRemove(name string) error {
+	metas, err := item.GetMetas()
+
+	if err != nil {
+		return err
+	}
+
+	delete(metas, name)
+
+	return item.SetMetas(metas)
+}
+
+// SetMeta sets a single metadata value.
+func (item *CartItem) SetMeta(name string, value string) error {
+	return item.MetasUpsert(map[string]string{name: value})
+}
+
+// GetMetas returns all metadata as a map. Returns empty map if no metas stored.
+func (item *CartItem) GetMetas() (map[string]string, error) {
+	metasStr := item.Get(COLUMN_METAS)
+
+	if metasStr == "" {
+		metasStr = "{}"
+	}
+
+	metasJson := map[string]string{}
+	errJson := json.Unmarshal([]byte(metasStr), &metasJson)
+	if errJson != nil {
+		return map[string]string{}, errJson
+	}
+
+	if metasJson == nil {
+		metasJson = map[string]string{}
+	}
+
+	return metasJson, nil
+}
+
+// MetasRemove removes multiple metadata entries.
+func (item *CartItem) MetasRemove(names []string) error {
+	for _, name := range names {
+		err := item.MetaRemove(name)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MetasUpsert merges the provided metadata with existing values.
+func (item *CartItem) MetasUpsert(metas map[string]string) error {
+	currentMetas, err := item.GetMetas()
+
+	if err != nil {
+		return err
+	}
+
+	for k, v := range metas {
+		currentMetas[k] = v
+	}
+
+	return item.SetMetas(currentMetas)
+}
+
+// SetMetas replaces all metadata with the provided map.
+// Warning: this overwrites any existing metadata.
+func (item *CartItem) SetMetas(metas map[string]string) error {
+	mapString, err := json.Marshal(metas)
+
+	if err != nil {
+		return err
+	}
+
+	item.Set(COLUMN_METAS, string(mapString))
+
+	return nil
+}
+
+// GetPrice returns the unit price snapshot as a string.
+func (item *CartItem) GetPrice() string {
+	return item.Get(COLUMN_PRICE)
+}
+
+// SetPrice sets the unit price snapshot from a string.
+func (item *CartItem) SetPrice(price string) CartItemInterface {
+	item.Set(COLUMN_PRICE, price)
+	return item
+}
+
+// GetPriceFloat returns the unit price snapshot as a float64.
+func (item *CartItem) GetPriceFloat() float64 {
+	return cast.ToFloat64(item.GetPrice())
+}
+
+// SetPriceFloat sets the unit price snapshot from a float64.
+func (item *CartItem) SetPriceFloat(price float64) CartItemInterface {
+	item.SetPrice(cast.ToString(price))
+	return item
+}
+
+// GetProductID returns the ID of the product, or of the product variant.
+func (item *CartItem) GetProductID() string {
+	return item.Get(COLUMN_PRODUCT_ID)
+}
+
+// SetProductID sets the ID of the product, or of the product variant.
+func (item *CartItem) SetProductID(productID string) CartItemInterface {
+	item.Set(COLUMN_PRODUCT_ID, productID)
+	return item
+}
+
+// GetQuantity returns the quantity as a string.
+func (item *CartItem) GetQuantity() string {
+	return item.Get(COLUMN_QUANTITY)
+}
+
+// SetQuantity sets the quantity from a string.
+func (item *CartItem) SetQuantity(quantity string) CartItemInterface {
+	item.Set(COLUMN_QUANTITY, quantity)
+	return item
+}
+
+// GetQuantityInt returns the quantity as an int64.
+func (item *CartItem) GetQuantityInt() int64 {
+	return cast.ToInt64(item.GetQuantity())
+}
+
+// SetQuantityInt sets the quantity from an int64.
+func (item *CartItem) SetQuantityInt(quantity int64) CartItemInterface {
+	item.SetQuantity(cast.ToString(quantity))
+	return item
+}
+
+// GetTitle returns the product title snapshot.
+func (item *CartItem) GetTitle() string {
+	return item.Get(COLUMN_TITLE)
+}
+
+// SetTitle sets the product title snapshot.
+func (item *CartItem) SetTitle(title string) CartItemInterface {
+	item.Set(COLUMN_TITLE, title)
+	return item
+}
+
+// GetUpdatedAt returns the last update timestamp.
+func (item *CartItem) GetUpdatedAt() string {
+	return item.Get(COLUMN_UPDATED_AT)
+}
+
+// GetUpdatedAtCarbon returns the last update timestamp as a Carbon instance.
+func (item *CartItem) GetUpdatedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(item.GetUpdatedAt())
+}
+
+// SetUpdatedAt sets the last update timestamp.
+func (item *CartItem) SetUpdatedAt(updatedAt string) CartItemInterface {
+	item.Set(COLUMN_UPDATED_AT, updatedAt)
+	return item
+}
+
+// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+func (item *CartItem) GetVersion() int64 {
+	return cast.ToInt64(item.Get(COLUMN_VERSION))
+}
+
+// SetVersion sets the version of the row the entity was loaded at.
+func (item *CartItem) SetVersion(version int64) CartItemInterface {
+	item.Set(COLUMN_VERSION, cast.ToString(version))
+	return item
+}
+
+// MarkAsNotDirty resets the dirty state, clearing all change tracking.
+func (item *CartItem) MarkAsNotDirty() {
+	item.DataObject.MarkAsNotDirty()
+}
+
+// roundPrice rounds an amount of money to cents
+func roundPrice(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
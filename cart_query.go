@@ -0,0 +1,471 @@
+package shopstore
+
+import "errors"
+
+type CartQueryInterface interface {
+	Validate() error
+
+	Columns() []string
+	SetColumns(columns []string) CartQueryInterface
+
+	HasCountOnly() bool
+	IsCountOnly() bool
+	SetCountOnly(countOnly bool) CartQueryInterface
+
+	HasCreatedAtGte() bool
+	CreatedAtGte() string
+	SetCreatedAtGte(createdAtGte string) CartQueryInterface
+
+	HasCreatedAtLte() bool
+	CreatedAtLte() string
+	SetCreatedAtLte(createdAtLte string) CartQueryInterface
+
+	HasCustomerID() bool
+	CustomerID() string
+	SetCustomerID(customerID string) CartQueryInterface
+
+	HasID() bool
+	ID() string
+	SetID(id string) CartQueryInterface
+
+	HasIDIn() bool
+	IDIn() []string
+	SetIDIn(idIn []string) CartQueryInterface
+
+	HasLimit() bool
+	Limit() int
+	SetLimit(limit int) CartQueryInterface
+
+	HasOffset() bool
+	Offset() int
+	SetOffset(offset int) CartQueryInterface
+
+	HasOrderBy() bool
+	OrderBy() string
+	SetOrderBy(orderBy string) CartQueryInterface
+
+	HasSortDirection() bool
+	SortDirection() string
+	SetSortDirection(sortDirection string) CartQueryInterface
+
+	// AddSort adds a sort key. Keys are applied in the order they are added,
+	// after the order_by column if one is set. Only the entity's sortable
+	// columns and the asc/desc directions are accepted by Validate.
+	HasSorts() bool
+	Sorts() []QuerySort
+	AddSort(column string, direction string) CartQueryInterface
+
+	// AfterCursor continues a keyset paginated list right after the row the
+	// cursor points at. Cursors are returned as NextCursor by the *ListPage methods.
+	HasAfterCursor() bool
+	AfterCursor() string
+	SetAfterCursor(cursor string) CartQueryInterface
+
+	HasSoftDeletedIncluded() bool
+	SoftDeletedIncluded() bool
+	SetSoftDeletedIncluded(softDeletedIncluded bool) CartQueryInterface
+
+	HasStatus() bool
+	Status() string
+	SetStatus(status string) CartQueryInterface
+
+	HasStatusIn() bool
+	StatusIn() []string
+	SetStatusIn(statusIn []string) CartQueryInterface
+
+	// UpdatedAtLte matches the carts last changed at or before the datetime,
+	// like abandoned carts.
+	HasUpdatedAtLte() bool
+	UpdatedAtLte() string
+	SetUpdatedAtLte(updatedAtLte string) CartQueryInterface
+
+	hasProperty(name string) bool
+}
+
+func NewCartQuery() CartQueryInterface {
+	return &cartQueryImplementation{
+		properties: make(map[string]any),
+	}
+}
+
+type cartQueryImplementation struct {
+	properties map[string]any
+}
+
+func (c *cartQueryImplementation) Validate() error {
+
+	if c.HasCreatedAtGte() && c.CreatedAtGte() == "" {
+		return errors.New("cart query. created_at_gte cannot be empty")
+	}
+
+	if c.HasCreatedAtLte() && c.CreatedAtLte() == "" {
+		return errors.New("cart query. created_at_lte cannot be empty")
+	}
+
+	if c.HasCustomerID() && c.CustomerID() == "" {
+		return errors.New("cart query. customer_id cannot be empty")
+	}
+
+	if c.HasID() && c.ID() == "" {
+		return errors.New("cart query. id cannot be empty")
+	}
+
+	if c.HasIDIn() && len(c.IDIn()) == 0 {
+		return errors.New("cart query. id_in cannot be empty")
+	}
+
+	if c.HasSortDirection() && c.SortDirection() == "" {
+		return errors.New("cart query. sort_direction cannot be empty")
+	}
+
+	if c.HasLimit() && c.Limit() <= 0 {
+		return errors.New("cart query. limit must be greater than 0")
+	}
+
+	if c.HasOffset() && c.Offset() < 0 {
+		return errors.New("cart query. offset must be greater than or equal to 0")
+	}
+
+	if c.HasOrderBy() && c.OrderBy() == "" {
+		return errors.New("cart query. order_by cannot be empty")
+	}
+
+	if c.HasStatus() && c.Status() == "" {
+		return errors.New("cart query. status cannot be empty")
+	}
+
+	if c.HasStatusIn() && len(c.StatusIn()) == 0 {
+		return errors.New("cart query. status_in cannot be empty")
+	}
+
+	if c.HasUpdatedAtLte() && c.UpdatedAtLte() == "" {
+		return errors.New("cart query. updated_at_lte cannot be empty")
+	}
+
+	if err := validateSort(c, cartSortableColumns); err != nil {
+		return errors.New("cart query. " + err.Error())
+	}
+
+	if c.HasAfterCursor() {
+		if c.AfterCursor() == "" {
+			return errors.New("cart query. after_cursor cannot be empty")
+		}
+
+		if c.HasOffset() {
+			return errors.New("cart query. after_cursor cannot be combined with offset")
+		}
+
+		if err := validateCursor(c.AfterCursor(), keysetSort(c)); err != nil {
+			return errors.New("cart query. after_cursor " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (c *cartQueryImplementation) Columns() []string {
+	if !c.hasProperty("columns") {
+		return []string{}
+	}
+
+	return c.properties["columns"].([]string)
+}
+
+func (c *cartQueryImplementation) SetColumns(columns []string) CartQueryInterface {
+	c.properties["columns"] = columns
+
+	return c
+}
+
+func (c *cartQueryImplementation) HasCountOnly() bool {
+	return c.hasProperty("count_only")
+}
+
+func (c *cartQueryImplementation) IsCountOnly() bool {
+	if !c.HasCountOnly() {
+		return false
+	}
+
+	return c.properties["count_only"].(bool)
+}
+
+func (c *cartQueryImplementation) SetCountOnly(countOnly bool) CartQueryInterface {
+	c.properties["count_only"] = countOnly
+
+	return c
+}
+
+func (c *cartQueryImplementation) HasCustomerID() bool {
+	return c.hasProperty("customer_id")
+}
+
+func (c *cartQueryImplementation) CustomerID() string {
+	if !c.HasCustomerID() {
+		return ""
+	}
+
+	return c.properties["customer_id"].(string)
+}
+
+func (c *cartQueryImplementation) SetCustomerID(customerID string) CartQueryInterface {
+	c.properties["customer_id"] = customerID
+
+	return c
+}
+
+func (c *cartQueryImplementation) HasCreatedAtGte() bool {
+	return c.hasProperty("created_at_gte")
+}
+
+func (c *cartQueryImplementation) CreatedAtGte() string {
+	if !c.HasCreatedAtGte() {
+		return ""
+	}
+
+	return c.properties["created_at_gte"].(string)
+}
+
+func (c *cartQueryImplementation) SetCreatedAtGte(createdAtGte string) CartQueryInterface {
+	c.properties["created_at_gte"] = createdAtGte
+
+	return c
+}
+
+func (c *cartQueryImplementation) HasCreatedAtLte() bool {
+	return c.hasProperty("created_at_lte")
+}
+
+func (c *cartQueryImplementation) CreatedAtLte() string {
+	if !c.HasCreatedAtLte() {
+		return ""
+	}
+
+	return c.properties["created_at_lte"].(string)
+}
+
+func (c *cartQueryImplementation) SetCreatedAtLte(createdAtLte string) CartQueryInterface {
+	c.properties["created_at_lte"] = createdAtLte
+
+	return c
+}
+
+func (c *cartQueryImplementation) HasID() bool {
+	return c.hasProperty("id")
+}
+
+func (c *cartQueryImplementation) ID() string {
+	if !c.HasID() {
+		return ""
+	}
+
+	return c.properties["id"].(string)
+}
+
+func (c *cartQueryImplementation) SetID(id string) CartQueryInterface {
+	c.properties["id"] = id
+
+	return c
+}
+
+func (c *cartQueryImplementation) HasIDIn() bool {
+	return c.hasProperty("id_in")
+}
+
+func (c *cartQueryImplementation) IDIn() []string {
+	if !c.HasIDIn() {
+		return []string{}
+	}
+
+	return c.properties["id_in"].([]string)
+}
+
+func (c *cartQueryImplementation) SetIDIn(idIn []string) CartQueryInterface {
+	c.properties["id_in"] = idIn
+
+	return c
+}
+
+func (c *cartQueryImplementation) HasLimit() bool {
+	return c.hasProperty("limit")
+}
+
+func (c *cartQueryImplementation) Limit() int {
+	if !c.HasLimit() {
+		return 0
+	}
+
+	return c.properties["limit"].(int)
+}
+
+func (c *cartQueryImplementation) SetLimit(limit int) CartQueryInterface {
+	c.properties["limit"] = limit
+
+	return c
+}
+
+func (c *cartQueryImplementation) HasOffset() bool {
+	return c.hasProperty("offset")
+}
+
+func (c *cartQueryImplementation) Offset() int {
+	if !c.HasOffset() {
+		return 0
+	}
+
+	return c.properties["offset"].(int)
+}
+
+func (c *cartQueryImplementation) SetOffset(offset int) CartQueryInterface {
+	c.properties["offset"] = offset
+
+	return c
+}
+
+func (c *cartQueryImplementation) HasOrderBy() bool {
+	return c.hasProperty("order_by")
+}
+
+func (c *cartQueryImplementation) OrderBy() string {
+	if !c.HasOrderBy() {
+		return ""
+	}
+
+	return c.properties["order_by"].(string)
+}
+
+func (c *cartQueryImplementation) SetOrderBy(orderBy string) CartQueryInterface {
+	c.properties["order_by"] = orderBy
+
+	return c
+}
+
+func (c *cartQueryImplementation) HasSortDirection() bool {
+	return c.hasProperty("sort_direction")
+}
+
+func (c *cartQueryImplementation) SortDirection() string {
+	if !c.HasSortDirection() {
+		return ""
+	}
+
+	return c.properties["sort_direction"].(string)
+}
+
+func (c *cartQueryImplementation) SetSortDirection(sortDirection string) CartQueryInterface {
+	c.properties["sort_direction"] = sortDirection
+
+	return c
+}
+
+func (c *cartQueryImplementation) HasSorts() bool {
+	return c.hasProperty("sorts")
+}
+
+func (c *cartQueryImplementation) Sorts() []QuerySort {
+	if !c.HasSorts() {
+		return []QuerySort{}
+	}
+
+	return c.properties["sorts"].([]QuerySort)
+}
+
+func (c *cartQueryImplementation) AddSort(column string, direction string) CartQueryInterface {
+	c.properties["sorts"] = appendSort(c.Sorts(), column, direction)
+
+	return c
+}
+
+func (c *cartQueryImplementation) HasSoftDeletedIncluded() bool {
+	return c.hasProperty("soft_deleted_included")
+}
+
+func (c *cartQueryImplementation) SoftDeletedIncluded() bool {
+	if !c.HasSoftDeletedIncluded() {
+		return false
+	}
+
+	return c.properties["soft_deleted_included"].(bool)
+}
+
+func (c *cartQueryImplementation) SetSoftDeletedIncluded(softDeletedIncluded bool) CartQueryInterface {
+	c.properties["soft_deleted_included"] = softDeletedIncluded
+
+	return c
+}
+
+func (c *cartQueryImplementation) HasStatus() bool {
+	return c.hasProperty("status")
+}
+
+func (c *cartQueryImplementation) Status() string {
+	if !c.HasStatus() {
+		return ""
+	}
+
+	return c.properties["status"].(string)
+}
+
+func (c *cartQueryImplementation) SetStatus(status string) CartQueryInterface {
+	c.properties["status"] = status
+
+	return c
+}
+
+func (c *cartQueryImplementation) HasStatusIn() bool {
+	return c.hasProperty("status_in")
+}
+
+func (c *cartQueryImplementation) StatusIn() []string {
+	if !c.HasStatusIn() {
+		return []string{}
+	}
+
+	return c.properties["status_in"].([]string)
+}
+
+func (c *cartQueryImplementation) SetStatusIn(statusIn []string) CartQueryInterface {
+	c.properties["status_in"] = statusIn
+
+	return c
+}
+
+func (c *cartQueryImplementation) HasUpdatedAtLte() bool {
+	return c.hasProperty("updated_at_lte")
+}
+
+func (c *cartQueryImplementation) UpdatedAtLte() string {
+	if !c.HasUpdatedAtLte() {
+		return ""
+	}
+
+	return c.properties["updated_at_lte"].(string)
+}
+
+func (c *cartQueryImplementation) SetUpdatedAtLte(updatedAtLte string) CartQueryInterface {
+	c.properties["updated_at_lte"] = updatedAtLte
+
+	return c
+}
+
+func (c *cartQueryImplementation) HasAfterCursor() bool {
+	return c.hasProperty("after_cursor")
+}
+
+func (c *cartQueryImplementation) AfterCursor() string {
+	if !c.HasAfterCursor() {
+		return ""
+	}
+
+	return c.properties["after_cursor"].(string)
+}
+
+func (c *cartQueryImplementation) SetAfterCursor(cursor string) CartQueryInterface {
+	c.properties["after_cursor"] = cursor
+
+	return c
+}
+
+func (c *cartQueryImplementation) hasProperty(name string) bool {
+	_, ok := c.properties[name]
+	return ok
+}
@@ -0,0 +1,53 @@
+package shopstore
+
+import "testing"
+
+func TestNewCartDefaults(t *testing.T) {
+	cart := NewCart()
+	if cart == nil {
+		t.Fatal("NewCart returned nil")
+	}
+
+	if cart.GetStatus() != CART_STATUS_ACTIVE || !cart.IsActive() {
+		t.Fatalf("expected status %q, got %q", CART_STATUS_ACTIVE, cart.GetStatus())
+	}
+
+	if !cart.IsGuest() || cart.GetDiscountID() != "" || cart.GetOrderID() != "" {
+		t.Fatalf("expected a guest cart without discount and order, got %v", cart.Data())
+	}
+
+	if cart.GetSoftDeletedAt() != MAX_DATETIME || cart.GetVersion() != 1 {
+		t.Fatalf("unexpected soft deleted at %q or version %d", cart.GetSoftDeletedAt(), cart.GetVersion())
+	}
+}
+
+func TestCartStatusPredicates(t *testing.T) {
+	cart := NewCart().SetCustomerID("CUSTOMER01_ID")
+
+	if cart.IsGuest() {
+		t.Fatal("expected a cart with a customer not to be a guest cart")
+	}
+
+	cart.SetStatus(CART_STATUS_CHECKED_OUT)
+	if cart.IsActive() || !cart.IsCheckedOut() {
+		t.Fatal("expected the cart to be checked out")
+	}
+
+	cart.SetStatus(CART_STATUS_EXPIRED)
+	if !cart.IsExpired() {
+		t.Fatal("expected the cart to be expired")
+	}
+
+	cart.SetStatus(CART_STATUS_MERGED)
+	if !cart.IsMerged() {
+		t.Fatal("expected the cart to be merged")
+	}
+}
+
+func TestCartItemSubtotal(t *testing.T) {
+	item := NewCartItem().SetPriceFloat(19.99).SetQuantityInt(3)
+
+	if item.GetSubtotalFloat() != 59.97 {
+		t.Fatalf("expected subtotal 59.97, got %v", item.GetSubtotalFloat())
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/dracory/dataobject"
 	"github.com/dromara/carbon/v2"
+	"github.com/spf13/cast"
 )
 
 // == CLASS ====================================================================
@@ -41,7 +42,8 @@ func NewCategory() CategoryInterface {
 		SetMemo("").        // By default empty
 		SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
 		SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
-		SetSoftDeletedAt(MAX_DATETIME)
+		SetSoftDeletedAt(MAX_DATETIME).
+		SetVersion(1)
 
 	_ = o.SetMetas(map[string]string{})
 
@@ -291,6 +293,17 @@ func (category *Category) SetUpdatedAt(updatedAt string) CategoryInterface {
 	return category
 }
 
+// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+func (category *Category) GetVersion() int64 {
+	return cast.ToInt64(category.Get(COLUMN_VERSION))
+}
+
+// SetVersion sets the version of the row the entity was loaded at.
+func (category *Category) SetVersion(version int64) CategoryInterface {
+	category.Set(COLUMN_VERSION, cast.ToString(version))
+	return category
+}
+
 // MarkAsNotDirty resets the dirty state, clearing all change tracking.
 func (category *Category) MarkAsNotDirty() {
 	category.DataObject.MarkAsNotDirty()
@@ -36,6 +36,19 @@ type CategoryQueryInterface interface {
 	SortDirection() string
 	SetSortDirection(sortDirection string) CategoryQueryInterface
 
+	// AddSort adds a sort key. Keys are applied in the order they are added,
+	// after the order_by column if one is set. Only the entity's sortable
+	// columns and the asc/desc directions are accepted by Validate.
+	HasSorts() bool
+	Sorts() []QuerySort
+	AddSort(column string, direction string) CategoryQueryInterface
+
+	// AfterCursor continues a keyset paginated list right after the row the
+	// cursor points at. Cursors are returned as NextCursor by the *ListPage methods.
+	HasAfterCursor() bool
+	AfterCursor() string
+	SetAfterCursor(cursor string) CategoryQueryInterface
+
 	HasParentID() bool
 	ParentID() string
 	SetParentID(parentID string) CategoryQueryInterface
@@ -102,6 +115,24 @@ func (c *categoryQueryImplementation) Validate() error {
 		return errors.New("category query. offset must be greater than or equal to 0")
 	}
 
+	if err := validateSort(c, categorySortableColumns); err != nil {
+		return errors.New("category query. " + err.Error())
+	}
+
+	if c.HasAfterCursor() {
+		if c.AfterCursor() == "" {
+			return errors.New("category query. after_cursor cannot be empty")
+		}
+
+		if c.HasOffset() {
+			return errors.New("category query. after_cursor cannot be combined with offset")
+		}
+
+		if err := validateCursor(c.AfterCursor(), keysetSort(c)); err != nil {
+			return errors.New("category query. after_cursor " + err.Error())
+		}
+	}
+
 	return nil
 }
 
@@ -245,6 +276,24 @@ func (c *categoryQueryImplementation) SetSortDirection(sortDirection string) Cat
 	return c
 }
 
+func (c *categoryQueryImplementation) HasSorts() bool {
+	return c.hasProperty("sorts")
+}
+
+func (c *categoryQueryImplementation) Sorts() []QuerySort {
+	if !c.HasSorts() {
+		return []QuerySort{}
+	}
+
+	return c.properties["sorts"].([]QuerySort)
+}
+
+func (c *categoryQueryImplementation) AddSort(column string, direction string) CategoryQueryInterface {
+	c.properties["sorts"] = appendSort(c.Sorts(), column, direction)
+
+	return c
+}
+
 func (c *categoryQueryImplementation) HasParentID() bool {
 	return c.hasProperty("parent_id")
 }
@@ -317,6 +366,24 @@ func (c *categoryQueryImplementation) SetTitleLike(titleLike string) CategoryQue
 	return c
 }
 
+func (c *categoryQueryImplementation) HasAfterCursor() bool {
+	return c.hasProperty("after_cursor")
+}
+
+func (c *categoryQueryImplementation) AfterCursor() string {
+	if !c.HasAfterCursor() {
+		return ""
+	}
+
+	return c.properties["after_cursor"].(string)
+}
+
+func (c *categoryQueryImplementation) SetAfterCursor(cursor string) CategoryQueryInterface {
+	c.properties["after_cursor"] = cursor
+
+	return c
+}
+
 func (c *categoryQueryImplementation) hasProperty(name string) bool {
 	_, ok := c.properties[name]
 	return ok
@@ -0,0 +1,61 @@
+package shopstore
+
+import (
+	"context"
+
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+	neatquery "github.com/dracory/neat/database/query"
+	"github.com/spf13/cast"
+)
+
+type blindWritesContextKey struct{}
+
+// WithBlindWrites returns a copy of ctx whose updates skip the version
+// check, overwriting any concurrent change (last write wins). Meant for
+// writes not based on a previously loaded state, like imports and fixes.
+func WithBlindWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, blindWritesContextKey{}, true)
+}
+
+// blindWritesAllowed reports whether ctx was returned by WithBlindWrites
+func blindWritesAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(blindWritesContextKey{}).(bool)
+	return allowed
+}
+
+// versionedUpdate writes row with the update query q, which selects the
+// row of an entity loaded at the given version, and increments the
+// version of the row. The write only applies while the row is still at
+// that version, failing with ErrConcurrentModification otherwise, unless
+// ctx allows blind writes or the version is unknown (below 1).
+//
+// Returns the version the entity is at after the write. The version a
+// blind write leaves the row at is read back with q, so within the same
+// transaction.
+func versionedUpdate(ctx context.Context, q contractsorm.Query, version int64, row map[string]any) (int64, error) {
+	if version < 1 || blindWritesAllowed(ctx) {
+		row[COLUMN_VERSION] = neatquery.RawExpr(COLUMN_VERSION + " + 1")
+		if _, err := q.Update(row); err != nil {
+			return version, err
+		}
+
+		var results []map[string]any
+		if err := q.Select([]string{COLUMN_VERSION}).Get(&results); err != nil || len(results) == 0 {
+			return version, err
+		}
+
+		return cast.ToInt64(mapAnyToString(results[0])[COLUMN_VERSION]), nil
+	}
+
+	row[COLUMN_VERSION] = version + 1
+	result, err := q.Where(COLUMN_VERSION+" = ?", version).Update(row)
+	if err != nil {
+		return version, err
+	}
+
+	if result.RowsAffected == 0 {
+		return version, ErrConcurrentModification
+	}
+
+	return version + 1, nil
+}
@@ -0,0 +1,253 @@
+package shopstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestStoreProductUpdate_ConcurrentModification(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	product := NewProduct().SetStatus(PRODUCT_STATUS_ACTIVE).SetTitle("Widget")
+	if err := store.ProductCreate(ctx, product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if product.GetVersion() != 1 {
+		t.Fatalf("expected a new product at version 1, got %d", product.GetVersion())
+	}
+
+	first, err := store.ProductFindByID(ctx, product.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	second, err := store.ProductFindByID(ctx, product.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	first.SetTitle("First")
+	if err := store.ProductUpdate(ctx, first); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if first.GetVersion() != 2 {
+		t.Fatalf("expected the updated product at version 2, got %d", first.GetVersion())
+	}
+
+	second.SetTitle("Second")
+	if err := store.ProductUpdate(ctx, second); !errors.Is(err, ErrConcurrentModification) {
+		t.Fatalf("expected ErrConcurrentModification, got %v", err)
+	}
+
+	// the first writer can carry on with the version it got back
+	first.SetStatus(PRODUCT_STATUS_DISABLED)
+	if err := store.ProductUpdate(ctx, first); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	found, err := store.ProductFindByID(ctx, product.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found.GetTitle() != "First" || found.GetVersion() != 3 {
+		t.Fatalf("expected title First at version 3, got %s at version %d", found.GetTitle(), found.GetVersion())
+	}
+}
+
+func TestStoreProductUpdate_BlindWrites(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	product := NewProduct().SetStatus(PRODUCT_STATUS_ACTIVE).SetTitle("Widget")
+	if err := store.ProductCreate(ctx, product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	stale, err := store.ProductFindByID(ctx, product.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	product.SetTitle("Fresh")
+	if err := store.ProductUpdate(ctx, product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	stale.SetTitle("Blind")
+	if err := store.ProductUpdate(WithBlindWrites(ctx), stale); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	found, err := store.ProductFindByID(ctx, product.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found.GetTitle() != "Blind" {
+		t.Fatalf("expected the blind write to win, got %s", found.GetTitle())
+	}
+
+	if found.GetVersion() != 3 {
+		t.Fatalf("expected blind writes to increment the version, got %d", found.GetVersion())
+	}
+
+	// the fresh copy was overwritten in the meantime
+	product.SetTitle("Too late")
+	if err := store.ProductUpdate(ctx, product); !errors.Is(err, ErrConcurrentModification) {
+		t.Fatalf("expected ErrConcurrentModification, got %v", err)
+	}
+}
+
+func TestStoreOrderUpdate_ConcurrentModificationRecordsNothing(t *testing.T) {
+	db, err := initDB(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	options := testStoreOptions(db)
+	options.OutboxEnabled = true
+	options.AuditLogEnabled = true
+
+	store, err := NewStore(options)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	order := NewOrder().SetCustomerID("CUSTOMER01_ID").SetStatus(ORDER_STATUS_PENDING)
+	if err := store.OrderCreate(ctx, order); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	stale, err := store.OrderFindByID(ctx, order.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	order.SetMemo("packed")
+	if err := store.OrderUpdate(ctx, order); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	stale.SetStatus(ORDER_STATUS_CANCELLED)
+	if err := store.OrderUpdate(ctx, stale); !errors.Is(err, ErrConcurrentModification) {
+		t.Fatalf("expected ErrConcurrentModification, got %v", err)
+	}
+
+	events, err := store.OutboxFetchPending(ctx, 10)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(events) != 1 || events[0].GetType() != OUTBOX_EVENT_ORDER_CREATED {
+		t.Fatalf("expected only the created event, got %d events", len(events))
+	}
+
+	count, err := store.AuditLogCount(ctx, NewAuditLogQuery().SetEntityID(order.GetID()))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if count != 2 {
+		t.Fatalf("expected the create and the first update to be audited, got %d entries", count)
+	}
+}
+
+func TestStoreProductUpdate_RetryAfterConflict(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	product := NewProduct().SetStatus(PRODUCT_STATUS_ACTIVE).SetTitle("Widget")
+	if err := store.ProductCreate(ctx, product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	stale, err := store.ProductFindByID(ctx, product.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	product.SetTitle("Other")
+	if err := store.ProductUpdate(ctx, product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	stale.SetTitle("Retried")
+	if err := store.ProductUpdate(ctx, stale); !errors.Is(err, ErrConcurrentModification) {
+		t.Fatalf("expected ErrConcurrentModification, got %v", err)
+	}
+
+	// the failed update keeps its changes, so a blind retry writes them
+	if err := store.ProductUpdate(WithBlindWrites(ctx), stale); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	found, err := store.ProductFindByID(ctx, product.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found.GetTitle() != "Retried" || found.GetVersion() != 3 {
+		t.Fatalf("expected title Retried at version 3, got %s at version %d", found.GetTitle(), found.GetVersion())
+	}
+
+	if len(stale.DataChanged()) != 0 {
+		t.Fatalf("expected the retried product clean, got changes %v", stale.DataChanged())
+	}
+
+	if stale.GetVersion() != 3 {
+		t.Fatalf("expected the retried product at the stored version 3, got %d", stale.GetVersion())
+	}
+}
+
+func TestStoreProductUpdate_CheckedAfterBlindWrite(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	product := NewProduct().SetStatus(PRODUCT_STATUS_ACTIVE).SetTitle("Widget")
+	if err := store.ProductCreate(ctx, product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	product.SetTitle("Blind")
+	if err := store.ProductUpdate(WithBlindWrites(ctx), product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// the blind write leaves the product at the version of its row
+	product.SetTitle("Checked")
+	if err := store.ProductUpdate(ctx, product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	found, err := store.ProductFindByID(ctx, product.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found.GetTitle() != "Checked" || found.GetVersion() != 3 || product.GetVersion() != 3 {
+		t.Fatalf("expected title Checked at version 3, got %s at version %d", found.GetTitle(), found.GetVersion())
+	}
+}
@@ -9,37 +9,110 @@ import (
 var (
 	ErrOrderHasActiveLineItems = errors.New("cannot delete order with active line items")
 	ErrOrderHasActiveMedia     = errors.New("cannot delete order with active media")
+	ErrOrderNotFound           = errors.New("order not found")
+	ErrOrderHasNoLineItems     = errors.New("order has no line items")
+	ErrOrderAddressMissing     = errors.New("order has no shipping or billing address")
 
 	ErrProductHasActiveVariants  = errors.New("cannot delete product with active variants")
 	ErrProductHasActiveLineItems = errors.New("cannot delete product referenced by active order line items")
 	ErrProductHasActiveMedia     = errors.New("cannot delete product with active media")
+	ErrProductNotFound           = errors.New("product not found")
+	ErrProductSKUExists          = errors.New("a product with this sku already exists")
+	ErrInsufficientQuantity      = errors.New("product quantity cannot go below zero")
+	ErrProductNotPurchasable     = errors.New("product is not active or has variants")
 
 	ErrCategoryHasActiveChildren = errors.New("cannot delete category with active children")
 	ErrCategoryHasActiveMedia    = errors.New("cannot delete category with active media")
+	ErrCategoryHasActiveProducts = errors.New("cannot delete category with active products")
+
+	ErrCartNotActive    = errors.New("cart is not active")
+	ErrCartNotFound     = errors.New("cart not found")
+	ErrCartItemNotFound = errors.New("cart item not found")
+	ErrCartEmpty        = errors.New("cart has no items")
+	ErrCartNotGuest     = errors.New("cart belongs to another customer")
+
+	ErrCustomerEmailExists        = errors.New("a customer with this email already exists")
+	ErrCustomerHasActiveAddresses = errors.New("cannot delete customer with active addresses")
+
+	ErrDiscountCodeExists    = errors.New("a discount with this code already exists")
+	ErrDiscountNotApplicable = errors.New("discount is not active or not within its validity period")
+
+	ErrShippingAddressMissing       = errors.New("no shipping address to quote shipping for")
+	ErrShippingMethodNotAvailable   = errors.New("shipping method is not available for the address or the items")
+	ErrShippingZoneHasActiveMethods = errors.New("cannot delete shipping zone with active methods")
+
+	ErrReferenceNotFound = errors.New("referenced entity does not exist or is soft deleted")
+
+	ErrConcurrentModification = errors.New("entity was modified concurrently, reload it and retry")
+
+	ErrInvalidBulkUpdate = errors.New("invalid bulk update")
+
+	ErrInvalidProductCSV = errors.New("invalid product csv")
+
+	ErrInvalidExport = errors.New("invalid store export")
 )
 
+const AUDIT_OPERATION_CREATE = "create"
+const AUDIT_OPERATION_DELETE = "delete"
+const AUDIT_OPERATION_SOFT_DELETE = "soft_delete"
+const AUDIT_OPERATION_UPDATE = "update"
+
 const CATEGORY_STATUS_ACTIVE = "active"
 const CATEGORY_STATUS_DRAFT = "draft"
 const CATEGORY_STATUS_INACTIVE = "inactive"
 
+const COLUMN_ACTOR_ID = "actor_id"
 const COLUMN_AMOUNT = "amount"
+const COLUMN_BILLING_ADDRESS = "billing_address"
+const COLUMN_CART_ID = "cart_id"
+const COLUMN_CATEGORY_ID = "category_id"
+const COLUMN_CITY = "city"
 const COLUMN_CODE = "code"
+const COLUMN_COUNTRY_CODE = "country_code"
+const COLUMN_COUNTRY_CODES = "country_codes"
 const COLUMN_CREATED_AT = "created_at"
 const COLUMN_CUSTOMER_ID = "customer_id"
 const COLUMN_DESCRIPTION = "description"
+const COLUMN_DISCOUNT_AMOUNT = "discount_amount"
+const COLUMN_DISCOUNT_ID = "discount_id"
+const COLUMN_DISPATCHED_AT = "dispatched_at"
+const COLUMN_EMAIL = "email"
 const COLUMN_ENDS_AT = "ends_at"
 const COLUMN_ENTITY_ID = "entity_id"
+const COLUMN_ENTITY_TYPE = "entity_type"
+const COLUMN_FREE_OVER_AMOUNT = "free_over_amount"
+const COLUMN_HEIGHT = "height"
 const COLUMN_ID = "id"
+const COLUMN_IS_DEFAULT_BILLING = "is_default_billing"
+const COLUMN_IS_DEFAULT_SHIPPING = "is_default_shipping"
+const COLUMN_LENGTH = "length"
+const COLUMN_LINE1 = "line1"
+const COLUMN_LINE2 = "line2"
 const COLUMN_MEDIA_TYPE = "media_type"
 const COLUMN_MEDIA_URL = "media_url"
 const COLUMN_MEMO = "memo"
 const COLUMN_METAS = "metas"
+const COLUMN_OPERATION = "operation"
 const COLUMN_ORDER_ID = "order_id"
 const COLUMN_PARENT_ID = "parent_id"
+const COLUMN_PAYLOAD = "payload"
+const COLUMN_PHONE = "phone"
+const COLUMN_POSTAL_CODE = "postal_code"
 const COLUMN_PRICE = "price"
+const COLUMN_PRICES_INCLUDE_TAX = "prices_include_tax"
 const COLUMN_PRODUCT_ID = "product_id"
 const COLUMN_QUANTITY = "quantity"
+const COLUMN_RATE = "rate"
+const COLUMN_RATE_RULES = "rate_rules"
+const COLUMN_RATE_TYPE = "rate_type"
+const COLUMN_REGION = "region"
+const COLUMN_REGIONS = "regions"
 const COLUMN_SEQUENCE = "sequence"
+const COLUMN_SHIPPING_ADDRESS = "shipping_address"
+const COLUMN_SHIPPING_AMOUNT = "shipping_amount"
+const COLUMN_SHIPPING_METHOD_ID = "shipping_method_id"
+const COLUMN_SHIPPING_METHOD_NAME = "shipping_method_name"
+const COLUMN_SHIPPING_ZONE_ID = "shipping_zone_id"
 const COLUMN_SOFT_DELETED_AT = "soft_deleted_at"
 
 // MAX_DATETIME is a far-future datetime used as the default soft-delete sentinel.
@@ -49,11 +122,33 @@ const MAX_DATETIME = neat.MaxDateTime
 const NULL_DATETIME = neat.NullDateTime
 
 const COLUMN_SHORT_DESCRIPTION = "short_description"
+const COLUMN_SKU = "sku"
 const COLUMN_STARTS_AT = "starts_at"
 const COLUMN_STATUS = "status"
+const COLUMN_TAX_AMOUNT = "tax_amount"
+const COLUMN_TAX_CLASS = "tax_class"
+const COLUMN_TAX_RATE = "tax_rate"
 const COLUMN_TYPE = "type"
 const COLUMN_TITLE = "title"
 const COLUMN_UPDATED_AT = "updated_at"
+const COLUMN_VALUES_AFTER = "values_after"
+const COLUMN_VALUES_BEFORE = "values_before"
+const COLUMN_WEIGHT = "weight"
+const COLUMN_WIDTH = "width"
+
+const ENTITY_TYPE_ADDRESS = "address"
+const ENTITY_TYPE_CART = "cart"
+const ENTITY_TYPE_CART_ITEM = "cart_item"
+const ENTITY_TYPE_CATEGORY = "category"
+const ENTITY_TYPE_CUSTOMER = "customer"
+const ENTITY_TYPE_DISCOUNT = "discount"
+const ENTITY_TYPE_MEDIA = "media"
+const ENTITY_TYPE_ORDER = "order"
+const ENTITY_TYPE_ORDER_LINE_ITEM = "order_line_item"
+const ENTITY_TYPE_PRODUCT = "product"
+const ENTITY_TYPE_SHIPPING_METHOD = "shipping_method"
+const ENTITY_TYPE_SHIPPING_ZONE = "shipping_zone"
+const ENTITY_TYPE_TAX_RATE = "tax_rate"
 
 const MEDIA_STATUS_DRAFT = "draft"
 const MEDIA_STATUS_ACTIVE = "active"
@@ -115,3 +210,10 @@ const PRODUCT_STATUS_DRAFT = "draft"
 const PRODUCT_STATUS_ACTIVE = "active"
 
 const PRODUCT_STATUS_DISABLED = "disabled"
+
+// TAX_CLASS_STANDARD is the tax class of new products. Any other class
+// name, like "reduced" or "zero", can be used with tax rates of its own.
+const TAX_CLASS_STANDARD = "standard"
+
+const SORT_DIRECTION_ASC = "asc"
+const SORT_DIRECTION_DESC = "desc"
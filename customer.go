@@ -0,0 +1,322 @@
+package shopstore
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/dracory/dataobject"
+	"github.com/dromara/carbon/v2"
+	"github.com/spf13/cast"
+)
+
+// == CONSTANTS ==============================================================
+
+// DEFAULT_CUSTOMER_TABLE_NAME is the table the customers are stored in
+// when NewStoreOptions.CustomerTableName is not set
+const DEFAULT_CUSTOMER_TABLE_NAME = "shop_customer"
+
+const CUSTOMER_STATUS_ACTIVE = "active"
+const CUSTOMER_STATUS_INACTIVE = "inactive"
+
+// == CLASS ==================================================================
+
+// Customer represents a customer of the shop store, referenced by the
+// customer_id of orders. Customers support soft deletion, metadata storage,
+// and status management.
+type Customer struct {
+	dataobject.DataObject
+}
+
+// == INTERFACES =============================================================
+
+// Compile-time interface compliance check
+var _ CustomerInterface = (*Customer)(nil)
+
+// == CONSTRUCTORS ===========================================================
+
+// NewCustomer creates a new customer with default values:
+// - Status: active
+// - Name: empty
+// - Email: empty
+// - Phone: empty
+// - Memo: empty
+// - CreatedAt: current UTC time
+// - UpdatedAt: current UTC time
+// - SoftDeletedAt: max datetime (not deleted)
+// - Metas: empty map
+func NewCustomer() CustomerInterface {
+	o := (&Customer{}).
+		SetID(GenerateShortID()).
+		SetStatus(CUSTOMER_STATUS_ACTIVE).
+		SetName("").
+		SetEmail("").
+		SetPhone("").
+		SetMemo("").
+		SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
+		SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
+		SetSoftDeletedAt(MAX_DATETIME).
+		SetVersion(1)
+
+	_ = o.SetMetas(map[string]string{})
+
+	return o
+}
+
+// NewCustomerFromExistingData creates a customer from existing data map.
+// Used when hydrating from database or external sources.
+func NewCustomerFromExistingData(data map[string]string) CustomerInterface {
+	o := &Customer{}
+	o.Hydrate(data)
+	return o
+}
+
+// normalizeEmail trims and lowercases an email address, so that lookups
+// by email do not depend on how the customer typed it
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// == METHODS ================================================================
+
+// IsActive returns true if the customer status is active.
+func (customer *Customer) IsActive() bool {
+	return customer.GetStatus() == CUSTOMER_STATUS_ACTIVE
+}
+
+// IsInactive returns true if the customer status is inactive.
+func (customer *Customer) IsInactive() bool {
+	return customer.GetStatus() == CUSTOMER_STATUS_INACTIVE
+}
+
+// IsSoftDeleted returns true if the customer is soft deleted.
+func (customer *Customer) IsSoftDeleted() bool {
+	return customer.GetSoftDeletedAt() != MAX_DATETIME
+}
+
+// == SETTERS AND GETTERS ====================================================
+
+// GetCreatedAt returns the creation timestamp as a string.
+func (customer *Customer) GetCreatedAt() string {
+	return customer.Get(COLUMN_CREATED_AT)
+}
+
+// GetCreatedAtCarbon returns the creation timestamp as a Carbon instance.
+func (customer *Customer) GetCreatedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(customer.GetCreatedAt())
+}
+
+// SetCreatedAt sets the creation timestamp.
+func (customer *Customer) SetCreatedAt(createdAt string) CustomerInterface {
+	customer.Set(COLUMN_CREATED_AT, createdAt)
+	return customer
+}
+
+// GetEmail returns the email address of the customer.
+func (customer *Customer) GetEmail() string {
+	return customer.Get(COLUMN_EMAIL)
+}
+
+// SetEmail sets the email address of the customer, trimmed and lowercased.
+func (customer *Customer) SetEmail(email string) CustomerInterface {
+	customer.Set(COLUMN_EMAIL, normalizeEmail(email))
+	return customer
+}
+
+// GetID returns the unique identifier.
+func (customer *Customer) GetID() string {
+	return customer.Get(COLUMN_ID)
+}
+
+// SetID sets the unique identifier.
+func (customer *Customer) SetID(id string) CustomerInterface {
+	customer.Set(COLUMN_ID, id)
+	return customer
+}
+
+// GetMemo returns the internal memo.
+func (customer *Customer) GetMemo() string {
+	return customer.Get(COLUMN_MEMO)
+}
+
+// SetMemo sets the internal memo.
+func (customer *Customer) SetMemo(memo string) CustomerInterface {
+	customer.Set(COLUMN_MEMO, memo)
+	return customer
+}
+
+// GetMeta returns a specific metadata value by name. Returns empty string if not found.
+func (customer *Customer) GetMeta(name string) string {
+	metas, err := customer.GetMetas()
+
+	if err != nil {
+		return ""
+	}
+
+	if value, exists := metas[name]; exists {
+		return value
+	}
+
+	return ""
+}
+
+// MetaRemove removes a single metadata entry.
+func (customer *Customer) MetaRemove(name string) error {
+	metas, err := customer.GetMetas()
+
+	if err != nil {
+		return err
+	}
+
+	delete(metas, name)
+
+	return customer.SetMetas(metas)
+}
+
+// SetMeta sets a single metadata value.
+func (customer *Customer) SetMeta(name string, value string) error {
+	return customer.MetasUpsert(map[string]string{name: value})
+}
+
+// GetMetas returns all metadata as a map. Returns empty map if no metas stored.
+func (customer *Customer) GetMetas() (map[string]string, error) {
+	metasStr := customer.Get(COLUMN_METAS)
+
+	if metasStr == "" {
+		metasStr = "{}"
+	}
+
+	metasJson := map[string]string{}
+	errJson := json.Unmarshal([]byte(metasStr), &metasJson)
+	if errJson != nil {
+		return map[string]string{}, errJson
+	}
+
+	if metasJson == nil {
+		metasJson = map[string]string{}
+	}
+
+	return metasJson, nil
+}
+
+// MetasRemove removes multiple metadata entries.
+func (customer *Customer) MetasRemove(names []string) error {
+	for _, name := range names {
+		err := customer.MetaRemove(name)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MetasUpsert merges the provided metadata with existing values.
+func (customer *Customer) MetasUpsert(metas map[string]string) error {
+	currentMetas, err := customer.GetMetas()
+
+	if err != nil {
+		return err
+	}
+
+	for k, v := range metas {
+		currentMetas[k] = v
+	}
+
+	return customer.SetMetas(currentMetas)
+}
+
+// SetMetas replaces all metadata with the provided map.
+// Warning: this overwrites any existing metadata.
+func (customer *Customer) SetMetas(metas map[string]string) error {
+	mapString, err := json.Marshal(metas)
+
+	if err != nil {
+		return err
+	}
+
+	customer.Set(COLUMN_METAS, string(mapString))
+
+	return nil
+}
+
+// GetName returns the full name of the customer.
+func (customer *Customer) GetName() string {
+	return customer.Get(COLUMN_NAME)
+}
+
+// SetName sets the full name of the customer.
+func (customer *Customer) SetName(name string) CustomerInterface {
+	customer.Set(COLUMN_NAME, name)
+	return customer
+}
+
+// GetPhone returns the phone number of the customer.
+func (customer *Customer) GetPhone() string {
+	return customer.Get(COLUMN_PHONE)
+}
+
+// SetPhone sets the phone number of the customer.
+func (customer *Customer) SetPhone(phone string) CustomerInterface {
+	customer.Set(COLUMN_PHONE, phone)
+	return customer
+}
+
+// GetSoftDeletedAt returns the soft deletion timestamp.
+func (customer *Customer) GetSoftDeletedAt() string {
+	return customer.Get(COLUMN_SOFT_DELETED_AT)
+}
+
+// GetSoftDeletedAtCarbon returns the soft deletion timestamp as a Carbon instance.
+func (customer *Customer) GetSoftDeletedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(customer.GetSoftDeletedAt())
+}
+
+// SetSoftDeletedAt sets the soft deletion timestamp.
+func (customer *Customer) SetSoftDeletedAt(deletedAt string) CustomerInterface {
+	customer.Set(COLUMN_SOFT_DELETED_AT, deletedAt)
+	return customer
+}
+
+// GetStatus returns the current status.
+func (customer *Customer) GetStatus() string {
+	return customer.Get(COLUMN_STATUS)
+}
+
+// SetStatus sets the current status.
+func (customer *Customer) SetStatus(status string) CustomerInterface {
+	customer.Set(COLUMN_STATUS, status)
+	return customer
+}
+
+// GetUpdatedAt returns the last update timestamp.
+func (customer *Customer) GetUpdatedAt() string {
+	return customer.Get(COLUMN_UPDATED_AT)
+}
+
+// GetUpdatedAtCarbon returns the last update timestamp as a Carbon instance.
+func (customer *Customer) GetUpdatedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(customer.GetUpdatedAt())
+}
+
+// SetUpdatedAt sets the last update timestamp.
+func (customer *Customer) SetUpdatedAt(updatedAt string) CustomerInterface {
+	customer.Set(COLUMN_UPDATED_AT, updatedAt)
+	return customer
+}
+
+// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+func (customer *Customer) GetVersion() int64 {
+	return cast.ToInt64(customer.Get(COLUMN_VERSION))
+}
+
+// SetVersion sets the version of the row the entity was loaded at.
+func (customer *Customer) SetVersion(version int64) CustomerInterface {
+	customer.Set(COLUMN_VERSION, cast.ToString(version))
+	return customer
+}
+
+// MarkAsNotDirty resets the dirty state, clearing all change tracking.
+func (customer *Customer) MarkAsNotDirty() {
+	customer.DataObject.MarkAsNotDirty()
+}
@@ -0,0 +1,96 @@
+package shopstore
+
+import "testing"
+
+func TestNewCustomerDefaults(t *testing.T) {
+	customer := NewCustomer()
+	if customer == nil {
+		t.Fatal("NewCustomer returned nil")
+	}
+
+	if customer.GetStatus() != CUSTOMER_STATUS_ACTIVE {
+		t.Fatalf("expected status %q, got %q", CUSTOMER_STATUS_ACTIVE, customer.GetStatus())
+	}
+
+	if customer.GetName() != "" || customer.GetEmail() != "" || customer.GetPhone() != "" {
+		t.Fatalf("expected empty name, email and phone, got %q, %q, %q", customer.GetName(), customer.GetEmail(), customer.GetPhone())
+	}
+
+	if customer.GetID() == "" {
+		t.Fatal("expected generated ID to be non-empty")
+	}
+
+	if customer.GetSoftDeletedAt() != MAX_DATETIME {
+		t.Fatalf("expected soft deleted at to be %q, got %q", MAX_DATETIME, customer.GetSoftDeletedAt())
+	}
+
+	if customer.GetVersion() != 1 {
+		t.Fatalf("expected version 1, got %d", customer.GetVersion())
+	}
+
+	metas, err := customer.GetMetas()
+	if err != nil {
+		t.Fatalf("unexpected error retrieving metas: %v", err)
+	}
+
+	if len(metas) != 0 {
+		t.Fatalf("expected no metas by default, got %v", metas)
+	}
+}
+
+func TestCustomerSetEmailNormalizes(t *testing.T) {
+	customer := NewCustomer().SetEmail("  Jane.Doe@Example.COM ")
+
+	if customer.GetEmail() != "jane.doe@example.com" {
+		t.Fatalf("expected the email trimmed and lowercased, got %q", customer.GetEmail())
+	}
+}
+
+func TestCustomerStatusPredicates(t *testing.T) {
+	customer := NewCustomer()
+
+	if !customer.IsActive() || customer.IsInactive() {
+		t.Fatal("expected a new customer to be active")
+	}
+
+	customer.SetStatus(CUSTOMER_STATUS_INACTIVE)
+
+	if customer.IsActive() || !customer.IsInactive() {
+		t.Fatal("expected the customer to be inactive")
+	}
+
+	if customer.IsSoftDeleted() {
+		t.Fatal("expected customer not to be soft deleted when timestamp is MAX_DATETIME")
+	}
+
+	customer.SetSoftDeletedAt("2024-01-01 00:00:00")
+
+	if !customer.IsSoftDeleted() {
+		t.Fatal("expected customer to be soft deleted when timestamp differs from MAX_DATETIME")
+	}
+}
+
+func TestCustomerMetasRoundTrip(t *testing.T) {
+	customer := NewCustomer()
+
+	if err := customer.SetMetas(map[string]string{"tier": "gold", "locale": "en"}); err != nil {
+		t.Fatalf("unexpected error setting metas: %v", err)
+	}
+
+	if err := customer.SetMeta("tier", "silver"); err != nil {
+		t.Fatalf("unexpected error setting meta: %v", err)
+	}
+
+	if err := customer.MetaRemove("locale"); err != nil {
+		t.Fatalf("unexpected error removing meta: %v", err)
+	}
+
+	metas, err := customer.GetMetas()
+	if err != nil {
+		t.Fatalf("unexpected error retrieving metas: %v", err)
+	}
+
+	if len(metas) != 1 || customer.GetMeta("tier") != "silver" {
+		t.Fatalf("unexpected metas %v", metas)
+	}
+}
@@ -0,0 +1,81 @@
+package shopstore
+
+import (
+	"strings"
+
+	contractsdatabase "github.com/dracory/neat/contracts/database"
+	"github.com/dracory/neat/database/schema/grammars"
+)
+
+// dialect identifies the SQL flavour of the store's database. The store
+// builds its queries through neat, so only the few constructs that neat
+// does not abstract away (JSON access, case insensitive matching, text
+// column defaults) are dialect specific.
+type dialect string
+
+const (
+	dialectSQLite   dialect = "sqlite"
+	dialectMySQL    dialect = "mysql"
+	dialectPostgres dialect = "postgres"
+)
+
+// dialectFromDriver maps a neat driver to its dialect. Drivers without
+// dedicated support get the SQLite flavour, the store's historic default.
+func dialectFromDriver(driver contractsdatabase.Driver) dialect {
+	switch driver {
+	case contractsdatabase.DriverMysql:
+		return dialectMySQL
+	case contractsdatabase.DriverPostgres:
+		return dialectPostgres
+	default:
+		return dialectSQLite
+	}
+}
+
+// jsonValue returns an SQL expression reading the top-level key of the JSON
+// stored in the text column as a string, together with the argument to
+// bind to its single placeholder. Empty columns read as NULL.
+func (d dialect) jsonValue(column string, key string) (string, any) {
+	switch d {
+	case dialectMySQL:
+		return "JSON_UNQUOTE(JSON_EXTRACT(NULLIF(" + column + ", ''), ?))", buildJsonPath(key)
+	case dialectPostgres:
+		return "jsonb_extract_path_text(CAST(NULLIF(" + column + ", '') AS jsonb), ?)", key
+	default:
+		return "json_extract(NULLIF(" + column + ", ''), ?)", buildJsonPath(key)
+	}
+}
+
+// like returns a case insensitive LIKE condition on column, with a
+// backslash escaping the wildcards in the bound pattern (see likeContains).
+func (d dialect) like(column string) string {
+	switch d {
+	case dialectMySQL:
+		// LOWER, as tables may use a case sensitive collation; backslashes
+		// are escapes in MySQL string literals too
+		return "LOWER(" + column + `) LIKE LOWER(?) ESCAPE '\\'`
+	case dialectPostgres:
+		return column + ` ILIKE ? ESCAPE '\'`
+	default:
+		return column + ` LIKE ? ESCAPE '\'`
+	}
+}
+
+// textDefault returns the default value for a text column. MySQL only
+// accepts expression defaults on TEXT columns.
+func (d dialect) textDefault(value string) any {
+	if d == dialectMySQL {
+		return grammars.Expression("('" + strings.ReplaceAll(value, "'", "''") + "')")
+	}
+
+	return value
+}
+
+// likeContains returns the LIKE pattern matching values containing term,
+// escaping the wildcards in term itself.
+func likeContains(term string) string {
+	term = strings.ReplaceAll(term, `\`, `\\`)
+	term = strings.ReplaceAll(term, "%", `\%`)
+	term = strings.ReplaceAll(term, "_", `\_`)
+	return "%" + term + "%"
+}
@@ -0,0 +1,60 @@
+package shopstore
+
+import (
+	"context"
+	"testing"
+
+	contractsdatabase "github.com/dracory/neat/contracts/database"
+)
+
+func TestDialectFromDriver(t *testing.T) {
+	cases := map[contractsdatabase.Driver]dialect{
+		contractsdatabase.DriverSqlite:   dialectSQLite,
+		contractsdatabase.DriverMysql:    dialectMySQL,
+		contractsdatabase.DriverPostgres: dialectPostgres,
+		contractsdatabase.DriverTurso:    dialectSQLite,
+	}
+
+	for driver, expected := range cases {
+		if got := dialectFromDriver(driver); got != expected {
+			t.Errorf("dialectFromDriver(%q) = %q, expected %q", driver, got, expected)
+		}
+	}
+}
+
+func TestLikeContains(t *testing.T) {
+	if got := likeContains(`50%_off\`); got != `%50\%\_off\\%` {
+		t.Fatalf("unexpected pattern %q", got)
+	}
+}
+
+func TestStoreProductListWithTitleLike_Wildcards(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+	for _, title := range []string{"50% OFF Widget", "500 Widgets", "O'Brien's Widget"} {
+		product := NewProduct().SetStatus(PRODUCT_STATUS_ACTIVE).SetTitle(title).SetQuantityInt(1).SetPriceFloat(10)
+		if err := store.ProductCreate(ctx, product); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	list, err := store.ProductList(ctx, NewProductQuery().SetTitleLike("50% off"))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if len(list) != 1 || list[0].GetTitle() != "50% OFF Widget" {
+		t.Fatalf("expected only the 50%% OFF Widget, got %d products", len(list))
+	}
+
+	list, err = store.ProductList(ctx, NewProductQuery().SetTitleLike("o'brien"))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("expected 1 product, got %d", len(list))
+	}
+}
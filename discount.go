@@ -68,7 +68,8 @@ func NewDiscount() DiscountInterface {
 		SetMemo("").
 		SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
 		SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
-		SetSoftDeletedAt(MAX_DATETIME)
+		SetSoftDeletedAt(MAX_DATETIME).
+		SetVersion(1)
 
 	d.SetMetas(map[string]string{})
 
@@ -372,6 +373,17 @@ func (d *Discount) SetUpdatedAt(updatedAt string) DiscountInterface {
 	return d
 }
 
+// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+func (d *Discount) GetVersion() int64 {
+	return cast.ToInt64(d.Get(COLUMN_VERSION))
+}
+
+// SetVersion sets the version of the row the entity was loaded at.
+func (d *Discount) SetVersion(version int64) DiscountInterface {
+	d.Set(COLUMN_VERSION, cast.ToString(version))
+	return d
+}
+
 // IsActive returns true if the discount status is active.
 func (d *Discount) IsActive() bool {
 	return d.GetStatus() == DISCOUNT_STATUS_ACTIVE
@@ -56,6 +56,19 @@ type DiscountQueryInterface interface {
 	SortDirection() string
 	SetSortDirection(sortDirection string) DiscountQueryInterface
 
+	// AddSort adds a sort key. Keys are applied in the order they are added,
+	// after the order_by column if one is set. Only the entity's sortable
+	// columns and the asc/desc directions are accepted by Validate.
+	HasSorts() bool
+	Sorts() []QuerySort
+	AddSort(column string, direction string) DiscountQueryInterface
+
+	// AfterCursor continues a keyset paginated list right after the row the
+	// cursor points at. Cursors are returned as NextCursor by the *ListPage methods.
+	HasAfterCursor() bool
+	AfterCursor() string
+	SetAfterCursor(cursor string) DiscountQueryInterface
+
 	HasSoftDeletedIncluded() bool
 	SoftDeletedIncluded() bool
 	SetSoftDeletedIncluded(softDeletedIncluded bool) DiscountQueryInterface
@@ -159,6 +172,24 @@ func (c *discountQueryImplementation) Validate() error {
 		return errors.New("discount query. type cannot be empty")
 	}
 
+	if err := validateSort(c, discountSortableColumns); err != nil {
+		return errors.New("discount query. " + err.Error())
+	}
+
+	if c.HasAfterCursor() {
+		if c.AfterCursor() == "" {
+			return errors.New("discount query. after_cursor cannot be empty")
+		}
+
+		if c.HasOffset() {
+			return errors.New("discount query. after_cursor cannot be combined with offset")
+		}
+
+		if err := validateCursor(c.AfterCursor(), keysetSort(c)); err != nil {
+			return errors.New("discount query. after_cursor " + err.Error())
+		}
+	}
+
 	return nil
 }
 
@@ -356,6 +387,24 @@ func (c *discountQueryImplementation) SetSortDirection(sortDirection string) Dis
 	return c
 }
 
+func (c *discountQueryImplementation) HasSorts() bool {
+	return c.hasProperty("sorts")
+}
+
+func (c *discountQueryImplementation) Sorts() []QuerySort {
+	if !c.HasSorts() {
+		return []QuerySort{}
+	}
+
+	return c.properties["sorts"].([]QuerySort)
+}
+
+func (c *discountQueryImplementation) AddSort(column string, direction string) DiscountQueryInterface {
+	c.properties["sorts"] = appendSort(c.Sorts(), column, direction)
+
+	return c
+}
+
 func (c *discountQueryImplementation) HasSoftDeletedIncluded() bool {
 	return c.hasProperty("soft_deleted_included")
 }
@@ -500,6 +549,24 @@ func (c *discountQueryImplementation) SetType(discountType string) DiscountQuery
 	return c
 }
 
+func (c *discountQueryImplementation) HasAfterCursor() bool {
+	return c.hasProperty("after_cursor")
+}
+
+func (c *discountQueryImplementation) AfterCursor() string {
+	if !c.HasAfterCursor() {
+		return ""
+	}
+
+	return c.properties["after_cursor"].(string)
+}
+
+func (c *discountQueryImplementation) SetAfterCursor(cursor string) DiscountQueryInterface {
+	c.properties["after_cursor"] = cursor
+
+	return c
+}
+
 func (c *discountQueryImplementation) hasProperty(name string) bool {
 	_, ok := c.properties[name]
 	return ok
@@ -0,0 +1,393 @@
+package shopstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+	"github.com/dromara/carbon/v2"
+	"github.com/samber/lo"
+)
+
+// STORE_EXPORT_FORMAT identifies the exports written by Store.Export
+const STORE_EXPORT_FORMAT = "shopstore"
+
+// STORE_EXPORT_VERSION is the version of the export format written by
+// Store.Export. Import reads the exports of this version and older.
+const STORE_EXPORT_VERSION = 1
+
+// STORE_IMPORT_MODE_MERGE writes the imported rows over the stored rows
+// with the same IDs, keeping the other stored rows
+const STORE_IMPORT_MODE_MERGE = "merge"
+
+// STORE_IMPORT_MODE_REPLACE deletes all the stored rows before importing
+const STORE_IMPORT_MODE_REPLACE = "replace"
+
+// Types of the lines of an export
+const (
+	exportLineHeader = "header"
+	exportLineRow    = "row"
+	exportLineFooter = "footer"
+)
+
+// StoreImportOptions configures Store.Import
+type StoreImportOptions struct {
+	// Mode is STORE_IMPORT_MODE_MERGE, the default, or STORE_IMPORT_MODE_REPLACE
+	Mode string
+
+	// AnonymizeCustomerIDs replaces the customer IDs of the orders with new
+	// IDs, the same for all the orders of a customer. The customers, their
+	// addresses and their carts get the new IDs too, with the name, email,
+	// phone and street lines cleared. The address snapshots of the orders
+	// only keep the city, region, postal code and country.
+	AnonymizeCustomerIDs bool
+}
+
+// exportLine is a line of an export: the header, a row of an entity table
+// or the footer, which counts the rows written per entity
+type exportLine struct {
+	Type       string            `json:"type"`
+	Format     string            `json:"format,omitempty"`
+	Version    int               `json:"version,omitempty"`
+	ExportedAt string            `json:"exported_at,omitempty"`
+	Entity     string            `json:"entity,omitempty"`
+	Data       map[string]string `json:"data,omitempty"`
+	Counts     map[string]int    `json:"counts,omitempty"`
+}
+
+// entityTable is the table of an entity type
+type entityTable struct {
+	entityType string
+	tableName  string
+}
+
+// entityTables lists the tables of the entities, referenced tables first
+func (store *Store) entityTables() []entityTable {
+	return []entityTable{
+		{ENTITY_TYPE_CATEGORY, store.categoryTableName},
+		{ENTITY_TYPE_CUSTOMER, store.customerTableName},
+		{ENTITY_TYPE_ADDRESS, store.addressTableName},
+		{ENTITY_TYPE_PRODUCT, store.productTableName},
+		{ENTITY_TYPE_DISCOUNT, store.discountTableName},
+		{ENTITY_TYPE_TAX_RATE, store.taxRateTableName},
+		{ENTITY_TYPE_SHIPPING_ZONE, store.shippingZoneTableName},
+		{ENTITY_TYPE_SHIPPING_METHOD, store.shippingMethodTableName},
+		{ENTITY_TYPE_ORDER, store.orderTableName},
+		{ENTITY_TYPE_ORDER_LINE_ITEM, store.orderLineItemTableName},
+		{ENTITY_TYPE_CART, store.cartTableName},
+		{ENTITY_TYPE_CART_ITEM, store.cartItemTableName},
+		{ENTITY_TYPE_MEDIA, store.mediaTableName},
+	}
+}
+
+// Export writes all the rows of the entity tables to w as JSON Lines, soft
+// deleted rows included: a header with the format version, a line per row
+// and a footer counting the rows, so that Import detects truncated exports.
+//
+// Rows are read in batches, each bounded by the operation timeout, and not
+// in a single transaction: writes during the export may show in part.
+func (store *Store) Export(ctx context.Context, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+
+	err := encoder.Encode(exportLine{
+		Type:       exportLineHeader,
+		Format:     STORE_EXPORT_FORMAT,
+		Version:    STORE_EXPORT_VERSION,
+		ExportedAt: carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC),
+	})
+	if err != nil {
+		return err
+	}
+
+	counts := map[string]int{}
+
+	for _, table := range store.entityTables() {
+		cursor := ""
+
+		for {
+			rows, err := store.exportBatch(ctx, table.tableName, cursor)
+			if err != nil {
+				return err
+			}
+
+			for _, row := range rows {
+				err := encoder.Encode(exportLine{Type: exportLineRow, Entity: table.entityType, Data: row})
+				if err != nil {
+					return err
+				}
+			}
+
+			counts[table.entityType] += len(rows)
+
+			if len(rows) < iterateBatchSize {
+				break
+			}
+
+			cursor = rows[len(rows)-1][COLUMN_ID]
+		}
+	}
+
+	return encoder.Encode(exportLine{Type: exportLineFooter, Counts: counts})
+}
+
+// exportBatch reads the next batch of rows of the table, in ID order,
+// following the row with the cursor ID
+func (store *Store) exportBatch(ctx context.Context, tableName string, cursor string) ([]map[string]string, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q := store.query(ctx).Table(tableName)
+	if cursor != "" {
+		q = q.Where(COLUMN_ID+" > ?", cursor)
+	}
+
+	var results []map[string]any
+	err := q.OrderBy(COLUMN_ID, SORT_DIRECTION_ASC).Limit(iterateBatchSize).Get(&results)
+	if err != nil {
+		return nil, store.operationError("export", err)
+	}
+
+	return lo.Map(results, func(result map[string]any, _ int) map[string]string {
+		return mapAnyToString(result)
+	}), nil
+}
+
+// Import reads an export written by Export and writes its rows, IDs
+// included, in a single transaction: either the whole export is imported
+// or nothing is. Merging writes the rows over the stored rows with the same
+// IDs, replacing deletes all the stored rows of the entity tables first.
+//
+// The rows are written as exported: hooks do not run, references are not
+// checked and neither the audit log nor the outbox record the import. The
+// operation timeout does not apply, bound a long import with ctx instead.
+func (store *Store) Import(ctx context.Context, r io.Reader, options StoreImportOptions) error {
+	mode := lo.CoalesceOrEmpty(options.Mode, STORE_IMPORT_MODE_MERGE)
+	if !slices.Contains([]string{STORE_IMPORT_MODE_MERGE, STORE_IMPORT_MODE_REPLACE}, mode) {
+		return errors.New("store import. mode must be merge or replace")
+	}
+
+	decoder := json.NewDecoder(r)
+
+	var header exportLine
+	if err := decoder.Decode(&header); err != nil {
+		return fmt.Errorf("%w: header: %v", ErrInvalidExport, err)
+	}
+
+	if header.Type != exportLineHeader || header.Format != STORE_EXPORT_FORMAT {
+		return fmt.Errorf("%w: not a %s export", ErrInvalidExport, STORE_EXPORT_FORMAT)
+	}
+
+	if header.Version < 1 || header.Version > STORE_EXPORT_VERSION {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidExport, header.Version)
+	}
+
+	tables := lo.SliceToMap(store.entityTables(), func(table entityTable) (string, string) {
+		return table.entityType, table.tableName
+	})
+
+	customerIDs := map[string]string{}
+
+	err := store.query(ctx).Transaction(func(tx contractsorm.Query) error {
+		if mode == STORE_IMPORT_MODE_REPLACE {
+			if err := store.importClear(tx); err != nil {
+				return err
+			}
+		}
+
+		counts := map[string]int{}
+		lineNumber := 1
+		batch := []map[string]string{}
+		batchEntity := ""
+
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+
+			err := importRows(tx, tables[batchEntity], batch, mode == STORE_IMPORT_MODE_MERGE)
+			batch = []map[string]string{}
+			return err
+		}
+
+		for {
+			lineNumber++
+
+			var line exportLine
+			if err := decoder.Decode(&line); err == io.EOF {
+				return fmt.Errorf("%w: the footer is missing, the export is truncated", ErrInvalidExport)
+			} else if err != nil {
+				return fmt.Errorf("%w: line %d: %v", ErrInvalidExport, lineNumber, err)
+			}
+
+			if line.Type == exportLineFooter {
+				if err := flush(); err != nil {
+					return err
+				}
+
+				return importAssertCounts(line.Counts, counts)
+			}
+
+			if line.Type != exportLineRow || line.Data[COLUMN_ID] == "" {
+				return fmt.Errorf("%w: line %d is not a row", ErrInvalidExport, lineNumber)
+			}
+
+			if _, ok := tables[line.Entity]; !ok {
+				return fmt.Errorf("%w: line %d: unknown entity %q", ErrInvalidExport, lineNumber, line.Entity)
+			}
+
+			if line.Entity != batchEntity || len(batch) == bulkBatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+
+				batchEntity = line.Entity
+			}
+
+			if options.AnonymizeCustomerIDs && line.Entity == ENTITY_TYPE_ORDER {
+				anonymizeCustomerID(line.Data, COLUMN_CUSTOMER_ID, customerIDs)
+				anonymizeOrderAddress(line.Data, COLUMN_SHIPPING_ADDRESS)
+				anonymizeOrderAddress(line.Data, COLUMN_BILLING_ADDRESS)
+			}
+
+			if options.AnonymizeCustomerIDs && line.Entity == ENTITY_TYPE_CART {
+				anonymizeCustomerID(line.Data, COLUMN_CUSTOMER_ID, customerIDs)
+			}
+
+			if options.AnonymizeCustomerIDs && line.Entity == ENTITY_TYPE_ADDRESS {
+				anonymizeCustomerID(line.Data, COLUMN_CUSTOMER_ID, customerIDs)
+				line.Data[COLUMN_NAME] = ""
+				line.Data[COLUMN_LINE1] = ""
+				line.Data[COLUMN_LINE2] = ""
+				line.Data[COLUMN_PHONE] = ""
+			}
+
+			if options.AnonymizeCustomerIDs && line.Entity == ENTITY_TYPE_CUSTOMER {
+				anonymizeCustomerID(line.Data, COLUMN_ID, customerIDs)
+				line.Data[COLUMN_NAME] = ""
+				line.Data[COLUMN_EMAIL] = ""
+				line.Data[COLUMN_PHONE] = ""
+			}
+
+			batch = append(batch, line.Data)
+			counts[line.Entity]++
+		}
+	})
+
+	return store.operationError("import", err)
+}
+
+// importClear deletes all the rows of the entity tables, referencing tables first
+func (store *Store) importClear(tx contractsorm.Query) error {
+	tables := store.entityTables()
+	slices.Reverse(tables)
+
+	for _, table := range tables {
+		_, err := statement(tx).Table(table.tableName).Where(COLUMN_ID+" <> ?", "").Delete()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importRows writes the rows of an export to the table within the
+// transaction tx. Merging, the rows with a stored ID are updated, the
+// others inserted.
+func importRows(tx contractsorm.Query, tableName string, rows []map[string]string, merge bool) error {
+	stored := map[string]struct{}{}
+
+	if merge {
+		ids := lo.Map(rows, func(row map[string]string, _ int) string {
+			return row[COLUMN_ID]
+		})
+
+		var results []map[string]any
+		err := statement(tx).
+			Table(tableName).
+			Select([]string{COLUMN_ID}).
+			WhereIn(COLUMN_ID, lo.ToAnySlice(ids)).
+			Get(&results)
+		if err != nil {
+			return err
+		}
+
+		for _, result := range results {
+			stored[mapAnyToString(result)[COLUMN_ID]] = struct{}{}
+		}
+	}
+
+	inserted := []map[string]string{}
+
+	for _, row := range rows {
+		if _, ok := stored[row[COLUMN_ID]]; !ok {
+			inserted = append(inserted, row)
+			continue
+		}
+
+		values := lo.MapValues(row, func(value string, column string) any {
+			return rowValue(column, value)
+		})
+
+		_, err := statement(tx).Table(tableName).Where(COLUMN_ID+" = ?", row[COLUMN_ID]).Update(values)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(inserted) == 0 {
+		return nil
+	}
+
+	return bulkInsert(tx, tableName, inserted)
+}
+
+// importAssertCounts checks that the rows read match the counts of the footer
+func importAssertCounts(expected map[string]int, counts map[string]int) error {
+	for _, entity := range lo.Union(lo.Keys(expected), lo.Keys(counts)) {
+		if expected[entity] != counts[entity] {
+			return fmt.Errorf("%w: %d %s rows read, the footer counts %d", ErrInvalidExport, counts[entity], entity, expected[entity])
+		}
+	}
+
+	return nil
+}
+
+// anonymizeCustomerID replaces the customer ID in the column of the row
+// data with a new ID, keeping the IDs already replaced so that a customer
+// keeps its orders
+func anonymizeCustomerID(data map[string]string, column string, customerIDs map[string]string) {
+	customerID := data[column]
+	if customerID == "" {
+		return
+	}
+
+	if _, ok := customerIDs[customerID]; !ok {
+		customerIDs[customerID] = GenerateShortID()
+	}
+
+	data[column] = customerIDs[customerID]
+}
+
+// anonymizeOrderAddress clears the recipient, street lines and phone of the
+// address snapshot in the column of the order data, keeping the area of the
+// address. Snapshots not holding an address are left as they are.
+func anonymizeOrderAddress(data map[string]string, column string) {
+	var address OrderAddress
+	if err := json.Unmarshal([]byte(data[column]), &address); err != nil || address.IsEmpty() {
+		return
+	}
+
+	address.Name = ""
+	address.Line1 = ""
+	address.Line2 = ""
+	address.Phone = ""
+
+	if jsonBytes, err := json.Marshal(address); err == nil {
+		data[column] = string(jsonBytes)
+	}
+}
@@ -0,0 +1,262 @@
+package shopstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// seedExportStore creates an entity of every type, a soft deleted product
+// and two orders of the same customer
+func seedExportStore(t *testing.T, store *Store) (ProductInterface, OrderInterface, OrderInterface) {
+	t.Helper()
+	ctx := context.Background()
+
+	category := NewCategory().SetTitle("Shirts")
+	if err := store.CategoryCreate(ctx, category); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	product := NewProduct().SetTitle("Shirt").SetSKU("SHIRT").SetPriceFloat(19.99)
+	_ = product.SetMeta("brand", "Acme")
+	retired := NewProduct().SetTitle("Retired")
+
+	if err := store.ProductCreateMany(ctx, []ProductInterface{product, retired}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.ProductSoftDelete(ctx, retired); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	customer := NewCustomer().SetID("CUSTOMER01").SetName("Jane Doe").SetEmail("jane@example.com")
+	if err := store.CustomerCreate(ctx, customer); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	address := NewAddress().SetCustomerID("CUSTOMER01").SetName("Jane Doe").SetLine1("1 High Street").SetCity("London").SetCountryCode("GB").SetIsDefaultShipping(true)
+	if err := store.AddressCreate(ctx, address); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.DiscountCreate(ctx, NewDiscount().SetTitle("Spring")); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.TaxRateCreate(ctx, NewTaxRate().SetCountryCode("GB").SetRate("20").SetName("VAT")); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	zone := NewShippingZone().SetName("United Kingdom")
+	_ = zone.SetCountryCodes([]string{"GB"})
+	if err := store.ShippingZoneCreate(ctx, zone); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	method := NewShippingMethod().SetShippingZoneID(zone.GetID()).SetName("Royal Mail").SetAmountFloat(3.5)
+	if err := store.ShippingMethodCreate(ctx, method); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	first := NewOrder().SetCustomerID("CUSTOMER01").SetPriceFloat(19.99).SetQuantityInt(1)
+	second := NewOrder().SetCustomerID("CUSTOMER01").SetPriceFloat(39.98).SetQuantityInt(2)
+	_ = first.SetShippingAddress(address.ToOrderAddress())
+
+	for _, order := range []OrderInterface{first, second} {
+		if err := store.OrderCreate(ctx, order); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	lineItem := NewOrderLineItem().SetOrderID(first.GetID()).SetProductID(product.GetID()).SetQuantityInt(1)
+	if err := store.OrderLineItemCreate(ctx, lineItem); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	media := NewMedia().SetEntityID(product.GetID()).SetType("image/png").SetURL("https://example.com/shirt.png").SetSequence(1)
+	if err := store.MediaCreate(ctx, media); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	return product, first, second
+}
+
+func TestStoreExportImport_Replace(t *testing.T) {
+	store := initIntegrityStore(t)
+	ctx := context.Background()
+
+	product, first, _ := seedExportStore(t, store)
+
+	var buffer bytes.Buffer
+	if err := store.Export(ctx, &buffer); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// 1 category, 1 customer, 1 address, 2 products, 1 discount, 1 tax rate,
+	// 1 shipping zone, 1 shipping method, 2 orders, 1 line item, 1 media
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 15 {
+		t.Fatalf("expected a header, 13 rows and a footer, got %d lines", len(lines))
+	}
+
+	imported := initIntegrityStore(t)
+	stale := NewProduct().SetTitle("Stale")
+	if err := imported.ProductCreate(ctx, stale); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	err := imported.Import(ctx, bytes.NewReader(buffer.Bytes()), StoreImportOptions{Mode: STORE_IMPORT_MODE_REPLACE})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	products, err := imported.ProductList(ctx, NewProductQuery().SetSoftDeletedIncluded(true))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(products) != 2 {
+		t.Fatalf("expected the 2 exported products only, got %d", len(products))
+	}
+
+	found := mustFindProduct(t, imported, product.GetID())
+	for column, value := range product.Data() {
+		if !productValuesEqual(column, found.Data()[column], value) {
+			t.Fatalf("expected %s %q, got %q", column, value, found.Data()[column])
+		}
+	}
+
+	order, err := imported.OrderFindByID(ctx, first.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if order == nil || order.GetCustomerID() != "CUSTOMER01" {
+		t.Fatalf("expected the order imported with its customer, got %v", order)
+	}
+
+	lineItems, err := imported.OrderLineItemCount(ctx, NewOrderLineItemQuery().SetOrderID(first.GetID()))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if lineItems != 1 {
+		t.Fatalf("expected the line item imported, got %d", lineItems)
+	}
+}
+
+func TestStoreImport_MergeAnonymized(t *testing.T) {
+	store := initIntegrityStore(t)
+	ctx := context.Background()
+
+	product, first, second := seedExportStore(t, store)
+
+	var buffer bytes.Buffer
+	if err := store.Export(ctx, &buffer); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	product.SetTitle("Shirt, renamed")
+	if err := store.ProductUpdate(ctx, product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	kept := NewProduct().SetTitle("Kept")
+	if err := store.ProductCreate(ctx, kept); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	err := store.Import(ctx, bytes.NewReader(buffer.Bytes()), StoreImportOptions{AnonymizeCustomerIDs: true})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found := mustFindProduct(t, store, product.GetID()); found.GetTitle() != "Shirt" || found.GetVersion() != 1 {
+		t.Fatalf("expected the exported product written back, got %q at version %d", found.GetTitle(), found.GetVersion())
+	}
+
+	if found, _ := store.ProductFindByID(ctx, kept.GetID()); found == nil {
+		t.Fatal("expected the product not in the export kept")
+	}
+
+	firstOrder, _ := store.OrderFindByID(ctx, first.GetID())
+	secondOrder, _ := store.OrderFindByID(ctx, second.GetID())
+
+	if firstOrder.GetCustomerID() == "CUSTOMER01" || firstOrder.GetCustomerID() == "" {
+		t.Fatalf("expected the customer ID anonymized, got %q", firstOrder.GetCustomerID())
+	}
+
+	if firstOrder.GetCustomerID() != secondOrder.GetCustomerID() {
+		t.Fatal("expected the orders of a customer to keep a shared customer ID")
+	}
+
+	customer, err := store.CustomerFindByID(ctx, firstOrder.GetCustomerID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if customer == nil || customer.GetName() != "" || customer.GetEmail() != "" {
+		t.Fatalf("expected the customer imported under the new ID without personal data, got %v", customer)
+	}
+
+	addresses, err := store.CustomerAddressList(ctx, customer.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(addresses) != 1 || addresses[0].GetLine1() != "" || addresses[0].GetCity() != "London" {
+		t.Fatalf("expected the address imported under the new ID without its street, got %v", addresses)
+	}
+
+	shipping, err := firstOrder.GetShippingAddress()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if shipping.Name != "" || shipping.Line1 != "" || shipping.City != "London" || shipping.CountryCode != "GB" {
+		t.Fatalf("expected the order address reduced to its area, got %+v", shipping)
+	}
+}
+
+func TestStoreImport_Invalid(t *testing.T) {
+	store := initIntegrityStore(t)
+	ctx := context.Background()
+
+	seedExportStore(t, store)
+
+	var buffer bytes.Buffer
+	if err := store.Export(ctx, &buffer); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	truncated := strings.Join(lines[:len(lines)-2], "\n")
+
+	imported := initIntegrityStore(t)
+
+	err := imported.Import(ctx, strings.NewReader(truncated), StoreImportOptions{Mode: STORE_IMPORT_MODE_REPLACE})
+	if !errors.Is(err, ErrInvalidExport) {
+		t.Fatalf("expected ErrInvalidExport for a truncated export, got %v", err)
+	}
+
+	count, err := imported.ProductCount(ctx, NewProductQuery().SetSoftDeletedIncluded(true))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if count != 0 {
+		t.Fatalf("expected nothing imported, got %d products", count)
+	}
+
+	newer := strings.Replace(lines[0], `"version":1`, `"version":2`, 1)
+	err = imported.Import(ctx, strings.NewReader(newer), StoreImportOptions{})
+	if !errors.Is(err, ErrInvalidExport) {
+		t.Fatalf("expected ErrInvalidExport for a newer version, got %v", err)
+	}
+
+	if err := imported.Import(ctx, strings.NewReader(buffer.String()), StoreImportOptions{Mode: "append"}); err == nil {
+		t.Fatal("expected an error for an unknown mode")
+	}
+}
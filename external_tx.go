@@ -0,0 +1,178 @@
+package shopstore
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+
+	"github.com/dracory/neat"
+	"github.com/dracory/neat/database"
+)
+
+// externalTxDB returns a database running every statement on the caller's
+// transaction tx, for the store operations accepting one. The transactions
+// started on it are part of tx: committing them commits nothing, and tx is
+// committed or rolled back by the caller alone.
+//
+// neat queries can not be bound to an existing *sql.Tx, so the statements
+// reach tx through a database/sql connection forwarding to it. Close the
+// returned *sql.DB when done; tx stays open.
+func (store *Store) externalTxDB(tx *sql.Tx) (*neat.Database, *sql.DB, error) {
+	sqlDB := sql.OpenDB(externalTxConnector{tx: tx})
+	sqlDB.SetMaxOpenConns(1) // tx runs a single statement at a time
+
+	db, err := neat.NewFromSQLDB(sqlDB, database.WithDriver(string(store.db.Query().Driver())))
+	if err != nil {
+		_ = sqlDB.Close()
+		return nil, nil, err
+	}
+
+	return db, sqlDB, nil
+}
+
+// externalTxConnector opens connections forwarding to a transaction
+type externalTxConnector struct {
+	tx *sql.Tx
+}
+
+func (connector externalTxConnector) Connect(context.Context) (driver.Conn, error) {
+	return externalTxConn(connector), nil
+}
+
+func (connector externalTxConnector) Driver() driver.Driver {
+	return externalTxDriver{}
+}
+
+// externalTxDriver is the driver of an externalTxConnector. Connections are
+// only opened through the connector.
+type externalTxDriver struct{}
+
+func (externalTxDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("shop store: external transaction connections are opened by their connector")
+}
+
+// externalTxConn runs the statements of a connection on a transaction
+type externalTxConn struct {
+	tx *sql.Tx
+}
+
+var (
+	_ driver.ConnBeginTx       = externalTxConn{}
+	_ driver.ExecerContext     = externalTxConn{}
+	_ driver.QueryerContext    = externalTxConn{}
+	_ driver.NamedValueChecker = externalTxConn{}
+	_ driver.Tx                = externalTxNestedTx{}
+)
+
+func (conn externalTxConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("shop store: prepared statements are not supported on an external transaction")
+}
+
+// Close leaves the transaction open, it belongs to the caller
+func (conn externalTxConn) Close() error {
+	return nil
+}
+
+func (conn externalTxConn) Begin() (driver.Tx, error) {
+	return externalTxNestedTx{}, nil
+}
+
+func (conn externalTxConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return externalTxNestedTx{}, nil
+}
+
+// CheckNamedValue passes the arguments on unconverted, for the driver of
+// the transaction to convert
+func (conn externalTxConn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+func (conn externalTxConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return conn.tx.ExecContext(ctx, query, externalTxArgs(args)...)
+}
+
+func (conn externalTxConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := conn.tx.QueryContext(ctx, query, externalTxArgs(args)...)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		_ = rows.Close()
+		return nil, err
+	}
+
+	return &externalTxRows{rows: rows, columns: columns}, nil
+}
+
+// externalTxArgs converts driver arguments back to database/sql ones
+func externalTxArgs(args []driver.NamedValue) []any {
+	values := make([]any, 0, len(args))
+	for _, arg := range args {
+		if arg.Name != "" {
+			values = append(values, sql.Named(arg.Name, arg.Value))
+			continue
+		}
+
+		values = append(values, arg.Value)
+	}
+
+	return values
+}
+
+// externalTxNestedTx is a transaction started within the external
+// transaction. Its statements are already part of it, so it neither
+// commits nor rolls back anything: a failure reaches the caller as an
+// error, and the caller rolls the external transaction back.
+type externalTxNestedTx struct{}
+
+func (externalTxNestedTx) Commit() error {
+	return nil
+}
+
+func (externalTxNestedTx) Rollback() error {
+	return nil
+}
+
+// externalTxRows reads the rows of a query run on the transaction
+type externalTxRows struct {
+	rows    *sql.Rows
+	columns []string
+}
+
+func (rows *externalTxRows) Columns() []string {
+	return rows.columns
+}
+
+func (rows *externalTxRows) Close() error {
+	return rows.rows.Close()
+}
+
+func (rows *externalTxRows) Next(dest []driver.Value) error {
+	if !rows.rows.Next() {
+		if err := rows.rows.Err(); err != nil {
+			return err
+		}
+
+		return io.EOF
+	}
+
+	values := make([]any, len(dest))
+	pointers := make([]any, len(dest))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	if err := rows.rows.Scan(pointers...); err != nil {
+		return err
+	}
+
+	for i, value := range values {
+		dest[i] = value
+	}
+
+	return nil
+}
@@ -8,12 +8,15 @@ require (
 	github.com/dracory/str v0.18.0
 	github.com/dracory/uid v1.9.0
 	github.com/dromara/carbon/v2 v2.6.16
+	github.com/go-sql-driver/mysql v1.10.0
+	github.com/lib/pq v1.12.3
 	github.com/samber/lo v1.53.0
 	github.com/spf13/cast v1.10.0
 	modernc.org/sqlite v1.53.0
 )
 
 require (
+	filippo.io/edwards25519 v1.2.0 // indirect
 	github.com/dustin/go-humanize v1.0.1 // indirect
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/mattn/go-isatty v0.0.22 // indirect
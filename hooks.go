@@ -0,0 +1,208 @@
+package shopstore
+
+import (
+	"context"
+	"sync"
+)
+
+// Hooks holds the lifecycle hooks registered for one entity type, see
+// Store.ProductHooks, Store.OrderHooks and friends.
+//
+// Hooks run synchronously, in registration order, as part of the store
+// operation. A Before hook returning an error vetoes the operation, which
+// then fails with that error. After hooks only run once the change has
+// been written. Soft deletes run the AfterSoftDelete hooks, not the update
+// hooks.
+//
+// The writes computing their changes in the store, the bulk UpdateMany
+// operations, ProductQuantityAdjust, CartCheckout, OrderCalculateTax,
+// OrderSetShippingMethod and AbandonedCheckoutExpire, only run the After
+// hooks. The rows they write without loading are read back for the
+// AfterUpdate hooks, when any are registered. CartExpireAbandoned runs no
+// hooks.
+type Hooks[T any] struct {
+	mu              sync.RWMutex
+	beforeCreate    []func(ctx context.Context, entity T) error
+	afterCreate     []func(ctx context.Context, entity T)
+	beforeUpdate    []func(ctx context.Context, entity T, changed map[string]string) error
+	afterUpdate     []func(ctx context.Context, entity T, changed map[string]string)
+	afterDelete     []func(ctx context.Context, id string)
+	afterSoftDelete []func(ctx context.Context, id string)
+}
+
+// BeforeCreate registers a hook run before an entity is inserted. The
+// hook may still modify the entity.
+func (hooks *Hooks[T]) BeforeCreate(hook func(ctx context.Context, entity T) error) {
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	hooks.beforeCreate = append(hooks.beforeCreate, hook)
+}
+
+// AfterCreate registers a hook run after an entity was inserted
+func (hooks *Hooks[T]) AfterCreate(hook func(ctx context.Context, entity T)) {
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	hooks.afterCreate = append(hooks.afterCreate, hook)
+}
+
+// BeforeUpdate registers a hook run before an entity is updated. changed
+// holds the modified fields, as returned by DataChanged.
+func (hooks *Hooks[T]) BeforeUpdate(hook func(ctx context.Context, entity T, changed map[string]string) error) {
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	hooks.beforeUpdate = append(hooks.beforeUpdate, hook)
+}
+
+// AfterUpdate registers a hook run after an entity was updated, with the
+// fields that were written.
+func (hooks *Hooks[T]) AfterUpdate(hook func(ctx context.Context, entity T, changed map[string]string)) {
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	hooks.afterUpdate = append(hooks.afterUpdate, hook)
+}
+
+// AfterDelete registers a hook run after an entity was hard deleted
+func (hooks *Hooks[T]) AfterDelete(hook func(ctx context.Context, id string)) {
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	hooks.afterDelete = append(hooks.afterDelete, hook)
+}
+
+// AfterSoftDelete registers a hook run after an entity was soft deleted
+func (hooks *Hooks[T]) AfterSoftDelete(hook func(ctx context.Context, id string)) {
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	hooks.afterSoftDelete = append(hooks.afterSoftDelete, hook)
+}
+
+// registered returns the hooks registered so far, read under mu. The hooks
+// then run without holding mu, so one may register hooks without
+// deadlocking.
+func registered[F any](mu *sync.RWMutex, hooks *[]F) []F {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return *hooks
+}
+
+func (hooks *Hooks[T]) runBeforeCreate(ctx context.Context, entity T) error {
+	for _, hook := range registered(&hooks.mu, &hooks.beforeCreate) {
+		if err := hook(ctx, entity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (hooks *Hooks[T]) runAfterCreate(ctx context.Context, entity T) {
+	for _, hook := range registered(&hooks.mu, &hooks.afterCreate) {
+		hook(ctx, entity)
+	}
+}
+
+func (hooks *Hooks[T]) runBeforeUpdate(ctx context.Context, entity T, changed map[string]string) error {
+	for _, hook := range registered(&hooks.mu, &hooks.beforeUpdate) {
+		if err := hook(ctx, entity, changed); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (hooks *Hooks[T]) runAfterUpdate(ctx context.Context, entity T, changed map[string]string) {
+	for _, hook := range registered(&hooks.mu, &hooks.afterUpdate) {
+		hook(ctx, entity, changed)
+	}
+}
+
+// hasAfterUpdate reports whether AfterUpdate hooks are registered, for the
+// writes having to read the entities back to run them
+func (hooks *Hooks[T]) hasAfterUpdate() bool {
+	hooks.mu.RLock()
+	defer hooks.mu.RUnlock()
+
+	return len(hooks.afterUpdate) > 0
+}
+
+// runAfterUpdateRows runs the AfterUpdate hooks on the rows written without
+// loading their entities, as read back by the write. changed returns the
+// fields written to a row.
+func runAfterUpdateRows[T any](ctx context.Context, hooks *Hooks[T], rows []map[string]string, hydrate func(data map[string]string) T, changed func(row map[string]string) map[string]string) {
+	for _, row := range rows {
+		hooks.runAfterUpdate(ctx, hydrate(row), changed(row))
+	}
+}
+
+func (hooks *Hooks[T]) runAfterDelete(ctx context.Context, id string) {
+	for _, hook := range registered(&hooks.mu, &hooks.afterDelete) {
+		hook(ctx, id)
+	}
+}
+
+func (hooks *Hooks[T]) runAfterSoftDelete(ctx context.Context, id string) {
+	for _, hook := range registered(&hooks.mu, &hooks.afterSoftDelete) {
+		hook(ctx, id)
+	}
+}
+
+// AddressHooks returns the lifecycle hooks of addresses
+func (store *Store) AddressHooks() *Hooks[AddressInterface] {
+	return &store.addressHooks
+}
+
+// CartHooks returns the lifecycle hooks of carts
+func (store *Store) CartHooks() *Hooks[CartInterface] {
+	return &store.cartHooks
+}
+
+// CategoryHooks returns the lifecycle hooks of categories
+func (store *Store) CategoryHooks() *Hooks[CategoryInterface] {
+	return &store.categoryHooks
+}
+
+// CustomerHooks returns the lifecycle hooks of customers
+func (store *Store) CustomerHooks() *Hooks[CustomerInterface] {
+	return &store.customerHooks
+}
+
+// DiscountHooks returns the lifecycle hooks of discounts
+func (store *Store) DiscountHooks() *Hooks[DiscountInterface] {
+	return &store.discountHooks
+}
+
+// MediaHooks returns the lifecycle hooks of media
+func (store *Store) MediaHooks() *Hooks[MediaInterface] {
+	return &store.mediaHooks
+}
+
+// OrderHooks returns the lifecycle hooks of orders
+func (store *Store) OrderHooks() *Hooks[OrderInterface] {
+	return &store.orderHooks
+}
+
+// OrderLineItemHooks returns the lifecycle hooks of order line items
+func (store *Store) OrderLineItemHooks() *Hooks[OrderLineItemInterface] {
+	return &store.orderLineItemHooks
+}
+
+// ProductHooks returns the lifecycle hooks of products
+func (store *Store) ProductHooks() *Hooks[ProductInterface] {
+	return &store.productHooks
+}
+
+// ShippingMethodHooks returns the lifecycle hooks of shipping methods
+func (store *Store) ShippingMethodHooks() *Hooks[ShippingMethodInterface] {
+	return &store.shippingMethodHooks
+}
+
+// ShippingZoneHooks returns the lifecycle hooks of shipping zones
+func (store *Store) ShippingZoneHooks() *Hooks[ShippingZoneInterface] {
+	return &store.shippingZoneHooks
+}
+
+// TaxRateHooks returns the lifecycle hooks of tax rates
+func (store *Store) TaxRateHooks() *Hooks[TaxRateInterface] {
+	return &store.taxRateHooks
+}
@@ -0,0 +1,233 @@
+package shopstore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStoreProductHooks(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+	events := []string{}
+
+	hooks := store.ProductHooks()
+	hooks.BeforeCreate(func(ctx context.Context, product ProductInterface) error {
+		product.SetMemo("set by hook")
+		events = append(events, "before create")
+		return nil
+	})
+	hooks.AfterCreate(func(ctx context.Context, product ProductInterface) {
+		events = append(events, "after create "+product.GetID())
+	})
+	hooks.BeforeUpdate(func(ctx context.Context, product ProductInterface, changed map[string]string) error {
+		events = append(events, "before update "+changed[COLUMN_TITLE])
+		return nil
+	})
+	hooks.AfterUpdate(func(ctx context.Context, product ProductInterface, changed map[string]string) {
+		events = append(events, "after update "+changed[COLUMN_TITLE])
+	})
+	hooks.AfterSoftDelete(func(ctx context.Context, id string) {
+		events = append(events, "after soft delete "+id)
+	})
+	hooks.AfterDelete(func(ctx context.Context, id string) {
+		events = append(events, "after delete "+id)
+	})
+
+	product := NewProduct().SetStatus(PRODUCT_STATUS_ACTIVE).SetTitle("Widget").SetQuantityInt(1).SetPriceFloat(10)
+	if err := store.ProductCreate(ctx, product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	found, err := store.ProductFindByID(ctx, product.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if found == nil || found.GetMemo() != "set by hook" {
+		t.Fatal("expected the BeforeCreate hook changes to be saved")
+	}
+
+	product.SetTitle("Gadget")
+	if err := store.ProductUpdate(ctx, product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.ProductSoftDelete(ctx, product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.ProductDeleteByID(ctx, product.GetID()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expected := []string{
+		"before create",
+		"after create " + product.GetID(),
+		"before update Gadget",
+		"after update Gadget",
+		"after soft delete " + product.GetID(),
+		"after delete " + product.GetID(),
+	}
+	if strings.Join(events, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected events %v, got %v", expected, events)
+	}
+}
+
+func TestStoreHooks_AfterHooksOfComputedWrites(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+	events := []string{}
+
+	store.ProductHooks().BeforeUpdate(func(ctx context.Context, product ProductInterface, changed map[string]string) error {
+		events = append(events, "before update product")
+		return nil
+	})
+	store.ProductHooks().AfterUpdate(func(ctx context.Context, product ProductInterface, changed map[string]string) {
+		events = append(events, "after update "+product.GetTitle()+" "+changed[COLUMN_TITLE]+changed[COLUMN_QUANTITY])
+	})
+	store.CartHooks().AfterUpdate(func(ctx context.Context, cart CartInterface, changed map[string]string) {
+		events = append(events, "after update cart "+changed[COLUMN_STATUS])
+	})
+	store.OrderHooks().AfterCreate(func(ctx context.Context, order OrderInterface) {
+		events = append(events, "after create order "+order.GetQuantity())
+	})
+	store.OrderLineItemHooks().AfterCreate(func(ctx context.Context, lineItem OrderLineItemInterface) {
+		events = append(events, "after create line item "+lineItem.GetQuantity())
+	})
+
+	product := createCartProduct(t, store, 10, 5)
+
+	if _, err := store.ProductUpdateMany(ctx, NewProductQuery().SetID(product.GetID()), map[string]string{COLUMN_TITLE: "Book"}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if _, err := store.ProductQuantityAdjust(ctx, product.GetID(), -1, ProductQuantityAdjustOptions{}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	cart := NewCart()
+	if err := store.CartCreate(ctx, cart); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if _, err := store.CartAddItem(ctx, cart.GetID(), product.GetID(), 2); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if _, err := store.CartCheckout(ctx, cart.GetID()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expected := []string{
+		"after update Book Book",
+		"after update Book 4",
+		"after create order 2",
+		"after create line item 2",
+		"after update cart " + CART_STATUS_CHECKED_OUT,
+		"after update Book 2",
+	}
+	if strings.Join(events, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected events %v, got %v", expected, events)
+	}
+}
+
+func TestStoreOrderHooks_BeforeHooksVeto(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+	errVetoed := errors.New("vetoed")
+
+	store.OrderHooks().BeforeCreate(func(ctx context.Context, order OrderInterface) error {
+		if order.GetStatus() == ORDER_STATUS_CANCELLED {
+			return errVetoed
+		}
+		return nil
+	})
+	store.OrderHooks().BeforeUpdate(func(ctx context.Context, order OrderInterface, changed map[string]string) error {
+		if _, ok := changed[COLUMN_PRICE]; ok {
+			return errVetoed
+		}
+		return nil
+	})
+
+	afterCreateCalls := 0
+	store.OrderHooks().AfterCreate(func(ctx context.Context, order OrderInterface) {
+		afterCreateCalls++
+	})
+
+	cancelled := NewOrder().SetCustomerID("CUSTOMER01_ID").SetStatus(ORDER_STATUS_CANCELLED)
+	if err := store.OrderCreate(ctx, cancelled); !errors.Is(err, errVetoed) {
+		t.Fatalf("expected the create to be vetoed, got %v", err)
+	}
+
+	count, err := store.OrderCount(ctx, NewOrderQuery())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if count != 0 || afterCreateCalls != 0 {
+		t.Fatalf("expected no order and no AfterCreate call, got %d orders and %d calls", count, afterCreateCalls)
+	}
+
+	order := NewOrder().SetCustomerID("CUSTOMER01_ID").SetStatus(ORDER_STATUS_PENDING).SetPriceFloat(10)
+	if err := store.OrderCreate(ctx, order); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	order.SetPriceFloat(1)
+	if err := store.OrderUpdate(ctx, order); !errors.Is(err, errVetoed) {
+		t.Fatalf("expected the update to be vetoed, got %v", err)
+	}
+
+	found, err := store.OrderFindByID(ctx, order.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if found.GetPriceFloat() != 10 {
+		t.Fatalf("expected the price to stay 10, got %v", found.GetPriceFloat())
+	}
+}
+
+func TestStoreHooks_HookRegisteringHooks(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+	created := []string{}
+
+	hooks := store.ProductHooks()
+	hooks.AfterCreate(func(ctx context.Context, product ProductInterface) {
+		if len(created) > 0 {
+			return
+		}
+
+		// registered while the hooks run, so only run from the next create on
+		hooks.AfterCreate(func(ctx context.Context, product ProductInterface) {
+			created = append(created, product.GetTitle())
+		})
+		created = append(created, "registered")
+	})
+
+	for _, title := range []string{"Widget", "Gadget"} {
+		if err := store.ProductCreate(ctx, NewProduct().SetTitle(title)); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	if strings.Join(created, ",") != "registered,Gadget" {
+		t.Fatalf("expected the hook registered by a hook run from the next create on, got %v", created)
+	}
+}
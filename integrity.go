@@ -0,0 +1,242 @@
+package shopstore
+
+import (
+	"context"
+	"fmt"
+
+	contractsschema "github.com/dracory/neat/contracts/database/schema"
+	"github.com/samber/lo"
+)
+
+// referenceCheck is a column holding the ID of a row in one of the tables
+type referenceCheck struct {
+	column     string
+	tableNames []string
+}
+
+// addressReferences lists the columns of an address referencing other entities
+func (store *Store) addressReferences() []referenceCheck {
+	return []referenceCheck{
+		{column: COLUMN_CUSTOMER_ID, tableNames: []string{store.customerTableName}},
+	}
+}
+
+// cartReferences lists the columns of a cart referencing other entities
+func (store *Store) cartReferences() []referenceCheck {
+	return []referenceCheck{
+		{column: COLUMN_CUSTOMER_ID, tableNames: []string{store.customerTableName}},
+		{column: COLUMN_DISCOUNT_ID, tableNames: []string{store.discountTableName}},
+	}
+}
+
+// categoryReferences lists the columns of a category referencing other entities
+func (store *Store) categoryReferences() []referenceCheck {
+	return []referenceCheck{
+		{column: COLUMN_PARENT_ID, tableNames: []string{store.categoryTableName}},
+	}
+}
+
+// mediaReferences lists the columns of a media referencing other entities.
+// Media can be attached to a category, an order or a product.
+func (store *Store) mediaReferences() []referenceCheck {
+	return []referenceCheck{
+		{column: COLUMN_ENTITY_ID, tableNames: []string{store.categoryTableName, store.orderTableName, store.productTableName}},
+	}
+}
+
+// orderLineItemReferences lists the columns of an order line item referencing other entities
+func (store *Store) orderLineItemReferences() []referenceCheck {
+	return []referenceCheck{
+		{column: COLUMN_ORDER_ID, tableNames: []string{store.orderTableName}},
+		{column: COLUMN_PRODUCT_ID, tableNames: []string{store.productTableName}},
+	}
+}
+
+// productReferences lists the columns of a product referencing other entities
+func (store *Store) productReferences() []referenceCheck {
+	return []referenceCheck{
+		{column: COLUMN_PARENT_ID, tableNames: []string{store.productTableName}},
+		{column: COLUMN_CATEGORY_ID, tableNames: []string{store.categoryTableName}},
+	}
+}
+
+// shippingMethodReferences lists the columns of a shipping method referencing other entities
+func (store *Store) shippingMethodReferences() []referenceCheck {
+	return []referenceCheck{
+		{column: COLUMN_SHIPPING_ZONE_ID, tableNames: []string{store.shippingZoneTableName}},
+	}
+}
+
+// assertReferencesExist checks, when referential integrity is enabled, that
+// the IDs held by the referencing columns present in data point at existing,
+// not soft deleted rows. Empty and "0" IDs mean "no reference" and are skipped.
+//
+// Pass the full data on create and only the changed data on update, so that
+// unchanged references are not checked again.
+func (store *Store) assertReferencesExist(ctx context.Context, data map[string]string, references []referenceCheck) error {
+	if !store.referentialIntegrityEnabled {
+		return nil
+	}
+
+	for _, reference := range references {
+		id, ok := data[reference.column]
+		if !ok || id == "" || id == "0" {
+			continue
+		}
+
+		exists, err := store.referenceExists(ctx, id, reference.tableNames)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			return fmt.Errorf("%w: %s %s", ErrReferenceNotFound, reference.column, id)
+		}
+	}
+
+	return nil
+}
+
+// assertManyReferencesExist is assertReferencesExist for the data of many
+// new rows of the table, checking each referenced ID once. References to
+// rows of the same batch (like variants of a new parent product) pass.
+func (store *Store) assertManyReferencesExist(ctx context.Context, tableName string, rows []map[string]string, references []referenceCheck) error {
+	if !store.referentialIntegrityEnabled {
+		return nil
+	}
+
+	batchIDs := lo.Keyify(lo.Map(rows, func(row map[string]string, _ int) string {
+		return row[COLUMN_ID]
+	}))
+
+	for _, reference := range references {
+		ids := lo.Uniq(lo.FilterMap(rows, func(row map[string]string, _ int) (string, bool) {
+			id := row[reference.column]
+			return id, id != "" && id != "0"
+		}))
+
+		if lo.Contains(reference.tableNames, tableName) {
+			ids = lo.Reject(ids, func(id string, _ int) bool {
+				_, ok := batchIDs[id]
+				return ok
+			})
+		}
+
+		for _, batch := range lo.Chunk(ids, bulkBatchSize) {
+			existing, err := store.existingReferences(ctx, batch, reference.tableNames)
+			if err != nil {
+				return err
+			}
+
+			for _, id := range batch {
+				if _, ok := existing[id]; !ok {
+					return fmt.Errorf("%w: %s %s", ErrReferenceNotFound, reference.column, id)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// existingReferences returns the IDs having a live row in any of the tables
+func (store *Store) existingReferences(ctx context.Context, ids []string, tableNames []string) (map[string]struct{}, error) {
+	existing := map[string]struct{}{}
+
+	for _, tableName := range tableNames {
+		var results []map[string]any
+
+		err := store.query(ctx).
+			Table(tableName).
+			Select([]string{COLUMN_ID}).
+			WhereIn(COLUMN_ID, lo.ToAnySlice(ids)).
+			Where(COLUMN_SOFT_DELETED_AT+" = ?", MAX_DATETIME).
+			Get(&results)
+		if err != nil {
+			return nil, store.operationError("reference check", err)
+		}
+
+		for _, result := range results {
+			existing[mapAnyToString(result)[COLUMN_ID]] = struct{}{}
+		}
+	}
+
+	return existing, nil
+}
+
+// referenceExists reports whether a live row with the ID exists in any of the tables
+func (store *Store) referenceExists(ctx context.Context, id string, tableNames []string) (bool, error) {
+	for _, tableName := range tableNames {
+		var count int64
+
+		err := store.query(ctx).
+			Table(tableName).
+			Where(COLUMN_ID+" = ?", id).
+			Where(COLUMN_SOFT_DELETED_AT+" = ?", MAX_DATETIME).
+			Count(&count)
+		if err != nil {
+			return false, store.operationError("reference check", err)
+		}
+
+		if count > 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// foreignKeyTable is a table and its references enforced by foreign keys
+type foreignKeyTable struct {
+	tableName  string
+	references []referenceCheck
+}
+
+// foreignKeyTables lists the references enforced by database foreign keys
+// when enabled: those always pointing at a single table. Media
+// (polymorphic), parent IDs (which use "0" for "no parent") and the
+// optional references stored as "" (cart customer and discount, product
+// category) cannot be enforced by the database and are left out.
+func (store *Store) foreignKeyTables() []foreignKeyTable {
+	return []foreignKeyTable{
+		{tableName: store.addressTableName, references: store.addressReferences()},
+		{tableName: store.cartItemTableName, references: []referenceCheck{
+			{column: COLUMN_CART_ID, tableNames: []string{store.cartTableName}},
+		}},
+		{tableName: store.orderLineItemTableName, references: store.orderLineItemReferences()},
+		{tableName: store.shippingMethodTableName, references: store.shippingMethodReferences()},
+	}
+}
+
+// foreignKeysCreate adds the database foreign keys listed by
+// foreignKeyTables, which are missing.
+//
+// Dialects not able to add a foreign key to an existing table, like SQLite,
+// are skipped by the schema builder.
+func (store *Store) foreignKeysCreate(schema contractsschema.Schema) error {
+	for _, foreignKeyTable := range store.foreignKeyTables() {
+		foreignKeys, err := schema.GetForeignKeys(foreignKeyTable.tableName)
+		if err != nil {
+			return err
+		}
+
+		existing := lo.FlatMap(foreignKeys, func(foreignKey contractsschema.ForeignKey, _ int) []string {
+			return foreignKey.Columns
+		})
+
+		for _, reference := range foreignKeyTable.references {
+			if lo.Contains(existing, reference.column) {
+				continue
+			}
+
+			err := schema.Table(foreignKeyTable.tableName, func(table contractsschema.Blueprint) {
+				table.Foreign(reference.column).References(COLUMN_ID).On(reference.tableNames[0])
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package shopstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	contractsschema "github.com/dracory/neat/contracts/database/schema"
+	"github.com/samber/lo"
+)
+
+func initIntegrityStore(t *testing.T) *Store {
+	db, err := initDB(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	options := testStoreOptions(db)
+	options.ReferentialIntegrityEnabled = true
+	options.ForeignKeysEnabled = true
+
+	store, err := NewStore(options)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	return store
+}
+
+func TestStoreReferentialIntegrity_Product(t *testing.T) {
+	store := initIntegrityStore(t)
+	ctx := context.Background()
+
+	err := store.ProductCreate(ctx, NewProduct().SetParentID("MISSING"))
+	if !errors.Is(err, ErrReferenceNotFound) {
+		t.Fatalf("expected ErrReferenceNotFound, got %v", err)
+	}
+
+	parent := NewProduct()
+	if err := store.ProductCreate(ctx, parent); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	variant := NewProduct().SetParentID(parent.GetID())
+	if err := store.ProductCreate(ctx, variant); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	variant.SetParentID("MISSING")
+	if err := store.ProductUpdate(ctx, variant); !errors.Is(err, ErrReferenceNotFound) {
+		t.Fatalf("expected ErrReferenceNotFound on update, got %v", err)
+	}
+
+	err = store.ProductCreate(ctx, NewProduct().SetCategoryID("MISSING"))
+	if !errors.Is(err, ErrReferenceNotFound) {
+		t.Fatalf("expected ErrReferenceNotFound for the category, got %v", err)
+	}
+}
+
+func TestStoreReferentialIntegrity_SoftDeletedReference(t *testing.T) {
+	store := initIntegrityStore(t)
+	ctx := context.Background()
+
+	order := NewOrder().SetCustomerID("CUSTOMER01_ID")
+	if err := store.OrderCreate(ctx, order); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	product := NewProduct()
+	if err := store.ProductCreate(ctx, product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	lineItem := NewOrderLineItem().SetOrderID(order.GetID()).SetProductID(product.GetID())
+	if err := store.OrderLineItemCreate(ctx, lineItem); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	deleted := NewProduct()
+	if err := store.ProductCreate(ctx, deleted); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.ProductSoftDelete(ctx, deleted); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	err := store.OrderLineItemCreate(ctx, NewOrderLineItem().SetOrderID(order.GetID()).SetProductID(deleted.GetID()))
+	if !errors.Is(err, ErrReferenceNotFound) {
+		t.Fatalf("expected ErrReferenceNotFound for a soft deleted product, got %v", err)
+	}
+}
+
+func TestStoreReferentialIntegrity_Media(t *testing.T) {
+	store := initIntegrityStore(t)
+	ctx := context.Background()
+
+	category := NewCategory().SetTitle("CATEGORY_TITLE")
+	if err := store.CategoryCreate(ctx, category); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.MediaCreate(ctx, newTestMedia().SetEntityID(category.GetID())); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	err := store.MediaCreate(ctx, newTestMedia().SetEntityID("MISSING"))
+	if !errors.Is(err, ErrReferenceNotFound) {
+		t.Fatalf("expected ErrReferenceNotFound, got %v", err)
+	}
+
+	err = store.CategoryCreate(ctx, NewCategory().SetTitle("CATEGORY_TITLE").SetParentID("MISSING"))
+	if !errors.Is(err, ErrReferenceNotFound) {
+		t.Fatalf("expected ErrReferenceNotFound, got %v", err)
+	}
+}
+
+func TestStoreReferentialIntegrity_DisabledByDefault(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.ProductCreate(context.Background(), NewProduct().SetParentID("MISSING")); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+}
+
+func newTestMedia() MediaInterface {
+	return NewMedia().
+		SetURL("https://example.com/image.jpg").
+		SetType(MEDIA_TYPE_IMAGE_JPG).
+		SetSequence(1)
+}
+
+func TestStoreForeignKeysCreate(t *testing.T) {
+	store := initIntegrityStore(t)
+
+	if store.dialect == dialectSQLite {
+		t.Skip("SQLite can not add foreign keys to existing tables")
+	}
+
+	for _, foreignKeyTable := range store.foreignKeyTables() {
+		foreignKeys, err := store.db.Schema().GetForeignKeys(foreignKeyTable.tableName)
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		columns := lo.FlatMap(foreignKeys, func(foreignKey contractsschema.ForeignKey, _ int) []string {
+			return foreignKey.Columns
+		})
+
+		for _, reference := range foreignKeyTable.references {
+			if !lo.Contains(columns, reference.column) {
+				t.Fatalf("expected a foreign key on %s.%s, got %v", foreignKeyTable.tableName, reference.column, columns)
+			}
+		}
+	}
+}
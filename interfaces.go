@@ -3,11 +3,397 @@ package shopstore
 import (
 	"context"
 	"database/sql"
+	"io"
+	"iter"
 	"log/slog"
+	"time"
 
 	"github.com/dromara/carbon/v2"
 )
 
+// AddressInterface defines the contract for the address entities of the
+// customer address books. Addresses support default shipping and billing
+// flags, soft deletion, and metadata storage.
+type AddressInterface interface {
+	// DataObject methods
+
+	// Data returns a map of all field values for serialization.
+	Data() map[string]string
+	// DataChanged returns a map of only the fields that have been modified since load.
+	DataChanged() map[string]string
+	// MarkAsNotDirty resets the dirty state, clearing all change tracking.
+	MarkAsNotDirty()
+
+	// Setters and Getters
+
+	// GetCity returns the city.
+	GetCity() string
+	// SetCity sets the city.
+	SetCity(city string) AddressInterface
+
+	// GetCountryCode returns the ISO 3166-1 alpha-2 country code.
+	GetCountryCode() string
+	// SetCountryCode sets the ISO 3166-1 alpha-2 country code, trimmed and uppercased.
+	SetCountryCode(countryCode string) AddressInterface
+
+	// GetCreatedAt returns the creation timestamp as a string.
+	GetCreatedAt() string
+	// GetCreatedAtCarbon returns the creation timestamp as a Carbon instance.
+	GetCreatedAtCarbon() *carbon.Carbon
+	// SetCreatedAt sets the creation timestamp.
+	SetCreatedAt(createdAt string) AddressInterface
+
+	// GetCustomerID returns the ID of the customer the address belongs to.
+	GetCustomerID() string
+	// SetCustomerID sets the ID of the customer the address belongs to.
+	SetCustomerID(customerID string) AddressInterface
+
+	// GetID returns the unique identifier.
+	GetID() string
+	// SetID sets the unique identifier.
+	SetID(id string) AddressInterface
+
+	// IsDefaultBilling returns true if this is the default billing address of the customer.
+	IsDefaultBilling() bool
+	// SetIsDefaultBilling sets whether this is the default billing address of the customer.
+	SetIsDefaultBilling(isDefault bool) AddressInterface
+
+	// IsDefaultShipping returns true if this is the default shipping address of the customer.
+	IsDefaultShipping() bool
+	// SetIsDefaultShipping sets whether this is the default shipping address of the customer.
+	SetIsDefaultShipping(isDefault bool) AddressInterface
+
+	// GetLine1 returns the first address line.
+	GetLine1() string
+	// SetLine1 sets the first address line.
+	SetLine1(line1 string) AddressInterface
+
+	// GetLine2 returns the second address line.
+	GetLine2() string
+	// SetLine2 sets the second address line.
+	SetLine2(line2 string) AddressInterface
+
+	// GetMemo returns the internal memo.
+	GetMemo() string
+	// SetMemo sets the internal memo.
+	SetMemo(memo string) AddressInterface
+
+	// GetMeta returns a specific metadata value by name.
+	GetMeta(name string) string
+	// GetMetas returns all metadata as a map.
+	GetMetas() (map[string]string, error)
+	// SetMeta sets a single metadata value.
+	SetMeta(name string, value string) error
+	// SetMetas replaces all metadata with the provided map.
+	SetMetas(metas map[string]string) error
+	// MetasUpsert merges the provided metadata with existing values.
+	MetasUpsert(metas map[string]string) error
+	// MetaRemove removes a single metadata entry.
+	MetaRemove(name string) error
+	// MetasRemove removes multiple metadata entries.
+	MetasRemove(names []string) error
+
+	// GetName returns the name of the recipient.
+	GetName() string
+	// SetName sets the name of the recipient.
+	SetName(name string) AddressInterface
+
+	// GetPhone returns the phone number of the recipient.
+	GetPhone() string
+	// SetPhone sets the phone number of the recipient.
+	SetPhone(phone string) AddressInterface
+
+	// GetPostalCode returns the postal code.
+	GetPostalCode() string
+	// SetPostalCode sets the postal code.
+	SetPostalCode(postalCode string) AddressInterface
+
+	// GetRegion returns the region, like a state, province or county.
+	GetRegion() string
+	// SetRegion sets the region.
+	SetRegion(region string) AddressInterface
+
+	// GetSoftDeletedAt returns the soft deletion timestamp.
+	GetSoftDeletedAt() string
+	// GetSoftDeletedAtCarbon returns the soft deletion timestamp as a Carbon instance.
+	GetSoftDeletedAtCarbon() *carbon.Carbon
+	// SetSoftDeletedAt sets the soft deletion timestamp.
+	SetSoftDeletedAt(deletedAt string) AddressInterface
+
+	// GetUpdatedAt returns the last update timestamp.
+	GetUpdatedAt() string
+	// GetUpdatedAtCarbon returns the last update timestamp as a Carbon instance.
+	GetUpdatedAtCarbon() *carbon.Carbon
+	// SetUpdatedAt sets the last update timestamp.
+	SetUpdatedAt(updatedAt string) AddressInterface
+
+	// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+	GetVersion() int64
+	// SetVersion sets the version of the row the entity was loaded at.
+	SetVersion(version int64) AddressInterface
+
+	// IsSoftDeleted returns true if the address is soft deleted.
+	IsSoftDeleted() bool
+
+	// ToOrderAddress returns the snapshot of the address to store on an order.
+	ToOrderAddress() OrderAddress
+}
+
+// AuditLogInterface defines the contract for audit log entries. An entry
+// is written in the same transaction as the change it records.
+type AuditLogInterface interface {
+	// DataObject methods
+
+	// Data returns a map of all field values for serialization.
+	Data() map[string]string
+	// DataChanged returns a map of only the fields that have been modified since load.
+	DataChanged() map[string]string
+	// MarkAsNotDirty resets the dirty state, clearing all change tracking.
+	MarkAsNotDirty()
+
+	// Setters and Getters
+
+	// GetActorID returns the ID of the actor who made the change, empty if unknown.
+	GetActorID() string
+	// SetActorID sets the ID of the actor who made the change.
+	SetActorID(actorID string) AuditLogInterface
+
+	// GetCreatedAt returns the time of the change as a string.
+	GetCreatedAt() string
+	// SetCreatedAt sets the time of the change.
+	SetCreatedAt(createdAt string) AuditLogInterface
+
+	// GetEntityID returns the ID of the changed entity.
+	GetEntityID() string
+	// SetEntityID sets the ID of the changed entity.
+	SetEntityID(entityID string) AuditLogInterface
+
+	// GetEntityType returns the type of the changed entity (e.g. "product").
+	GetEntityType() string
+	// SetEntityType sets the type of the changed entity.
+	SetEntityType(entityType string) AuditLogInterface
+
+	// GetID returns the unique identifier.
+	GetID() string
+	// SetID sets the unique identifier.
+	SetID(id string) AuditLogInterface
+
+	// GetOperation returns the operation, one of the AUDIT_OPERATION_ constants.
+	GetOperation() string
+	// SetOperation sets the operation.
+	SetOperation(operation string) AuditLogInterface
+
+	// GetValuesAfter returns the changed fields after the change as a JSON string.
+	GetValuesAfter() string
+	// GetValuesAfterMap returns the changed fields after the change decoded as a map.
+	GetValuesAfterMap() (map[string]string, error)
+	// SetValuesAfter sets the changed fields after the change as a JSON string.
+	SetValuesAfter(values string) AuditLogInterface
+	// SetValuesAfterMap sets the changed fields after the change from a map, stored as JSON.
+	SetValuesAfterMap(values map[string]string) error
+
+	// GetValuesBefore returns the changed fields before the change as a JSON string.
+	GetValuesBefore() string
+	// GetValuesBeforeMap returns the changed fields before the change decoded as a map.
+	GetValuesBeforeMap() (map[string]string, error)
+	// SetValuesBefore sets the changed fields before the change as a JSON string.
+	SetValuesBefore(values string) AuditLogInterface
+	// SetValuesBeforeMap sets the changed fields before the change from a map, stored as JSON.
+	SetValuesBeforeMap(values map[string]string) error
+}
+
+// CartInterface defines the contract for cart entities. Carts belong to a
+// customer, or to a guest while their customer ID is empty, and are
+// converted into orders by CartCheckout.
+type CartInterface interface {
+	// DataObject methods
+
+	// Data returns a map of all field values for serialization.
+	Data() map[string]string
+	// DataChanged returns a map of only the fields that have been modified since load.
+	DataChanged() map[string]string
+	// MarkAsNotDirty resets the dirty state, clearing all change tracking.
+	MarkAsNotDirty()
+
+	// Setters and Getters
+
+	// GetCreatedAt returns the creation timestamp as a string.
+	GetCreatedAt() string
+	// GetCreatedAtCarbon returns the creation timestamp as a Carbon instance.
+	GetCreatedAtCarbon() *carbon.Carbon
+	// SetCreatedAt sets the creation timestamp.
+	SetCreatedAt(createdAt string) CartInterface
+
+	// GetCustomerID returns the ID of the customer, empty for a guest cart.
+	GetCustomerID() string
+	// SetCustomerID sets the ID of the customer, empty for a guest cart.
+	SetCustomerID(customerID string) CartInterface
+
+	// GetDiscountID returns the ID of the discount applied to the cart.
+	GetDiscountID() string
+	// SetDiscountID sets the ID of the discount applied to the cart.
+	SetDiscountID(discountID string) CartInterface
+
+	// GetID returns the unique identifier.
+	GetID() string
+	// SetID sets the unique identifier.
+	SetID(id string) CartInterface
+
+	// GetMemo returns the internal memo.
+	GetMemo() string
+	// SetMemo sets the internal memo.
+	SetMemo(memo string) CartInterface
+
+	// GetMeta returns a specific metadata value by name.
+	GetMeta(name string) string
+	// GetMetas returns all metadata as a map.
+	GetMetas() (map[string]string, error)
+	// SetMeta sets a single metadata value.
+	SetMeta(name string, value string) error
+	// SetMetas replaces all metadata with the provided map.
+	SetMetas(metas map[string]string) error
+	// MetasUpsert merges the provided metadata with existing values.
+	MetasUpsert(metas map[string]string) error
+	// MetaRemove removes a single metadata entry.
+	MetaRemove(name string) error
+	// MetasRemove removes multiple metadata entries.
+	MetasRemove(names []string) error
+
+	// GetOrderID returns the ID of the order the cart was checked out as.
+	GetOrderID() string
+	// SetOrderID sets the ID of the order the cart was checked out as.
+	SetOrderID(orderID string) CartInterface
+
+	// GetSoftDeletedAt returns the soft deletion timestamp.
+	GetSoftDeletedAt() string
+	// GetSoftDeletedAtCarbon returns the soft deletion timestamp as a Carbon instance.
+	GetSoftDeletedAtCarbon() *carbon.Carbon
+	// SetSoftDeletedAt sets the soft deletion timestamp.
+	SetSoftDeletedAt(deletedAt string) CartInterface
+
+	// GetStatus returns the current status.
+	GetStatus() string
+	// SetStatus sets the current status.
+	SetStatus(status string) CartInterface
+
+	// GetUpdatedAt returns the last update timestamp.
+	GetUpdatedAt() string
+	// GetUpdatedAtCarbon returns the last update timestamp as a Carbon instance.
+	GetUpdatedAtCarbon() *carbon.Carbon
+	// SetUpdatedAt sets the last update timestamp.
+	SetUpdatedAt(updatedAt string) CartInterface
+
+	// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+	GetVersion() int64
+	// SetVersion sets the version of the row the entity was loaded at.
+	SetVersion(version int64) CartInterface
+
+	// Status predicates
+
+	// IsActive returns true if the cart status is active.
+	IsActive() bool
+	// IsCheckedOut returns true if the cart was converted into an order.
+	IsCheckedOut() bool
+	// IsExpired returns true if the cart was abandoned and expired.
+	IsExpired() bool
+	// IsGuest returns true if the cart does not belong to a customer.
+	IsGuest() bool
+	// IsMerged returns true if the cart was merged into the cart of a customer.
+	IsMerged() bool
+	// IsSoftDeleted returns true if the cart is soft deleted.
+	IsSoftDeleted() bool
+}
+
+// CartItemInterface defines the contract for the items of a cart, holding
+// a snapshot of the title and unit price of their product.
+type CartItemInterface interface {
+	// DataObject methods
+
+	// Data returns a map of all field values for serialization.
+	Data() map[string]string
+	// DataChanged returns a map of only the fields that have been modified since load.
+	DataChanged() map[string]string
+	// MarkAsNotDirty resets the dirty state, clearing all change tracking.
+	MarkAsNotDirty()
+
+	// Setters and Getters
+
+	// GetCartID returns the ID of the cart the item belongs to.
+	GetCartID() string
+	// SetCartID sets the ID of the cart the item belongs to.
+	SetCartID(cartID string) CartItemInterface
+
+	// GetCreatedAt returns the creation timestamp as a string.
+	GetCreatedAt() string
+	// GetCreatedAtCarbon returns the creation timestamp as a Carbon instance.
+	GetCreatedAtCarbon() *carbon.Carbon
+	// SetCreatedAt sets the creation timestamp.
+	SetCreatedAt(createdAt string) CartItemInterface
+
+	// GetID returns the unique identifier.
+	GetID() string
+	// SetID sets the unique identifier.
+	SetID(id string) CartItemInterface
+
+	// GetMeta returns a specific metadata value by name.
+	GetMeta(name string) string
+	// GetMetas returns all metadata as a map.
+	GetMetas() (map[string]string, error)
+	// SetMeta sets a single metadata value.
+	SetMeta(name string, value string) error
+	// SetMetas replaces all metadata with the provided map.
+	SetMetas(metas map[string]string) error
+	// MetasUpsert merges the provided metadata with existing values.
+	MetasUpsert(metas map[string]string) error
+	// MetaRemove removes a single metadata entry.
+	MetaRemove(name string) error
+	// MetasRemove removes multiple metadata entries.
+	MetasRemove(names []string) error
+
+	// GetPrice returns the unit price snapshot as a string.
+	GetPrice() string
+	// SetPrice sets the unit price snapshot from a string.
+	SetPrice(price string) CartItemInterface
+	// GetPriceFloat returns the unit price snapshot as a float64.
+	GetPriceFloat() float64
+	// SetPriceFloat sets the unit price snapshot from a float64.
+	SetPriceFloat(price float64) CartItemInterface
+
+	// GetProductID returns the ID of the product, or of the product variant.
+	GetProductID() string
+	// SetProductID sets the ID of the product, or of the product variant.
+	SetProductID(productID string) CartItemInterface
+
+	// GetQuantity returns the quantity as a string.
+	GetQuantity() string
+	// SetQuantity sets the quantity from a string.
+	SetQuantity(quantity string) CartItemInterface
+	// GetQuantityInt returns the quantity as an int64.
+	GetQuantityInt() int64
+	// SetQuantityInt sets the quantity from an int64.
+	SetQuantityInt(quantity int64) CartItemInterface
+
+	// GetTitle returns the product title snapshot.
+	GetTitle() string
+	// SetTitle sets the product title snapshot.
+	SetTitle(title string) CartItemInterface
+
+	// GetUpdatedAt returns the last update timestamp.
+	GetUpdatedAt() string
+	// GetUpdatedAtCarbon returns the last update timestamp as a Carbon instance.
+	GetUpdatedAtCarbon() *carbon.Carbon
+	// SetUpdatedAt sets the last update timestamp.
+	SetUpdatedAt(updatedAt string) CartItemInterface
+
+	// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+	GetVersion() int64
+	// SetVersion sets the version of the row the entity was loaded at.
+	SetVersion(version int64) CartItemInterface
+
+	// GetSubtotalFloat returns the unit price times the quantity, rounded to cents.
+	GetSubtotalFloat() float64
+}
+
 // CategoryInterface defines the contract for category entities in the shop store.
 // Categories support hierarchical structures (parent-child relationships),
 // soft deletion, metadata storage, and status management.
@@ -80,32 +466,133 @@ type CategoryInterface interface {
 	// GetSoftDeletedAtCarbon returns the soft deletion timestamp as a Carbon instance.
 	GetSoftDeletedAtCarbon() *carbon.Carbon
 	// SetSoftDeletedAt sets the soft deletion timestamp.
-	SetSoftDeletedAt(deletedAt string) CategoryInterface
+	SetSoftDeletedAt(deletedAt string) CategoryInterface
+
+	// GetUpdatedAt returns the last update timestamp.
+	GetUpdatedAt() string
+	// GetUpdatedAtCarbon returns the last update timestamp as a Carbon instance.
+	GetUpdatedAtCarbon() *carbon.Carbon
+	// SetUpdatedAt sets the last update timestamp.
+	SetUpdatedAt(updatedAt string) CategoryInterface
+
+	// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+	GetVersion() int64
+	// SetVersion sets the version of the row the entity was loaded at.
+	SetVersion(version int64) CategoryInterface
+
+	// Status predicates
+
+	// IsActive returns true if status is active.
+	IsActive() bool
+	// IsDraft returns true if status is draft.
+	IsDraft() bool
+	// IsInactive returns true if status is inactive.
+	IsInactive() bool
+	// IsSoftDeleted returns true if the category is soft deleted.
+	IsSoftDeleted() bool
+
+	// Hierarchy predicates
+
+	// IsRoot returns true if this is a root category (no parent).
+	IsRoot() bool
+	// IsChild returns true if this category has a parent.
+	IsChild() bool
+}
+
+// CustomerInterface defines the contract for customer entities, referenced
+// by the customer_id of orders. Customers support soft deletion, metadata
+// storage, and status management.
+type CustomerInterface interface {
+	// DataObject methods
+
+	// Data returns a map of all field values for serialization.
+	Data() map[string]string
+	// DataChanged returns a map of only the fields that have been modified since load.
+	DataChanged() map[string]string
+	// MarkAsNotDirty resets the dirty state, clearing all change tracking.
+	MarkAsNotDirty()
+
+	// Setters and Getters
+
+	// GetCreatedAt returns the creation timestamp as a string.
+	GetCreatedAt() string
+	// GetCreatedAtCarbon returns the creation timestamp as a Carbon instance.
+	GetCreatedAtCarbon() *carbon.Carbon
+	// SetCreatedAt sets the creation timestamp.
+	SetCreatedAt(createdAt string) CustomerInterface
+
+	// GetEmail returns the email address, unique among the live customers.
+	GetEmail() string
+	// SetEmail sets the email address, trimmed and lowercased.
+	SetEmail(email string) CustomerInterface
+
+	// GetID returns the unique identifier.
+	GetID() string
+	// SetID sets the unique identifier.
+	SetID(id string) CustomerInterface
+
+	// GetMemo returns the internal memo.
+	GetMemo() string
+	// SetMemo sets the internal memo.
+	SetMemo(memo string) CustomerInterface
+
+	// GetMeta returns a specific metadata value by name.
+	GetMeta(name string) string
+	// GetMetas returns all metadata as a map.
+	GetMetas() (map[string]string, error)
+	// SetMeta sets a single metadata value.
+	SetMeta(name string, value string) error
+	// SetMetas replaces all metadata with the provided map.
+	SetMetas(metas map[string]string) error
+	// MetasUpsert merges the provided metadata with existing values.
+	MetasUpsert(metas map[string]string) error
+	// MetaRemove removes a single metadata entry.
+	MetaRemove(name string) error
+	// MetasRemove removes multiple metadata entries.
+	MetasRemove(names []string) error
+
+	// GetName returns the full name.
+	GetName() string
+	// SetName sets the full name.
+	SetName(name string) CustomerInterface
+
+	// GetPhone returns the phone number.
+	GetPhone() string
+	// SetPhone sets the phone number.
+	SetPhone(phone string) CustomerInterface
+
+	// GetSoftDeletedAt returns the soft deletion timestamp.
+	GetSoftDeletedAt() string
+	// GetSoftDeletedAtCarbon returns the soft deletion timestamp as a Carbon instance.
+	GetSoftDeletedAtCarbon() *carbon.Carbon
+	// SetSoftDeletedAt sets the soft deletion timestamp.
+	SetSoftDeletedAt(deletedAt string) CustomerInterface
+
+	// GetStatus returns the current status.
+	GetStatus() string
+	// SetStatus sets the current status.
+	SetStatus(status string) CustomerInterface
 
 	// GetUpdatedAt returns the last update timestamp.
 	GetUpdatedAt() string
 	// GetUpdatedAtCarbon returns the last update timestamp as a Carbon instance.
 	GetUpdatedAtCarbon() *carbon.Carbon
 	// SetUpdatedAt sets the last update timestamp.
-	SetUpdatedAt(updatedAt string) CategoryInterface
+	SetUpdatedAt(updatedAt string) CustomerInterface
+
+	// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+	GetVersion() int64
+	// SetVersion sets the version of the row the entity was loaded at.
+	SetVersion(version int64) CustomerInterface
 
 	// Status predicates
 
 	// IsActive returns true if status is active.
 	IsActive() bool
-	// IsDraft returns true if status is draft.
-	IsDraft() bool
 	// IsInactive returns true if status is inactive.
 	IsInactive() bool
-	// IsSoftDeleted returns true if the category is soft deleted.
+	// IsSoftDeleted returns true if the customer is soft deleted.
 	IsSoftDeleted() bool
-
-	// Hierarchy predicates
-
-	// IsRoot returns true if this is a root category (no parent).
-	IsRoot() bool
-	// IsChild returns true if this category has a parent.
-	IsChild() bool
 }
 
 // DiscountInterface defines the contract for discount/promotion entities.
@@ -213,6 +700,11 @@ type DiscountInterface interface {
 	// SetUpdatedAt sets the last update timestamp.
 	SetUpdatedAt(updatedAt string) DiscountInterface
 
+	// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+	GetVersion() int64
+	// SetVersion sets the version of the row the entity was loaded at.
+	SetVersion(version int64) DiscountInterface
+
 	// Status predicates
 
 	// IsActive returns true if status is active.
@@ -330,6 +822,11 @@ type MediaInterface interface {
 	// SetUpdatedAt sets the last update timestamp.
 	SetUpdatedAt(updatedAt string) MediaInterface
 
+	// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+	GetVersion() int64
+	// SetVersion sets the version of the row the entity was loaded at.
+	SetVersion(version int64) MediaInterface
+
 	// Status predicates
 
 	// IsActive returns true if status is active.
@@ -349,6 +846,24 @@ type MediaInterface interface {
 	IsVideo() bool
 }
 
+// OrderAddress is the snapshot of an address stored on an order, as JSON.
+// It is a copy, so editing the address book never alters past orders.
+type OrderAddress struct {
+	Name        string `json:"name,omitempty"`
+	Line1       string `json:"line1,omitempty"`
+	Line2       string `json:"line2,omitempty"`
+	City        string `json:"city,omitempty"`
+	Region      string `json:"region,omitempty"`
+	PostalCode  string `json:"postal_code,omitempty"`
+	CountryCode string `json:"country_code,omitempty"`
+	Phone       string `json:"phone,omitempty"`
+}
+
+// IsEmpty returns true if no field of the address is set.
+func (address OrderAddress) IsEmpty() bool {
+	return address == OrderAddress{}
+}
+
 // OrderInterface defines the contract for order entities.
 // Orders track customer purchases with status workflow, pricing, quantity management,
 // soft deletion, and metadata storage. Supports various order states from pending to completed.
@@ -391,6 +906,11 @@ type OrderInterface interface {
 
 	// Setters and Getters
 
+	// GetBillingAddress returns the snapshot of the billing address.
+	GetBillingAddress() (OrderAddress, error)
+	// SetBillingAddress sets the snapshot of the billing address.
+	SetBillingAddress(address OrderAddress) error
+
 	// GetCreatedAt returns the creation timestamp as a string.
 	GetCreatedAt() string
 	// GetCreatedAtCarbon returns the creation timestamp as a Carbon instance.
@@ -403,6 +923,20 @@ type OrderInterface interface {
 	// SetCustomerID sets the customer ID.
 	SetCustomerID(customerID string) OrderInterface
 
+	// GetDiscountAmount returns the amount taken off the order by its discount.
+	GetDiscountAmount() string
+	// SetDiscountAmount sets the amount taken off the order by its discount.
+	SetDiscountAmount(amount string) OrderInterface
+	// GetDiscountAmountFloat returns the discount amount as a float64.
+	GetDiscountAmountFloat() float64
+	// SetDiscountAmountFloat sets the discount amount from a float64.
+	SetDiscountAmountFloat(amount float64) OrderInterface
+
+	// GetDiscountID returns the ID of the discount redeemed by the order.
+	GetDiscountID() string
+	// SetDiscountID sets the ID of the discount redeemed by the order.
+	SetDiscountID(discountID string) OrderInterface
+
 	// GetID returns the unique identifier.
 	GetID() string
 	// SetID sets the unique identifier.
@@ -437,6 +971,11 @@ type OrderInterface interface {
 	// SetPriceFloat sets the price from a float64.
 	SetPriceFloat(price float64) OrderInterface
 
+	// PricesIncludeTax returns true if the prices of the order and its line items include the tax.
+	PricesIncludeTax() bool
+	// SetPricesIncludeTax sets whether the prices of the order and its line items include the tax.
+	SetPricesIncludeTax(pricesIncludeTax bool) OrderInterface
+
 	// GetQuantity returns the quantity as a string.
 	GetQuantity() string
 	// SetQuantity sets the quantity from a string.
@@ -446,6 +985,30 @@ type OrderInterface interface {
 	// SetQuantityInt sets the quantity from an int64.
 	SetQuantityInt(quantity int64) OrderInterface
 
+	// GetShippingAddress returns the snapshot of the shipping address.
+	GetShippingAddress() (OrderAddress, error)
+	// SetShippingAddress sets the snapshot of the shipping address.
+	SetShippingAddress(address OrderAddress) error
+
+	// GetShippingAmount returns the shipping cost of the order as a string.
+	GetShippingAmount() string
+	// SetShippingAmount sets the shipping cost of the order from a string.
+	SetShippingAmount(shippingAmount string) OrderInterface
+	// GetShippingAmountFloat returns the shipping cost of the order as a float64.
+	GetShippingAmountFloat() float64
+	// SetShippingAmountFloat sets the shipping cost of the order from a float64.
+	SetShippingAmountFloat(shippingAmount float64) OrderInterface
+
+	// GetShippingMethodID returns the ID of the shipping method chosen for the order, empty if none is.
+	GetShippingMethodID() string
+	// SetShippingMethodID sets the ID of the shipping method chosen for the order.
+	SetShippingMethodID(shippingMethodID string) OrderInterface
+
+	// GetShippingMethodName returns the name of the chosen shipping method, as it was when chosen.
+	GetShippingMethodName() string
+	// SetShippingMethodName sets the name of the chosen shipping method.
+	SetShippingMethodName(shippingMethodName string) OrderInterface
+
 	// GetSoftDeletedAt returns the soft deletion timestamp.
 	GetSoftDeletedAt() string
 	// GetSoftDeletedAtCarbon returns the soft deletion timestamp as a Carbon instance.
@@ -458,12 +1021,26 @@ type OrderInterface interface {
 	// SetStatus sets the current status.
 	SetStatus(status string) OrderInterface
 
+	// GetTaxAmount returns the total tax of the line items as a string.
+	GetTaxAmount() string
+	// SetTaxAmount sets the total tax of the line items from a string.
+	SetTaxAmount(taxAmount string) OrderInterface
+	// GetTaxAmountFloat returns the total tax of the line items as a float64.
+	GetTaxAmountFloat() float64
+	// SetTaxAmountFloat sets the total tax of the line items from a float64.
+	SetTaxAmountFloat(taxAmount float64) OrderInterface
+
 	// GetUpdatedAt returns the last update timestamp.
 	GetUpdatedAt() string
 	// GetUpdatedAtCarbon returns the last update timestamp as a Carbon instance.
 	GetUpdatedAtCarbon() *carbon.Carbon
 	// SetUpdatedAt sets the last update timestamp.
 	SetUpdatedAt(updatedAt string) OrderInterface
+
+	// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+	GetVersion() int64
+	// SetVersion sets the version of the row the entity was loaded at.
+	SetVersion(version int64) OrderInterface
 }
 
 // OrderLineItemInterface defines the contract for order line item entities.
@@ -553,6 +1130,24 @@ type OrderLineItemInterface interface {
 	// SetStatus sets the current status.
 	SetStatus(status string) OrderLineItemInterface
 
+	// GetTaxAmount returns the tax of the whole line as a string.
+	GetTaxAmount() string
+	// SetTaxAmount sets the tax of the whole line from a string.
+	SetTaxAmount(taxAmount string) OrderLineItemInterface
+	// GetTaxAmountFloat returns the tax of the whole line as a float64.
+	GetTaxAmountFloat() float64
+	// SetTaxAmountFloat sets the tax of the whole line from a float64.
+	SetTaxAmountFloat(taxAmount float64) OrderLineItemInterface
+
+	// GetTaxRate returns the tax rate applied to the line, in percent, as a string.
+	GetTaxRate() string
+	// SetTaxRate sets the tax rate applied to the line, in percent, from a string.
+	SetTaxRate(taxRate string) OrderLineItemInterface
+	// GetTaxRateFloat returns the tax rate applied to the line, in percent, as a float64.
+	GetTaxRateFloat() float64
+	// SetTaxRateFloat sets the tax rate applied to the line, in percent, from a float64.
+	SetTaxRateFloat(taxRate float64) OrderLineItemInterface
+
 	// GetTitle returns the line item title.
 	GetTitle() string
 	// SetTitle sets the line item title.
@@ -565,6 +1160,11 @@ type OrderLineItemInterface interface {
 	// SetUpdatedAt sets the last update timestamp.
 	SetUpdatedAt(updatedAt string) OrderLineItemInterface
 
+	// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+	GetVersion() int64
+	// SetVersion sets the version of the row the entity was loaded at.
+	SetVersion(version int64) OrderLineItemInterface
+
 	// Status predicates
 
 	// IsActive returns true if status is active.
@@ -584,6 +1184,66 @@ type OrderLineItemInterface interface {
 	IsFree() bool
 }
 
+// OutboxEventInterface defines the contract for outbox event entities.
+// Outbox events are written in the same transaction as the store changes
+// causing them and stay pending until marked as dispatched.
+type OutboxEventInterface interface {
+	// DataObject methods
+
+	// Data returns a map of all field values for serialization.
+	Data() map[string]string
+	// DataChanged returns a map of only the fields that have been modified since load.
+	DataChanged() map[string]string
+	// MarkAsNotDirty resets the dirty state, clearing all change tracking.
+	MarkAsNotDirty()
+
+	// Setters and Getters
+
+	// GetCreatedAt returns the creation timestamp as a string.
+	GetCreatedAt() string
+	// SetCreatedAt sets the creation timestamp.
+	SetCreatedAt(createdAt string) OutboxEventInterface
+
+	// GetDispatchedAt returns the dispatch timestamp, max datetime while pending.
+	GetDispatchedAt() string
+	// SetDispatchedAt sets the dispatch timestamp.
+	SetDispatchedAt(dispatchedAt string) OutboxEventInterface
+
+	// GetEntityID returns the ID of the entity the event is about.
+	GetEntityID() string
+	// SetEntityID sets the ID of the entity the event is about.
+	SetEntityID(entityID string) OutboxEventInterface
+
+	// GetEntityType returns the type of the entity the event is about.
+	GetEntityType() string
+	// SetEntityType sets the type of the entity the event is about.
+	SetEntityType(entityType string) OutboxEventInterface
+
+	// GetID returns the unique identifier.
+	GetID() string
+	// SetID sets the unique identifier.
+	SetID(id string) OutboxEventInterface
+
+	// GetPayload returns the event payload as a JSON string.
+	GetPayload() string
+	// GetPayloadMap returns the event payload decoded as a map.
+	GetPayloadMap() (map[string]string, error)
+	// SetPayload sets the event payload as a JSON string.
+	SetPayload(payload string) OutboxEventInterface
+	// SetPayloadMap sets the event payload from a map, stored as JSON.
+	SetPayloadMap(payload map[string]string) error
+
+	// GetType returns the event type (e.g. "order.status_changed").
+	GetType() string
+	// SetType sets the event type.
+	SetType(eventType string) OutboxEventInterface
+
+	// Business logic predicates
+
+	// IsDispatched returns true once the event was marked as dispatched.
+	IsDispatched() bool
+}
+
 // VariantMatrixSchema defines a single dimension for product variants.
 // Used to define variant attributes like color, size, material, etc.
 // Supports optional predefined options and required/optional flags.
@@ -712,11 +1372,62 @@ type ProductInterface interface {
 	// SetShortDescription sets the short/abbreviated description.
 	SetShortDescription(shortDescription string) ProductInterface
 
+	// GetCategoryID returns the ID of the category of the product (empty if none).
+	GetCategoryID() string
+	// SetCategoryID sets the ID of the category of the product.
+	SetCategoryID(categoryID string) ProductInterface
+
+	// GetSKU returns the stock keeping unit (empty if none).
+	GetSKU() string
+	// SetSKU sets the stock keeping unit, unique among the live products.
+	SetSKU(sku string) ProductInterface
+
 	// GetStatus returns the current status.
 	GetStatus() string
 	// SetStatus sets the current status.
 	SetStatus(status string) ProductInterface
 
+	// GetTaxClass returns the tax class, which selects the tax rates of the product.
+	GetTaxClass() string
+	// SetTaxClass sets the tax class, which selects the tax rates of the product.
+	SetTaxClass(taxClass string) ProductInterface
+
+	// GetWeight returns the weight of the product, in the weight unit of the shop as a string.
+	GetWeight() string
+	// SetWeight sets the weight of the product, in the weight unit of the shop from a string.
+	SetWeight(weight string) ProductInterface
+	// GetWeightFloat returns the weight of the product, in the weight unit of the shop as a float64.
+	GetWeightFloat() float64
+	// SetWeightFloat sets the weight of the product, in the weight unit of the shop from a float64.
+	SetWeightFloat(weight float64) ProductInterface
+
+	// GetLength returns the length of the product as a string.
+	GetLength() string
+	// SetLength sets the length of the product from a string.
+	SetLength(length string) ProductInterface
+	// GetLengthFloat returns the length of the product as a float64.
+	GetLengthFloat() float64
+	// SetLengthFloat sets the length of the product from a float64.
+	SetLengthFloat(length float64) ProductInterface
+
+	// GetWidth returns the width of the product as a string.
+	GetWidth() string
+	// SetWidth sets the width of the product from a string.
+	SetWidth(width string) ProductInterface
+	// GetWidthFloat returns the width of the product as a float64.
+	GetWidthFloat() float64
+	// SetWidthFloat sets the width of the product from a float64.
+	SetWidthFloat(width float64) ProductInterface
+
+	// GetHeight returns the height of the product as a string.
+	GetHeight() string
+	// SetHeight sets the height of the product from a string.
+	SetHeight(height string) ProductInterface
+	// GetHeightFloat returns the height of the product as a float64.
+	GetHeightFloat() float64
+	// SetHeightFloat sets the height of the product from a float64.
+	SetHeightFloat(height float64) ProductInterface
+
 	// GetTitle returns the product title.
 	GetTitle() string
 	// SetTitle sets the product title.
@@ -729,6 +1440,11 @@ type ProductInterface interface {
 	// SetUpdatedAt sets the last update timestamp.
 	SetUpdatedAt(updatedAt string) ProductInterface
 
+	// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+	GetVersion() int64
+	// SetVersion sets the version of the row the entity was loaded at.
+	SetVersion(version int64) ProductInterface
+
 	// GetVariantMatrixSchema returns the variant matrix schema configuration.
 	GetVariantMatrixSchema() (VariantMatrixSchema, error)
 	// SetVariantMatrixSchema sets the variant matrix schema configuration.
@@ -742,14 +1458,330 @@ type ProductInterface interface {
 	SetVariantMatrixValues(values map[string]string) error
 }
 
+// ShippingMethodInterface defines the contract for shipping method
+// entities. A method delivers to the addresses of a shipping zone, at a
+// flat, weight based or price based cost.
+type ShippingMethodInterface interface {
+	// DataObject methods
+
+	// Data returns a map of all field values for serialization.
+	Data() map[string]string
+	// DataChanged returns a map of only the fields that have been modified since load.
+	DataChanged() map[string]string
+	// MarkAsNotDirty resets the dirty state, clearing all change tracking.
+	MarkAsNotDirty()
+
+	// Setters and Getters
+
+	// GetAmount returns the cost of a flat rate method as a string.
+	GetAmount() string
+	// SetAmount sets the cost of a flat rate method from a string.
+	SetAmount(amount string) ShippingMethodInterface
+	// GetAmountFloat returns the cost of a flat rate method as a float64.
+	GetAmountFloat() float64
+	// SetAmountFloat sets the cost of a flat rate method from a float64.
+	SetAmountFloat(amount float64) ShippingMethodInterface
+
+	// GetCreatedAt returns the creation timestamp as a string.
+	GetCreatedAt() string
+	// GetCreatedAtCarbon returns the creation timestamp as a Carbon instance.
+	GetCreatedAtCarbon() *carbon.Carbon
+	// SetCreatedAt sets the creation timestamp.
+	SetCreatedAt(createdAt string) ShippingMethodInterface
+
+	// GetFreeOverAmount returns the subtotal from which the method is free, as a string.
+	GetFreeOverAmount() string
+	// SetFreeOverAmount sets the subtotal from which the method is free, from a string.
+	SetFreeOverAmount(amount string) ShippingMethodInterface
+	// GetFreeOverAmountFloat returns the subtotal from which the method is free, 0 when it never is.
+	GetFreeOverAmountFloat() float64
+	// SetFreeOverAmountFloat sets the subtotal from which the method is free, 0 for never.
+	SetFreeOverAmountFloat(amount float64) ShippingMethodInterface
+
+	// GetID returns the unique identifier.
+	GetID() string
+	// SetID sets the unique identifier.
+	SetID(id string) ShippingMethodInterface
+
+	// GetMemo returns the internal memo.
+	GetMemo() string
+	// SetMemo sets the internal memo.
+	SetMemo(memo string) ShippingMethodInterface
+
+	// GetMeta returns a specific metadata value by name.
+	GetMeta(name string) string
+	// GetMetas returns all metadata as a map.
+	GetMetas() (map[string]string, error)
+	// SetMeta sets a single metadata value.
+	SetMeta(name string, value string) error
+	// SetMetas replaces all metadata with the provided map.
+	SetMetas(metas map[string]string) error
+	// MetasUpsert merges the provided metadata with existing values.
+	MetasUpsert(metas map[string]string) error
+	// MetaRemove removes a single metadata entry.
+	MetaRemove(name string) error
+	// MetasRemove removes multiple metadata entries.
+	MetasRemove(names []string) error
+
+	// GetName returns the name of the method shown to shoppers, like "Express".
+	GetName() string
+	// SetName sets the name of the method shown to shoppers.
+	SetName(name string) ShippingMethodInterface
+
+	// GetRateRules returns the brackets of a weight or price based method.
+	GetRateRules() ([]ShippingRateRule, error)
+	// SetRateRules sets the brackets of a weight or price based method.
+	SetRateRules(rules []ShippingRateRule) error
+
+	// GetRateType returns how the cost is calculated (flat, weight or price).
+	GetRateType() string
+	// SetRateType sets how the cost is calculated.
+	SetRateType(rateType string) ShippingMethodInterface
+
+	// GetShippingZoneID returns the ID of the zone the method delivers to.
+	GetShippingZoneID() string
+	// SetShippingZoneID sets the ID of the zone the method delivers to.
+	SetShippingZoneID(shippingZoneID string) ShippingMethodInterface
+
+	// GetSoftDeletedAt returns the soft deletion timestamp.
+	GetSoftDeletedAt() string
+	// GetSoftDeletedAtCarbon returns the soft deletion timestamp as a Carbon instance.
+	GetSoftDeletedAtCarbon() *carbon.Carbon
+	// SetSoftDeletedAt sets the soft deletion timestamp.
+	SetSoftDeletedAt(deletedAt string) ShippingMethodInterface
+
+	// GetStatus returns the status (active or inactive).
+	GetStatus() string
+	// SetStatus sets the status.
+	SetStatus(status string) ShippingMethodInterface
+
+	// GetUpdatedAt returns the last update timestamp.
+	GetUpdatedAt() string
+	// GetUpdatedAtCarbon returns the last update timestamp as a Carbon instance.
+	GetUpdatedAtCarbon() *carbon.Carbon
+	// SetUpdatedAt sets the last update timestamp.
+	SetUpdatedAt(updatedAt string) ShippingMethodInterface
+
+	// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+	GetVersion() int64
+	// SetVersion sets the version of the row the entity was loaded at.
+	SetVersion(version int64) ShippingMethodInterface
+
+	// IsActive returns true if the shipping method status is active.
+	IsActive() bool
+	// IsInactive returns true if the shipping method status is inactive.
+	IsInactive() bool
+	// IsSoftDeleted returns true if the shipping method is soft deleted.
+	IsSoftDeleted() bool
+}
+
+// ShippingZoneInterface defines the contract for shipping zone entities. A
+// zone groups the countries, or regions of countries, its shipping methods
+// deliver to.
+type ShippingZoneInterface interface {
+	// DataObject methods
+
+	// Data returns a map of all field values for serialization.
+	Data() map[string]string
+	// DataChanged returns a map of only the fields that have been modified since load.
+	DataChanged() map[string]string
+	// MarkAsNotDirty resets the dirty state, clearing all change tracking.
+	MarkAsNotDirty()
+
+	// Setters and Getters
+
+	// GetCountryCodes returns the ISO 3166-1 alpha-2 codes of the countries of the zone.
+	GetCountryCodes() ([]string, error)
+	// SetCountryCodes sets the countries of the zone, trimmed and uppercased. None delivers everywhere.
+	SetCountryCodes(countryCodes []string) error
+
+	// GetCreatedAt returns the creation timestamp as a string.
+	GetCreatedAt() string
+	// GetCreatedAtCarbon returns the creation timestamp as a Carbon instance.
+	GetCreatedAtCarbon() *carbon.Carbon
+	// SetCreatedAt sets the creation timestamp.
+	SetCreatedAt(createdAt string) ShippingZoneInterface
+
+	// GetID returns the unique identifier.
+	GetID() string
+	// SetID sets the unique identifier.
+	SetID(id string) ShippingZoneInterface
+
+	// GetMemo returns the internal memo.
+	GetMemo() string
+	// SetMemo sets the internal memo.
+	SetMemo(memo string) ShippingZoneInterface
+
+	// GetMeta returns a specific metadata value by name.
+	GetMeta(name string) string
+	// GetMetas returns all metadata as a map.
+	GetMetas() (map[string]string, error)
+	// SetMeta sets a single metadata value.
+	SetMeta(name string, value string) error
+	// SetMetas replaces all metadata with the provided map.
+	SetMetas(metas map[string]string) error
+	// MetasUpsert merges the provided metadata with existing values.
+	MetasUpsert(metas map[string]string) error
+	// MetaRemove removes a single metadata entry.
+	MetaRemove(name string) error
+	// MetasRemove removes multiple metadata entries.
+	MetasRemove(names []string) error
+
+	// GetName returns the name of the zone, like "Europe".
+	GetName() string
+	// SetName sets the name of the zone.
+	SetName(name string) ShippingZoneInterface
+
+	// GetRegions returns the regions of the zone, empty for the whole countries.
+	GetRegions() ([]string, error)
+	// SetRegions sets the regions of the countries of the zone, trimmed. None covers the whole countries.
+	SetRegions(regions []string) error
+
+	// GetSoftDeletedAt returns the soft deletion timestamp.
+	GetSoftDeletedAt() string
+	// GetSoftDeletedAtCarbon returns the soft deletion timestamp as a Carbon instance.
+	GetSoftDeletedAtCarbon() *carbon.Carbon
+	// SetSoftDeletedAt sets the soft deletion timestamp.
+	SetSoftDeletedAt(deletedAt string) ShippingZoneInterface
+
+	// GetStatus returns the status (active or inactive).
+	GetStatus() string
+	// SetStatus sets the status.
+	SetStatus(status string) ShippingZoneInterface
+
+	// GetUpdatedAt returns the last update timestamp.
+	GetUpdatedAt() string
+	// GetUpdatedAtCarbon returns the last update timestamp as a Carbon instance.
+	GetUpdatedAtCarbon() *carbon.Carbon
+	// SetUpdatedAt sets the last update timestamp.
+	SetUpdatedAt(updatedAt string) ShippingZoneInterface
+
+	// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+	GetVersion() int64
+	// SetVersion sets the version of the row the entity was loaded at.
+	SetVersion(version int64) ShippingZoneInterface
+
+	// IsActive returns true if the shipping zone status is active.
+	IsActive() bool
+	// IsInactive returns true if the shipping zone status is inactive.
+	IsInactive() bool
+	// IsSoftDeleted returns true if the shipping zone is soft deleted.
+	IsSoftDeleted() bool
+}
+
+// TaxRateInterface defines the contract for tax rate entities. A rate
+// applies to the products of a tax class shipped to a country, or to a
+// region of a country.
+type TaxRateInterface interface {
+	// DataObject methods
+
+	// Data returns a map of all field values for serialization.
+	Data() map[string]string
+	// DataChanged returns a map of only the fields that have been modified since load.
+	DataChanged() map[string]string
+	// MarkAsNotDirty resets the dirty state, clearing all change tracking.
+	MarkAsNotDirty()
+
+	// Setters and Getters
+
+	// GetCountryCode returns the ISO 3166-1 alpha-2 country code.
+	GetCountryCode() string
+	// SetCountryCode sets the ISO 3166-1 alpha-2 country code, trimmed and uppercased.
+	SetCountryCode(countryCode string) TaxRateInterface
+
+	// GetCreatedAt returns the creation timestamp as a string.
+	GetCreatedAt() string
+	// GetCreatedAtCarbon returns the creation timestamp as a Carbon instance.
+	GetCreatedAtCarbon() *carbon.Carbon
+	// SetCreatedAt sets the creation timestamp.
+	SetCreatedAt(createdAt string) TaxRateInterface
+
+	// GetID returns the unique identifier.
+	GetID() string
+	// SetID sets the unique identifier.
+	SetID(id string) TaxRateInterface
+
+	// GetMemo returns the internal memo.
+	GetMemo() string
+	// SetMemo sets the internal memo.
+	SetMemo(memo string) TaxRateInterface
+
+	// GetMeta returns a specific metadata value by name.
+	GetMeta(name string) string
+	// GetMetas returns all metadata as a map.
+	GetMetas() (map[string]string, error)
+	// SetMeta sets a single metadata value.
+	SetMeta(name string, value string) error
+	// SetMetas replaces all metadata with the provided map.
+	SetMetas(metas map[string]string) error
+	// MetasUpsert merges the provided metadata with existing values.
+	MetasUpsert(metas map[string]string) error
+	// MetaRemove removes a single metadata entry.
+	MetaRemove(name string) error
+	// MetasRemove removes multiple metadata entries.
+	MetasRemove(names []string) error
+
+	// GetName returns the name of the tax shown to shoppers, like "VAT".
+	GetName() string
+	// SetName sets the name of the tax shown to shoppers.
+	SetName(name string) TaxRateInterface
+
+	// GetRate returns the rate in percent as a string.
+	GetRate() string
+	// SetRate sets the rate in percent from a string.
+	SetRate(rate string) TaxRateInterface
+	// GetRateFloat returns the rate in percent as a float64.
+	GetRateFloat() float64
+	// SetRateFloat sets the rate in percent from a float64.
+	SetRateFloat(rate float64) TaxRateInterface
+
+	// GetRegion returns the region the rate applies to, empty for the whole country.
+	GetRegion() string
+	// SetRegion sets the region the rate applies to, empty for the whole country.
+	SetRegion(region string) TaxRateInterface
+
+	// GetSoftDeletedAt returns the soft deletion timestamp.
+	GetSoftDeletedAt() string
+	// GetSoftDeletedAtCarbon returns the soft deletion timestamp as a Carbon instance.
+	GetSoftDeletedAtCarbon() *carbon.Carbon
+	// SetSoftDeletedAt sets the soft deletion timestamp.
+	SetSoftDeletedAt(deletedAt string) TaxRateInterface
+
+	// GetTaxClass returns the tax class the rate applies to.
+	GetTaxClass() string
+	// SetTaxClass sets the tax class the rate applies to.
+	SetTaxClass(taxClass string) TaxRateInterface
+
+	// GetUpdatedAt returns the last update timestamp.
+	GetUpdatedAt() string
+	// GetUpdatedAtCarbon returns the last update timestamp as a Carbon instance.
+	GetUpdatedAtCarbon() *carbon.Carbon
+	// SetUpdatedAt sets the last update timestamp.
+	SetUpdatedAt(updatedAt string) TaxRateInterface
+
+	// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+	GetVersion() int64
+	// SetVersion sets the version of the row the entity was loaded at.
+	SetVersion(version int64) TaxRateInterface
+
+	// IsSoftDeleted returns true if the tax rate is soft deleted.
+	IsSoftDeleted() bool
+}
+
 // StoreInterface defines the contract for the shop store database operations.
 // Provides CRUD operations, soft deletion, counting, listing with pagination,
-// and variant management for all entity types (categories, discounts, media, orders, products).
+// and variant management for all entity types (categories, customers, discounts, media, orders, products).
 type StoreInterface interface {
-	// MigrateDown drops the shop store tables
+	// MigrateDown reverts all the applied migrations, dropping the shop store tables.
 	MigrateDown(ctx context.Context, tx ...*sql.Tx) error
 
-	// MigrateUp creates or updates database tables to match the current schema.
+	// MigrateStatus lists the schema migrations and whether they are applied.
+	MigrateStatus(ctx context.Context) ([]MigrationStatus, error)
+
+	// MigrateTo applies or reverts migrations until the schema is at the given version.
+	MigrateTo(ctx context.Context, version int, tx ...*sql.Tx) error
+
+	// MigrateUp applies all the pending migrations.
 	MigrateUp(ctx context.Context, tx ...*sql.Tx) error
 
 	// DB returns the underlying SQL database connection.
@@ -757,10 +1789,71 @@ type StoreInterface interface {
 	// EnableDebug enables or disables debug logging for SQL queries.
 	EnableDebug(debug bool, sqlLogger ...*slog.Logger)
 
+	// Export writes all the rows of the entity tables as versioned JSON Lines.
+	Export(ctx context.Context, w io.Writer) error
+	// Import writes the rows of an export, merging them with or replacing the stored rows.
+	Import(ctx context.Context, r io.Reader, options StoreImportOptions) error
+
+	// Abandoned checkout operations
+
+	// AbandonedCheckoutReport lists the pending orders and active carts left older than the threshold.
+	AbandonedCheckoutReport(ctx context.Context, options AbandonedCheckoutOptions) (AbandonedCheckoutReport, error)
+	// AbandonedCheckoutExpire cancels the abandoned orders, releasing their reserved stock, and expires the abandoned carts.
+	AbandonedCheckoutExpire(ctx context.Context, options AbandonedCheckoutOptions) (AbandonedCheckoutExpireResult, error)
+
+	// Audit log operations
+
+	// AuditLogCount returns the count of audit log entries matching the query options.
+	AuditLogCount(ctx context.Context, options AuditLogQueryInterface) (int64, error)
+	// AuditLogList retrieves the audit log entries matching the query options.
+	AuditLogList(ctx context.Context, options AuditLogQueryInterface) ([]AuditLogInterface, error)
+
+	// Outbox operations
+
+	// OutboxFetchPending returns up to limit events not yet dispatched, oldest first.
+	OutboxFetchPending(ctx context.Context, limit int) ([]OutboxEventInterface, error)
+	// OutboxMarkDispatched marks the events with the given IDs as dispatched.
+	OutboxMarkDispatched(ctx context.Context, ids ...string) error
+
+	// Lifecycle hooks
+
+	// AddressHooks returns the lifecycle hooks run on address changes.
+	AddressHooks() *Hooks[AddressInterface]
+	// CartHooks returns the lifecycle hooks run on cart changes.
+	CartHooks() *Hooks[CartInterface]
+	// CategoryHooks returns the lifecycle hooks run on category changes.
+	CategoryHooks() *Hooks[CategoryInterface]
+	// CustomerHooks returns the lifecycle hooks run on customer changes.
+	CustomerHooks() *Hooks[CustomerInterface]
+	// DiscountHooks returns the lifecycle hooks run on discount changes.
+	DiscountHooks() *Hooks[DiscountInterface]
+	// MediaHooks returns the lifecycle hooks run on media changes.
+	MediaHooks() *Hooks[MediaInterface]
+	// OrderHooks returns the lifecycle hooks run on order changes.
+	OrderHooks() *Hooks[OrderInterface]
+	// OrderLineItemHooks returns the lifecycle hooks run on order line item changes.
+	OrderLineItemHooks() *Hooks[OrderLineItemInterface]
+	// ProductHooks returns the lifecycle hooks run on product changes.
+	ProductHooks() *Hooks[ProductInterface]
+	// ShippingMethodHooks returns the lifecycle hooks run on shipping method changes.
+	ShippingMethodHooks() *Hooks[ShippingMethodInterface]
+	// ShippingZoneHooks returns the lifecycle hooks run on shipping zone changes.
+	ShippingZoneHooks() *Hooks[ShippingZoneInterface]
+	// TaxRateHooks returns the lifecycle hooks run on tax rate changes.
+	TaxRateHooks() *Hooks[TaxRateInterface]
+
 	// Table name methods
 
+	// AddressTableName returns the database table name for customer addresses.
+	AddressTableName() string
+	// CartTableName returns the database table name for shopping carts.
+	CartTableName() string
+	// CartItemTableName returns the database table name for shopping cart items.
+	CartItemTableName() string
 	// CategoryTableName returns the database table name for categories.
 	CategoryTableName() string
+	// CustomerTableName returns the database table name for customers.
+	CustomerTableName() string
 	// DiscountTableName returns the database table name for discounts.
 	DiscountTableName() string
 	// MediaTableName returns the database table name for media.
@@ -771,6 +1864,90 @@ type StoreInterface interface {
 	OrderLineItemTableName() string
 	// ProductTableName returns the database table name for products.
 	ProductTableName() string
+	// ShippingMethodTableName returns the database table name for shipping methods.
+	ShippingMethodTableName() string
+	// ShippingZoneTableName returns the database table name for shipping zones.
+	ShippingZoneTableName() string
+	// TaxRateTableName returns the database table name for tax rates.
+	TaxRateTableName() string
+	// OutboxTableName returns the database table name for outbox events.
+	OutboxTableName() string
+	// AuditLogTableName returns the database table name for audit log entries.
+	AuditLogTableName() string
+
+	// Address operations
+
+	// AddressCount returns the total count of addresses matching the query options.
+	AddressCount(ctx context.Context, options AddressQueryInterface) (int64, error)
+	// AddressCreate inserts a new address into the database, clearing the
+	// default flags it sets on the other addresses of its customer.
+	AddressCreate(ctx context.Context, address AddressInterface) error
+	// AddressDelete permanently deletes an address from the database.
+	AddressDelete(ctx context.Context, address AddressInterface) error
+	// AddressDeleteByID permanently deletes an address by its ID.
+	AddressDeleteByID(ctx context.Context, addressID string) error
+	// AddressFindByID retrieves an address by its unique ID.
+	AddressFindByID(ctx context.Context, addressID string) (AddressInterface, error)
+	// AddressList retrieves a list of addresses matching the query options.
+	AddressList(ctx context.Context, options AddressQueryInterface) ([]AddressInterface, error)
+	// AddressListPage retrieves a single cursor paginated page of addresses matching the query options.
+	AddressListPage(ctx context.Context, options AddressQueryInterface) (ListPage[AddressInterface], error)
+	// AddressIterate streams the addresses matching the query options in batches.
+	AddressIterate(ctx context.Context, options AddressQueryInterface) iter.Seq2[AddressInterface, error]
+	// AddressSoftDelete soft deletes an address by setting the deleted timestamp.
+	AddressSoftDelete(ctx context.Context, address AddressInterface) error
+	// AddressSoftDeleteByID soft deletes an address by its ID.
+	AddressSoftDeleteByID(ctx context.Context, addressID string) error
+	// AddressSoftDeleteMany soft deletes the addresses matching the query options in a single transaction.
+	AddressSoftDeleteMany(ctx context.Context, options AddressQueryInterface) (int64, error)
+	// AddressUpdate updates an existing address in the database, clearing the
+	// default flags it sets on the other addresses of its customer.
+	AddressUpdate(ctx context.Context, address AddressInterface) error
+	// AddressUpdateMany sets the fields on the addresses matching the query options in a single transaction.
+	AddressUpdateMany(ctx context.Context, options AddressQueryInterface, fields map[string]string) (int64, error)
+
+	// Cart operations
+
+	// CartAddItem adds the quantity of an active product to an active cart,
+	// increasing the quantity of the item when the product is in the cart.
+	CartAddItem(ctx context.Context, cartID string, productID string, quantity int64) (CartItemInterface, error)
+	// CartApplyDiscount applies the valid discount with the code to an active cart, an empty code removing it.
+	CartApplyDiscount(ctx context.Context, cartID string, code string) error
+	// CartCheckout converts an active cart into a pending order with line items, reserving the stock, in a single transaction.
+	CartCheckout(ctx context.Context, cartID string) (OrderInterface, error)
+	// CartCount returns the total count of carts matching the query options.
+	CartCount(ctx context.Context, options CartQueryInterface) (int64, error)
+	// CartCreate inserts a new cart into the database.
+	CartCreate(ctx context.Context, cart CartInterface) error
+	// CartDelete permanently deletes a cart and its items from the database.
+	CartDelete(ctx context.Context, cart CartInterface) error
+	// CartDeleteByID permanently deletes a cart and its items by the cart ID.
+	CartDeleteByID(ctx context.Context, cartID string) error
+	// CartExpireAbandoned marks the active carts not changed for idleFor or longer as expired.
+	CartExpireAbandoned(ctx context.Context, idleFor time.Duration) (int64, error)
+	// CartFindByID retrieves a cart by its unique ID.
+	CartFindByID(ctx context.Context, cartID string) (CartInterface, error)
+	// CartItemList retrieves the items of a cart, in the order they were added.
+	CartItemList(ctx context.Context, cartID string) ([]CartItemInterface, error)
+	// CartList retrieves a list of carts matching the query options.
+	CartList(ctx context.Context, options CartQueryInterface) ([]CartInterface, error)
+	// CartListPage retrieves a single cursor paginated page of carts matching the query options.
+	CartListPage(ctx context.Context, options CartQueryInterface) (ListPage[CartInterface], error)
+	// CartIterate streams the carts matching the query options in batches.
+	CartIterate(ctx context.Context, options CartQueryInterface) iter.Seq2[CartInterface, error]
+	// CartMerge hands an active guest cart over to the customer, merging it
+	// into the active cart of the customer if any, and returns the cart of the customer.
+	CartMerge(ctx context.Context, guestCartID string, customerID string) (CartInterface, error)
+	// CartRemoveItem removes an item from an active cart.
+	CartRemoveItem(ctx context.Context, cartID string, itemID string) error
+	// CartSoftDelete soft deletes a cart by setting the deleted timestamp.
+	CartSoftDelete(ctx context.Context, cart CartInterface) error
+	// CartSoftDeleteByID soft deletes a cart by its ID.
+	CartSoftDeleteByID(ctx context.Context, cartID string) error
+	// CartUpdate updates an existing cart in the database.
+	CartUpdate(ctx context.Context, cart CartInterface) error
+	// CartUpdateItemQuantity sets the quantity of an item of an active cart, 0 removing it.
+	CartUpdateItemQuantity(ctx context.Context, cartID string, itemID string, quantity int64) error
 
 	// Category operations
 
@@ -786,12 +1963,55 @@ type StoreInterface interface {
 	CategoryFindByID(context context.Context, categoryID string) (CategoryInterface, error)
 	// CategoryList retrieves a list of categories matching the query options.
 	CategoryList(context context.Context, options CategoryQueryInterface) ([]CategoryInterface, error)
+	// CategoryListPage retrieves a single cursor paginated page of categories matching the query options.
+	CategoryListPage(context context.Context, options CategoryQueryInterface) (ListPage[CategoryInterface], error)
+	// CategoryIterate streams the categories matching the query options in batches.
+	CategoryIterate(context context.Context, options CategoryQueryInterface) iter.Seq2[CategoryInterface, error]
 	// CategorySoftDelete soft deletes a category by setting the deleted timestamp.
 	CategorySoftDelete(context context.Context, category CategoryInterface) error
 	// CategorySoftDeleteByID soft deletes a category by its ID.
 	CategorySoftDeleteByID(context context.Context, categoryID string) error
+	// CategorySoftDeleteMany soft deletes the categories matching the query options in a single transaction.
+	CategorySoftDeleteMany(context context.Context, options CategoryQueryInterface) (int64, error)
 	// CategoryUpdate updates an existing category in the database.
 	CategoryUpdate(contxt context.Context, category CategoryInterface) error
+	// CategoryUpdateMany sets the fields on the categories matching the query options in a single transaction.
+	CategoryUpdateMany(context context.Context, options CategoryQueryInterface, fields map[string]string) (int64, error)
+
+	// Customer operations
+
+	// CustomerAddressList retrieves the live addresses of a customer, default addresses first.
+	CustomerAddressList(ctx context.Context, customerID string) ([]AddressInterface, error)
+	// CustomerCount returns the total count of customers matching the query options.
+	CustomerCount(ctx context.Context, options CustomerQueryInterface) (int64, error)
+	// CustomerCreate inserts a new customer into the database.
+	CustomerCreate(ctx context.Context, customer CustomerInterface) error
+	// CustomerDelete permanently deletes a customer from the database.
+	CustomerDelete(ctx context.Context, customer CustomerInterface) error
+	// CustomerDeleteByID permanently deletes a customer by its ID.
+	CustomerDeleteByID(ctx context.Context, customerID string) error
+	// CustomerFindByEmail retrieves the live customer with the email.
+	CustomerFindByEmail(ctx context.Context, email string) (CustomerInterface, error)
+	// CustomerFindByID retrieves a customer by its unique ID.
+	CustomerFindByID(ctx context.Context, customerID string) (CustomerInterface, error)
+	// CustomerList retrieves a list of customers matching the query options.
+	CustomerList(ctx context.Context, options CustomerQueryInterface) ([]CustomerInterface, error)
+	// CustomerListPage retrieves a single cursor paginated page of customers matching the query options.
+	CustomerListPage(ctx context.Context, options CustomerQueryInterface) (ListPage[CustomerInterface], error)
+	// CustomerIterate streams the customers matching the query options in batches.
+	CustomerIterate(ctx context.Context, options CustomerQueryInterface) iter.Seq2[CustomerInterface, error]
+	// CustomerOrderList retrieves the orders of a customer matching the query options.
+	CustomerOrderList(ctx context.Context, customerID string, options OrderQueryInterface) ([]OrderInterface, error)
+	// CustomerSoftDelete soft deletes a customer by setting the deleted timestamp.
+	CustomerSoftDelete(ctx context.Context, customer CustomerInterface) error
+	// CustomerSoftDeleteByID soft deletes a customer by its ID.
+	CustomerSoftDeleteByID(ctx context.Context, customerID string) error
+	// CustomerSoftDeleteMany soft deletes the customers matching the query options in a single transaction.
+	CustomerSoftDeleteMany(ctx context.Context, options CustomerQueryInterface) (int64, error)
+	// CustomerUpdate updates an existing customer in the database.
+	CustomerUpdate(ctx context.Context, customer CustomerInterface) error
+	// CustomerUpdateMany sets the fields on the customers matching the query options in a single transaction.
+	CustomerUpdateMany(ctx context.Context, options CustomerQueryInterface, fields map[string]string) (int64, error)
 
 	// Discount operations
 
@@ -809,12 +2029,20 @@ type StoreInterface interface {
 	DiscountFindByCode(ctx context.Context, code string) (DiscountInterface, error)
 	// DiscountList retrieves a list of discounts matching the query options.
 	DiscountList(ctx context.Context, options DiscountQueryInterface) ([]DiscountInterface, error)
+	// DiscountListPage retrieves a single cursor paginated page of discounts matching the query options.
+	DiscountListPage(ctx context.Context, options DiscountQueryInterface) (ListPage[DiscountInterface], error)
+	// DiscountIterate streams the discounts matching the query options in batches.
+	DiscountIterate(ctx context.Context, options DiscountQueryInterface) iter.Seq2[DiscountInterface, error]
 	// DiscountSoftDelete soft deletes a discount by setting the deleted timestamp.
 	DiscountSoftDelete(ctx context.Context, discount DiscountInterface) error
 	// DiscountSoftDeleteByID soft deletes a discount by its ID.
 	DiscountSoftDeleteByID(ctx context.Context, discountID string) error
+	// DiscountSoftDeleteMany soft deletes the discounts matching the query options in a single transaction.
+	DiscountSoftDeleteMany(ctx context.Context, options DiscountQueryInterface) (int64, error)
 	// DiscountUpdate updates an existing discount in the database.
 	DiscountUpdate(ctx context.Context, discount DiscountInterface) error
+	// DiscountUpdateMany sets the fields on the discounts matching the query options in a single transaction.
+	DiscountUpdateMany(ctx context.Context, options DiscountQueryInterface, fields map[string]string) (int64, error)
 
 	// Media operations
 
@@ -822,6 +2050,8 @@ type StoreInterface interface {
 	MediaCount(ctx context.Context, options MediaQueryInterface) (int64, error)
 	// MediaCreate inserts a new media into the database.
 	MediaCreate(ctx context.Context, media MediaInterface) error
+	// MediaCreateMany inserts many new media in a single transaction.
+	MediaCreateMany(ctx context.Context, mediaList []MediaInterface) error
 	// MediaDelete permanently deletes a media from the database.
 	MediaDelete(ctx context.Context, media MediaInterface) error
 	// MediaDeleteByID permanently deletes a media by its ID.
@@ -830,15 +2060,25 @@ type StoreInterface interface {
 	MediaFindByID(ctx context.Context, mediaID string) (MediaInterface, error)
 	// MediaList retrieves a list of media matching the query options.
 	MediaList(ctx context.Context, options MediaQueryInterface) ([]MediaInterface, error)
+	// MediaListPage retrieves a single cursor paginated page of media matching the query options.
+	MediaListPage(ctx context.Context, options MediaQueryInterface) (ListPage[MediaInterface], error)
+	// MediaIterate streams the media matching the query options in batches.
+	MediaIterate(ctx context.Context, options MediaQueryInterface) iter.Seq2[MediaInterface, error]
 	// MediaSoftDelete soft deletes a media by setting the deleted timestamp.
 	MediaSoftDelete(ctx context.Context, media MediaInterface) error
 	// MediaSoftDeleteByID soft deletes a media by its ID.
 	MediaSoftDeleteByID(ctx context.Context, mediaID string) error
+	// MediaSoftDeleteMany soft deletes the media matching the query options in a single transaction.
+	MediaSoftDeleteMany(ctx context.Context, options MediaQueryInterface) (int64, error)
 	// MediaUpdate updates an existing media in the database.
 	MediaUpdate(ctx context.Context, media MediaInterface) error
+	// MediaUpdateMany sets the fields on the media matching the query options in a single transaction.
+	MediaUpdateMany(ctx context.Context, options MediaQueryInterface, fields map[string]string) (int64, error)
 
 	// Order operations
 
+	// OrderCalculateTax calculates and stores the tax of the line items and the tax total of an order.
+	OrderCalculateTax(ctx context.Context, orderID string) (OrderInterface, error)
 	// OrderCount returns the total count of orders matching the query options.
 	OrderCount(ctx context.Context, options OrderQueryInterface) (int64, error)
 	// OrderCreate inserts a new order into the database.
@@ -851,12 +2091,22 @@ type StoreInterface interface {
 	OrderFindByID(ctx context.Context, id string) (OrderInterface, error)
 	// OrderList retrieves a list of orders matching the query options.
 	OrderList(ctx context.Context, options OrderQueryInterface) ([]OrderInterface, error)
+	// OrderListPage retrieves a single cursor paginated page of orders matching the query options.
+	OrderListPage(ctx context.Context, options OrderQueryInterface) (ListPage[OrderInterface], error)
+	// OrderIterate streams the orders matching the query options in batches.
+	OrderIterate(ctx context.Context, options OrderQueryInterface) iter.Seq2[OrderInterface, error]
+	// OrderSetShippingMethod stores the shipping method chosen for an order, with its cost.
+	OrderSetShippingMethod(ctx context.Context, orderID string, shippingMethodID string) (OrderInterface, error)
 	// OrderSoftDelete soft deletes an order by setting the deleted timestamp.
 	OrderSoftDelete(ctx context.Context, order OrderInterface) error
 	// OrderSoftDeleteByID soft deletes an order by its ID.
 	OrderSoftDeleteByID(ctx context.Context, id string) error
+	// OrderSoftDeleteMany soft deletes the orders matching the query options in a single transaction.
+	OrderSoftDeleteMany(ctx context.Context, options OrderQueryInterface) (int64, error)
 	// OrderUpdate updates an existing order in the database.
 	OrderUpdate(ctx context.Context, order OrderInterface) error
+	// OrderUpdateMany sets the fields on the orders matching the query options in a single transaction.
+	OrderUpdateMany(ctx context.Context, options OrderQueryInterface, fields map[string]string) (int64, error)
 
 	// OrderLineItem operations
 
@@ -864,6 +2114,8 @@ type StoreInterface interface {
 	OrderLineItemCount(ctx context.Context, options OrderLineItemQueryInterface) (int64, error)
 	// OrderLineItemCreate inserts a new line item into the database.
 	OrderLineItemCreate(ctx context.Context, orderLineItem OrderLineItemInterface) error
+	// OrderLineItemCreateMany inserts many new line items in a single transaction.
+	OrderLineItemCreateMany(ctx context.Context, orderLineItems []OrderLineItemInterface) error
 	// OrderLineItemDelete permanently deletes a line item from the database.
 	OrderLineItemDelete(ctx context.Context, orderLineItem OrderLineItemInterface) error
 	// OrderLineItemDeleteByID permanently deletes a line item by its ID.
@@ -872,12 +2124,20 @@ type StoreInterface interface {
 	OrderLineItemFindByID(ctx context.Context, id string) (OrderLineItemInterface, error)
 	// OrderLineItemList retrieves a list of line items matching the query options.
 	OrderLineItemList(ctx context.Context, options OrderLineItemQueryInterface) ([]OrderLineItemInterface, error)
+	// OrderLineItemListPage retrieves a single cursor paginated page of line items matching the query options.
+	OrderLineItemListPage(ctx context.Context, options OrderLineItemQueryInterface) (ListPage[OrderLineItemInterface], error)
+	// OrderLineItemIterate streams the line items matching the query options in batches.
+	OrderLineItemIterate(ctx context.Context, options OrderLineItemQueryInterface) iter.Seq2[OrderLineItemInterface, error]
 	// OrderLineItemSoftDelete soft deletes a line item by setting the deleted timestamp.
 	OrderLineItemSoftDelete(ctx context.Context, orderLineItem OrderLineItemInterface) error
 	// OrderLineItemSoftDeleteByID soft deletes a line item by its ID.
 	OrderLineItemSoftDeleteByID(ctx context.Context, id string) error
+	// OrderLineItemSoftDeleteMany soft deletes the line items matching the query options in a single transaction.
+	OrderLineItemSoftDeleteMany(ctx context.Context, options OrderLineItemQueryInterface) (int64, error)
 	// OrderLineItemUpdate updates an existing line item in the database.
 	OrderLineItemUpdate(ctx context.Context, orderLineItem OrderLineItemInterface) error
+	// OrderLineItemUpdateMany sets the fields on the line items matching the query options in a single transaction.
+	OrderLineItemUpdateMany(ctx context.Context, options OrderLineItemQueryInterface, fields map[string]string) (int64, error)
 
 	// Product operations
 
@@ -885,20 +2145,39 @@ type StoreInterface interface {
 	ProductCount(ctx context.Context, options ProductQueryInterface) (int64, error)
 	// ProductCreate inserts a new product into the database.
 	ProductCreate(ctx context.Context, product ProductInterface) error
+	// ProductCreateMany inserts many new products in a single transaction.
+	ProductCreateMany(ctx context.Context, products []ProductInterface) error
 	// ProductDelete permanently deletes a product from the database.
 	ProductDelete(ctx context.Context, product ProductInterface) error
 	// ProductDeleteByID permanently deletes a product by its ID.
 	ProductDeleteByID(ctx context.Context, productID string) error
 	// ProductFindByID retrieves a product by its unique ID.
 	ProductFindByID(ctx context.Context, productID string) (ProductInterface, error)
+	// ProductFindBySKU retrieves a live product by its unique SKU.
+	ProductFindBySKU(ctx context.Context, sku string) (ProductInterface, error)
 	// ProductList retrieves a list of products matching the query options.
 	ProductList(ctx context.Context, options ProductQueryInterface) ([]ProductInterface, error)
+	// ProductListPage retrieves a single cursor paginated page of products matching the query options.
+	ProductListPage(ctx context.Context, options ProductQueryInterface) (ListPage[ProductInterface], error)
+	// ProductIterate streams the products matching the query options in batches.
+	ProductIterate(ctx context.Context, options ProductQueryInterface) iter.Seq2[ProductInterface, error]
 	// ProductSoftDelete soft deletes a product by setting the deleted timestamp.
 	ProductSoftDelete(ctx context.Context, product ProductInterface) error
 	// ProductSoftDeleteByID soft deletes a product by its ID.
 	ProductSoftDeleteByID(ctx context.Context, productID string) error
+	// ProductSoftDeleteMany soft deletes the products matching the query options in a single transaction.
+	ProductSoftDeleteMany(ctx context.Context, options ProductQueryInterface) (int64, error)
+	// ProductQuantityAdjust atomically adds delta to the product quantity and returns the new quantity.
+	ProductQuantityAdjust(ctx context.Context, productID string, delta int64, opts ProductQuantityAdjustOptions) (int64, error)
 	// ProductUpdate updates an existing product in the database.
 	ProductUpdate(ctx context.Context, product ProductInterface) error
+	// ProductUpsertBySKU creates the product, or updates the changed fields of the product with its SKU,
+	// among the columns when given, otherwise among all but the quantity.
+	ProductUpsertBySKU(ctx context.Context, product ProductInterface, columns ...string) (ProductUpsertResult, error)
+	// ProductUpsertManyBySKU upserts many products by SKU, see ProductUpsertBySKU.
+	ProductUpsertManyBySKU(ctx context.Context, products []ProductInterface, columns ...string) (ProductUpsertResult, error)
+	// ProductUpdateMany sets the fields on the products matching the query options in a single transaction.
+	ProductUpdateMany(ctx context.Context, options ProductQueryInterface, fields map[string]string) (int64, error)
 
 	// Variant operations
 
@@ -908,4 +2187,112 @@ type StoreInterface interface {
 	ProductIsParent(ctx context.Context, productID string) (bool, error)
 	// ProductGetParent retrieves the parent product for a variant.
 	ProductGetParent(ctx context.Context, productID string) (ProductInterface, error)
+
+	// Import and export
+
+	// ProductExportCSV writes the products matching the query options as CSV, one row per variant.
+	ProductExportCSV(ctx context.Context, w io.Writer, options ProductQueryInterface) error
+	// ProductImportCSV validates and creates the products read from CSV, see ProductExportCSV.
+	ProductImportCSV(ctx context.Context, r io.Reader, options ProductCSVImportOptions) (ProductCSVImportReport, error)
+
+	// Product feeds
+
+	// ProductFeed returns the items of the product feed for comparison shopping sites.
+	ProductFeed(ctx context.Context, options ProductFeedOptions) ([]ProductFeedItem, error)
+	// ProductFeedXML writes the product feed as Google Merchant XML.
+	ProductFeedXML(ctx context.Context, w io.Writer, options ProductFeedOptions) error
+	// ProductFeedCSV writes the product feed as CSV.
+	ProductFeedCSV(ctx context.Context, w io.Writer, options ProductFeedOptions) error
+
+	// Shipping operations
+
+	// ShippingQuote returns the shipping methods available for an order or a cart, with their costs.
+	ShippingQuote(ctx context.Context, request ShippingQuoteRequest) ([]ShippingOption, error)
+
+	// Shipping method operations
+
+	// ShippingMethodCount returns the total count of shipping methods matching the query options.
+	ShippingMethodCount(ctx context.Context, options ShippingMethodQueryInterface) (int64, error)
+	// ShippingMethodCreate inserts a new shipping method into the database.
+	ShippingMethodCreate(ctx context.Context, shippingMethod ShippingMethodInterface) error
+	// ShippingMethodDelete permanently deletes a shipping method from the database.
+	ShippingMethodDelete(ctx context.Context, shippingMethod ShippingMethodInterface) error
+	// ShippingMethodDeleteByID permanently deletes a shipping method by its ID.
+	ShippingMethodDeleteByID(ctx context.Context, shippingMethodID string) error
+	// ShippingMethodFindByID retrieves a shipping method by its unique ID.
+	ShippingMethodFindByID(ctx context.Context, shippingMethodID string) (ShippingMethodInterface, error)
+	// ShippingMethodList retrieves a list of shipping methods matching the query options.
+	ShippingMethodList(ctx context.Context, options ShippingMethodQueryInterface) ([]ShippingMethodInterface, error)
+	// ShippingMethodListPage retrieves a single cursor paginated page of shipping methods matching the query options.
+	ShippingMethodListPage(ctx context.Context, options ShippingMethodQueryInterface) (ListPage[ShippingMethodInterface], error)
+	// ShippingMethodIterate streams the shipping methods matching the query options in batches.
+	ShippingMethodIterate(ctx context.Context, options ShippingMethodQueryInterface) iter.Seq2[ShippingMethodInterface, error]
+	// ShippingMethodSoftDelete soft deletes a shipping method by setting the deleted timestamp.
+	ShippingMethodSoftDelete(ctx context.Context, shippingMethod ShippingMethodInterface) error
+	// ShippingMethodSoftDeleteByID soft deletes a shipping method by its ID.
+	ShippingMethodSoftDeleteByID(ctx context.Context, shippingMethodID string) error
+	// ShippingMethodSoftDeleteMany soft deletes the shipping methods matching the query options in a single transaction.
+	ShippingMethodSoftDeleteMany(ctx context.Context, options ShippingMethodQueryInterface) (int64, error)
+	// ShippingMethodUpdate updates an existing shipping method in the database.
+	ShippingMethodUpdate(ctx context.Context, shippingMethod ShippingMethodInterface) error
+	// ShippingMethodUpdateMany sets the fields on the shipping methods matching the query options in a single transaction.
+	ShippingMethodUpdateMany(ctx context.Context, options ShippingMethodQueryInterface, fields map[string]string) (int64, error)
+
+	// Shipping zone operations
+
+	// ShippingZoneCount returns the total count of shipping zones matching the query options.
+	ShippingZoneCount(ctx context.Context, options ShippingZoneQueryInterface) (int64, error)
+	// ShippingZoneCreate inserts a new shipping zone into the database.
+	ShippingZoneCreate(ctx context.Context, shippingZone ShippingZoneInterface) error
+	// ShippingZoneDelete permanently deletes a shipping zone without active methods from the database.
+	ShippingZoneDelete(ctx context.Context, shippingZone ShippingZoneInterface) error
+	// ShippingZoneDeleteByID permanently deletes a shipping zone by its ID.
+	ShippingZoneDeleteByID(ctx context.Context, shippingZoneID string) error
+	// ShippingZoneFindByID retrieves a shipping zone by its unique ID.
+	ShippingZoneFindByID(ctx context.Context, shippingZoneID string) (ShippingZoneInterface, error)
+	// ShippingZoneList retrieves a list of shipping zones matching the query options.
+	ShippingZoneList(ctx context.Context, options ShippingZoneQueryInterface) ([]ShippingZoneInterface, error)
+	// ShippingZoneListPage retrieves a single cursor paginated page of shipping zones matching the query options.
+	ShippingZoneListPage(ctx context.Context, options ShippingZoneQueryInterface) (ListPage[ShippingZoneInterface], error)
+	// ShippingZoneIterate streams the shipping zones matching the query options in batches.
+	ShippingZoneIterate(ctx context.Context, options ShippingZoneQueryInterface) iter.Seq2[ShippingZoneInterface, error]
+	// ShippingZoneSoftDelete soft deletes a shipping zone by setting the deleted timestamp.
+	ShippingZoneSoftDelete(ctx context.Context, shippingZone ShippingZoneInterface) error
+	// ShippingZoneSoftDeleteByID soft deletes a shipping zone by its ID.
+	ShippingZoneSoftDeleteByID(ctx context.Context, shippingZoneID string) error
+	// ShippingZoneSoftDeleteMany soft deletes the shipping zones matching the query options in a single transaction.
+	ShippingZoneSoftDeleteMany(ctx context.Context, options ShippingZoneQueryInterface) (int64, error)
+	// ShippingZoneUpdate updates an existing shipping zone in the database.
+	ShippingZoneUpdate(ctx context.Context, shippingZone ShippingZoneInterface) error
+	// ShippingZoneUpdateMany sets the fields on the shipping zones matching the query options in a single transaction.
+	ShippingZoneUpdateMany(ctx context.Context, options ShippingZoneQueryInterface, fields map[string]string) (int64, error)
+
+	// Tax rate operations
+
+	// TaxRateCount returns the total count of tax rates matching the query options.
+	TaxRateCount(ctx context.Context, options TaxRateQueryInterface) (int64, error)
+	// TaxRateCreate inserts a new tax rate into the database.
+	TaxRateCreate(ctx context.Context, taxRate TaxRateInterface) error
+	// TaxRateDelete permanently deletes a tax rate from the database.
+	TaxRateDelete(ctx context.Context, taxRate TaxRateInterface) error
+	// TaxRateDeleteByID permanently deletes a tax rate by its ID.
+	TaxRateDeleteByID(ctx context.Context, taxRateID string) error
+	// TaxRateFindByID retrieves a tax rate by its unique ID.
+	TaxRateFindByID(ctx context.Context, taxRateID string) (TaxRateInterface, error)
+	// TaxRateList retrieves a list of tax rates matching the query options.
+	TaxRateList(ctx context.Context, options TaxRateQueryInterface) ([]TaxRateInterface, error)
+	// TaxRateListPage retrieves a single cursor paginated page of tax rates matching the query options.
+	TaxRateListPage(ctx context.Context, options TaxRateQueryInterface) (ListPage[TaxRateInterface], error)
+	// TaxRateIterate streams the tax rates matching the query options in batches.
+	TaxRateIterate(ctx context.Context, options TaxRateQueryInterface) iter.Seq2[TaxRateInterface, error]
+	// TaxRateSoftDelete soft deletes a tax rate by setting the deleted timestamp.
+	TaxRateSoftDelete(ctx context.Context, taxRate TaxRateInterface) error
+	// TaxRateSoftDeleteByID soft deletes a tax rate by its ID.
+	TaxRateSoftDeleteByID(ctx context.Context, taxRateID string) error
+	// TaxRateSoftDeleteMany soft deletes the tax rates matching the query options in a single transaction.
+	TaxRateSoftDeleteMany(ctx context.Context, options TaxRateQueryInterface) (int64, error)
+	// TaxRateUpdate updates an existing tax rate in the database.
+	TaxRateUpdate(ctx context.Context, taxRate TaxRateInterface) error
+	// TaxRateUpdateMany sets the fields on the tax rates matching the query options in a single transaction.
+	TaxRateUpdateMany(ctx context.Context, options TaxRateQueryInterface, fields map[string]string) (int64, error)
 }
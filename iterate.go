@@ -0,0 +1,129 @@
+package shopstore
+
+import (
+	"context"
+	"iter"
+
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+)
+
+// iterateBatchSize is the number of rows fetched per round-trip by the *Iterate methods
+const iterateBatchSize = 500
+
+// keysetQueryOptions is the subset of the query builders used to walk
+// a list in keyset order. All entity query interfaces satisfy it.
+type keysetQueryOptions interface {
+	keysetOptions
+	HasLimit() bool
+	Limit() int
+	HasOffset() bool
+}
+
+// iterateInBatches streams the rows matched by a list query in keyset
+// ordered batches of iterateBatchSize, so that only one batch is held in
+// memory at a time and no connection is held open between batches.
+//
+// All the query options are honoured: a limit caps the total number of rows
+// yielded and an offset is only applied to the first batch. Iteration stops
+// with the context error once ctx is cancelled. The store's operation timeout
+// applies to each batch, rather than to the iteration as a whole.
+func iterateInBatches[T any](
+	store *Store,
+	ctx context.Context,
+	operation string,
+	options keysetQueryOptions,
+	build func(ctx context.Context) (contractsorm.Query, error),
+	hydrate func(data map[string]string) T,
+) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		keys := keysetSort(options)
+		sorted := isSorted(options)
+
+		remaining := -1 // no limit
+		if options.HasLimit() {
+			remaining = options.Limit()
+		}
+
+		cursor := ""
+
+		// fetch runs a single batch query, bounded by the operation timeout
+		fetch := func(batchSize int) ([]map[string]any, error) {
+			ctx, cancel := store.operationContext(ctx)
+			defer cancel()
+
+			q, err := build(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			if !sorted {
+				q = orderByKeyset(q, keys)
+			}
+
+			if cursor != "" {
+				q, err = whereAfterCursor(q, store.dialect, keys, cursor)
+				if err != nil {
+					return nil, err
+				}
+
+				if options.HasOffset() {
+					q = q.Offset(0) // the offset was consumed by the first batch
+				}
+			}
+
+			var results []map[string]any
+			if err := q.Limit(batchSize).Get(&results); err != nil {
+				return nil, store.operationError(operation, err)
+			}
+
+			return results, nil
+		}
+
+		for remaining != 0 {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			batchSize := iterateBatchSize
+			if remaining > 0 && remaining < batchSize {
+				batchSize = remaining
+			}
+
+			results, err := fetch(batchSize)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			var last map[string]string
+
+			for _, result := range results {
+				last = mapAnyToString(result)
+				if !yield(hydrate(last), nil) {
+					return
+				}
+			}
+
+			if len(results) < batchSize {
+				return
+			}
+
+			if remaining > 0 {
+				remaining -= len(results)
+			}
+
+			cursor = encodeCursor(keys, last)
+		}
+	}
+}
+
+// iterateError returns an iterator yielding a single error
+func iterateError[T any](err error) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		yield(zero, err)
+	}
+}
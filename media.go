@@ -44,7 +44,8 @@ func NewMedia() MediaInterface {
 		SetMemo("").        // By default empty
 		SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
 		SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
-		SetSoftDeletedAt(MAX_DATETIME)
+		SetSoftDeletedAt(MAX_DATETIME).
+		SetVersion(1)
 
 	_ = o.SetMetas(map[string]string{})
 
@@ -284,6 +285,17 @@ func (m *Media) SetUpdatedAt(updatedAt string) MediaInterface {
 	return m
 }
 
+// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+func (m *Media) GetVersion() int64 {
+	return cast.ToInt64(m.Get(COLUMN_VERSION))
+}
+
+// SetVersion sets the version of the row the entity was loaded at.
+func (m *Media) SetVersion(version int64) MediaInterface {
+	m.Set(COLUMN_VERSION, cast.ToString(version))
+	return m
+}
+
 // GetURL returns the media file URL.
 func (m *Media) GetURL() string {
 	return m.Get(COLUMN_MEDIA_URL)
@@ -16,6 +16,10 @@ type MediaQueryInterface interface {
 	EntityID() string
 	SetEntityID(entityID string) MediaQueryInterface
 
+	HasEntityIDIn() bool
+	EntityIDIn() []string
+	SetEntityIDIn(entityIDIn []string) MediaQueryInterface
+
 	HasID() bool
 	ID() string
 	SetID(id string) MediaQueryInterface
@@ -40,6 +44,19 @@ type MediaQueryInterface interface {
 	SortDirection() string
 	SetSortDirection(sortDirection string) MediaQueryInterface
 
+	// AddSort adds a sort key. Keys are applied in the order they are added,
+	// after the order_by column if one is set. Only the entity's sortable
+	// columns and the asc/desc directions are accepted by Validate.
+	HasSorts() bool
+	Sorts() []QuerySort
+	AddSort(column string, direction string) MediaQueryInterface
+
+	// AfterCursor continues a keyset paginated list right after the row the
+	// cursor points at. Cursors are returned as NextCursor by the *ListPage methods.
+	HasAfterCursor() bool
+	AfterCursor() string
+	SetAfterCursor(cursor string) MediaQueryInterface
+
 	HasSoftDeletedIncluded() bool
 	SoftDeletedIncluded() bool
 	SetSoftDeletedIncluded(softDeletedIncluded bool) MediaQueryInterface
@@ -82,6 +99,10 @@ func (c *mediaQueryImplementation) Validate() error {
 		return errors.New("media query. entity_id cannot be empty")
 	}
 
+	if c.HasEntityIDIn() && len(c.EntityIDIn()) == 0 {
+		return errors.New("media query. entity_id_in cannot be empty")
+	}
+
 	if c.HasStatus() && c.Status() == "" {
 		return errors.New("media query. status cannot be empty")
 	}
@@ -106,6 +127,24 @@ func (c *mediaQueryImplementation) Validate() error {
 		return errors.New("media query. limit cannot be negative")
 	}
 
+	if err := validateSort(c, mediaSortableColumns); err != nil {
+		return errors.New("media query. " + err.Error())
+	}
+
+	if c.HasAfterCursor() {
+		if c.AfterCursor() == "" {
+			return errors.New("media query. after_cursor cannot be empty")
+		}
+
+		if c.HasOffset() {
+			return errors.New("media query. after_cursor cannot be combined with offset")
+		}
+
+		if err := validateCursor(c.AfterCursor(), keysetSort(c)); err != nil {
+			return errors.New("media query. after_cursor " + err.Error())
+		}
+	}
+
 	return nil
 }
 
@@ -159,6 +198,24 @@ func (c *mediaQueryImplementation) SetEntityID(entityID string) MediaQueryInterf
 	return c
 }
 
+func (c *mediaQueryImplementation) HasEntityIDIn() bool {
+	return c.hasProperty("entity_id_in")
+}
+
+func (c *mediaQueryImplementation) EntityIDIn() []string {
+	if !c.HasEntityIDIn() {
+		return []string{}
+	}
+
+	return c.properties["entity_id_in"].([]string)
+}
+
+func (c *mediaQueryImplementation) SetEntityIDIn(entityIDIn []string) MediaQueryInterface {
+	c.properties["entity_id_in"] = entityIDIn
+
+	return c
+}
+
 func (c *mediaQueryImplementation) HasID() bool {
 	return c.hasProperty("id")
 }
@@ -267,6 +324,24 @@ func (c *mediaQueryImplementation) SetSortDirection(sortDirection string) MediaQ
 	return c
 }
 
+func (c *mediaQueryImplementation) HasSorts() bool {
+	return c.hasProperty("sorts")
+}
+
+func (c *mediaQueryImplementation) Sorts() []QuerySort {
+	if !c.HasSorts() {
+		return []QuerySort{}
+	}
+
+	return c.properties["sorts"].([]QuerySort)
+}
+
+func (c *mediaQueryImplementation) AddSort(column string, direction string) MediaQueryInterface {
+	c.properties["sorts"] = appendSort(c.Sorts(), column, direction)
+
+	return c
+}
+
 func (c *mediaQueryImplementation) SetParentID(parentID string) MediaQueryInterface {
 	c.properties["parent_id"] = parentID
 
@@ -345,6 +420,24 @@ func (c *mediaQueryImplementation) SetType(type_ string) MediaQueryInterface {
 	return c
 }
 
+func (c *mediaQueryImplementation) HasAfterCursor() bool {
+	return c.hasProperty("after_cursor")
+}
+
+func (c *mediaQueryImplementation) AfterCursor() string {
+	if !c.HasAfterCursor() {
+		return ""
+	}
+
+	return c.properties["after_cursor"].(string)
+}
+
+func (c *mediaQueryImplementation) SetAfterCursor(cursor string) MediaQueryInterface {
+	c.properties["after_cursor"] = cursor
+
+	return c
+}
+
 func (c *mediaQueryImplementation) hasProperty(name string) bool {
 	_, ok := c.properties[name]
 	return ok
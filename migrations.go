@@ -0,0 +1,1205 @@
+package shopstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dracory/neat"
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+	contractsschema "github.com/dracory/neat/contracts/database/schema"
+	"github.com/dromara/carbon/v2"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+)
+
+// DEFAULT_MIGRATION_TABLE_NAME is the table recording the applied migrations
+// when NewStoreOptions.MigrationTableName is not set
+const DEFAULT_MIGRATION_TABLE_NAME = "shop_migration"
+
+const COLUMN_VERSION = "version"
+const COLUMN_NAME = "name"
+const COLUMN_APPLIED_AT = "applied_at"
+
+// MigrationStatus describes a migration of the store schema and whether it
+// has been applied to the database
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt string
+}
+
+// migration is a single numbered, reversible change to the store schema.
+//
+// Up steps must tolerate the change already being in place, as databases
+// created before migrations were tracked hold the schema without the records.
+type migration struct {
+	version int
+	name    string
+	up      migrationStep
+	down    migrationStep
+}
+
+// migrationStep changes the schema inside the migration transaction tx.
+// Data reads and writes must go through tx as well.
+type migrationStep func(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error
+
+// migrations returns the registry of the store schema migrations, ordered
+// by version. Released migrations must never be changed or renumbered; add
+// a new one instead.
+func (store *Store) migrations() []migration {
+	return []migration{
+		{
+			version: 1,
+			name:    "category_table_create",
+			up:      migration_001_category_table_create,
+			down:    dropTable(store.categoryTableName),
+		},
+		{
+			version: 2,
+			name:    "discount_table_create",
+			up:      migration_002_discount_table_create,
+			down:    dropTable(store.discountTableName),
+		},
+		{
+			version: 3,
+			name:    "media_table_create",
+			up:      migration_003_media_table_create,
+			down:    dropTable(store.mediaTableName),
+		},
+		{
+			version: 4,
+			name:    "order_table_create",
+			up:      migration_004_order_table_create,
+			down:    dropTable(store.orderTableName),
+		},
+		{
+			version: 5,
+			name:    "order_line_item_table_create",
+			up:      migration_005_order_line_item_table_create,
+			down:    dropTable(store.orderLineItemTableName),
+		},
+		{
+			version: 6,
+			name:    "product_table_create",
+			up:      migration_006_product_table_create,
+			down:    dropTable(store.productTableName),
+		},
+		{
+			version: 7,
+			name:    "product_table_add_parent_id",
+			up:      migration_007_product_table_add_parent_id,
+			down:    dropColumns(store.productTableName, COLUMN_PARENT_ID),
+		},
+		{
+			version: 8,
+			name:    "product_table_add_variant_dimensions",
+			up:      migration_008_product_table_add_variant_dimensions,
+			down:    dropColumns(store.productTableName, COLUMN_VARIANT_MATRIX_SCHEMA, COLUMN_VARIANT_MATRIX_VALUES),
+		},
+		{
+			version: 9,
+			name:    "indexes_create",
+			up:      migration_009_indexes_create,
+			down:    migration_009_indexes_drop,
+		},
+		{
+			version: 10,
+			name:    "discount_table_add_code_unique",
+			up:      migration_010_discount_table_add_code_unique,
+			down:    dropIndex(store.discountTableName, "unique", COLUMN_CODE, COLUMN_SOFT_DELETED_AT),
+		},
+		{
+			version: 11,
+			name:    "outbox_table_create",
+			up:      migration_011_outbox_table_create,
+			down:    dropTable(store.outboxTableName),
+		},
+		{
+			version: 12,
+			name:    "audit_log_table_create",
+			up:      migration_012_audit_log_table_create,
+			down:    dropTable(store.auditLogTableName),
+		},
+		{
+			version: 13,
+			name:    "version_columns_add",
+			up:      migration_013_version_columns_add,
+			down:    migration_013_version_columns_drop,
+		},
+		{
+			version: 14,
+			name:    "product_table_add_sku",
+			up:      migration_014_product_table_add_sku,
+			down:    migration_014_product_table_drop_sku,
+		},
+		{
+			version: 15,
+			name:    "product_table_add_category_id",
+			up:      migration_015_product_table_add_category_id,
+			down:    migration_015_product_table_drop_category_id,
+		},
+		{
+			version: 16,
+			name:    "customer_table_create",
+			up:      migration_016_customer_table_create,
+			down:    dropTable(store.customerTableName),
+		},
+		{
+			version: 17,
+			name:    "address_table_create",
+			up:      migration_017_address_table_create,
+			down:    dropTable(store.addressTableName),
+		},
+		{
+			version: 18,
+			name:    "order_table_add_addresses",
+			up:      migration_018_order_table_add_addresses,
+			down:    dropColumns(store.orderTableName, COLUMN_SHIPPING_ADDRESS, COLUMN_BILLING_ADDRESS),
+		},
+		{
+			version: 19,
+			name:    "cart_table_create",
+			up:      migration_019_cart_table_create,
+			down:    dropTable(store.cartTableName),
+		},
+		{
+			version: 20,
+			name:    "cart_item_table_create",
+			up:      migration_020_cart_item_table_create,
+			down:    dropTable(store.cartItemTableName),
+		},
+		{
+			version: 21,
+			name:    "order_table_add_discount",
+			up:      migration_021_order_table_add_discount,
+			down:    dropColumns(store.orderTableName, COLUMN_DISCOUNT_ID, COLUMN_DISCOUNT_AMOUNT),
+		},
+		{
+			version: 22,
+			name:    "tax_rate_table_create",
+			up:      migration_022_tax_rate_table_create,
+			down:    dropTable(store.taxRateTableName),
+		},
+		{
+			version: 23,
+			name:    "product_table_add_tax_class",
+			up:      migration_023_product_table_add_tax_class,
+			down:    dropColumns(store.productTableName, COLUMN_TAX_CLASS),
+		},
+		{
+			version: 24,
+			name:    "order_table_add_tax",
+			up:      migration_024_order_table_add_tax,
+			down:    dropColumns(store.orderTableName, COLUMN_TAX_AMOUNT, COLUMN_PRICES_INCLUDE_TAX),
+		},
+		{
+			version: 25,
+			name:    "order_line_item_table_add_tax",
+			up:      migration_025_order_line_item_table_add_tax,
+			down:    dropColumns(store.orderLineItemTableName, COLUMN_TAX_RATE, COLUMN_TAX_AMOUNT),
+		},
+		{
+			version: 26,
+			name:    "shipping_zone_table_create",
+			up:      migration_026_shipping_zone_table_create,
+			down:    dropTable(store.shippingZoneTableName),
+		},
+		{
+			version: 27,
+			name:    "shipping_method_table_create",
+			up:      migration_027_shipping_method_table_create,
+			down:    dropTable(store.shippingMethodTableName),
+		},
+		{
+			version: 28,
+			name:    "product_table_add_dimensions",
+			up:      migration_028_product_table_add_dimensions,
+			down:    dropColumns(store.productTableName, COLUMN_WEIGHT, COLUMN_LENGTH, COLUMN_WIDTH, COLUMN_HEIGHT),
+		},
+		{
+			version: 29,
+			name:    "order_table_add_shipping",
+			up:      migration_029_order_table_add_shipping,
+			down:    dropColumns(store.orderTableName, COLUMN_SHIPPING_METHOD_ID, COLUMN_SHIPPING_METHOD_NAME, COLUMN_SHIPPING_AMOUNT),
+		},
+	}
+}
+
+// MigrateUp applies all the pending migrations, and adds the foreign keys
+// when enabled in the store options
+func (store *Store) MigrateUp(ctx context.Context, tx ...*sql.Tx) error {
+	db, done, err := store.migrationDatabase(tx...)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	migrations := store.migrations()
+
+	if err := store.migrateTo(ctx, db, migrations[len(migrations)-1].version); err != nil {
+		return err
+	}
+
+	if store.foreignKeysEnabled {
+		return store.foreignKeysCreate(db.Schema())
+	}
+
+	return nil
+}
+
+// MigrateDown reverts all the applied migrations, dropping the shop store
+// tables, and then drops the migration table itself
+func (store *Store) MigrateDown(ctx context.Context, tx ...*sql.Tx) error {
+	db, done, err := store.migrationDatabase(tx...)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	if err := store.migrateTo(ctx, db, 0); err != nil {
+		return err
+	}
+
+	return db.Schema().DropIfExists(store.migrationTableName)
+}
+
+// MigrateTo applies or reverts migrations until the schema is at the given
+// version. Version 0 reverts every migration.
+//
+// All the steps run in a single transaction, together with the records of
+// the applied versions, so a failed migration leaves no partial schema on
+// databases supporting transactional DDL (SQLite, PostgreSQL). When tx is
+// passed the steps and records run on it, and committing or rolling it back
+// is left to the caller; otherwise the store runs its own transaction.
+// MySQL commits a transaction implicitly on each schema change.
+func (store *Store) MigrateTo(ctx context.Context, version int, tx ...*sql.Tx) error {
+	db, done, err := store.migrationDatabase(tx...)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	return store.migrateTo(ctx, db, version)
+}
+
+// migrationDatabase returns the database the migrations run on: the
+// caller's transaction when passed, the store database otherwise. Call
+// done once the migrations are over.
+func (store *Store) migrationDatabase(tx ...*sql.Tx) (db *neat.Database, done func(), err error) {
+	if len(tx) == 0 || tx[0] == nil {
+		return store.db, func() {}, nil
+	}
+
+	db, sqlDB, err := store.externalTxDB(tx[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return db, func() { _ = sqlDB.Close() }, nil
+}
+
+// migrateTo is MigrateTo on the database db
+func (store *Store) migrateTo(ctx context.Context, db *neat.Database, version int) error {
+	migrations := store.migrations()
+
+	if version < 0 || version > migrations[len(migrations)-1].version {
+		return errors.New("shop store: unknown migration version " + strconv.Itoa(version))
+	}
+
+	if err := store.migrationTableCreate(db.Schema()); err != nil {
+		return err
+	}
+
+	// schema changes can take long on big tables, so only the deadline
+	// of ctx applies, not the store's operation timeout
+	err := queryWithContext(db.Query(), ctx).Transaction(func(q contractsorm.Query) error {
+		schema := db.Schema().WithTransaction(q)
+
+		applied, err := store.migrationsApplied(q)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range migrations {
+			if m.version > version || lo.HasKey(applied, m.version) {
+				continue
+			}
+
+			if err := m.up(store, schema, q); err != nil {
+				return errors.New("shop store: migration " + strconv.Itoa(m.version) + " " + m.name + " failed: " + err.Error())
+			}
+
+			err := statement(q).Table(store.migrationTableName).Create(map[string]any{
+				COLUMN_VERSION:    m.version,
+				COLUMN_NAME:       m.name,
+				COLUMN_APPLIED_AT: carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, m := range slices.Backward(migrations) {
+			if m.version <= version || !lo.HasKey(applied, m.version) {
+				continue
+			}
+
+			if err := m.down(store, schema, q); err != nil {
+				return errors.New("shop store: migration " + strconv.Itoa(m.version) + " " + m.name + " rollback failed: " + err.Error())
+			}
+
+			_, err := statement(q).Table(store.migrationTableName).Where(COLUMN_VERSION+" = ?", m.version).Delete()
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return store.operationError("migrate", err)
+}
+
+// MigrateStatus lists all the known migrations and whether they are applied
+func (store *Store) MigrateStatus(ctx context.Context) ([]MigrationStatus, error) {
+	applied := map[int]string{}
+
+	if store.db.Schema().HasTable(store.migrationTableName) {
+		ctx, cancel := store.operationContext(ctx)
+		defer cancel()
+
+		var err error
+		applied, err = store.migrationsApplied(store.query(ctx))
+		if err != nil {
+			return nil, store.operationError("migrate status", err)
+		}
+	}
+
+	statuses := []MigrationStatus{}
+
+	for _, m := range store.migrations() {
+		appliedAt, ok := applied[m.version]
+
+		statuses = append(statuses, MigrationStatus{
+			Version:   m.version,
+			Name:      m.name,
+			Applied:   ok,
+			AppliedAt: appliedAt,
+		})
+	}
+
+	return statuses, nil
+}
+
+// migrationsApplied returns the applied migration versions and when they were applied
+func (store *Store) migrationsApplied(q contractsorm.Query) (map[int]string, error) {
+	var results []map[string]any
+	if err := statement(q).Table(store.migrationTableName).Get(&results); err != nil {
+		return nil, err
+	}
+
+	applied := map[int]string{}
+
+	for _, result := range results {
+		row := mapAnyToString(result)
+		applied[cast.ToInt(row[COLUMN_VERSION])] = row[COLUMN_APPLIED_AT]
+	}
+
+	return applied, nil
+}
+
+func (store *Store) migrationTableCreate(schema contractsschema.Schema) error {
+	if schema.HasTable(store.migrationTableName) {
+		return nil
+	}
+
+	return schema.Create(store.migrationTableName, func(table contractsschema.Blueprint) {
+		table.Integer(COLUMN_VERSION)
+		table.Primary(COLUMN_VERSION)
+		table.String(COLUMN_NAME, 255)
+		table.DateTime(COLUMN_APPLIED_AT)
+	})
+}
+
+// dropTable returns a down step dropping the table
+func dropTable(tableName string) migrationStep {
+	return func(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+		return schema.DropIfExists(tableName)
+	}
+}
+
+// dropColumns returns a down step dropping the columns, if the table still has them
+func dropColumns(tableName string, columns ...string) migrationStep {
+	return func(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+		existing := lo.Filter(columns, func(column string, _ int) bool {
+			return schema.HasColumn(tableName, column)
+		})
+
+		if len(existing) == 0 {
+			return nil
+		}
+
+		return schema.DropColumns(tableName, existing)
+	}
+}
+
+// dropIndex returns a down step dropping the index, if the table still has it
+func dropIndex(tableName string, indexType string, columns ...string) migrationStep {
+	return func(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+		name := indexName(tableName, indexType, columns...)
+
+		if !schema.HasIndex(tableName, name) {
+			return nil
+		}
+
+		return schema.Table(tableName, func(table contractsschema.Blueprint) {
+			table.DropIndexByName(name)
+		})
+	}
+}
+
+// indexName returns the name the schema builder gives an index of the
+// type "index" or "unique" on the columns
+func indexName(tableName string, indexType string, columns ...string) string {
+	return strings.ToLower(tableName + "_" + strings.Join(columns, "_") + "_" + indexType)
+}
+
+func migration_001_category_table_create(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	if schema.HasTable(store.categoryTableName) {
+		return nil
+	}
+
+	return schema.Create(store.categoryTableName, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_ID, 40)
+		table.Primary(COLUMN_ID)
+		table.String(COLUMN_STATUS, 20)
+		table.String(COLUMN_PARENT_ID, 40)
+		table.String(COLUMN_TITLE, 255)
+		table.Text(COLUMN_DESCRIPTION)
+		table.Text(COLUMN_METAS)
+		table.Text(COLUMN_MEMO)
+		table.DateTime(COLUMN_CREATED_AT)
+		table.DateTime(COLUMN_UPDATED_AT)
+		table.DateTime(COLUMN_SOFT_DELETED_AT)
+	})
+}
+
+func migration_002_discount_table_create(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	if schema.HasTable(store.discountTableName) {
+		return nil
+	}
+
+	return schema.Create(store.discountTableName, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_ID, 40)
+		table.Primary(COLUMN_ID)
+		table.String(COLUMN_STATUS, 20)
+		table.String(COLUMN_TITLE, 255)
+		table.Text(COLUMN_DESCRIPTION)
+		table.String(COLUMN_TYPE, 20)
+		table.Decimal(COLUMN_AMOUNT)
+		table.String(COLUMN_CODE, 100)
+		table.DateTime(COLUMN_STARTS_AT)
+		table.DateTime(COLUMN_ENDS_AT)
+		table.Text(COLUMN_METAS)
+		table.Text(COLUMN_MEMO)
+		table.DateTime(COLUMN_CREATED_AT)
+		table.DateTime(COLUMN_UPDATED_AT)
+		table.DateTime(COLUMN_SOFT_DELETED_AT)
+	})
+}
+
+func migration_003_media_table_create(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	if schema.HasTable(store.mediaTableName) {
+		return nil
+	}
+
+	return schema.Create(store.mediaTableName, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_ID, 40)
+		table.Primary(COLUMN_ID)
+		table.String(COLUMN_STATUS, 20)
+		table.String(COLUMN_ENTITY_ID, 40)
+		table.Integer(COLUMN_SEQUENCE)
+		table.String(COLUMN_MEDIA_TYPE, 50)
+		table.String(COLUMN_MEDIA_URL, 510)
+		table.String(COLUMN_TITLE, 255)
+		table.Text(COLUMN_DESCRIPTION)
+		table.Text(COLUMN_MEMO)
+		table.Text(COLUMN_METAS)
+		table.DateTime(COLUMN_CREATED_AT)
+		table.DateTime(COLUMN_UPDATED_AT)
+		table.DateTime(COLUMN_SOFT_DELETED_AT)
+	})
+}
+
+func migration_004_order_table_create(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	if schema.HasTable(store.orderTableName) {
+		return nil
+	}
+
+	return schema.Create(store.orderTableName, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_ID, 40)
+		table.Primary(COLUMN_ID)
+		table.String(COLUMN_STATUS, 20)
+		table.String(COLUMN_CUSTOMER_ID, 40)
+		table.Integer(COLUMN_QUANTITY)
+		table.Decimal(COLUMN_PRICE)
+		table.Text(COLUMN_METAS)
+		table.Text(COLUMN_MEMO)
+		table.DateTime(COLUMN_CREATED_AT)
+		table.DateTime(COLUMN_UPDATED_AT)
+		table.DateTime(COLUMN_SOFT_DELETED_AT)
+	})
+}
+
+func migration_005_order_line_item_table_create(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	if schema.HasTable(store.orderLineItemTableName) {
+		return nil
+	}
+
+	return schema.Create(store.orderLineItemTableName, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_ID, 40)
+		table.Primary(COLUMN_ID)
+		table.String(COLUMN_STATUS, 20)
+		table.String(COLUMN_ORDER_ID, 40)
+		table.String(COLUMN_PRODUCT_ID, 40)
+		table.String(COLUMN_TITLE, 255)
+		table.Integer(COLUMN_QUANTITY)
+		table.Decimal(COLUMN_PRICE)
+		table.Text(COLUMN_METAS)
+		table.Text(COLUMN_MEMO)
+		table.DateTime(COLUMN_CREATED_AT)
+		table.DateTime(COLUMN_UPDATED_AT)
+		table.DateTime(COLUMN_SOFT_DELETED_AT)
+	})
+}
+
+// migration_006_product_table_create creates the product table as first
+// released. The columns added since are added by the following migrations.
+func migration_006_product_table_create(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	if schema.HasTable(store.productTableName) {
+		return nil
+	}
+
+	return schema.Create(store.productTableName, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_ID, 40)
+		table.Primary(COLUMN_ID)
+		table.String(COLUMN_STATUS, 20)
+		table.String(COLUMN_TITLE, 255)
+		table.Text(COLUMN_DESCRIPTION)
+		table.Text(COLUMN_SHORT_DESCRIPTION)
+		table.Integer(COLUMN_QUANTITY)
+		table.Decimal(COLUMN_PRICE)
+		table.Text(COLUMN_METAS)
+		table.Text(COLUMN_MEMO)
+		table.DateTime(COLUMN_CREATED_AT)
+		table.DateTime(COLUMN_UPDATED_AT)
+		table.DateTime(COLUMN_SOFT_DELETED_AT)
+	})
+}
+
+// migration_007_product_table_add_parent_id adds the parent_id column used by product variants
+func migration_007_product_table_add_parent_id(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	if schema.HasColumn(store.productTableName, COLUMN_PARENT_ID) {
+		return nil
+	}
+
+	return schema.Table(store.productTableName, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_PARENT_ID, 40).Default("0")
+	})
+}
+
+// migration_008_product_table_add_variant_dimensions adds the variant matrix columns
+func migration_008_product_table_add_variant_dimensions(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	if !schema.HasColumn(store.productTableName, COLUMN_VARIANT_MATRIX_SCHEMA) {
+		err := schema.Table(store.productTableName, func(table contractsschema.Blueprint) {
+			table.Text(COLUMN_VARIANT_MATRIX_SCHEMA).Default(store.dialect.textDefault("{}"))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !schema.HasColumn(store.productTableName, COLUMN_VARIANT_MATRIX_VALUES) {
+		err := schema.Table(store.productTableName, func(table contractsschema.Blueprint) {
+			table.Text(COLUMN_VARIANT_MATRIX_VALUES).Default(store.dialect.textDefault("{}"))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// indexedColumns are the columns each table is filtered by, indexed by migration 9
+func (store *Store) indexedColumns() map[string][]string {
+	return map[string][]string{
+		store.categoryTableName:      {COLUMN_PARENT_ID, COLUMN_STATUS, COLUMN_SOFT_DELETED_AT},
+		store.discountTableName:      {COLUMN_STATUS, COLUMN_SOFT_DELETED_AT},
+		store.mediaTableName:         {COLUMN_ENTITY_ID, COLUMN_STATUS, COLUMN_SOFT_DELETED_AT},
+		store.orderTableName:         {COLUMN_CUSTOMER_ID, COLUMN_STATUS, COLUMN_SOFT_DELETED_AT},
+		store.orderLineItemTableName: {COLUMN_ORDER_ID, COLUMN_PRODUCT_ID, COLUMN_STATUS, COLUMN_SOFT_DELETED_AT},
+		store.productTableName:       {COLUMN_PARENT_ID, COLUMN_STATUS, COLUMN_SOFT_DELETED_AT},
+	}
+}
+
+// migration_009_indexes_create indexes the foreign key, status and soft
+// delete columns, which every list query filters by
+func migration_009_indexes_create(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	for tableName, columns := range store.indexedColumns() {
+		for _, column := range columns {
+			if schema.HasIndex(tableName, indexName(tableName, "index", column)) {
+				continue
+			}
+
+			err := schema.Table(tableName, func(table contractsschema.Blueprint) {
+				table.Index(column)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func migration_009_indexes_drop(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	for tableName, columns := range store.indexedColumns() {
+		for _, column := range columns {
+			if err := dropIndex(tableName, "index", column)(store, schema, tx); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// migration_010_discount_table_add_code_unique prevents two live discounts
+// from sharing a code. Soft deleted discounts hold distinct soft_deleted_at
+// values, so their codes can be reused.
+func migration_010_discount_table_add_code_unique(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	name := indexName(store.discountTableName, "unique", COLUMN_CODE, COLUMN_SOFT_DELETED_AT)
+
+	if schema.HasIndex(store.discountTableName, name) {
+		return nil
+	}
+
+	var duplicates []map[string]any
+	err := statement(tx).
+		Table(store.discountTableName).
+		Select(COLUMN_CODE).
+		Where(COLUMN_SOFT_DELETED_AT+" = ?", MAX_DATETIME).
+		Group(COLUMN_CODE).
+		Having("COUNT(*) > ?", 1).
+		Get(&duplicates)
+	if err != nil {
+		return err
+	}
+
+	if len(duplicates) > 0 {
+		codes := lo.Map(duplicates, func(row map[string]any, _ int) string {
+			return cast.ToString(row[COLUMN_CODE])
+		})
+
+		return errors.New("duplicate discount codes must be resolved first: " + strings.Join(codes, ", "))
+	}
+
+	// raw SQL, as the schema builder compiles Unique to a plain index on SQLite
+	return schema.Sql("CREATE UNIQUE INDEX " + name + " ON " + store.discountTableName +
+		" (" + COLUMN_CODE + ", " + COLUMN_SOFT_DELETED_AT + ")")
+}
+
+// migration_011_outbox_table_create creates the table recording the domain
+// events until they are relayed
+func migration_011_outbox_table_create(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	if schema.HasTable(store.outboxTableName) {
+		return nil
+	}
+
+	return schema.Create(store.outboxTableName, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_ID, 40)
+		table.Primary(COLUMN_ID)
+		table.String(COLUMN_TYPE, 100)
+		table.String(COLUMN_ENTITY_TYPE, 40)
+		table.String(COLUMN_ENTITY_ID, 40)
+		table.Text(COLUMN_PAYLOAD)
+		table.DateTime(COLUMN_CREATED_AT)
+		table.DateTime(COLUMN_DISPATCHED_AT)
+		table.Index(COLUMN_DISPATCHED_AT, COLUMN_CREATED_AT)
+	})
+}
+
+// migration_012_audit_log_table_create creates the table recording the
+// audit log of the store changes
+func migration_012_audit_log_table_create(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	if schema.HasTable(store.auditLogTableName) {
+		return nil
+	}
+
+	return schema.Create(store.auditLogTableName, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_ID, 40)
+		table.Primary(COLUMN_ID)
+		table.String(COLUMN_ENTITY_TYPE, 40)
+		table.String(COLUMN_ENTITY_ID, 40)
+		table.String(COLUMN_OPERATION, 20)
+		table.String(COLUMN_ACTOR_ID, 40)
+		table.LongText(COLUMN_VALUES_BEFORE)
+		table.LongText(COLUMN_VALUES_AFTER)
+		table.DateTime(COLUMN_CREATED_AT)
+		table.Index(COLUMN_ENTITY_TYPE, COLUMN_ENTITY_ID, COLUMN_CREATED_AT)
+		table.Index(COLUMN_ACTOR_ID, COLUMN_CREATED_AT)
+		table.Index(COLUMN_CREATED_AT)
+	})
+}
+
+// versionedTables are the entity tables given a version column by migration
+// 13, for the optimistic concurrency control of updates
+func (store *Store) versionedTables() []string {
+	return []string{
+		store.categoryTableName,
+		store.discountTableName,
+		store.mediaTableName,
+		store.orderTableName,
+		store.orderLineItemTableName,
+		store.productTableName,
+	}
+}
+
+// migration_013_version_columns_add adds the version column to the entity
+// tables. Existing rows start at version 1, like new entities.
+func migration_013_version_columns_add(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	for _, tableName := range store.versionedTables() {
+		if schema.HasColumn(tableName, COLUMN_VERSION) {
+			continue
+		}
+
+		err := schema.Table(tableName, func(table contractsschema.Blueprint) {
+			table.BigInteger(COLUMN_VERSION).Default(1)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func migration_013_version_columns_drop(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	for _, tableName := range store.versionedTables() {
+		if err := dropColumns(tableName, COLUMN_VERSION)(store, schema, tx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migration_014_product_table_add_sku adds the SKU column, unique among the
+// live products. Products without a SKU store NULL, which unique indexes
+// never consider equal.
+func migration_014_product_table_add_sku(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	if !schema.HasColumn(store.productTableName, COLUMN_SKU) {
+		err := schema.Table(store.productTableName, func(table contractsschema.Blueprint) {
+			table.String(COLUMN_SKU, 100).Nullable()
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	name := indexName(store.productTableName, "unique", COLUMN_SKU, COLUMN_SOFT_DELETED_AT)
+
+	if schema.HasIndex(store.productTableName, name) {
+		return nil
+	}
+
+	// raw SQL, as the schema builder compiles Unique to a plain index on SQLite
+	return schema.Sql("CREATE UNIQUE INDEX " + name + " ON " + store.productTableName +
+		" (" + COLUMN_SKU + ", " + COLUMN_SOFT_DELETED_AT + ")")
+}
+
+func migration_014_product_table_drop_sku(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	err := dropIndex(store.productTableName, "unique", COLUMN_SKU, COLUMN_SOFT_DELETED_AT)(store, schema, tx)
+	if err != nil {
+		return err
+	}
+
+	return dropColumns(store.productTableName, COLUMN_SKU)(store, schema, tx)
+}
+
+// migration_015_product_table_add_category_id adds the indexed category_id
+// column, placing products in a category
+func migration_015_product_table_add_category_id(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	if !schema.HasColumn(store.productTableName, COLUMN_CATEGORY_ID) {
+		err := schema.Table(store.productTableName, func(table contractsschema.Blueprint) {
+			table.String(COLUMN_CATEGORY_ID, 40).Default("")
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if schema.HasIndex(store.productTableName, indexName(store.productTableName, "index", COLUMN_CATEGORY_ID)) {
+		return nil
+	}
+
+	return schema.Table(store.productTableName, func(table contractsschema.Blueprint) {
+		table.Index(COLUMN_CATEGORY_ID)
+	})
+}
+
+func migration_015_product_table_drop_category_id(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	if err := dropIndex(store.productTableName, "index", COLUMN_CATEGORY_ID)(store, schema, tx); err != nil {
+		return err
+	}
+
+	return dropColumns(store.productTableName, COLUMN_CATEGORY_ID)(store, schema, tx)
+}
+
+// migration_016_customer_table_create creates the customer table, with the
+// email unique among the live customers. Customers without an email store
+// NULL, which unique indexes never consider equal.
+func migration_016_customer_table_create(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	if !schema.HasTable(store.customerTableName) {
+		err := schema.Create(store.customerTableName, func(table contractsschema.Blueprint) {
+			table.String(COLUMN_ID, 40)
+			table.Primary(COLUMN_ID)
+			table.String(COLUMN_STATUS, 20)
+			table.String(COLUMN_NAME, 255)
+			table.String(COLUMN_EMAIL, 255).Nullable()
+			table.String(COLUMN_PHONE, 50)
+			table.Text(COLUMN_METAS)
+			table.Text(COLUMN_MEMO)
+			table.DateTime(COLUMN_CREATED_AT)
+			table.DateTime(COLUMN_UPDATED_AT)
+			table.DateTime(COLUMN_SOFT_DELETED_AT)
+			table.BigInteger(COLUMN_VERSION).Default(1)
+			table.Index(COLUMN_STATUS)
+			table.Index(COLUMN_SOFT_DELETED_AT)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	name := indexName(store.customerTableName, "unique", COLUMN_EMAIL, COLUMN_SOFT_DELETED_AT)
+
+	if schema.HasIndex(store.customerTableName, name) {
+		return nil
+	}
+
+	// raw SQL, as the schema builder compiles Unique to a plain index on SQLite
+	return schema.Sql("CREATE UNIQUE INDEX " + name + " ON " + store.customerTableName +
+		" (" + COLUMN_EMAIL + ", " + COLUMN_SOFT_DELETED_AT + ")")
+}
+
+// migration_017_address_table_create creates the address table, holding
+// the address books of the customers
+func migration_017_address_table_create(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	if schema.HasTable(store.addressTableName) {
+		return nil
+	}
+
+	return schema.Create(store.addressTableName, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_ID, 40)
+		table.Primary(COLUMN_ID)
+		table.String(COLUMN_CUSTOMER_ID, 40)
+		table.String(COLUMN_NAME, 255)
+		table.String(COLUMN_LINE1, 255)
+		table.String(COLUMN_LINE2, 255)
+		table.String(COLUMN_CITY, 100)
+		table.String(COLUMN_REGION, 100)
+		table.String(COLUMN_POSTAL_CODE, 20)
+		table.String(COLUMN_COUNTRY_CODE, 2)
+		table.String(COLUMN_PHONE, 50)
+		table.Integer(COLUMN_IS_DEFAULT_SHIPPING).Default(0)
+		table.Integer(COLUMN_IS_DEFAULT_BILLING).Default(0)
+		table.Text(COLUMN_METAS)
+		table.Text(COLUMN_MEMO)
+		table.DateTime(COLUMN_CREATED_AT)
+		table.DateTime(COLUMN_UPDATED_AT)
+		table.DateTime(COLUMN_SOFT_DELETED_AT)
+		table.BigInteger(COLUMN_VERSION).Default(1)
+		table.Index(COLUMN_CUSTOMER_ID)
+		table.Index(COLUMN_SOFT_DELETED_AT)
+	})
+}
+
+// migration_018_order_table_add_addresses adds the shipping and billing
+// address snapshot columns, as JSON
+func migration_018_order_table_add_addresses(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	for _, column := range []string{COLUMN_SHIPPING_ADDRESS, COLUMN_BILLING_ADDRESS} {
+		if schema.HasColumn(store.orderTableName, column) {
+			continue
+		}
+
+		err := schema.Table(store.orderTableName, func(table contractsschema.Blueprint) {
+			table.Text(column).Default(store.dialect.textDefault("{}"))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migration_019_cart_table_create creates the cart table, holding the
+// shopping carts of guests and customers
+func migration_019_cart_table_create(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	if schema.HasTable(store.cartTableName) {
+		return nil
+	}
+
+	return schema.Create(store.cartTableName, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_ID, 40)
+		table.Primary(COLUMN_ID)
+		table.String(COLUMN_STATUS, 20)
+		table.String(COLUMN_CUSTOMER_ID, 40).Default("")
+		table.String(COLUMN_DISCOUNT_ID, 40).Default("")
+		table.String(COLUMN_ORDER_ID, 40).Default("")
+		table.Text(COLUMN_METAS)
+		table.Text(COLUMN_MEMO)
+		table.DateTime(COLUMN_CREATED_AT)
+		table.DateTime(COLUMN_UPDATED_AT)
+		table.DateTime(COLUMN_SOFT_DELETED_AT)
+		table.BigInteger(COLUMN_VERSION).Default(1)
+		table.Index(COLUMN_STATUS, COLUMN_UPDATED_AT)
+		table.Index(COLUMN_CUSTOMER_ID)
+		table.Index(COLUMN_SOFT_DELETED_AT)
+	})
+}
+
+// migration_020_cart_item_table_create creates the cart item table, holding
+// the products in the carts with their price when added
+func migration_020_cart_item_table_create(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	if schema.HasTable(store.cartItemTableName) {
+		return nil
+	}
+
+	return schema.Create(store.cartItemTableName, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_ID, 40)
+		table.Primary(COLUMN_ID)
+		table.String(COLUMN_CART_ID, 40)
+		table.String(COLUMN_PRODUCT_ID, 40)
+		table.String(COLUMN_TITLE, 255)
+		table.Integer(COLUMN_QUANTITY)
+		table.Decimal(COLUMN_PRICE)
+		table.Text(COLUMN_METAS)
+		table.DateTime(COLUMN_CREATED_AT)
+		table.DateTime(COLUMN_UPDATED_AT)
+		table.BigInteger(COLUMN_VERSION).Default(1)
+		table.Index(COLUMN_CART_ID)
+		table.Index(COLUMN_PRODUCT_ID)
+	})
+}
+
+// migration_021_order_table_add_discount adds the discount_id and
+// discount_amount columns, recording the discount an order was placed with
+func migration_021_order_table_add_discount(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	if !schema.HasColumn(store.orderTableName, COLUMN_DISCOUNT_ID) {
+		err := schema.Table(store.orderTableName, func(table contractsschema.Blueprint) {
+			table.String(COLUMN_DISCOUNT_ID, 40).Default("")
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if schema.HasColumn(store.orderTableName, COLUMN_DISCOUNT_AMOUNT) {
+		return nil
+	}
+
+	return schema.Table(store.orderTableName, func(table contractsschema.Blueprint) {
+		table.Decimal(COLUMN_DISCOUNT_AMOUNT).Default(0)
+	})
+}
+
+// migration_022_tax_rate_table_create creates the table of the tax rates,
+// by tax class, country and region
+func migration_022_tax_rate_table_create(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	if schema.HasTable(store.taxRateTableName) {
+		return nil
+	}
+
+	return schema.Create(store.taxRateTableName, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_ID, 40)
+		table.Primary(COLUMN_ID)
+		table.String(COLUMN_TAX_CLASS, 40)
+		table.String(COLUMN_COUNTRY_CODE, 2)
+		table.String(COLUMN_REGION, 100)
+		table.Decimal(COLUMN_RATE).Total(7).Places(4).Default(0)
+		table.String(COLUMN_NAME, 100)
+		table.Text(COLUMN_METAS)
+		table.Text(COLUMN_MEMO)
+		table.DateTime(COLUMN_CREATED_AT)
+		table.DateTime(COLUMN_UPDATED_AT)
+		table.DateTime(COLUMN_SOFT_DELETED_AT)
+		table.BigInteger(COLUMN_VERSION).Default(1)
+		table.Index(COLUMN_COUNTRY_CODE)
+		table.Index(COLUMN_SOFT_DELETED_AT)
+	})
+}
+
+// migration_023_product_table_add_tax_class adds the tax_class column, the
+// existing products falling in the standard class
+func migration_023_product_table_add_tax_class(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	if schema.HasColumn(store.productTableName, COLUMN_TAX_CLASS) {
+		return nil
+	}
+
+	return schema.Table(store.productTableName, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_TAX_CLASS, 40).Default(TAX_CLASS_STANDARD)
+	})
+}
+
+// migration_024_order_table_add_tax adds the tax_amount column, the tax
+// total of an order, and the prices_include_tax flag
+func migration_024_order_table_add_tax(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	if !schema.HasColumn(store.orderTableName, COLUMN_TAX_AMOUNT) {
+		err := schema.Table(store.orderTableName, func(table contractsschema.Blueprint) {
+			table.Decimal(COLUMN_TAX_AMOUNT).Default(0)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if schema.HasColumn(store.orderTableName, COLUMN_PRICES_INCLUDE_TAX) {
+		return nil
+	}
+
+	return schema.Table(store.orderTableName, func(table contractsschema.Blueprint) {
+		table.Integer(COLUMN_PRICES_INCLUDE_TAX).Default(0)
+	})
+}
+
+// migration_025_order_line_item_table_add_tax adds the tax_rate and
+// tax_amount columns, the tax of each line item
+func migration_025_order_line_item_table_add_tax(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	for _, column := range []string{COLUMN_TAX_RATE, COLUMN_TAX_AMOUNT} {
+		if schema.HasColumn(store.orderLineItemTableName, column) {
+			continue
+		}
+
+		err := schema.Table(store.orderLineItemTableName, func(table contractsschema.Blueprint) {
+			if column == COLUMN_TAX_RATE {
+				table.Decimal(column).Total(7).Places(4).Default(0)
+				return
+			}
+
+			table.Decimal(column).Default(0)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migration_026_shipping_zone_table_create creates the table of the
+// shipping zones, the countries and regions shipping methods deliver to
+func migration_026_shipping_zone_table_create(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	if schema.HasTable(store.shippingZoneTableName) {
+		return nil
+	}
+
+	return schema.Create(store.shippingZoneTableName, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_ID, 40)
+		table.Primary(COLUMN_ID)
+		table.String(COLUMN_STATUS, 40)
+		table.String(COLUMN_NAME, 100)
+		table.Text(COLUMN_COUNTRY_CODES)
+		table.Text(COLUMN_REGIONS)
+		table.Text(COLUMN_METAS)
+		table.Text(COLUMN_MEMO)
+		table.DateTime(COLUMN_CREATED_AT)
+		table.DateTime(COLUMN_UPDATED_AT)
+		table.DateTime(COLUMN_SOFT_DELETED_AT)
+		table.BigInteger(COLUMN_VERSION).Default(1)
+		table.Index(COLUMN_STATUS)
+		table.Index(COLUMN_SOFT_DELETED_AT)
+	})
+}
+
+// migration_027_shipping_method_table_create creates the table of the
+// shipping methods, with their rate rules
+func migration_027_shipping_method_table_create(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	if schema.HasTable(store.shippingMethodTableName) {
+		return nil
+	}
+
+	return schema.Create(store.shippingMethodTableName, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_ID, 40)
+		table.Primary(COLUMN_ID)
+		table.String(COLUMN_STATUS, 40)
+		table.String(COLUMN_SHIPPING_ZONE_ID, 40)
+		table.String(COLUMN_NAME, 100)
+		table.String(COLUMN_RATE_TYPE, 40)
+		table.Decimal(COLUMN_AMOUNT).Default(0)
+		table.Text(COLUMN_RATE_RULES)
+		table.Decimal(COLUMN_FREE_OVER_AMOUNT).Default(0)
+		table.Text(COLUMN_METAS)
+		table.Text(COLUMN_MEMO)
+		table.DateTime(COLUMN_CREATED_AT)
+		table.DateTime(COLUMN_UPDATED_AT)
+		table.DateTime(COLUMN_SOFT_DELETED_AT)
+		table.BigInteger(COLUMN_VERSION).Default(1)
+		table.Index(COLUMN_SHIPPING_ZONE_ID)
+		table.Index(COLUMN_SOFT_DELETED_AT)
+	})
+}
+
+// migration_028_product_table_add_dimensions adds the weight, length,
+// width and height columns, 0 for the existing products
+func migration_028_product_table_add_dimensions(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	for _, column := range []string{COLUMN_WEIGHT, COLUMN_LENGTH, COLUMN_WIDTH, COLUMN_HEIGHT} {
+		if schema.HasColumn(store.productTableName, column) {
+			continue
+		}
+
+		err := schema.Table(store.productTableName, func(table contractsschema.Blueprint) {
+			table.Decimal(column).Total(10).Places(3).Default(0)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migration_029_order_table_add_shipping adds the shipping_method_id,
+// shipping_method_name and shipping_amount columns, the shipping method
+// chosen for an order and its cost
+func migration_029_order_table_add_shipping(store *Store, schema contractsschema.Schema, tx contractsorm.Query) error {
+	for _, column := range []string{COLUMN_SHIPPING_METHOD_ID, COLUMN_SHIPPING_METHOD_NAME, COLUMN_SHIPPING_AMOUNT} {
+		if schema.HasColumn(store.orderTableName, column) {
+			continue
+		}
+
+		err := schema.Table(store.orderTableName, func(table contractsschema.Blueprint) {
+			switch column {
+			case COLUMN_SHIPPING_METHOD_ID:
+				table.String(column, 40).Default("")
+			case COLUMN_SHIPPING_METHOD_NAME:
+				table.String(column, 100).Default("")
+			default:
+				table.Decimal(column).Default(0)
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,297 @@
+package shopstore
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestStoreMigrateStatus(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	statuses, err := store.MigrateStatus(context.Background())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(statuses) == 0 {
+		t.Fatal("expected migrations")
+	}
+
+	for i, status := range statuses {
+		if status.Version != i+1 {
+			t.Fatalf("expected version %d, got %d", i+1, status.Version)
+		}
+
+		if !status.Applied || status.AppliedAt == "" {
+			t.Fatalf("expected migration %d %s to be applied", status.Version, status.Name)
+		}
+	}
+}
+
+func TestStoreMigrateTo(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+	s := store.(*Store)
+
+	if err := store.MigrateTo(ctx, 6); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if s.db.Schema().HasColumn(s.productTableName, COLUMN_PARENT_ID) {
+		t.Fatal("expected parent_id to be dropped when migrating down to version 6")
+	}
+
+	statuses, err := store.MigrateStatus(ctx)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	for _, status := range statuses {
+		if status.Applied != (status.Version <= 6) {
+			t.Fatalf("unexpected applied=%v for migration %d", status.Applied, status.Version)
+		}
+	}
+
+	if err := store.MigrateUp(ctx); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if !s.db.Schema().HasColumn(s.productTableName, COLUMN_PARENT_ID) {
+		t.Fatal("expected parent_id to be added back")
+	}
+
+	if err := store.MigrateTo(ctx, -1); err == nil {
+		t.Fatal("expected error for unknown version")
+	}
+
+	if err := store.MigrateTo(ctx, len(statuses)+1); err == nil {
+		t.Fatal("expected error for unknown version")
+	}
+}
+
+func TestStoreMigrateUp_RollsBackFailedMigration(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+	s := store.(*Store)
+
+	if s.dialect == dialectMySQL {
+		t.Skip("MySQL commits DDL statements implicitly, so migrations can not be rolled back")
+	}
+
+	if err := store.MigrateTo(ctx, 4); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// a view named like the product table makes the product table migration fail
+	if err := s.db.Schema().Sql("CREATE VIEW " + s.productTableName + " AS SELECT 1 AS id"); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	t.Cleanup(func() { _ = s.db.Schema().Sql("DROP VIEW " + s.productTableName) })
+
+	if err := store.MigrateUp(ctx); err == nil {
+		t.Fatal("expected the product table migration to fail")
+	}
+
+	if s.db.Schema().HasTable(s.orderLineItemTableName) {
+		t.Fatal("expected the order line item table created in the failed run to be rolled back")
+	}
+
+	statuses, err := store.MigrateStatus(ctx)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	for _, status := range statuses {
+		if status.Applied != (status.Version <= 4) {
+			t.Fatalf("unexpected applied=%v for migration %d", status.Applied, status.Version)
+		}
+	}
+}
+
+func TestStoreMigrateUp_ExistingSchemaWithoutRecords(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+	s := store.(*Store)
+
+	// databases created before migrations were tracked have no migration table
+	if err := s.db.Schema().DropIfExists(s.migrationTableName); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.MigrateUp(ctx); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	statuses, err := store.MigrateStatus(ctx)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	for _, status := range statuses {
+		if !status.Applied {
+			t.Fatalf("expected migration %d to be recorded", status.Version)
+		}
+	}
+}
+
+func TestStoreMigrateDown_DropsAllTables(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+	s := store.(*Store)
+
+	if err := store.MigrateDown(ctx); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	for _, table := range []string{s.categoryTableName, s.productTableName, s.migrationTableName} {
+		if s.db.Schema().HasTable(table) {
+			t.Fatalf("expected table %s to be dropped", table)
+		}
+	}
+}
+
+func TestStoreMigrateUp_ExternalTransaction(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+	s := store.(*Store)
+
+	if err := store.MigrateDown(ctx); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if s.dialect != dialectMySQL {
+		tx, err := s.DB().Begin()
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		if err := store.MigrateUp(ctx, tx); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		if err := tx.Rollback(); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		if s.db.Schema().HasTable(s.productTableName) || s.db.Schema().HasTable(s.migrationTableName) {
+			t.Fatal("expected the rolled back transaction to leave no tables")
+		}
+	}
+
+	tx, err := s.DB().Begin()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.MigrateUp(ctx, tx); err != nil {
+		_ = tx.Rollback()
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	statuses, err := store.MigrateStatus(ctx)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	for _, status := range statuses {
+		if !status.Applied {
+			t.Fatalf("expected migration %d %s to be applied", status.Version, status.Name)
+		}
+	}
+
+	if _, err := store.ProductCount(ctx, NewProductQuery()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+}
+
+func TestStoreMigrateUp_CreatesIndexes(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	s := store.(*Store)
+
+	expected := map[string]string{
+		s.orderTableName:         indexName(s.orderTableName, "index", COLUMN_CUSTOMER_ID),
+		s.orderLineItemTableName: indexName(s.orderLineItemTableName, "index", COLUMN_PRODUCT_ID),
+		s.mediaTableName:         indexName(s.mediaTableName, "index", COLUMN_ENTITY_ID),
+		s.productTableName:       indexName(s.productTableName, "index", COLUMN_SOFT_DELETED_AT),
+		s.discountTableName:      indexName(s.discountTableName, "unique", COLUMN_CODE, COLUMN_SOFT_DELETED_AT),
+	}
+
+	for table, index := range expected {
+		if !s.db.Schema().HasIndex(table, index) {
+			t.Fatalf("expected index %s on %s", index, table)
+		}
+	}
+
+	if err := store.MigrateTo(context.Background(), 8); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	for table, index := range expected {
+		if s.db.Schema().HasIndex(table, index) {
+			t.Fatalf("expected index %s on %s to be dropped", index, table)
+		}
+	}
+}
+
+func TestStoreMigrateUp_DuplicateDiscountCodes(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	if err := store.MigrateTo(ctx, 9); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// inserted directly, as the store writes the columns of later migrations
+	for range 2 {
+		row := map[string]any{}
+		for k, v := range NewDiscount().SetCode("SUMMER").Data() {
+			row[k] = v
+		}
+		delete(row, COLUMN_VERSION)
+
+		if err := store.(*Store).query(ctx).Table(store.DiscountTableName()).Create(row); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	err = store.MigrateUp(ctx)
+	if err == nil || !strings.Contains(err.Error(), "SUMMER") {
+		t.Fatalf("expected duplicate code error, got %v", err)
+	}
+}
@@ -29,7 +29,14 @@ var _ OrderInterface = (*Order)(nil)
 // - Status: pending
 // - Quantity: 1
 // - Price: 0.00 (free)
+// - DiscountID: empty
+// - DiscountAmount: 0.00
+// - TaxAmount: 0.00
+// - PricesIncludeTax: false
+// - ShippingMethodID, ShippingMethodName: empty (none chosen)
+// - ShippingAmount: 0.00
 // - Memo: empty
+// - ShippingAddress, BillingAddress: empty
 // - CreatedAt: current UTC time
 // - UpdatedAt: current UTC time
 // - SoftDeletedAt: max datetime (not deleted)
@@ -40,12 +47,22 @@ func NewOrder() OrderInterface {
 		SetStatus(ORDER_STATUS_PENDING).
 		SetQuantityInt(1). // By default 1
 		SetPriceFloat(0).  // Free. By default
+		SetDiscountID("").
+		SetDiscountAmountFloat(0).
+		SetTaxAmountFloat(0).
+		SetPricesIncludeTax(false).
+		SetShippingMethodID("").
+		SetShippingMethodName("").
+		SetShippingAmountFloat(0).
 		SetMemo("").
 		SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
 		SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
-		SetSoftDeletedAt(MAX_DATETIME)
+		SetSoftDeletedAt(MAX_DATETIME).
+		SetVersion(1)
 
 	_ = o.SetMetas(map[string]string{})
+	_ = o.SetShippingAddress(OrderAddress{})
+	_ = o.SetBillingAddress(OrderAddress{})
 
 	return o
 }
@@ -122,6 +139,16 @@ func (order *Order) IsShipped() bool {
 
 // == GETTERS & SETTERS ========================================================
 
+// GetBillingAddress returns the snapshot of the billing address.
+func (order *Order) GetBillingAddress() (OrderAddress, error) {
+	return order.getAddress(COLUMN_BILLING_ADDRESS)
+}
+
+// SetBillingAddress sets the snapshot of the billing address.
+func (order *Order) SetBillingAddress(address OrderAddress) error {
+	return order.setAddress(COLUMN_BILLING_ADDRESS, address)
+}
+
 // GetCreatedAt returns the creation timestamp as a string.
 func (order *Order) GetCreatedAt() string {
 	return order.Get(COLUMN_CREATED_AT)
@@ -149,6 +176,39 @@ func (order *Order) SetCustomerID(id string) OrderInterface {
 	return order
 }
 
+// GetDiscountAmount returns the amount taken off the order by its discount.
+func (order *Order) GetDiscountAmount() string {
+	return order.Get(COLUMN_DISCOUNT_AMOUNT)
+}
+
+// SetDiscountAmount sets the amount taken off the order by its discount.
+func (order *Order) SetDiscountAmount(amount string) OrderInterface {
+	order.Set(COLUMN_DISCOUNT_AMOUNT, amount)
+	return order
+}
+
+// GetDiscountAmountFloat returns the discount amount as a float64.
+func (order *Order) GetDiscountAmountFloat() float64 {
+	return cast.ToFloat64(order.GetDiscountAmount())
+}
+
+// SetDiscountAmountFloat sets the discount amount from a float64.
+func (order *Order) SetDiscountAmountFloat(amount float64) OrderInterface {
+	order.SetDiscountAmount(cast.ToString(amount))
+	return order
+}
+
+// GetDiscountID returns the ID of the discount redeemed by the order.
+func (order *Order) GetDiscountID() string {
+	return order.Get(COLUMN_DISCOUNT_ID)
+}
+
+// SetDiscountID sets the ID of the discount redeemed by the order.
+func (order *Order) SetDiscountID(discountID string) OrderInterface {
+	order.Set(COLUMN_DISCOUNT_ID, discountID)
+	return order
+}
+
 // GetID returns the unique identifier.
 func (order *Order) GetID() string {
 	return order.Get(COLUMN_ID)
@@ -292,6 +352,18 @@ func (order *Order) SetPriceFloat(price float64) OrderInterface {
 	return order
 }
 
+// PricesIncludeTax returns true if the prices of the order and its line
+// items include the tax, false if the tax is added on top of them.
+func (order *Order) PricesIncludeTax() bool {
+	return cast.ToBool(order.Get(COLUMN_PRICES_INCLUDE_TAX))
+}
+
+// SetPricesIncludeTax sets whether the prices of the order and its line items include the tax.
+func (order *Order) SetPricesIncludeTax(pricesIncludeTax bool) OrderInterface {
+	order.Set(COLUMN_PRICES_INCLUDE_TAX, flagValue(pricesIncludeTax))
+	return order
+}
+
 // GetQuantity returns the quantity as a string.
 func (order *Order) GetQuantity() string {
 	return order.Get(COLUMN_QUANTITY)
@@ -315,6 +387,60 @@ func (order *Order) SetQuantityInt(quantity int64) OrderInterface {
 	return order
 }
 
+// GetShippingAddress returns the snapshot of the shipping address.
+func (order *Order) GetShippingAddress() (OrderAddress, error) {
+	return order.getAddress(COLUMN_SHIPPING_ADDRESS)
+}
+
+// SetShippingAddress sets the snapshot of the shipping address.
+func (order *Order) SetShippingAddress(address OrderAddress) error {
+	return order.setAddress(COLUMN_SHIPPING_ADDRESS, address)
+}
+
+// GetShippingAmount returns the shipping cost of the order as a string.
+func (order *Order) GetShippingAmount() string {
+	return order.Get(COLUMN_SHIPPING_AMOUNT)
+}
+
+// SetShippingAmount sets the shipping cost of the order from a string.
+func (order *Order) SetShippingAmount(shippingAmount string) OrderInterface {
+	order.Set(COLUMN_SHIPPING_AMOUNT, shippingAmount)
+	return order
+}
+
+// GetShippingAmountFloat returns the shipping cost of the order as a float64.
+func (order *Order) GetShippingAmountFloat() float64 {
+	return cast.ToFloat64(order.GetShippingAmount())
+}
+
+// SetShippingAmountFloat sets the shipping cost of the order from a float64.
+func (order *Order) SetShippingAmountFloat(shippingAmount float64) OrderInterface {
+	order.SetShippingAmount(cast.ToString(shippingAmount))
+	return order
+}
+
+// GetShippingMethodID returns the ID of the shipping method chosen for the order, empty if none is.
+func (order *Order) GetShippingMethodID() string {
+	return order.Get(COLUMN_SHIPPING_METHOD_ID)
+}
+
+// SetShippingMethodID sets the ID of the shipping method chosen for the order.
+func (order *Order) SetShippingMethodID(shippingMethodID string) OrderInterface {
+	order.Set(COLUMN_SHIPPING_METHOD_ID, shippingMethodID)
+	return order
+}
+
+// GetShippingMethodName returns the name of the chosen shipping method, as it was when chosen.
+func (order *Order) GetShippingMethodName() string {
+	return order.Get(COLUMN_SHIPPING_METHOD_NAME)
+}
+
+// SetShippingMethodName sets the name of the chosen shipping method.
+func (order *Order) SetShippingMethodName(shippingMethodName string) OrderInterface {
+	order.Set(COLUMN_SHIPPING_METHOD_NAME, shippingMethodName)
+	return order
+}
+
 // GetSoftDeletedAt returns the soft deletion timestamp.
 func (order *Order) GetSoftDeletedAt() string {
 	return order.Get(COLUMN_SOFT_DELETED_AT)
@@ -331,6 +457,28 @@ func (order *Order) SetSoftDeletedAt(deletedAt string) OrderInterface {
 	return order
 }
 
+// GetTaxAmount returns the total tax of the line items as a string.
+func (order *Order) GetTaxAmount() string {
+	return order.Get(COLUMN_TAX_AMOUNT)
+}
+
+// SetTaxAmount sets the total tax of the line items from a string.
+func (order *Order) SetTaxAmount(taxAmount string) OrderInterface {
+	order.Set(COLUMN_TAX_AMOUNT, taxAmount)
+	return order
+}
+
+// GetTaxAmountFloat returns the total tax of the line items as a float64.
+func (order *Order) GetTaxAmountFloat() float64 {
+	return cast.ToFloat64(order.GetTaxAmount())
+}
+
+// SetTaxAmountFloat sets the total tax of the line items from a float64.
+func (order *Order) SetTaxAmountFloat(taxAmount float64) OrderInterface {
+	order.SetTaxAmount(cast.ToString(taxAmount))
+	return order
+}
+
 // GetUpdatedAt returns the last update timestamp.
 func (order *Order) GetUpdatedAt() string {
 	return order.Get(COLUMN_UPDATED_AT)
@@ -347,7 +495,39 @@ func (order *Order) SetUpdatedAt(updatedAt string) OrderInterface {
 	return order
 }
 
+// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+func (order *Order) GetVersion() int64 {
+	return cast.ToInt64(order.Get(COLUMN_VERSION))
+}
+
+// SetVersion sets the version of the row the entity was loaded at.
+func (order *Order) SetVersion(version int64) OrderInterface {
+	order.Set(COLUMN_VERSION, cast.ToString(version))
+	return order
+}
+
 // MarkAsNotDirty resets the dirty state, clearing all change tracking.
 func (order *Order) MarkAsNotDirty() {
 	order.DataObject.MarkAsNotDirty()
 }
+
+// getAddress returns the address snapshot stored as JSON in the column
+func (order *Order) getAddress(column string) (OrderAddress, error) {
+	addressJSON := order.Get(column)
+	if addressJSON == "" || addressJSON == "null" {
+		return OrderAddress{}, nil
+	}
+	var address OrderAddress
+	err := json.Unmarshal([]byte(addressJSON), &address)
+	return address, err
+}
+
+// setAddress stores the address snapshot as JSON in the column
+func (order *Order) setAddress(column string, address OrderAddress) error {
+	jsonBytes, err := json.Marshal(address)
+	if err != nil {
+		return err
+	}
+	order.Set(column, string(jsonBytes))
+	return nil
+}
@@ -30,6 +30,8 @@ var _ OrderLineItemInterface = (*OrderLineItem)(nil)
 // - Title: empty
 // - Quantity: 1
 // - Price: 0.00 (free)
+// - TaxRate: 0 (percent)
+// - TaxAmount: 0.00
 // - Memo: empty
 // - CreatedAt: current UTC time
 // - UpdatedAt: current UTC time
@@ -42,10 +44,13 @@ func NewOrderLineItem() OrderLineItemInterface {
 		SetTitle("").
 		SetQuantityInt(1). // By default 1
 		SetPriceFloat(0).  // Free. By default
+		SetTaxRateFloat(0).
+		SetTaxAmountFloat(0).
 		SetMemo("").
 		SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
 		SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
-		SetSoftDeletedAt(MAX_DATETIME)
+		SetSoftDeletedAt(MAX_DATETIME).
+		SetVersion(1)
 
 	_ = o.SetMetas(map[string]string{})
 
@@ -284,6 +289,50 @@ func (o *OrderLineItem) SetStatus(status string) OrderLineItemInterface {
 	return o
 }
 
+// GetTaxAmount returns the tax of the whole line as a string.
+func (o *OrderLineItem) GetTaxAmount() string {
+	return o.Get(COLUMN_TAX_AMOUNT)
+}
+
+// SetTaxAmount sets the tax of the whole line from a string.
+func (o *OrderLineItem) SetTaxAmount(taxAmount string) OrderLineItemInterface {
+	o.Set(COLUMN_TAX_AMOUNT, taxAmount)
+	return o
+}
+
+// GetTaxAmountFloat returns the tax of the whole line as a float64.
+func (o *OrderLineItem) GetTaxAmountFloat() float64 {
+	return cast.ToFloat64(o.GetTaxAmount())
+}
+
+// SetTaxAmountFloat sets the tax of the whole line from a float64.
+func (o *OrderLineItem) SetTaxAmountFloat(taxAmount float64) OrderLineItemInterface {
+	o.SetTaxAmount(cast.ToString(taxAmount))
+	return o
+}
+
+// GetTaxRate returns the tax rate applied to the line, in percent, as a string.
+func (o *OrderLineItem) GetTaxRate() string {
+	return o.Get(COLUMN_TAX_RATE)
+}
+
+// SetTaxRate sets the tax rate applied to the line, in percent, from a string.
+func (o *OrderLineItem) SetTaxRate(taxRate string) OrderLineItemInterface {
+	o.Set(COLUMN_TAX_RATE, taxRate)
+	return o
+}
+
+// GetTaxRateFloat returns the tax rate applied to the line, in percent, as a float64.
+func (o *OrderLineItem) GetTaxRateFloat() float64 {
+	return cast.ToFloat64(o.GetTaxRate())
+}
+
+// SetTaxRateFloat sets the tax rate applied to the line, in percent, from a float64.
+func (o *OrderLineItem) SetTaxRateFloat(taxRate float64) OrderLineItemInterface {
+	o.SetTaxRate(cast.ToString(taxRate))
+	return o
+}
+
 // GetTitle returns the line item title.
 func (o *OrderLineItem) GetTitle() string {
 	return o.Get(COLUMN_TITLE)
@@ -311,6 +360,17 @@ func (o *OrderLineItem) SetUpdatedAt(updatedAt string) OrderLineItemInterface {
 	return o
 }
 
+// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+func (o *OrderLineItem) GetVersion() int64 {
+	return cast.ToInt64(o.Get(COLUMN_VERSION))
+}
+
+// SetVersion sets the version of the row the entity was loaded at.
+func (o *OrderLineItem) SetVersion(version int64) OrderLineItemInterface {
+	o.Set(COLUMN_VERSION, cast.ToString(version))
+	return o
+}
+
 // MarkAsNotDirty resets the dirty state, clearing all change tracking.
 func (o *OrderLineItem) MarkAsNotDirty() {
 	o.DataObject.MarkAsNotDirty()
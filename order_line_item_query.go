@@ -56,6 +56,19 @@ type OrderLineItemQueryInterface interface {
 	SortDirection() string
 	SetSortDirection(sortDirection string) OrderLineItemQueryInterface
 
+	// AddSort adds a sort key. Keys are applied in the order they are added,
+	// after the order_by column if one is set. Only the entity's sortable
+	// columns and the asc/desc directions are accepted by Validate.
+	HasSorts() bool
+	Sorts() []QuerySort
+	AddSort(column string, direction string) OrderLineItemQueryInterface
+
+	// AfterCursor continues a keyset paginated list right after the row the
+	// cursor points at. Cursors are returned as NextCursor by the *ListPage methods.
+	HasAfterCursor() bool
+	AfterCursor() string
+	SetAfterCursor(cursor string) OrderLineItemQueryInterface
+
 	HasSoftDeletedIncluded() bool
 	SoftDeletedIncluded() bool
 	SetSoftDeletedIncluded(softDeletedIncluded bool) OrderLineItemQueryInterface
@@ -137,6 +150,24 @@ func (c *orderLineItemQueryImplementation) Validate() error {
 		return errors.New("orderLineItem query. status cannot be empty")
 	}
 
+	if err := validateSort(c, orderLineItemSortableColumns); err != nil {
+		return errors.New("orderLineItem query. " + err.Error())
+	}
+
+	if c.HasAfterCursor() {
+		if c.AfterCursor() == "" {
+			return errors.New("orderLineItem query. after_cursor cannot be empty")
+		}
+
+		if c.HasOffset() {
+			return errors.New("orderLineItem query. after_cursor cannot be combined with offset")
+		}
+
+		if err := validateCursor(c.AfterCursor(), keysetSort(c)); err != nil {
+			return errors.New("orderLineItem query. after_cursor " + err.Error())
+		}
+	}
+
 	return nil
 }
 
@@ -388,6 +419,24 @@ func (c *orderLineItemQueryImplementation) SetSortDirection(sortDirection string
 	return c
 }
 
+func (c *orderLineItemQueryImplementation) HasSorts() bool {
+	return c.hasProperty("sorts")
+}
+
+func (c *orderLineItemQueryImplementation) Sorts() []QuerySort {
+	if !c.HasSorts() {
+		return []QuerySort{}
+	}
+
+	return c.properties["sorts"].([]QuerySort)
+}
+
+func (c *orderLineItemQueryImplementation) AddSort(column string, direction string) OrderLineItemQueryInterface {
+	c.properties["sorts"] = appendSort(c.Sorts(), column, direction)
+
+	return c
+}
+
 func (c *orderLineItemQueryImplementation) HasSoftDeletedIncluded() bool {
 	return c.hasProperty("soft_deleted_included")
 }
@@ -442,6 +491,24 @@ func (c *orderLineItemQueryImplementation) SetStatusIn(statusIn []string) OrderL
 	return c
 }
 
+func (c *orderLineItemQueryImplementation) HasAfterCursor() bool {
+	return c.hasProperty("after_cursor")
+}
+
+func (c *orderLineItemQueryImplementation) AfterCursor() string {
+	if !c.HasAfterCursor() {
+		return ""
+	}
+
+	return c.properties["after_cursor"].(string)
+}
+
+func (c *orderLineItemQueryImplementation) SetAfterCursor(cursor string) OrderLineItemQueryInterface {
+	c.properties["after_cursor"] = cursor
+
+	return c
+}
+
 func (c *orderLineItemQueryImplementation) hasProperty(name string) bool {
 	_, ok := c.properties[name]
 	return ok
@@ -48,6 +48,19 @@ type OrderQueryInterface interface {
 	SortDirection() string
 	SetSortDirection(sortDirection string) OrderQueryInterface
 
+	// AddSort adds a sort key. Keys are applied in the order they are added,
+	// after the order_by column if one is set. Only the entity's sortable
+	// columns and the asc/desc directions are accepted by Validate.
+	HasSorts() bool
+	Sorts() []QuerySort
+	AddSort(column string, direction string) OrderQueryInterface
+
+	// AfterCursor continues a keyset paginated list right after the row the
+	// cursor points at. Cursors are returned as NextCursor by the *ListPage methods.
+	HasAfterCursor() bool
+	AfterCursor() string
+	SetAfterCursor(cursor string) OrderQueryInterface
+
 	HasSoftDeletedIncluded() bool
 	SoftDeletedIncluded() bool
 	SetSoftDeletedIncluded(softDeletedIncluded bool) OrderQueryInterface
@@ -115,6 +128,24 @@ func (c *orderQueryImplementation) Validate() error {
 		return errors.New("order query. order_by cannot be empty")
 	}
 
+	if err := validateSort(c, orderSortableColumns); err != nil {
+		return errors.New("order query. " + err.Error())
+	}
+
+	if c.HasAfterCursor() {
+		if c.AfterCursor() == "" {
+			return errors.New("order query. after_cursor cannot be empty")
+		}
+
+		if c.HasOffset() {
+			return errors.New("order query. after_cursor cannot be combined with offset")
+		}
+
+		if err := validateCursor(c.AfterCursor(), keysetSort(c)); err != nil {
+			return errors.New("order query. after_cursor " + err.Error())
+		}
+	}
+
 	return nil
 }
 
@@ -312,6 +343,24 @@ func (c *orderQueryImplementation) SetSortDirection(sortDirection string) OrderQ
 	return c
 }
 
+func (c *orderQueryImplementation) HasSorts() bool {
+	return c.hasProperty("sorts")
+}
+
+func (c *orderQueryImplementation) Sorts() []QuerySort {
+	if !c.HasSorts() {
+		return []QuerySort{}
+	}
+
+	return c.properties["sorts"].([]QuerySort)
+}
+
+func (c *orderQueryImplementation) AddSort(column string, direction string) OrderQueryInterface {
+	c.properties["sorts"] = appendSort(c.Sorts(), column, direction)
+
+	return c
+}
+
 func (c *orderQueryImplementation) HasSoftDeletedIncluded() bool {
 	return c.hasProperty("soft_deleted_included")
 }
@@ -366,6 +415,24 @@ func (c *orderQueryImplementation) SetStatusIn(statusIn []string) OrderQueryInte
 	return c
 }
 
+func (c *orderQueryImplementation) HasAfterCursor() bool {
+	return c.hasProperty("after_cursor")
+}
+
+func (c *orderQueryImplementation) AfterCursor() string {
+	if !c.HasAfterCursor() {
+		return ""
+	}
+
+	return c.properties["after_cursor"].(string)
+}
+
+func (c *orderQueryImplementation) SetAfterCursor(cursor string) OrderQueryInterface {
+	c.properties["after_cursor"] = cursor
+
+	return c
+}
+
 func (c *orderQueryImplementation) hasProperty(name string) bool {
 	_, ok := c.properties[name]
 	return ok
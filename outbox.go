@@ -0,0 +1,135 @@
+package shopstore
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dromara/carbon/v2"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+)
+
+// DEFAULT_OUTBOX_TABLE_NAME is the outbox table when
+// NewStoreOptions.OutboxTableName is not set
+const DEFAULT_OUTBOX_TABLE_NAME = "shop_outbox"
+
+// OUTBOX_EVENT_DISCOUNT_REDEEMED is recorded when a cart with a discount
+// is checked out. The payload holds the "order_id", the "code" and the
+// "amount" taken off the order.
+const OUTBOX_EVENT_DISCOUNT_REDEEMED = "discount.redeemed"
+
+// OUTBOX_EVENT_ORDER_CREATED is recorded when an order is created
+const OUTBOX_EVENT_ORDER_CREATED = "order.created"
+
+// OUTBOX_EVENT_ORDER_STATUS_CHANGED is recorded when an order changes
+// status. The payload holds the "from" and "to" statuses.
+const OUTBOX_EVENT_ORDER_STATUS_CHANGED = "order.status_changed"
+
+// OUTBOX_EVENT_PRODUCT_STOCK_LOW is recorded when an update or an
+// adjustment brings the quantity of a product down to
+// NewStoreOptions.LowStockThreshold or below. The payload holds the "quantity" and the "threshold".
+const OUTBOX_EVENT_PRODUCT_STOCK_LOW = "product.stock_low"
+
+// OutboxFetchPending returns up to limit events not yet marked as
+// dispatched, oldest first. Events stay pending until OutboxMarkDispatched
+// is called, so a relay crashing in between delivers them again (at least
+// once delivery).
+func (store *Store) OutboxFetchPending(ctx context.Context, limit int) ([]OutboxEventInterface, error) {
+	if limit < 1 {
+		return []OutboxEventInterface{}, errors.New("outbox fetch pending. limit must be positive")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	var results []map[string]any
+	err := store.query(ctx).
+		Table(store.outboxTableName).
+		Where(COLUMN_DISPATCHED_AT+" = ?", MAX_DATETIME).
+		OrderBy(COLUMN_CREATED_AT, SORT_DIRECTION_ASC).
+		OrderBy(COLUMN_ID, SORT_DIRECTION_ASC).
+		Limit(limit).
+		Get(&results)
+	if err != nil {
+		return []OutboxEventInterface{}, store.operationError("outbox fetch pending", err)
+	}
+
+	list := []OutboxEventInterface{}
+	for _, result := range results {
+		list = append(list, NewOutboxEventFromExistingData(mapAnyToString(result)))
+	}
+
+	return list, nil
+}
+
+// OutboxMarkDispatched marks the events with the given IDs as dispatched,
+// so they are no longer returned by OutboxFetchPending
+func (store *Store) OutboxMarkDispatched(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	_, err := store.query(ctx).
+		Table(store.outboxTableName).
+		WhereIn(COLUMN_ID, lo.ToAnySlice(ids)).
+		Where(COLUMN_DISPATCHED_AT+" = ?", MAX_DATETIME).
+		Update(map[string]any{
+			COLUMN_DISPATCHED_AT: carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC),
+		})
+
+	return store.operationError("outbox mark dispatched", err)
+}
+
+// orderOutboxEvents returns the events caused by writing the changed
+// fields of the order with the given ID, over the stored values before
+func (store *Store) orderOutboxEvents(orderID string, before map[string]string, dataChanged map[string]string) ([]OutboxEventInterface, error) {
+	status, changed := dataChanged[COLUMN_STATUS]
+	if !store.outboxEnabled || !changed {
+		return nil, nil
+	}
+
+	current := before[COLUMN_STATUS]
+	if current == status {
+		return nil, nil
+	}
+
+	event := NewOutboxEvent(OUTBOX_EVENT_ORDER_STATUS_CHANGED, ENTITY_TYPE_ORDER, orderID)
+	if err := event.SetPayloadMap(map[string]string{"from": current, "to": status}); err != nil {
+		return nil, err
+	}
+
+	return []OutboxEventInterface{event}, nil
+}
+
+// productOutboxEvents returns the events caused by writing the changed
+// fields of the product with the given ID, over the stored values before
+func (store *Store) productOutboxEvents(productID string, before map[string]string, dataChanged map[string]string) ([]OutboxEventInterface, error) {
+	quantity, changed := dataChanged[COLUMN_QUANTITY]
+	if !store.outboxEnabled || !changed {
+		return nil, nil
+	}
+
+	return store.productStockLowEvents(productID, cast.ToInt(before[COLUMN_QUANTITY]), cast.ToInt(quantity))
+}
+
+// productStockLowEvents returns the stock low event when the quantity of
+// the product drops from above the low stock threshold to at or below it
+func (store *Store) productStockLowEvents(productID string, from int, to int) ([]OutboxEventInterface, error) {
+	if from <= store.lowStockThreshold || to > store.lowStockThreshold {
+		return nil, nil
+	}
+
+	event := NewOutboxEvent(OUTBOX_EVENT_PRODUCT_STOCK_LOW, ENTITY_TYPE_PRODUCT, productID)
+	err := event.SetPayloadMap(map[string]string{
+		"quantity":  cast.ToString(to),
+		"threshold": cast.ToString(store.lowStockThreshold),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return []OutboxEventInterface{event}, nil
+}
@@ -0,0 +1,157 @@
+package shopstore
+
+import (
+	"encoding/json"
+
+	"github.com/dracory/dataobject"
+	"github.com/dromara/carbon/v2"
+)
+
+// == TYPE ====================================================================
+
+// OutboxEvent is a domain event (e.g. an order changing status) recorded in
+// the outbox table in the same transaction as the change causing it, to be
+// relayed to a message queue by a worker.
+type OutboxEvent struct {
+	dataobject.DataObject
+}
+
+// == INTERFACES ===============================================================
+
+// Compile-time interface compliance check
+var _ OutboxEventInterface = (*OutboxEvent)(nil)
+
+// == CONSTRUCTORS =============================================================
+
+// NewOutboxEvent creates a new, not yet dispatched, outbox event:
+// - Payload: empty JSON object
+// - CreatedAt: current UTC time
+// - DispatchedAt: max datetime (pending)
+func NewOutboxEvent(eventType string, entityType string, entityID string) OutboxEventInterface {
+	o := (&OutboxEvent{}).
+		SetID(GenerateShortID()).
+		SetType(eventType).
+		SetEntityType(entityType).
+		SetEntityID(entityID).
+		SetPayload("{}").
+		SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
+		SetDispatchedAt(MAX_DATETIME)
+
+	return o
+}
+
+// NewOutboxEventFromExistingData creates an outbox event from existing data map.
+// Used when hydrating from database or external sources.
+func NewOutboxEventFromExistingData(data map[string]string) OutboxEventInterface {
+	o := &OutboxEvent{}
+	o.Hydrate(data)
+	return o
+}
+
+// == SETTESR AND GETTERS =====================================================
+
+// GetCreatedAt returns the creation timestamp as a string.
+func (o *OutboxEvent) GetCreatedAt() string {
+	return o.Get(COLUMN_CREATED_AT)
+}
+
+// SetCreatedAt sets the creation timestamp.
+func (o *OutboxEvent) SetCreatedAt(createdAt string) OutboxEventInterface {
+	o.Set(COLUMN_CREATED_AT, createdAt)
+	return o
+}
+
+// GetDispatchedAt returns the dispatch timestamp, max datetime while pending.
+func (o *OutboxEvent) GetDispatchedAt() string {
+	return o.Get(COLUMN_DISPATCHED_AT)
+}
+
+// SetDispatchedAt sets the dispatch timestamp.
+func (o *OutboxEvent) SetDispatchedAt(dispatchedAt string) OutboxEventInterface {
+	o.Set(COLUMN_DISPATCHED_AT, dispatchedAt)
+	return o
+}
+
+// GetEntityID returns the ID of the entity the event is about.
+func (o *OutboxEvent) GetEntityID() string {
+	return o.Get(COLUMN_ENTITY_ID)
+}
+
+// SetEntityID sets the ID of the entity the event is about.
+func (o *OutboxEvent) SetEntityID(entityID string) OutboxEventInterface {
+	o.Set(COLUMN_ENTITY_ID, entityID)
+	return o
+}
+
+// GetEntityType returns the type of the entity the event is about (e.g. "order").
+func (o *OutboxEvent) GetEntityType() string {
+	return o.Get(COLUMN_ENTITY_TYPE)
+}
+
+// SetEntityType sets the type of the entity the event is about.
+func (o *OutboxEvent) SetEntityType(entityType string) OutboxEventInterface {
+	o.Set(COLUMN_ENTITY_TYPE, entityType)
+	return o
+}
+
+// GetID returns the unique identifier.
+func (o *OutboxEvent) GetID() string {
+	return o.Get(COLUMN_ID)
+}
+
+// SetID sets the unique identifier.
+func (o *OutboxEvent) SetID(id string) OutboxEventInterface {
+	o.Set(COLUMN_ID, id)
+	return o
+}
+
+// GetPayload returns the event payload as a JSON string.
+func (o *OutboxEvent) GetPayload() string {
+	return o.Get(COLUMN_PAYLOAD)
+}
+
+// GetPayloadMap returns the event payload decoded as a map.
+func (o *OutboxEvent) GetPayloadMap() (map[string]string, error) {
+	payload := map[string]string{}
+	if err := json.Unmarshal([]byte(o.GetPayload()), &payload); err != nil {
+		return map[string]string{}, err
+	}
+	return payload, nil
+}
+
+// SetPayload sets the event payload as a JSON string.
+func (o *OutboxEvent) SetPayload(payload string) OutboxEventInterface {
+	o.Set(COLUMN_PAYLOAD, payload)
+	return o
+}
+
+// SetPayloadMap sets the event payload from a map, stored as JSON.
+func (o *OutboxEvent) SetPayloadMap(payload map[string]string) error {
+	jsonBytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	o.SetPayload(string(jsonBytes))
+	return nil
+}
+
+// GetType returns the event type (e.g. "order.status_changed").
+func (o *OutboxEvent) GetType() string {
+	return o.Get(COLUMN_TYPE)
+}
+
+// SetType sets the event type.
+func (o *OutboxEvent) SetType(eventType string) OutboxEventInterface {
+	o.Set(COLUMN_TYPE, eventType)
+	return o
+}
+
+// IsDispatched returns true once the event was marked as dispatched.
+func (o *OutboxEvent) IsDispatched() bool {
+	return o.GetDispatchedAt() != MAX_DATETIME
+}
+
+// MarkAsNotDirty resets the dirty state, clearing all change tracking.
+func (o *OutboxEvent) MarkAsNotDirty() {
+	o.DataObject.MarkAsNotDirty()
+}
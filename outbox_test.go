@@ -0,0 +1,207 @@
+package shopstore
+
+import (
+	"context"
+	"testing"
+)
+
+func initOutboxStore(t *testing.T) *Store {
+	db, err := initDB(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	options := testStoreOptions(db)
+	options.OutboxEnabled = true
+	options.LowStockThreshold = 5
+
+	store, err := NewStore(options)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	return store
+}
+
+func TestStoreOutbox_OrderEvents(t *testing.T) {
+	store := initOutboxStore(t)
+	ctx := context.Background()
+
+	order := NewOrder().SetCustomerID("CUSTOMER01_ID").SetStatus(ORDER_STATUS_PENDING)
+	if err := store.OrderCreate(ctx, order); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	order.SetStatus(ORDER_STATUS_COMPLETED)
+	if err := store.OrderUpdate(ctx, order); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// no status change, no event
+	order.SetMemo("memo")
+	if err := store.OrderUpdate(ctx, order); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	events, err := store.OutboxFetchPending(ctx, 10)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+
+	if events[0].GetType() != OUTBOX_EVENT_ORDER_CREATED || events[0].GetEntityID() != order.GetID() {
+		t.Fatalf("unexpected first event %v", events[0].Data())
+	}
+
+	if events[1].GetType() != OUTBOX_EVENT_ORDER_STATUS_CHANGED || events[1].GetEntityType() != ENTITY_TYPE_ORDER {
+		t.Fatalf("unexpected second event %v", events[1].Data())
+	}
+
+	payload, err := events[1].GetPayloadMap()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if payload["from"] != ORDER_STATUS_PENDING || payload["to"] != ORDER_STATUS_COMPLETED {
+		t.Fatalf("unexpected payload %v", payload)
+	}
+
+	if err := store.OutboxMarkDispatched(ctx, events[0].GetID()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	events, err = store.OutboxFetchPending(ctx, 10)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(events) != 1 || events[0].GetType() != OUTBOX_EVENT_ORDER_STATUS_CHANGED {
+		t.Fatalf("expected only the status changed event to be pending, got %d events", len(events))
+	}
+}
+
+func TestStoreOutbox_ProductStockLow(t *testing.T) {
+	store := initOutboxStore(t)
+	ctx := context.Background()
+
+	product := NewProduct().SetStatus(PRODUCT_STATUS_ACTIVE).SetTitle("Widget").SetQuantityInt(10).SetPriceFloat(10)
+	if err := store.ProductCreate(ctx, product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	for _, quantity := range []int64{6, 5, 2, 8} {
+		product.SetQuantityInt(quantity)
+		if err := store.ProductUpdate(ctx, product); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	events, err := store.OutboxFetchPending(ctx, 10)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// only crossing the threshold (6 -> 5) records an event
+	if len(events) != 1 || events[0].GetType() != OUTBOX_EVENT_PRODUCT_STOCK_LOW {
+		t.Fatalf("expected 1 stock low event, got %d events", len(events))
+	}
+
+	payload, err := events[0].GetPayloadMap()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if payload["quantity"] != "5" || payload["threshold"] != "5" {
+		t.Fatalf("unexpected payload %v", payload)
+	}
+}
+
+func TestStoreOutbox_RolledBackWithTheChange(t *testing.T) {
+	store := initOutboxStore(t)
+	ctx := context.Background()
+
+	order := NewOrder().SetCustomerID("CUSTOMER01_ID").SetStatus(ORDER_STATUS_PENDING)
+	if err := store.OrderCreate(ctx, order); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// without the outbox table the event can not be recorded
+	if err := store.db.Schema().Drop(store.outboxTableName); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	order.SetStatus(ORDER_STATUS_COMPLETED)
+	if err := store.OrderUpdate(ctx, order); err == nil {
+		t.Fatal("expected the update to fail")
+	}
+
+	found, err := store.OrderFindByID(ctx, order.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found.GetStatus() != ORDER_STATUS_PENDING {
+		t.Fatalf("expected the status change to be rolled back, got %s", found.GetStatus())
+	}
+}
+
+func TestStoreOutbox_DisabledByDefault(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	order := NewOrder().SetCustomerID("CUSTOMER01_ID")
+	if err := store.OrderCreate(ctx, order); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	events, err := store.OutboxFetchPending(ctx, 10)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(events) != 0 {
+		t.Fatalf("expected no events, got %d", len(events))
+	}
+}
+
+func TestStoreOutbox_ProductQuantityAdjustStockLow(t *testing.T) {
+	store := initOutboxStore(t)
+	ctx := context.Background()
+
+	product := NewProduct().SetStatus(PRODUCT_STATUS_ACTIVE).SetTitle("Widget").SetQuantityInt(7)
+	if err := store.ProductCreate(ctx, product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	for _, delta := range []int64{-1, -2, -1} {
+		if _, err := store.ProductQuantityAdjust(ctx, product.GetID(), delta, ProductQuantityAdjustOptions{}); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	events, err := store.OutboxFetchPending(ctx, 10)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// 7 -> 6 -> 4 -> 3, crossing the threshold of 5 once
+	if len(events) != 1 || events[0].GetType() != OUTBOX_EVENT_PRODUCT_STOCK_LOW {
+		t.Fatalf("expected 1 stock low event, got %d events", len(events))
+	}
+
+	payload, err := events[0].GetPayloadMap()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if payload["quantity"] != "4" {
+		t.Fatalf("unexpected payload %v", payload)
+	}
+}
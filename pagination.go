@@ -0,0 +1,268 @@
+package shopstore
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+	"github.com/samber/lo"
+)
+
+// ListPage is a single page of a keyset (cursor) paginated list.
+//
+// NextCursor is empty when there are no more items. Otherwise pass it to
+// the query's SetAfterCursor to fetch the following page.
+type ListPage[T any] struct {
+	Items      []T
+	NextCursor string
+	HasMore    bool
+}
+
+// keysetCursor is the decoded form of an opaque pagination cursor.
+// It records the sort the page was produced with, so that a cursor
+// cannot silently be reused against a differently sorted query.
+type keysetCursor struct {
+	Keys []keysetCursorKey `json:"k"`
+	ID   string            `json:"i"`
+}
+
+// keysetCursorKey is the value of a single sort key of the row a cursor points at
+type keysetCursorKey struct {
+	Column    string `json:"c"`
+	Direction string `json:"d"`
+	Value     string `json:"v"`
+}
+
+// keysetOptions is the subset of the query builders describing the keyset
+// order of a list. All entity query interfaces satisfy it.
+type keysetOptions interface {
+	sortOptions
+	HasAfterCursor() bool
+	AfterCursor() string
+}
+
+// keysetSort returns the keys keyset pagination sorts by: the order_by
+// column, if any, followed by the keys added with AddSort. When neither is
+// set the list is sorted by creation time, newest first. Keys following the
+// ID are dropped, as the ID alone already makes the order unique.
+func keysetSort(options sortOptions) []QuerySort {
+	keys := []QuerySort{}
+
+	if options.OrderBy() != "" || len(options.Sorts()) == 0 {
+		keys = append(keys, QuerySort{
+			Column:    lo.Ternary(options.OrderBy() != "", options.OrderBy(), COLUMN_CREATED_AT),
+			Direction: lo.Ternary(options.SortDirection() != "", options.SortDirection(), SORT_DIRECTION_DESC),
+		})
+	}
+
+	keys = append(keys, options.Sorts()...)
+
+	for i := range keys {
+		keys[i].Direction = strings.ToLower(keys[i].Direction)
+
+		if keys[i].Column == COLUMN_ID {
+			return keys[:i+1]
+		}
+	}
+
+	return keys
+}
+
+// encodeCursor builds the opaque cursor pointing right after the given row
+func encodeCursor(keys []QuerySort, data map[string]string) string {
+	cursor := keysetCursor{
+		Keys: make([]keysetCursorKey, 0, len(keys)),
+		ID:   data[COLUMN_ID],
+	}
+
+	for _, key := range keys {
+		cursor.Keys = append(cursor.Keys, keysetCursorKey{
+			Column:    key.Column,
+			Direction: key.Direction,
+			Value:     data[key.Column],
+		})
+	}
+
+	jsonBytes, _ := json.Marshal(cursor) // a struct of strings always marshals
+	return base64.RawURLEncoding.EncodeToString(jsonBytes)
+}
+
+// decodeCursor parses an opaque cursor created by encodeCursor
+func decodeCursor(cursor string) (keysetCursor, error) {
+	jsonBytes, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return keysetCursor{}, errors.New("invalid cursor")
+	}
+
+	var decoded keysetCursor
+	if err := json.Unmarshal(jsonBytes, &decoded); err != nil {
+		return keysetCursor{}, errors.New("invalid cursor")
+	}
+
+	if len(decoded.Keys) == 0 || decoded.ID == "" {
+		return keysetCursor{}, errors.New("invalid cursor")
+	}
+
+	return decoded, nil
+}
+
+// validateCursor checks that a cursor is well formed and was produced by a
+// query sorted the same way as the one it is being applied to.
+func validateCursor(cursor string, keys []QuerySort) error {
+	decoded, err := decodeCursor(cursor)
+	if err != nil {
+		return err
+	}
+
+	if len(decoded.Keys) != len(keys) {
+		return errors.New("cursor does not match the query sort")
+	}
+
+	for i, key := range keys {
+		if decoded.Keys[i].Column != key.Column || decoded.Keys[i].Direction != key.Direction {
+			return errors.New("cursor does not match the query sort")
+		}
+	}
+
+	return nil
+}
+
+// orderByKeyset sorts the query by the given keys, using the ID as a
+// tiebreaker so that rows sharing the same sort values have a stable order.
+func orderByKeyset(q contractsorm.Query, keys []QuerySort) contractsorm.Query {
+	for _, key := range keys {
+		q = q.OrderBy(key.Column, key.Direction)
+	}
+
+	if last := keys[len(keys)-1]; last.Column != COLUMN_ID {
+		q = q.OrderBy(COLUMN_ID, last.Direction)
+	}
+
+	return q
+}
+
+// whereAfterCursor restricts the query to the rows following the cursor
+// in keyset order. The query must also be sorted with orderByKeyset.
+//
+// The columns are taken from the (validated) query keys rather than from
+// the cursor, which only supplies the values, so a forged cursor can never
+// inject a column name into the SQL.
+func whereAfterCursor(q contractsorm.Query, d dialect, keys []QuerySort, cursor string) (contractsorm.Query, error) {
+	if err := validateCursor(cursor, keys); err != nil {
+		return nil, err
+	}
+
+	decoded, _ := decodeCursor(cursor) // already validated
+
+	// the ID tiebreaker is the last key, unless the sort already ends with it
+	columns := make([]QuerySort, 0, len(keys)+1)
+	values := make([]string, 0, len(keys)+1)
+
+	for i, key := range keys {
+		columns = append(columns, key)
+		values = append(values, lo.Ternary(key.Column == COLUMN_ID, decoded.ID, decoded.Keys[i].Value))
+	}
+
+	if last := keys[len(keys)-1]; last.Column != COLUMN_ID {
+		columns = append(columns, QuerySort{Column: COLUMN_ID, Direction: last.Direction})
+		values = append(values, decoded.ID)
+	}
+
+	// (a > ?) OR (a = ? AND b > ?) OR (a = ? AND b = ? AND id > ?)
+	conditions := []string{}
+	args := []any{}
+
+	for i, key := range columns {
+		parts := []string{}
+
+		for j := 0; j < i; j++ {
+			part, partArgs := keysetEqual(columns[j].Column, values[j])
+			parts = append(parts, part)
+			args = append(args, partArgs...)
+		}
+
+		part, partArgs := d.keysetFollowing(key, values[i])
+		parts = append(parts, part)
+		args = append(args, partArgs...)
+
+		conditions = append(conditions, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return q.Where("("+strings.Join(conditions, " OR ")+")", args...), nil
+}
+
+// keysetEqual returns the condition matching the rows holding the cursor
+// value in the column. An empty value of a nullable column is a NULL, as
+// those columns never store empty strings (see rowValue).
+func keysetEqual(column string, value string) (string, []any) {
+	if value == "" && slices.Contains(nullableColumns, column) {
+		return column + " IS NULL", nil
+	}
+
+	return column + " = ?", []any{value}
+}
+
+// keysetFollowing returns the condition matching the rows sorted after the
+// cursor value by the key. NULLs sort before every value, except on
+// PostgreSQL where they sort after them, so whether they follow the cursor
+// depends on the dialect as well as on the direction.
+func (d dialect) keysetFollowing(key QuerySort, value string) (string, []any) {
+	ascending := key.Direction == SORT_DIRECTION_ASC
+	operator := lo.Ternary(ascending, ">", "<")
+
+	if !slices.Contains(nullableColumns, key.Column) {
+		return key.Column + " " + operator + " ?", []any{value}
+	}
+
+	nullsFollow := ascending == (d == dialectPostgres)
+
+	if value == "" {
+		// the cursor is at a NULL: either the values or nothing follows
+		return lo.Ternary(nullsFollow, "1 = 0", key.Column+" IS NOT NULL"), nil
+	}
+
+	if nullsFollow {
+		return "(" + key.Column + " " + operator + " ? OR " + key.Column + " IS NULL)", []any{value}
+	}
+
+	return key.Column + " " + operator + " ?", []any{value}
+}
+
+// keysetPageQuery prepares a list query for fetching a single page: it makes
+// sure the rows are in keyset order and asks for one extra row, which is used
+// to tell whether another page follows.
+func keysetPageQuery(q contractsorm.Query, limit int, options keysetOptions) contractsorm.Query {
+	// with a sort or a cursor the list query is already in keyset order
+	if !isSorted(options) {
+		q = orderByKeyset(q, keysetSort(options))
+	}
+
+	return q.Limit(limit + 1)
+}
+
+// newListPage builds a page from the rows fetched by a keysetPageQuery
+func newListPage[T interface{ Data() map[string]string }](items []T, limit int, options sortOptions) ListPage[T] {
+	page := ListPage[T]{
+		Items: items,
+	}
+
+	if len(items) <= limit {
+		return page
+	}
+
+	page.Items = items[:limit]
+	page.HasMore = true
+
+	page.NextCursor = encodeCursor(keysetSort(options), page.Items[limit-1].Data())
+
+	return page
+}
+
+// isSorted reports whether the list query orders its rows in keyset order
+// itself, which it does when given a sort or a cursor.
+func isSorted(options keysetOptions) bool {
+	return options.HasOrderBy() || options.HasSorts() || options.HasAfterCursor()
+}
@@ -33,6 +33,10 @@ var _ ProductInterface = (*Product)(nil)
 // - Quantity: 0
 // - Price: 0.00 (free)
 // - ParentID: empty (not a variant)
+// - SKU: empty (none)
+// - CategoryID: empty (uncategorized)
+// - TaxClass: standard
+// - Weight, Length, Width, Height: 0 (not set)
 // - Memo: empty
 // - CreatedAt: current UTC time
 // - UpdatedAt: current UTC time
@@ -50,10 +54,18 @@ func NewProduct() ProductInterface {
 		SetQuantityInt(0). // By default 0
 		SetPriceFloat(0).  // Free. By default
 		SetParentID("").   // No parent by default (not a variant)
+		SetSKU("").
+		SetCategoryID("").
+		SetTaxClass(TAX_CLASS_STANDARD).
+		SetWeightFloat(0).
+		SetLengthFloat(0).
+		SetWidthFloat(0).
+		SetHeightFloat(0).
 		SetMemo("").
 		SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
 		SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
-		SetSoftDeletedAt(MAX_DATETIME)
+		SetSoftDeletedAt(MAX_DATETIME).
+		SetVersion(1)
 
 	_ = o.SetMetas(map[string]string{})
 	_ = o.SetVariantMatrixSchema(VariantMatrixSchema{})
@@ -189,6 +201,17 @@ func (product *Product) Slug() string {
 
 // == GETTERS & SETTERS ========================================================
 
+// GetCategoryID returns the ID of the category of the product (empty if none).
+func (product *Product) GetCategoryID() string {
+	return product.Get(COLUMN_CATEGORY_ID)
+}
+
+// SetCategoryID sets the ID of the category of the product.
+func (product *Product) SetCategoryID(categoryID string) ProductInterface {
+	product.Set(COLUMN_CATEGORY_ID, categoryID)
+	return product
+}
+
 // GetCreatedAt returns the creation timestamp as a string.
 func (product *Product) GetCreatedAt() string {
 	return product.Get(COLUMN_CREATED_AT)
@@ -216,6 +239,28 @@ func (product *Product) SetDescription(description string) ProductInterface {
 	return product
 }
 
+// GetHeight returns the height of the product as a string.
+func (product *Product) GetHeight() string {
+	return product.Get(COLUMN_HEIGHT)
+}
+
+// SetHeight sets the height of the product from a string.
+func (product *Product) SetHeight(height string) ProductInterface {
+	product.Set(COLUMN_HEIGHT, height)
+	return product
+}
+
+// GetHeightFloat returns the height of the product as a float64.
+func (product *Product) GetHeightFloat() float64 {
+	return cast.ToFloat64(product.GetHeight())
+}
+
+// SetHeightFloat sets the height of the product from a float64.
+func (product *Product) SetHeightFloat(height float64) ProductInterface {
+	product.SetHeight(cast.ToString(height))
+	return product
+}
+
 // GetID returns the unique identifier.
 func (product *Product) GetID() string {
 	return product.Get(COLUMN_ID)
@@ -227,6 +272,28 @@ func (product *Product) SetID(id string) ProductInterface {
 	return product
 }
 
+// GetLength returns the length of the product as a string.
+func (product *Product) GetLength() string {
+	return product.Get(COLUMN_LENGTH)
+}
+
+// SetLength sets the length of the product from a string.
+func (product *Product) SetLength(length string) ProductInterface {
+	product.Set(COLUMN_LENGTH, length)
+	return product
+}
+
+// GetLengthFloat returns the length of the product as a float64.
+func (product *Product) GetLengthFloat() float64 {
+	return cast.ToFloat64(product.GetLength())
+}
+
+// SetLengthFloat sets the length of the product from a float64.
+func (product *Product) SetLengthFloat(length float64) ProductInterface {
+	product.SetLength(cast.ToString(length))
+	return product
+}
+
 // GetMemo returns the internal memo.
 func (product *Product) GetMemo() string {
 	return product.Get(COLUMN_MEMO)
@@ -378,6 +445,17 @@ func (product *Product) SetShortDescription(shortDescription string) ProductInte
 	return product
 }
 
+// GetSKU returns the stock keeping unit, unique among the live products.
+func (product *Product) GetSKU() string {
+	return product.Get(COLUMN_SKU)
+}
+
+// SetSKU sets the stock keeping unit. Empty means the product has none.
+func (product *Product) SetSKU(sku string) ProductInterface {
+	product.Set(COLUMN_SKU, sku)
+	return product
+}
+
 // GetSoftDeletedAt returns the soft deletion timestamp.
 func (product *Product) GetSoftDeletedAt() string {
 	return product.Get(COLUMN_SOFT_DELETED_AT)
@@ -405,6 +483,17 @@ func (product *Product) SetStatus(status string) ProductInterface {
 	return product
 }
 
+// GetTaxClass returns the tax class, which selects the tax rates of the product.
+func (product *Product) GetTaxClass() string {
+	return product.Get(COLUMN_TAX_CLASS)
+}
+
+// SetTaxClass sets the tax class, which selects the tax rates of the product.
+func (product *Product) SetTaxClass(taxClass string) ProductInterface {
+	product.Set(COLUMN_TAX_CLASS, taxClass)
+	return product
+}
+
 // GetTitle returns the product title.
 func (product *Product) GetTitle() string {
 	return product.Get(COLUMN_TITLE)
@@ -432,6 +521,61 @@ func (product *Product) SetUpdatedAt(updatedAt string) ProductInterface {
 	return product
 }
 
+// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+func (product *Product) GetVersion() int64 {
+	return cast.ToInt64(product.Get(COLUMN_VERSION))
+}
+
+// SetVersion sets the version of the row the entity was loaded at.
+func (product *Product) SetVersion(version int64) ProductInterface {
+	product.Set(COLUMN_VERSION, cast.ToString(version))
+	return product
+}
+
+// GetWeight returns the weight of the product, in the weight unit of the shop as a string.
+func (product *Product) GetWeight() string {
+	return product.Get(COLUMN_WEIGHT)
+}
+
+// SetWeight sets the weight of the product, in the weight unit of the shop from a string.
+func (product *Product) SetWeight(weight string) ProductInterface {
+	product.Set(COLUMN_WEIGHT, weight)
+	return product
+}
+
+// GetWeightFloat returns the weight of the product as a float64.
+func (product *Product) GetWeightFloat() float64 {
+	return cast.ToFloat64(product.GetWeight())
+}
+
+// SetWeightFloat sets the weight of the product, in the weight unit of the shop from a float64.
+func (product *Product) SetWeightFloat(weight float64) ProductInterface {
+	product.SetWeight(cast.ToString(weight))
+	return product
+}
+
+// GetWidth returns the width of the product as a string.
+func (product *Product) GetWidth() string {
+	return product.Get(COLUMN_WIDTH)
+}
+
+// SetWidth sets the width of the product from a string.
+func (product *Product) SetWidth(width string) ProductInterface {
+	product.Set(COLUMN_WIDTH, width)
+	return product
+}
+
+// GetWidthFloat returns the width of the product as a float64.
+func (product *Product) GetWidthFloat() float64 {
+	return cast.ToFloat64(product.GetWidth())
+}
+
+// SetWidthFloat sets the width of the product from a float64.
+func (product *Product) SetWidthFloat(width float64) ProductInterface {
+	product.SetWidth(cast.ToString(width))
+	return product
+}
+
 // MarkAsNotDirty resets the dirty state, clearing all change tracking.
 func (product *Product) MarkAsNotDirty() {
 	product.DataObject.MarkAsNotDirty()
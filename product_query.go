@@ -14,13 +14,29 @@ const (
 	propertyOffset              = "offset"
 	propertyOrderBy             = "order_by"
 	propertySortDirection       = "sort_direction"
+	propertySorts               = "sorts"
 	propertySoftDeletedIncluded = "soft_deleted_included"
 	propertyStatus              = "status"
 	propertyStatusIn            = "status_in"
 	propertyTitleLike           = "title_like"
 	propertyParentID            = "parent_id"
+	propertyParentIDIn          = "parent_id_in"
+	propertySKU                 = "sku"
+	propertySKUIn               = "sku_in"
+	propertyCategoryID          = "category_id"
 	propertyMetasIn             = "metas_in"
 	propertyMetasNotIn          = "metas_not_in"
+	propertyPriceGte            = "price_gte"
+	propertyPriceLte            = "price_lte"
+	propertyQuantityGte         = "quantity_gte"
+	propertyQuantityLte         = "quantity_lte"
+	propertyInStockOnly         = "in_stock_only"
+	propertyIsParent            = "is_parent"
+	propertyIsVariant           = "is_variant"
+	propertyIsSimple            = "is_simple"
+	propertyUpdatedAtGte        = "updated_at_gte"
+	propertyUpdatedAtLte        = "updated_at_lte"
+	propertyAfterCursor         = "after_cursor"
 )
 
 type ProductQueryInterface interface {
@@ -69,6 +85,19 @@ type ProductQueryInterface interface {
 	SortDirection() string
 	SetSortDirection(sortDirection string) ProductQueryInterface
 
+	// AddSort adds a sort key. Keys are applied in the order they are added,
+	// after the order_by column if one is set. Only the entity's sortable
+	// columns and the asc/desc directions are accepted by Validate.
+	HasSorts() bool
+	Sorts() []QuerySort
+	AddSort(column string, direction string) ProductQueryInterface
+
+	// AfterCursor continues a keyset paginated list right after the row the
+	// cursor points at. Cursors are returned as NextCursor by the *ListPage methods.
+	HasAfterCursor() bool
+	AfterCursor() string
+	SetAfterCursor(cursor string) ProductQueryInterface
+
 	HasSoftDeletedIncluded() bool
 	SoftDeletedIncluded() bool
 	SetSoftDeletedIncluded(softDeletedIncluded bool) ProductQueryInterface
@@ -89,6 +118,22 @@ type ProductQueryInterface interface {
 	ParentID() string
 	SetParentID(parentID string) ProductQueryInterface
 
+	HasParentIDIn() bool
+	ParentIDIn() []string
+	SetParentIDIn(parentIDIn []string) ProductQueryInterface
+
+	HasSKU() bool
+	SKU() string
+	SetSKU(sku string) ProductQueryInterface
+
+	HasSKUIn() bool
+	SKUIn() []string
+	SetSKUIn(skuIn []string) ProductQueryInterface
+
+	HasCategoryID() bool
+	CategoryID() string
+	SetCategoryID(categoryID string) ProductQueryInterface
+
 	HasMetasIn() bool
 	MetasIn() map[string]string
 	SetMetasIn(metasIn map[string]string) ProductQueryInterface
@@ -97,6 +142,51 @@ type ProductQueryInterface interface {
 	MetasNotIn() map[string]string
 	SetMetasNotIn(metasNotIn map[string]string) ProductQueryInterface
 
+	HasPriceGte() bool
+	PriceGte() float64
+	SetPriceGte(priceGte float64) ProductQueryInterface
+
+	HasPriceLte() bool
+	PriceLte() float64
+	SetPriceLte(priceLte float64) ProductQueryInterface
+
+	HasQuantityGte() bool
+	QuantityGte() int64
+	SetQuantityGte(quantityGte int64) ProductQueryInterface
+
+	HasQuantityLte() bool
+	QuantityLte() int64
+	SetQuantityLte(quantityLte int64) ProductQueryInterface
+
+	// InStockOnly restricts the results to products with a quantity greater than zero.
+	HasInStockOnly() bool
+	InStockOnly() bool
+	SetInStockOnly(inStockOnly bool) ProductQueryInterface
+
+	// IsParent restricts the results to top-level products that have at least one
+	// (non soft deleted) variant pointing at them.
+	HasIsParent() bool
+	IsParent() bool
+	SetIsParent(isParent bool) ProductQueryInterface
+
+	// IsVariant restricts the results to products that have a parent.
+	HasIsVariant() bool
+	IsVariant() bool
+	SetIsVariant(isVariant bool) ProductQueryInterface
+
+	// IsSimple restricts the results to products that are neither a parent nor a variant.
+	HasIsSimple() bool
+	IsSimple() bool
+	SetIsSimple(isSimple bool) ProductQueryInterface
+
+	HasUpdatedAtGte() bool
+	UpdatedAtGte() string
+	SetUpdatedAtGte(updatedAtGte string) ProductQueryInterface
+
+	HasUpdatedAtLte() bool
+	UpdatedAtLte() string
+	SetUpdatedAtLte(updatedAtLte string) ProductQueryInterface
+
 	hasProperty(name string) bool
 }
 
@@ -160,6 +250,22 @@ func (c *productQueryImplementation) Validate() error {
 		return errors.New("product query. title_like cannot be empty")
 	}
 
+	if c.HasParentIDIn() && len(c.ParentIDIn()) == 0 {
+		return errors.New("product query. parent_id_in cannot be empty")
+	}
+
+	if c.HasSKU() && c.SKU() == "" {
+		return errors.New("product query. sku cannot be empty")
+	}
+
+	if c.HasSKUIn() && len(c.SKUIn()) == 0 {
+		return errors.New("product query. sku_in cannot be empty")
+	}
+
+	if c.HasCategoryID() && c.CategoryID() == "" {
+		return errors.New("product query. category_id cannot be empty")
+	}
+
 	if c.HasMetasIn() {
 		if len(c.MetasIn()) == 0 {
 			return errors.New("product query. metas_in cannot be empty")
@@ -182,6 +288,63 @@ func (c *productQueryImplementation) Validate() error {
 		}
 	}
 
+	if c.HasPriceGte() && c.PriceGte() < 0 {
+		return errors.New("product query. price_gte cannot be negative")
+	}
+
+	if c.HasPriceLte() && c.PriceLte() < 0 {
+		return errors.New("product query. price_lte cannot be negative")
+	}
+
+	if c.HasPriceGte() && c.HasPriceLte() && c.PriceGte() > c.PriceLte() {
+		return errors.New("product query. price_gte cannot be greater than price_lte")
+	}
+
+	if c.HasQuantityGte() && c.HasQuantityLte() && c.QuantityGte() > c.QuantityLte() {
+		return errors.New("product query. quantity_gte cannot be greater than quantity_lte")
+	}
+
+	if c.InStockOnly() && c.HasQuantityLte() && c.QuantityLte() < 1 {
+		return errors.New("product query. in_stock_only cannot be combined with quantity_lte less than 1")
+	}
+
+	kinds := 0
+	for _, selected := range []bool{c.IsParent(), c.IsVariant(), c.IsSimple()} {
+		if selected {
+			kinds++
+		}
+	}
+
+	if kinds > 1 {
+		return errors.New("product query. only one of is_parent, is_variant and is_simple can be set")
+	}
+
+	if c.HasUpdatedAtGte() && c.UpdatedAtGte() == "" {
+		return errors.New("product query. updated_at_gte cannot be empty")
+	}
+
+	if c.HasUpdatedAtLte() && c.UpdatedAtLte() == "" {
+		return errors.New("product query. updated_at_lte cannot be empty")
+	}
+
+	if err := validateSort(c, productSortableColumns); err != nil {
+		return errors.New("product query. " + err.Error())
+	}
+
+	if c.HasAfterCursor() {
+		if c.AfterCursor() == "" {
+			return errors.New("product query. after_cursor cannot be empty")
+		}
+
+		if c.HasOffset() {
+			return errors.New("product query. after_cursor cannot be combined with offset")
+		}
+
+		if err := validateCursor(c.AfterCursor(), keysetSort(c)); err != nil {
+			return errors.New("product query. after_cursor " + err.Error())
+		}
+	}
+
 	return nil
 }
 
@@ -379,6 +542,24 @@ func (c *productQueryImplementation) SetSortDirection(sortDirection string) Prod
 	return c
 }
 
+func (c *productQueryImplementation) HasSorts() bool {
+	return c.hasProperty(propertySorts)
+}
+
+func (c *productQueryImplementation) Sorts() []QuerySort {
+	if !c.HasSorts() {
+		return []QuerySort{}
+	}
+
+	return c.properties[propertySorts].([]QuerySort)
+}
+
+func (c *productQueryImplementation) AddSort(column string, direction string) ProductQueryInterface {
+	c.properties[propertySorts] = appendSort(c.Sorts(), column, direction)
+
+	return c
+}
+
 func (c *productQueryImplementation) HasSoftDeletedIncluded() bool {
 	return c.hasProperty(propertySoftDeletedIncluded)
 }
@@ -469,6 +650,78 @@ func (c *productQueryImplementation) SetParentID(parentID string) ProductQueryIn
 	return c
 }
 
+func (c *productQueryImplementation) HasParentIDIn() bool {
+	return c.hasProperty(propertyParentIDIn)
+}
+
+func (c *productQueryImplementation) ParentIDIn() []string {
+	if !c.HasParentIDIn() {
+		return []string{}
+	}
+
+	return c.properties[propertyParentIDIn].([]string)
+}
+
+func (c *productQueryImplementation) SetParentIDIn(parentIDIn []string) ProductQueryInterface {
+	c.properties[propertyParentIDIn] = parentIDIn
+
+	return c
+}
+
+func (c *productQueryImplementation) HasSKU() bool {
+	return c.hasProperty(propertySKU)
+}
+
+func (c *productQueryImplementation) SKU() string {
+	if !c.HasSKU() {
+		return ""
+	}
+
+	return c.properties[propertySKU].(string)
+}
+
+func (c *productQueryImplementation) SetSKU(sku string) ProductQueryInterface {
+	c.properties[propertySKU] = sku
+
+	return c
+}
+
+func (c *productQueryImplementation) HasSKUIn() bool {
+	return c.hasProperty(propertySKUIn)
+}
+
+func (c *productQueryImplementation) SKUIn() []string {
+	if !c.HasSKUIn() {
+		return []string{}
+	}
+
+	return c.properties[propertySKUIn].([]string)
+}
+
+func (c *productQueryImplementation) SetSKUIn(skuIn []string) ProductQueryInterface {
+	c.properties[propertySKUIn] = skuIn
+
+	return c
+}
+
+func (c *productQueryImplementation) HasCategoryID() bool {
+	return c.hasProperty(propertyCategoryID)
+}
+
+func (c *productQueryImplementation) CategoryID() string {
+	if !c.HasCategoryID() {
+		return ""
+	}
+
+	return c.properties[propertyCategoryID].(string)
+}
+
+func (c *productQueryImplementation) SetCategoryID(categoryID string) ProductQueryInterface {
+	c.properties[propertyCategoryID] = categoryID
+
+	return c
+}
+
 func (c *productQueryImplementation) HasMetasIn() bool {
 	return c.hasProperty(propertyMetasIn)
 }
@@ -505,6 +758,204 @@ func (c *productQueryImplementation) SetMetasNotIn(metasNotIn map[string]string)
 	return c
 }
 
+func (c *productQueryImplementation) HasPriceGte() bool {
+	return c.hasProperty(propertyPriceGte)
+}
+
+func (c *productQueryImplementation) PriceGte() float64 {
+	if !c.HasPriceGte() {
+		return 0
+	}
+
+	return c.properties[propertyPriceGte].(float64)
+}
+
+func (c *productQueryImplementation) SetPriceGte(priceGte float64) ProductQueryInterface {
+	c.properties[propertyPriceGte] = priceGte
+
+	return c
+}
+
+func (c *productQueryImplementation) HasPriceLte() bool {
+	return c.hasProperty(propertyPriceLte)
+}
+
+func (c *productQueryImplementation) PriceLte() float64 {
+	if !c.HasPriceLte() {
+		return 0
+	}
+
+	return c.properties[propertyPriceLte].(float64)
+}
+
+func (c *productQueryImplementation) SetPriceLte(priceLte float64) ProductQueryInterface {
+	c.properties[propertyPriceLte] = priceLte
+
+	return c
+}
+
+func (c *productQueryImplementation) HasQuantityGte() bool {
+	return c.hasProperty(propertyQuantityGte)
+}
+
+func (c *productQueryImplementation) QuantityGte() int64 {
+	if !c.HasQuantityGte() {
+		return 0
+	}
+
+	return c.properties[propertyQuantityGte].(int64)
+}
+
+func (c *productQueryImplementation) SetQuantityGte(quantityGte int64) ProductQueryInterface {
+	c.properties[propertyQuantityGte] = quantityGte
+
+	return c
+}
+
+func (c *productQueryImplementation) HasQuantityLte() bool {
+	return c.hasProperty(propertyQuantityLte)
+}
+
+func (c *productQueryImplementation) QuantityLte() int64 {
+	if !c.HasQuantityLte() {
+		return 0
+	}
+
+	return c.properties[propertyQuantityLte].(int64)
+}
+
+func (c *productQueryImplementation) SetQuantityLte(quantityLte int64) ProductQueryInterface {
+	c.properties[propertyQuantityLte] = quantityLte
+
+	return c
+}
+
+func (c *productQueryImplementation) HasInStockOnly() bool {
+	return c.hasProperty(propertyInStockOnly)
+}
+
+func (c *productQueryImplementation) InStockOnly() bool {
+	if !c.HasInStockOnly() {
+		return false
+	}
+
+	return c.properties[propertyInStockOnly].(bool)
+}
+
+func (c *productQueryImplementation) SetInStockOnly(inStockOnly bool) ProductQueryInterface {
+	c.properties[propertyInStockOnly] = inStockOnly
+
+	return c
+}
+
+func (c *productQueryImplementation) HasIsParent() bool {
+	return c.hasProperty(propertyIsParent)
+}
+
+func (c *productQueryImplementation) IsParent() bool {
+	if !c.HasIsParent() {
+		return false
+	}
+
+	return c.properties[propertyIsParent].(bool)
+}
+
+func (c *productQueryImplementation) SetIsParent(isParent bool) ProductQueryInterface {
+	c.properties[propertyIsParent] = isParent
+
+	return c
+}
+
+func (c *productQueryImplementation) HasIsVariant() bool {
+	return c.hasProperty(propertyIsVariant)
+}
+
+func (c *productQueryImplementation) IsVariant() bool {
+	if !c.HasIsVariant() {
+		return false
+	}
+
+	return c.properties[propertyIsVariant].(bool)
+}
+
+func (c *productQueryImplementation) SetIsVariant(isVariant bool) ProductQueryInterface {
+	c.properties[propertyIsVariant] = isVariant
+
+	return c
+}
+
+func (c *productQueryImplementation) HasIsSimple() bool {
+	return c.hasProperty(propertyIsSimple)
+}
+
+func (c *productQueryImplementation) IsSimple() bool {
+	if !c.HasIsSimple() {
+		return false
+	}
+
+	return c.properties[propertyIsSimple].(bool)
+}
+
+func (c *productQueryImplementation) SetIsSimple(isSimple bool) ProductQueryInterface {
+	c.properties[propertyIsSimple] = isSimple
+
+	return c
+}
+
+func (c *productQueryImplementation) HasUpdatedAtGte() bool {
+	return c.hasProperty(propertyUpdatedAtGte)
+}
+
+func (c *productQueryImplementation) UpdatedAtGte() string {
+	if !c.HasUpdatedAtGte() {
+		return ""
+	}
+
+	return c.properties[propertyUpdatedAtGte].(string)
+}
+
+func (c *productQueryImplementation) SetUpdatedAtGte(updatedAtGte string) ProductQueryInterface {
+	c.properties[propertyUpdatedAtGte] = updatedAtGte
+
+	return c
+}
+
+func (c *productQueryImplementation) HasUpdatedAtLte() bool {
+	return c.hasProperty(propertyUpdatedAtLte)
+}
+
+func (c *productQueryImplementation) UpdatedAtLte() string {
+	if !c.HasUpdatedAtLte() {
+		return ""
+	}
+
+	return c.properties[propertyUpdatedAtLte].(string)
+}
+
+func (c *productQueryImplementation) SetUpdatedAtLte(updatedAtLte string) ProductQueryInterface {
+	c.properties[propertyUpdatedAtLte] = updatedAtLte
+
+	return c
+}
+
+func (c *productQueryImplementation) HasAfterCursor() bool {
+	return c.hasProperty(propertyAfterCursor)
+}
+
+func (c *productQueryImplementation) AfterCursor() string {
+	if !c.HasAfterCursor() {
+		return ""
+	}
+
+	return c.properties[propertyAfterCursor].(string)
+}
+
+func (c *productQueryImplementation) SetAfterCursor(cursor string) ProductQueryInterface {
+	c.properties[propertyAfterCursor] = cursor
+
+	return c
+}
+
 func (c *productQueryImplementation) hasProperty(name string) bool {
 	_, ok := c.properties[name]
 	return ok
@@ -0,0 +1,208 @@
+package shopstore
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// QuerySort is a single key of a (multi-column) list sort
+type QuerySort struct {
+	Column    string
+	Direction string
+}
+
+// Sortable columns, per entity. Only these can be passed to SetOrderBy or
+// AddSort, so that arbitrary strings never reach the ORDER BY clause.
+var auditLogSortableColumns = []string{
+	COLUMN_ID,
+	COLUMN_ENTITY_TYPE,
+	COLUMN_ENTITY_ID,
+	COLUMN_OPERATION,
+	COLUMN_ACTOR_ID,
+	COLUMN_CREATED_AT,
+}
+
+var addressSortableColumns = []string{
+	COLUMN_ID,
+	COLUMN_CUSTOMER_ID,
+	COLUMN_NAME,
+	COLUMN_COUNTRY_CODE,
+	COLUMN_POSTAL_CODE,
+	COLUMN_IS_DEFAULT_SHIPPING,
+	COLUMN_IS_DEFAULT_BILLING,
+	COLUMN_CREATED_AT,
+	COLUMN_UPDATED_AT,
+	COLUMN_SOFT_DELETED_AT,
+}
+
+var cartSortableColumns = []string{
+	COLUMN_ID,
+	COLUMN_STATUS,
+	COLUMN_CUSTOMER_ID,
+	COLUMN_CREATED_AT,
+	COLUMN_UPDATED_AT,
+	COLUMN_SOFT_DELETED_AT,
+}
+
+var categorySortableColumns = []string{
+	COLUMN_ID,
+	COLUMN_STATUS,
+	COLUMN_PARENT_ID,
+	COLUMN_TITLE,
+	COLUMN_CREATED_AT,
+	COLUMN_UPDATED_AT,
+	COLUMN_SOFT_DELETED_AT,
+}
+
+var customerSortableColumns = []string{
+	COLUMN_ID,
+	COLUMN_STATUS,
+	COLUMN_NAME,
+	COLUMN_EMAIL,
+	COLUMN_CREATED_AT,
+	COLUMN_UPDATED_AT,
+	COLUMN_SOFT_DELETED_AT,
+}
+
+var discountSortableColumns = []string{
+	COLUMN_ID,
+	COLUMN_STATUS,
+	COLUMN_TITLE,
+	COLUMN_TYPE,
+	COLUMN_AMOUNT,
+	COLUMN_CODE,
+	COLUMN_STARTS_AT,
+	COLUMN_ENDS_AT,
+	COLUMN_CREATED_AT,
+	COLUMN_UPDATED_AT,
+	COLUMN_SOFT_DELETED_AT,
+}
+
+var mediaSortableColumns = []string{
+	COLUMN_ID,
+	COLUMN_STATUS,
+	COLUMN_ENTITY_ID,
+	COLUMN_SEQUENCE,
+	COLUMN_MEDIA_TYPE,
+	COLUMN_MEDIA_URL,
+	COLUMN_TITLE,
+	COLUMN_CREATED_AT,
+	COLUMN_UPDATED_AT,
+	COLUMN_SOFT_DELETED_AT,
+}
+
+var orderSortableColumns = []string{
+	COLUMN_ID,
+	COLUMN_STATUS,
+	COLUMN_CUSTOMER_ID,
+	COLUMN_QUANTITY,
+	COLUMN_PRICE,
+	COLUMN_CREATED_AT,
+	COLUMN_UPDATED_AT,
+	COLUMN_SOFT_DELETED_AT,
+}
+
+var orderLineItemSortableColumns = []string{
+	COLUMN_ID,
+	COLUMN_STATUS,
+	COLUMN_ORDER_ID,
+	COLUMN_PRODUCT_ID,
+	COLUMN_TITLE,
+	COLUMN_QUANTITY,
+	COLUMN_PRICE,
+	COLUMN_CREATED_AT,
+	COLUMN_UPDATED_AT,
+	COLUMN_SOFT_DELETED_AT,
+}
+
+var productSortableColumns = []string{
+	COLUMN_ID,
+	COLUMN_STATUS,
+	COLUMN_PARENT_ID,
+	COLUMN_SKU,
+	COLUMN_TITLE,
+	COLUMN_QUANTITY,
+	COLUMN_PRICE,
+	COLUMN_CREATED_AT,
+	COLUMN_UPDATED_AT,
+	COLUMN_SOFT_DELETED_AT,
+}
+
+var shippingMethodSortableColumns = []string{
+	COLUMN_ID,
+	COLUMN_STATUS,
+	COLUMN_SHIPPING_ZONE_ID,
+	COLUMN_NAME,
+	COLUMN_RATE_TYPE,
+	COLUMN_AMOUNT,
+	COLUMN_CREATED_AT,
+	COLUMN_UPDATED_AT,
+	COLUMN_SOFT_DELETED_AT,
+}
+
+var shippingZoneSortableColumns = []string{
+	COLUMN_ID,
+	COLUMN_STATUS,
+	COLUMN_NAME,
+	COLUMN_CREATED_AT,
+	COLUMN_UPDATED_AT,
+	COLUMN_SOFT_DELETED_AT,
+}
+
+var taxRateSortableColumns = []string{
+	COLUMN_ID,
+	COLUMN_TAX_CLASS,
+	COLUMN_COUNTRY_CODE,
+	COLUMN_REGION,
+	COLUMN_RATE,
+	COLUMN_NAME,
+	COLUMN_CREATED_AT,
+	COLUMN_UPDATED_AT,
+	COLUMN_SOFT_DELETED_AT,
+}
+
+// sortOptions is the subset of the query builders describing the sort
+type sortOptions interface {
+	HasOrderBy() bool
+	OrderBy() string
+	HasSortDirection() bool
+	SortDirection() string
+	HasSorts() bool
+	Sorts() []QuerySort
+}
+
+// validateSort checks the order_by, sort_direction and the keys added with
+// AddSort against the sortable columns of the entity.
+func validateSort(options sortOptions, sortableColumns []string) error {
+	if options.HasOrderBy() && !slices.Contains(sortableColumns, options.OrderBy()) {
+		return errors.New("order_by " + options.OrderBy() + " is not a sortable column")
+	}
+
+	if options.HasSortDirection() && !isSortDirection(options.SortDirection()) {
+		return errors.New("sort_direction must be asc or desc")
+	}
+
+	for _, sort := range options.Sorts() {
+		if !slices.Contains(sortableColumns, sort.Column) {
+			return errors.New("sort column " + sort.Column + " is not a sortable column")
+		}
+
+		if !isSortDirection(sort.Direction) {
+			return errors.New("sort direction for " + sort.Column + " must be asc or desc")
+		}
+	}
+
+	return nil
+}
+
+// isSortDirection reports whether the direction is asc or desc, in any case
+func isSortDirection(direction string) bool {
+	direction = strings.ToLower(direction)
+	return direction == SORT_DIRECTION_ASC || direction == SORT_DIRECTION_DESC
+}
+
+// appendSort returns a copy of the sorts with the given key added
+func appendSort(sorts []QuerySort, column string, direction string) []QuerySort {
+	return append(slices.Clone(sorts), QuerySort{Column: column, Direction: direction})
+}
@@ -0,0 +1,127 @@
+package shopstore
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// ShippingQuoteRequest tells ShippingQuote what to quote the shipping of:
+// the live line items of an order, or the items of a cart
+type ShippingQuoteRequest struct {
+	// OrderID is the order to quote for. Set either OrderID or CartID.
+	OrderID string
+
+	// CartID is the cart to quote for. Set either OrderID or CartID.
+	CartID string
+
+	// Address is the address to deliver to. It defaults to the shipping
+	// address of the order, or to the default shipping address of the
+	// customer of the cart.
+	Address OrderAddress
+}
+
+// ShippingOption is a shipping method available for a ShippingQuote, and
+// what it costs
+type ShippingOption struct {
+	Method ShippingMethodInterface
+
+	// Zone is the shipping zone of the address the method delivers to
+	Zone ShippingZoneInterface
+
+	// Cost is the shipping cost, rounded to the cent
+	Cost float64
+}
+
+// shippingParcel is what a shipping quote is for
+type shippingParcel struct {
+	// weight is the weight of the products times their quantities
+	weight float64
+
+	// subtotal is the item total less the discount
+	subtotal float64
+}
+
+// Specificity of the match of a shipping zone with an address
+const (
+	shippingZoneNoMatch = iota
+	shippingZoneMatchEverywhere
+	shippingZoneMatchCountry
+	shippingZoneMatchRegion
+)
+
+// shippingZoneMatch tells how specifically the zone covers the address: by
+// one of its regions, by its country, or as a zone without countries
+// delivering everywhere
+func shippingZoneMatch(zone ShippingZoneInterface, address OrderAddress) (int, error) {
+	countryCodes, err := zone.GetCountryCodes()
+	if err != nil {
+		return shippingZoneNoMatch, err
+	}
+
+	regions, err := zone.GetRegions()
+	if err != nil {
+		return shippingZoneNoMatch, err
+	}
+
+	if len(countryCodes) == 0 {
+		return shippingZoneMatchEverywhere, nil
+	}
+
+	countryCode := strings.ToUpper(strings.TrimSpace(address.CountryCode))
+	if !slices.Contains(countryCodes, countryCode) {
+		return shippingZoneNoMatch, nil
+	}
+
+	if len(regions) == 0 {
+		return shippingZoneMatchCountry, nil
+	}
+
+	region := strings.TrimSpace(address.Region)
+	if region != "" && slices.ContainsFunc(regions, func(zoneRegion string) bool {
+		return strings.EqualFold(zoneRegion, region)
+	}) {
+		return shippingZoneMatchRegion, nil
+	}
+
+	return shippingZoneNoMatch, nil
+}
+
+// shippingCostOf returns what the method costs for the parcel. It returns
+// false when the method does not deliver it: a weight or price based
+// method without a rate rule for its weight or subtotal, or an unknown
+// rate type. A method with a free over amount costs nothing from that
+// subtotal on.
+func shippingCostOf(method ShippingMethodInterface, parcel shippingParcel) (float64, bool, error) {
+	cost := 0.0
+
+	switch method.GetRateType() {
+	case SHIPPING_RATE_TYPE_FLAT:
+		cost = method.GetAmountFloat()
+	case SHIPPING_RATE_TYPE_WEIGHT, SHIPPING_RATE_TYPE_PRICE:
+		rules, err := method.GetRateRules()
+		if err != nil {
+			return 0, false, err
+		}
+
+		value := lo.Ternary(method.GetRateType() == SHIPPING_RATE_TYPE_WEIGHT, parcel.weight, parcel.subtotal)
+
+		rule, found := lo.Find(rules, func(rule ShippingRateRule) bool {
+			return value >= rule.Min && (rule.Max == 0 || value < rule.Max)
+		})
+		if !found {
+			return 0, false, nil
+		}
+
+		cost = rule.Cost
+	default:
+		return 0, false, nil
+	}
+
+	if freeOver := method.GetFreeOverAmountFloat(); freeOver > 0 && parcel.subtotal >= freeOver {
+		cost = 0
+	}
+
+	return roundPrice(max(cost, 0)), true, nil
+}
@@ -0,0 +1,408 @@
+package shopstore
+
+import (
+	"encoding/json"
+
+	"github.com/dracory/dataobject"
+	"github.com/dromara/carbon/v2"
+	"github.com/spf13/cast"
+)
+
+// == CONSTANTS ==============================================================
+
+// DEFAULT_SHIPPING_METHOD_TABLE_NAME is the table the shipping methods are
+// stored in when NewStoreOptions.ShippingMethodTableName is not set
+const DEFAULT_SHIPPING_METHOD_TABLE_NAME = "shop_shipping_method"
+
+const SHIPPING_METHOD_STATUS_ACTIVE = "active"
+const SHIPPING_METHOD_STATUS_INACTIVE = "inactive"
+
+// SHIPPING_RATE_TYPE_FLAT charges the amount of the method
+const SHIPPING_RATE_TYPE_FLAT = "flat"
+
+// SHIPPING_RATE_TYPE_WEIGHT charges the cost of the rate rule the total
+// weight of the products falls in
+const SHIPPING_RATE_TYPE_WEIGHT = "weight"
+
+// SHIPPING_RATE_TYPE_PRICE charges the cost of the rate rule the subtotal,
+// after discounts, falls in
+const SHIPPING_RATE_TYPE_PRICE = "price"
+
+// == CLASS ==================================================================
+
+// ShippingMethod represents a way of delivering to the addresses of a
+// shipping zone, like "Standard" or "Express", and what it costs.
+type ShippingMethod struct {
+	dataobject.DataObject
+}
+
+// ShippingRateRule is a bracket of a weight or price based shipping
+// method: a weight or subtotal from Min up to, but excluding, Max costs
+// Cost. A Max of 0 leaves the bracket open ended.
+type ShippingRateRule struct {
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max,omitempty"`
+	Cost float64 `json:"cost"`
+}
+
+// == INTERFACES =============================================================
+
+// Compile-time interface compliance check
+var _ ShippingMethodInterface = (*ShippingMethod)(nil)
+
+// == CONSTRUCTORS ===========================================================
+
+// NewShippingMethod creates a new shipping method with default values:
+// - Status: active
+// - ShippingZoneID: empty
+// - Name: empty
+// - RateType: flat
+// - Amount: 0
+// - RateRules: empty
+// - FreeOverAmount: 0 (never free)
+// - Memo: empty
+// - CreatedAt: current UTC time
+// - UpdatedAt: current UTC time
+// - SoftDeletedAt: max datetime (not deleted)
+// - Metas: empty map
+func NewShippingMethod() ShippingMethodInterface {
+	o := (&ShippingMethod{}).
+		SetID(GenerateShortID()).
+		SetStatus(SHIPPING_METHOD_STATUS_ACTIVE).
+		SetShippingZoneID("").
+		SetName("").
+		SetRateType(SHIPPING_RATE_TYPE_FLAT).
+		SetAmountFloat(0).
+		SetFreeOverAmountFloat(0).
+		SetMemo("").
+		SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
+		SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
+		SetSoftDeletedAt(MAX_DATETIME).
+		SetVersion(1)
+
+	_ = o.SetRateRules([]ShippingRateRule{})
+	_ = o.SetMetas(map[string]string{})
+
+	return o
+}
+
+// NewShippingMethodFromExistingData creates a shipping method from existing data map.
+// Used when hydrating from database or external sources.
+func NewShippingMethodFromExistingData(data map[string]string) ShippingMethodInterface {
+	o := &ShippingMethod{}
+	o.Hydrate(data)
+	return o
+}
+
+// == METHODS ================================================================
+
+// IsActive returns true if the shipping method status is active.
+func (shippingMethod *ShippingMethod) IsActive() bool {
+	return shippingMethod.GetStatus() == SHIPPING_METHOD_STATUS_ACTIVE
+}
+
+// IsInactive returns true if the shipping method status is inactive.
+func (shippingMethod *ShippingMethod) IsInactive() bool {
+	return shippingMethod.GetStatus() == SHIPPING_METHOD_STATUS_INACTIVE
+}
+
+// IsSoftDeleted returns true if the shipping method is soft deleted.
+func (shippingMethod *ShippingMethod) IsSoftDeleted() bool {
+	return shippingMethod.GetSoftDeletedAt() != MAX_DATETIME
+}
+
+// == SETTERS AND GETTERS ====================================================
+
+// GetAmount returns the cost of a flat rate method as a string.
+func (shippingMethod *ShippingMethod) GetAmount() string {
+	return shippingMethod.Get(COLUMN_AMOUNT)
+}
+
+// SetAmount sets the cost of a flat rate method from a string.
+func (shippingMethod *ShippingMethod) SetAmount(amount string) ShippingMethodInterface {
+	shippingMethod.Set(COLUMN_AMOUNT, amount)
+	return shippingMethod
+}
+
+// GetAmountFloat returns the cost of a flat rate method as a float64.
+func (shippingMethod *ShippingMethod) GetAmountFloat() float64 {
+	return cast.ToFloat64(shippingMethod.GetAmount())
+}
+
+// SetAmountFloat sets the cost of a flat rate method from a float64.
+func (shippingMethod *ShippingMethod) SetAmountFloat(amount float64) ShippingMethodInterface {
+	shippingMethod.SetAmount(cast.ToString(amount))
+	return shippingMethod
+}
+
+// GetCreatedAt returns the creation timestamp as a string.
+func (shippingMethod *ShippingMethod) GetCreatedAt() string {
+	return shippingMethod.Get(COLUMN_CREATED_AT)
+}
+
+// GetCreatedAtCarbon returns the creation timestamp as a Carbon instance.
+func (shippingMethod *ShippingMethod) GetCreatedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(shippingMethod.GetCreatedAt())
+}
+
+// SetCreatedAt sets the creation timestamp.
+func (shippingMethod *ShippingMethod) SetCreatedAt(createdAt string) ShippingMethodInterface {
+	shippingMethod.Set(COLUMN_CREATED_AT, createdAt)
+	return shippingMethod
+}
+
+// GetFreeOverAmount returns the subtotal from which the method is free, as a string.
+func (shippingMethod *ShippingMethod) GetFreeOverAmount() string {
+	return shippingMethod.Get(COLUMN_FREE_OVER_AMOUNT)
+}
+
+// SetFreeOverAmount sets the subtotal from which the method is free, from a string.
+func (shippingMethod *ShippingMethod) SetFreeOverAmount(amount string) ShippingMethodInterface {
+	shippingMethod.Set(COLUMN_FREE_OVER_AMOUNT, amount)
+	return shippingMethod
+}
+
+// GetFreeOverAmountFloat returns the subtotal, after discounts, from which
+// the method is free, 0 when it never is.
+func (shippingMethod *ShippingMethod) GetFreeOverAmountFloat() float64 {
+	return cast.ToFloat64(shippingMethod.GetFreeOverAmount())
+}
+
+// SetFreeOverAmountFloat sets the subtotal from which the method is free, 0 for never.
+func (shippingMethod *ShippingMethod) SetFreeOverAmountFloat(amount float64) ShippingMethodInterface {
+	shippingMethod.SetFreeOverAmount(cast.ToString(amount))
+	return shippingMethod
+}
+
+// GetID returns the unique identifier.
+func (shippingMethod *ShippingMethod) GetID() string {
+	return shippingMethod.Get(COLUMN_ID)
+}
+
+// SetID sets the unique identifier.
+func (shippingMethod *ShippingMethod) SetID(id string) ShippingMethodInterface {
+	shippingMethod.Set(COLUMN_ID, id)
+	return shippingMethod
+}
+
+// GetMemo returns the internal memo.
+func (shippingMethod *ShippingMethod) GetMemo() string {
+	return shippingMethod.Get(COLUMN_MEMO)
+}
+
+// SetMemo sets the internal memo.
+func (shippingMethod *ShippingMethod) SetMemo(memo string) ShippingMethodInterface {
+	shippingMethod.Set(COLUMN_MEMO, memo)
+	return shippingMethod
+}
+
+// GetMeta returns a specific metadata value by name. Returns empty string if not found.
+func (shippingMethod *ShippingMethod) GetMeta(name string) string {
+	metas, err := shippingMethod.GetMetas()
+
+	if err != nil {
+		return ""
+	}
+
+	if value, exists := metas[name]; exists {
+		return value
+	}
+
+	return ""
+}
+
+// MetaRemove removes a single metadata entry.
+func (shippingMethod *ShippingMethod) MetaRemove(name string) error {
+	metas, err := shippingMethod.GetMetas()
+
+	if err != nil {
+		return err
+	}
+
+	delete(metas, name)
+
+	return shippingMethod.SetMetas(metas)
+}
+
+// SetMeta sets a single metadata value.
+func (shippingMethod *ShippingMethod) SetMeta(name string, value string) error {
+	return shippingMethod.MetasUpsert(map[string]string{name: value})
+}
+
+// GetMetas returns all metadata as a map. Returns empty map if no metas stored.
+func (shippingMethod *ShippingMethod) GetMetas() (map[string]string, error) {
+	metasStr := shippingMethod.Get(COLUMN_METAS)
+
+	if metasStr == "" {
+		metasStr = "{}"
+	}
+
+	metasJson := map[string]string{}
+	errJson := json.Unmarshal([]byte(metasStr), &metasJson)
+	if errJson != nil {
+		return map[string]string{}, errJson
+	}
+
+	if metasJson == nil {
+		metasJson = map[string]string{}
+	}
+
+	return metasJson, nil
+}
+
+// MetasRemove removes multiple metadata entries.
+func (shippingMethod *ShippingMethod) MetasRemove(names []string) error {
+	for _, name := range names {
+		err := shippingMethod.MetaRemove(name)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MetasUpsert merges the provided metadata with existing values.
+func (shippingMethod *ShippingMethod) MetasUpsert(metas map[string]string) error {
+	currentMetas, err := shippingMethod.GetMetas()
+
+	if err != nil {
+		return err
+	}
+
+	for k, v := range metas {
+		currentMetas[k] = v
+	}
+
+	return shippingMethod.SetMetas(currentMetas)
+}
+
+// SetMetas replaces all metadata with the provided map.
+// Warning: this overwrites any existing metadata.
+func (shippingMethod *ShippingMethod) SetMetas(metas map[string]string) error {
+	mapString, err := json.Marshal(metas)
+
+	if err != nil {
+		return err
+	}
+
+	shippingMethod.Set(COLUMN_METAS, string(mapString))
+
+	return nil
+}
+
+// GetName returns the name of the method shown to shoppers, like "Express".
+func (shippingMethod *ShippingMethod) GetName() string {
+	return shippingMethod.Get(COLUMN_NAME)
+}
+
+// SetName sets the name of the method shown to shoppers.
+func (shippingMethod *ShippingMethod) SetName(name string) ShippingMethodInterface {
+	shippingMethod.Set(COLUMN_NAME, name)
+	return shippingMethod
+}
+
+// GetRateRules returns the brackets of a weight or price based method.
+func (shippingMethod *ShippingMethod) GetRateRules() ([]ShippingRateRule, error) {
+	rulesJSON := shippingMethod.Get(COLUMN_RATE_RULES)
+	if rulesJSON == "" || rulesJSON == "null" {
+		return []ShippingRateRule{}, nil
+	}
+
+	rules := []ShippingRateRule{}
+	err := json.Unmarshal([]byte(rulesJSON), &rules)
+	return rules, err
+}
+
+// SetRateRules sets the brackets of a weight or price based method.
+func (shippingMethod *ShippingMethod) SetRateRules(rules []ShippingRateRule) error {
+	jsonBytes, err := json.Marshal(rules)
+	if err != nil {
+		return err
+	}
+
+	shippingMethod.Set(COLUMN_RATE_RULES, string(jsonBytes))
+	return nil
+}
+
+// GetRateType returns how the cost is calculated (flat, weight or price).
+func (shippingMethod *ShippingMethod) GetRateType() string {
+	return shippingMethod.Get(COLUMN_RATE_TYPE)
+}
+
+// SetRateType sets how the cost is calculated.
+func (shippingMethod *ShippingMethod) SetRateType(rateType string) ShippingMethodInterface {
+	shippingMethod.Set(COLUMN_RATE_TYPE, rateType)
+	return shippingMethod
+}
+
+// GetShippingZoneID returns the ID of the zone the method delivers to.
+func (shippingMethod *ShippingMethod) GetShippingZoneID() string {
+	return shippingMethod.Get(COLUMN_SHIPPING_ZONE_ID)
+}
+
+// SetShippingZoneID sets the ID of the zone the method delivers to.
+func (shippingMethod *ShippingMethod) SetShippingZoneID(shippingZoneID string) ShippingMethodInterface {
+	shippingMethod.Set(COLUMN_SHIPPING_ZONE_ID, shippingZoneID)
+	return shippingMethod
+}
+
+// GetSoftDeletedAt returns the soft deletion timestamp.
+func (shippingMethod *ShippingMethod) GetSoftDeletedAt() string {
+	return shippingMethod.Get(COLUMN_SOFT_DELETED_AT)
+}
+
+// GetSoftDeletedAtCarbon returns the soft deletion timestamp as a Carbon instance.
+func (shippingMethod *ShippingMethod) GetSoftDeletedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(shippingMethod.GetSoftDeletedAt())
+}
+
+// SetSoftDeletedAt sets the soft deletion timestamp.
+func (shippingMethod *ShippingMethod) SetSoftDeletedAt(deletedAt string) ShippingMethodInterface {
+	shippingMethod.Set(COLUMN_SOFT_DELETED_AT, deletedAt)
+	return shippingMethod
+}
+
+// GetStatus returns the status (active or inactive). Only active methods are quoted.
+func (shippingMethod *ShippingMethod) GetStatus() string {
+	return shippingMethod.Get(COLUMN_STATUS)
+}
+
+// SetStatus sets the status.
+func (shippingMethod *ShippingMethod) SetStatus(status string) ShippingMethodInterface {
+	shippingMethod.Set(COLUMN_STATUS, status)
+	return shippingMethod
+}
+
+// GetUpdatedAt returns the last update timestamp.
+func (shippingMethod *ShippingMethod) GetUpdatedAt() string {
+	return shippingMethod.Get(COLUMN_UPDATED_AT)
+}
+
+// GetUpdatedAtCarbon returns the last update timestamp as a Carbon instance.
+func (shippingMethod *ShippingMethod) GetUpdatedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(shippingMethod.GetUpdatedAt())
+}
+
+// SetUpdatedAt sets the last update timestamp.
+func (shippingMethod *ShippingMethod) SetUpdatedAt(updatedAt string) ShippingMethodInterface {
+	shippingMethod.Set(COLUMN_UPDATED_AT, updatedAt)
+	return shippingMethod
+}
+
+// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+func (shippingMethod *ShippingMethod) GetVersion() int64 {
+	return cast.ToInt64(shippingMethod.Get(COLUMN_VERSION))
+}
+
+// SetVersion sets the version of the row the entity was loaded at.
+func (shippingMethod *ShippingMethod) SetVersion(version int64) ShippingMethodInterface {
+	shippingMethod.Set(COLUMN_VERSION, cast.ToString(version))
+	return shippingMethod
+}
+
+// MarkAsNotDirty resets the dirty state, clearing all change tracking.
+func (shippingMethod *ShippingMethod) MarkAsNotDirty() {
+	shippingMethod.DataObject.MarkAsNotDirty()
+}
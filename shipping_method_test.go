@@ -0,0 +1,80 @@
+package shopstore
+
+import "testing"
+
+func TestNewShippingMethodDefaults(t *testing.T) {
+	method := NewShippingMethod()
+	if method == nil {
+		t.Fatal("NewShippingMethod returned nil")
+	}
+
+	if method.GetID() == "" {
+		t.Fatal("expected generated ID to be non-empty")
+	}
+
+	if !method.IsActive() || method.GetRateType() != SHIPPING_RATE_TYPE_FLAT {
+		t.Fatalf("expected an active flat rate method, got %v", method.Data())
+	}
+
+	if method.GetAmountFloat() != 0 || method.GetFreeOverAmountFloat() != 0 {
+		t.Fatalf("expected no amounts, got %v", method.Data())
+	}
+
+	rules, err := method.GetRateRules()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(rules) != 0 {
+		t.Fatalf("expected no rate rules, got %v", rules)
+	}
+
+	if method.GetVersion() != 1 {
+		t.Fatalf("expected version 1, got %d", method.GetVersion())
+	}
+}
+
+func TestShippingCostOf(t *testing.T) {
+	flat := NewShippingMethod().SetAmountFloat(4.99).SetFreeOverAmountFloat(50)
+
+	byWeight := NewShippingMethod().SetRateType(SHIPPING_RATE_TYPE_WEIGHT)
+	_ = byWeight.SetRateRules([]ShippingRateRule{
+		{Min: 0, Max: 2, Cost: 3},
+		{Min: 2, Max: 10, Cost: 7.5},
+	})
+
+	byPrice := NewShippingMethod().SetRateType(SHIPPING_RATE_TYPE_PRICE)
+	_ = byPrice.SetRateRules([]ShippingRateRule{
+		{Min: 0, Max: 25, Cost: 5},
+		{Min: 25, Cost: 2},
+	})
+
+	unknown := NewShippingMethod().SetRateType("pigeon")
+
+	cases := []struct {
+		name      string
+		method    ShippingMethodInterface
+		parcel    shippingParcel
+		cost      float64
+		available bool
+	}{
+		{"flat", flat, shippingParcel{weight: 1, subtotal: 20}, 4.99, true},
+		{"flat free over", flat, shippingParcel{weight: 1, subtotal: 50}, 0, true},
+		{"weight first rule", byWeight, shippingParcel{weight: 1.5, subtotal: 20}, 3, true},
+		{"weight rule boundary", byWeight, shippingParcel{weight: 2, subtotal: 20}, 7.5, true},
+		{"weight over the rules", byWeight, shippingParcel{weight: 10, subtotal: 20}, 0, false},
+		{"price open rule", byPrice, shippingParcel{subtotal: 1000}, 2, true},
+		{"unknown rate type", unknown, shippingParcel{subtotal: 20}, 0, false},
+	}
+
+	for _, c := range cases {
+		cost, available, err := shippingCostOf(c.method, c.parcel)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+
+		if cost != c.cost || available != c.available {
+			t.Fatalf("%s: expected %v (available %v), got %v (available %v)", c.name, c.cost, c.available, cost, available)
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package shopstore
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/dracory/dataobject"
+	"github.com/dromara/carbon/v2"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+)
+
+// == CONSTANTS ==============================================================
+
+// DEFAULT_SHIPPING_ZONE_TABLE_NAME is the table the shipping zones are
+// stored in when NewStoreOptions.ShippingZoneTableName is not set
+const DEFAULT_SHIPPING_ZONE_TABLE_NAME = "shop_shipping_zone"
+
+const SHIPPING_ZONE_STATUS_ACTIVE = "active"
+const SHIPPING_ZONE_STATUS_INACTIVE = "inactive"
+
+// == CLASS ==================================================================
+
+// ShippingZone represents the countries, or regions of countries, a set of
+// shipping methods delivers to. A zone without countries delivers
+// everywhere no other zone does, like a "rest of the world" zone.
+type ShippingZone struct {
+	dataobject.DataObject
+}
+
+// == INTERFACES =============================================================
+
+// Compile-time interface compliance check
+var _ ShippingZoneInterface = (*ShippingZone)(nil)
+
+// == CONSTRUCTORS ===========================================================
+
+// NewShippingZone creates a new shipping zone with default values:
+// - Status: active
+// - Name: empty
+// - CountryCodes, Regions: empty (delivers everywhere)
+// - Memo: empty
+// - CreatedAt: current UTC time
+// - UpdatedAt: current UTC time
+// - SoftDeletedAt: max datetime (not deleted)
+// - Metas: empty map
+func NewShippingZone() ShippingZoneInterface {
+	o := (&ShippingZone{}).
+		SetID(GenerateShortID()).
+		SetStatus(SHIPPING_ZONE_STATUS_ACTIVE).
+		SetName("").
+		SetMemo("").
+		SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
+		SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
+		SetSoftDeletedAt(MAX_DATETIME).
+		SetVersion(1)
+
+	_ = o.SetCountryCodes([]string{})
+	_ = o.SetRegions([]string{})
+	_ = o.SetMetas(map[string]string{})
+
+	return o
+}
+
+// NewShippingZoneFromExistingData creates a shipping zone from existing data map.
+// Used when hydrating from database or external sources.
+func NewShippingZoneFromExistingData(data map[string]string) ShippingZoneInterface {
+	o := &ShippingZone{}
+	o.Hydrate(data)
+	return o
+}
+
+// == METHODS ================================================================
+
+// IsActive returns true if the shipping zone status is active.
+func (shippingZone *ShippingZone) IsActive() bool {
+	return shippingZone.GetStatus() == SHIPPING_ZONE_STATUS_ACTIVE
+}
+
+// IsInactive returns true if the shipping zone status is inactive.
+func (shippingZone *ShippingZone) IsInactive() bool {
+	return shippingZone.GetStatus() == SHIPPING_ZONE_STATUS_INACTIVE
+}
+
+// IsSoftDeleted returns true if the shipping zone is soft deleted.
+func (shippingZone *ShippingZone) IsSoftDeleted() bool {
+	return shippingZone.GetSoftDeletedAt() != MAX_DATETIME
+}
+
+// == SETTERS AND GETTERS ====================================================
+
+// GetCountryCodes returns the ISO 3166-1 alpha-2 codes of the countries of the zone.
+func (shippingZone *ShippingZone) GetCountryCodes() ([]string, error) {
+	return shippingZone.getList(COLUMN_COUNTRY_CODES)
+}
+
+// SetCountryCodes sets the countries of the zone, trimmed and uppercased.
+// No countries makes the zone deliver everywhere.
+func (shippingZone *ShippingZone) SetCountryCodes(countryCodes []string) error {
+	return shippingZone.setList(COLUMN_COUNTRY_CODES, lo.Map(countryCodes, func(countryCode string, _ int) string {
+		return strings.ToUpper(strings.TrimSpace(countryCode))
+	}))
+}
+
+// GetCreatedAt returns the creation timestamp as a string.
+func (shippingZone *ShippingZone) GetCreatedAt() string {
+	return shippingZone.Get(COLUMN_CREATED_AT)
+}
+
+// GetCreatedAtCarbon returns the creation timestamp as a Carbon instance.
+func (shippingZone *ShippingZone) GetCreatedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(shippingZone.GetCreatedAt())
+}
+
+// SetCreatedAt sets the creation timestamp.
+func (shippingZone *ShippingZone) SetCreatedAt(createdAt string) ShippingZoneInterface {
+	shippingZone.Set(COLUMN_CREATED_AT, createdAt)
+	return shippingZone
+}
+
+// GetID returns the unique identifier.
+func (shippingZone *ShippingZone) GetID() string {
+	return shippingZone.Get(COLUMN_ID)
+}
+
+// SetID sets the unique identifier.
+func (shippingZone *ShippingZone) SetID(id string) ShippingZoneInterface {
+	shippingZone.Set(COLUMN_ID, id)
+	return shippingZone
+}
+
+// GetMemo returns the internal memo.
+func (shippingZone *ShippingZone) GetMemo() string {
+	return shippingZone.Get(COLUMN_MEMO)
+}
+
+// SetMemo sets the internal memo.
+func (shippingZone *ShippingZone) SetMemo(memo string) ShippingZoneInterface {
+	shippingZone.Set(COLUMN_MEMO, memo)
+	return shippingZone
+}
+
+// GetMeta returns a specific metadata value by name. Returns empty string if not found.
+func (shippingZone *ShippingZone) GetMeta(name string) string {
+	metas, err := shippingZone.GetMetas()
+
+	if err != nil {
+		return ""
+	}
+
+	if value, exists := metas[name]; exists {
+		return value
+	}
+
+	return ""
+}
+
+// MetaRemove removes a single metadata entry.
+func (shippingZone *ShippingZone) MetaRemove(name string) error {
+	metas, err := shippingZone.GetMetas()
+
+	if err != nil {
+		return err
+	}
+
+	delete(metas, name)
+
+	return shippingZone.SetMetas(metas)
+}
+
+// SetMeta sets a single metadata value.
+func (shippingZone *ShippingZone) SetMeta(name string, value string) error {
+	return shippingZone.MetasUpsert(map[string]string{name: value})
+}
+
+// GetMetas returns all metadata as a map. Returns empty map if no metas stored.
+func (shippingZone *ShippingZone) GetMetas() (map[string]string, error) {
+	metasStr := shippingZone.Get(COLUMN_METAS)
+
+	if metasStr == "" {
+		metasStr = "{}"
+	}
+
+	metasJson := map[string]string{}
+	errJson := json.Unmarshal([]byte(metasStr), &metasJson)
+	if errJson != nil {
+		return map[string]string{}, errJson
+	}
+
+	if metasJson == nil {
+		metasJson = map[string]string{}
+	}
+
+	return metasJson, nil
+}
+
+// MetasRemove removes multiple metadata entries.
+func (shippingZone *ShippingZone) MetasRemove(names []string) error {
+	for _, name := range names {
+		err := shippingZone.MetaRemove(name)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MetasUpsert merges the provided metadata with existing values.
+func (shippingZone *ShippingZone) MetasUpsert(metas map[string]string) error {
+	currentMetas, err := shippingZone.GetMetas()
+
+	if err != nil {
+		return err
+	}
+
+	for k, v := range metas {
+		currentMetas[k] = v
+	}
+
+	return shippingZone.SetMetas(currentMetas)
+}
+
+// SetMetas replaces all metadata with the provided map.
+// Warning: this overwrites any existing metadata.
+func (shippingZone *ShippingZone) SetMetas(metas map[string]string) error {
+	mapString, err := json.Marshal(metas)
+
+	if err != nil {
+		return err
+	}
+
+	shippingZone.Set(COLUMN_METAS, string(mapString))
+
+	return nil
+}
+
+// GetName returns the name of the zone, like "Europe".
+func (shippingZone *ShippingZone) GetName() string {
+	return shippingZone.Get(COLUMN_NAME)
+}
+
+// SetName sets the name of the zone.
+func (shippingZone *ShippingZone) SetName(name string) ShippingZoneInterface {
+	shippingZone.Set(COLUMN_NAME, name)
+	return shippingZone
+}
+
+// GetRegions returns the regions of the zone, empty for the whole countries.
+func (shippingZone *ShippingZone) GetRegions() ([]string, error) {
+	return shippingZone.getList(COLUMN_REGIONS)
+}
+
+// SetRegions sets the regions of the countries of the zone, trimmed. No
+// regions makes the zone cover the whole countries.
+func (shippingZone *ShippingZone) SetRegions(regions []string) error {
+	return shippingZone.setList(COLUMN_REGIONS, lo.Map(regions, func(region string, _ int) string {
+		return strings.TrimSpace(region)
+	}))
+}
+
+// GetSoftDeletedAt returns the soft deletion timestamp.
+func (shippingZone *ShippingZone) GetSoftDeletedAt() string {
+	return shippingZone.Get(COLUMN_SOFT_DELETED_AT)
+}
+
+// GetSoftDeletedAtCarbon returns the soft deletion timestamp as a Carbon instance.
+func (shippingZone *ShippingZone) GetSoftDeletedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(shippingZone.GetSoftDeletedAt())
+}
+
+// SetSoftDeletedAt sets the soft deletion timestamp.
+func (shippingZone *ShippingZone) SetSoftDeletedAt(deletedAt string) ShippingZoneInterface {
+	shippingZone.Set(COLUMN_SOFT_DELETED_AT, deletedAt)
+	return shippingZone
+}
+
+// GetStatus returns the status (active or inactive).
+func (shippingZone *ShippingZone) GetStatus() string {
+	return shippingZone.Get(COLUMN_STATUS)
+}
+
+// SetStatus sets the status.
+func (shippingZone *ShippingZone) SetStatus(status string) ShippingZoneInterface {
+	shippingZone.Set(COLUMN_STATUS, status)
+	return shippingZone
+}
+
+// GetUpdatedAt returns the last update timestamp.
+func (shippingZone *ShippingZone) GetUpdatedAt() string {
+	return shippingZone.Get(COLUMN_UPDATED_AT)
+}
+
+// GetUpdatedAtCarbon returns the last update timestamp as a Carbon instance.
+func (shippingZone *ShippingZone) GetUpdatedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(shippingZone.GetUpdatedAt())
+}
+
+// SetUpdatedAt sets the last update timestamp.
+func (shippingZone *ShippingZone) SetUpdatedAt(updatedAt string) ShippingZoneInterface {
+	shippingZone.Set(COLUMN_UPDATED_AT, updatedAt)
+	return shippingZone
+}
+
+// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+func (shippingZone *ShippingZone) GetVersion() int64 {
+	return cast.ToInt64(shippingZone.Get(COLUMN_VERSION))
+}
+
+// SetVersion sets the version of the row the entity was loaded at.
+func (shippingZone *ShippingZone) SetVersion(version int64) ShippingZoneInterface {
+	shippingZone.Set(COLUMN_VERSION, cast.ToString(version))
+	return shippingZone
+}
+
+// MarkAsNotDirty resets the dirty state, clearing all change tracking.
+func (shippingZone *ShippingZone) MarkAsNotDirty() {
+	shippingZone.DataObject.MarkAsNotDirty()
+}
+
+// getList decodes the JSON list stored in the column
+func (shippingZone *ShippingZone) getList(column string) ([]string, error) {
+	listJSON := shippingZone.Get(column)
+	if listJSON == "" || listJSON == "null" {
+		return []string{}, nil
+	}
+
+	list := []string{}
+	err := json.Unmarshal([]byte(listJSON), &list)
+	return list, err
+}
+
+// setList stores the list in the column as JSON, without the empty and
+// duplicate values
+func (shippingZone *ShippingZone) setList(column string, list []string) error {
+	jsonBytes, err := json.Marshal(lo.Uniq(lo.Compact(list)))
+	if err != nil {
+		return err
+	}
+
+	shippingZone.Set(column, string(jsonBytes))
+	return nil
+}
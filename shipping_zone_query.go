@@ -0,0 +1,391 @@
+package shopstore
+
+import "errors"
+
+type ShippingZoneQueryInterface interface {
+	Validate() error
+
+	Columns() []string
+	SetColumns(columns []string) ShippingZoneQueryInterface
+
+	HasCountOnly() bool
+	IsCountOnly() bool
+	SetCountOnly(countOnly bool) ShippingZoneQueryInterface
+
+	HasCreatedAtGte() bool
+	CreatedAtGte() string
+	SetCreatedAtGte(createdAtGte string) ShippingZoneQueryInterface
+
+	HasCreatedAtLte() bool
+	CreatedAtLte() string
+	SetCreatedAtLte(createdAtLte string) ShippingZoneQueryInterface
+
+	HasID() bool
+	ID() string
+	SetID(id string) ShippingZoneQueryInterface
+
+	HasIDIn() bool
+	IDIn() []string
+	SetIDIn(idIn []string) ShippingZoneQueryInterface
+
+	HasLimit() bool
+	Limit() int
+	SetLimit(limit int) ShippingZoneQueryInterface
+
+	HasOffset() bool
+	Offset() int
+	SetOffset(offset int) ShippingZoneQueryInterface
+
+	HasOrderBy() bool
+	OrderBy() string
+	SetOrderBy(orderBy string) ShippingZoneQueryInterface
+
+	HasSortDirection() bool
+	SortDirection() string
+	SetSortDirection(sortDirection string) ShippingZoneQueryInterface
+
+	// AddSort adds a sort key. Keys are applied in the order they are added,
+	// after the order_by column if one is set. Only the entity's sortable
+	// columns and the asc/desc directions are accepted by Validate.
+	HasSorts() bool
+	Sorts() []QuerySort
+	AddSort(column string, direction string) ShippingZoneQueryInterface
+
+	// AfterCursor continues a keyset paginated list right after the row the
+	// cursor points at. Cursors are returned as NextCursor by the *ListPage methods.
+	HasAfterCursor() bool
+	AfterCursor() string
+	SetAfterCursor(cursor string) ShippingZoneQueryInterface
+
+	HasSoftDeletedIncluded() bool
+	SoftDeletedIncluded() bool
+	SetSoftDeletedIncluded(softDeletedIncluded bool) ShippingZoneQueryInterface
+
+	HasStatus() bool
+	Status() string
+	SetStatus(status string) ShippingZoneQueryInterface
+
+	hasProperty(name string) bool
+}
+
+func NewShippingZoneQuery() ShippingZoneQueryInterface {
+	return &shippingZoneQueryImplementation{
+		properties: make(map[string]any),
+	}
+}
+
+type shippingZoneQueryImplementation struct {
+	properties map[string]any
+}
+
+func (c *shippingZoneQueryImplementation) Validate() error {
+
+	if c.HasCreatedAtGte() && c.CreatedAtGte() == "" {
+		return errors.New("shipping zone query. created_at_gte cannot be empty")
+	}
+
+	if c.HasCreatedAtLte() && c.CreatedAtLte() == "" {
+		return errors.New("shipping zone query. created_at_lte cannot be empty")
+	}
+
+	if c.HasID() && c.ID() == "" {
+		return errors.New("shipping zone query. id cannot be empty")
+	}
+
+	if c.HasIDIn() && len(c.IDIn()) == 0 {
+		return errors.New("shipping zone query. id_in cannot be empty")
+	}
+
+	if c.HasStatus() && c.Status() == "" {
+		return errors.New("shipping zone query. status cannot be empty")
+	}
+
+	if c.HasSortDirection() && c.SortDirection() == "" {
+		return errors.New("shipping zone query. sort_direction cannot be empty")
+	}
+
+	if c.HasLimit() && c.Limit() <= 0 {
+		return errors.New("shipping zone query. limit must be greater than 0")
+	}
+
+	if c.HasOffset() && c.Offset() < 0 {
+		return errors.New("shipping zone query. offset must be greater than or equal to 0")
+	}
+
+	if c.HasOrderBy() && c.OrderBy() == "" {
+		return errors.New("shipping zone query. order_by cannot be empty")
+	}
+
+	if err := validateSort(c, shippingZoneSortableColumns); err != nil {
+		return errors.New("shipping zone query. " + err.Error())
+	}
+
+	if c.HasAfterCursor() {
+		if c.AfterCursor() == "" {
+			return errors.New("shipping zone query. after_cursor cannot be empty")
+		}
+
+		if c.HasOffset() {
+			return errors.New("shipping zone query. after_cursor cannot be combined with offset")
+		}
+
+		if err := validateCursor(c.AfterCursor(), keysetSort(c)); err != nil {
+			return errors.New("shipping zone query. after_cursor " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (c *shippingZoneQueryImplementation) Columns() []string {
+	if !c.hasProperty("columns") {
+		return []string{}
+	}
+
+	return c.properties["columns"].([]string)
+}
+
+func (c *shippingZoneQueryImplementation) SetColumns(columns []string) ShippingZoneQueryInterface {
+	c.properties["columns"] = columns
+
+	return c
+}
+
+func (c *shippingZoneQueryImplementation) HasCountOnly() bool {
+	return c.hasProperty("count_only")
+}
+
+func (c *shippingZoneQueryImplementation) IsCountOnly() bool {
+	if !c.HasCountOnly() {
+		return false
+	}
+
+	return c.properties["count_only"].(bool)
+}
+
+func (c *shippingZoneQueryImplementation) SetCountOnly(countOnly bool) ShippingZoneQueryInterface {
+	c.properties["count_only"] = countOnly
+
+	return c
+}
+
+func (c *shippingZoneQueryImplementation) HasCreatedAtGte() bool {
+	return c.hasProperty("created_at_gte")
+}
+
+func (c *shippingZoneQueryImplementation) CreatedAtGte() string {
+	if !c.HasCreatedAtGte() {
+		return ""
+	}
+
+	return c.properties["created_at_gte"].(string)
+}
+
+func (c *shippingZoneQueryImplementation) SetCreatedAtGte(createdAtGte string) ShippingZoneQueryInterface {
+	c.properties["created_at_gte"] = createdAtGte
+
+	return c
+}
+
+func (c *shippingZoneQueryImplementation) HasCreatedAtLte() bool {
+	return c.hasProperty("created_at_lte")
+}
+
+func (c *shippingZoneQueryImplementation) CreatedAtLte() string {
+	if !c.HasCreatedAtLte() {
+		return ""
+	}
+
+	return c.properties["created_at_lte"].(string)
+}
+
+func (c *shippingZoneQueryImplementation) SetCreatedAtLte(createdAtLte string) ShippingZoneQueryInterface {
+	c.properties["created_at_lte"] = createdAtLte
+
+	return c
+}
+
+func (c *shippingZoneQueryImplementation) HasID() bool {
+	return c.hasProperty("id")
+}
+
+func (c *shippingZoneQueryImplementation) ID() string {
+	if !c.HasID() {
+		return ""
+	}
+
+	return c.properties["id"].(string)
+}
+
+func (c *shippingZoneQueryImplementation) SetID(id string) ShippingZoneQueryInterface {
+	c.properties["id"] = id
+
+	return c
+}
+
+func (c *shippingZoneQueryImplementation) HasIDIn() bool {
+	return c.hasProperty("id_in")
+}
+
+func (c *shippingZoneQueryImplementation) IDIn() []string {
+	if !c.HasIDIn() {
+		return []string{}
+	}
+
+	return c.properties["id_in"].([]string)
+}
+
+func (c *shippingZoneQueryImplementation) SetIDIn(idIn []string) ShippingZoneQueryInterface {
+	c.properties["id_in"] = idIn
+
+	return c
+}
+
+func (c *shippingZoneQueryImplementation) HasLimit() bool {
+	return c.hasProperty("limit")
+}
+
+func (c *shippingZoneQueryImplementation) Limit() int {
+	if !c.HasLimit() {
+		return 0
+	}
+
+	return c.properties["limit"].(int)
+}
+
+func (c *shippingZoneQueryImplementation) SetLimit(limit int) ShippingZoneQueryInterface {
+	c.properties["limit"] = limit
+
+	return c
+}
+
+func (c *shippingZoneQueryImplementation) HasOffset() bool {
+	return c.hasProperty("offset")
+}
+
+func (c *shippingZoneQueryImplementation) Offset() int {
+	if !c.HasOffset() {
+		return 0
+	}
+
+	return c.properties["offset"].(int)
+}
+
+func (c *shippingZoneQueryImplementation) SetOffset(offset int) ShippingZoneQueryInterface {
+	c.properties["offset"] = offset
+
+	return c
+}
+
+func (c *shippingZoneQueryImplementation) HasOrderBy() bool {
+	return c.hasProperty("order_by")
+}
+
+func (c *shippingZoneQueryImplementation) OrderBy() string {
+	if !c.HasOrderBy() {
+		return ""
+	}
+
+	return c.properties["order_by"].(string)
+}
+
+func (c *shippingZoneQueryImplementation) SetOrderBy(orderBy string) ShippingZoneQueryInterface {
+	c.properties["order_by"] = orderBy
+
+	return c
+}
+
+func (c *shippingZoneQueryImplementation) HasSortDirection() bool {
+	return c.hasProperty("sort_direction")
+}
+
+func (c *shippingZoneQueryImplementation) SortDirection() string {
+	if !c.HasSortDirection() {
+		return ""
+	}
+
+	return c.properties["sort_direction"].(string)
+}
+
+func (c *shippingZoneQueryImplementation) SetSortDirection(sortDirection string) ShippingZoneQueryInterface {
+	c.properties["sort_direction"] = sortDirection
+
+	return c
+}
+
+func (c *shippingZoneQueryImplementation) HasSorts() bool {
+	return c.hasProperty("sorts")
+}
+
+func (c *shippingZoneQueryImplementation) Sorts() []QuerySort {
+	if !c.HasSorts() {
+		return []QuerySort{}
+	}
+
+	return c.properties["sorts"].([]QuerySort)
+}
+
+func (c *shippingZoneQueryImplementation) AddSort(column string, direction string) ShippingZoneQueryInterface {
+	c.properties["sorts"] = appendSort(c.Sorts(), column, direction)
+
+	return c
+}
+
+func (c *shippingZoneQueryImplementation) HasSoftDeletedIncluded() bool {
+	return c.hasProperty("soft_deleted_included")
+}
+
+func (c *shippingZoneQueryImplementation) SoftDeletedIncluded() bool {
+	if !c.HasSoftDeletedIncluded() {
+		return false
+	}
+
+	return c.properties["soft_deleted_included"].(bool)
+}
+
+func (c *shippingZoneQueryImplementation) SetSoftDeletedIncluded(softDeletedIncluded bool) ShippingZoneQueryInterface {
+	c.properties["soft_deleted_included"] = softDeletedIncluded
+
+	return c
+}
+
+func (c *shippingZoneQueryImplementation) HasStatus() bool {
+	return c.hasProperty("status")
+}
+
+func (c *shippingZoneQueryImplementation) Status() string {
+	if !c.HasStatus() {
+		return ""
+	}
+
+	return c.properties["status"].(string)
+}
+
+func (c *shippingZoneQueryImplementation) SetStatus(status string) ShippingZoneQueryInterface {
+	c.properties["status"] = status
+
+	return c
+}
+
+func (c *shippingZoneQueryImplementation) HasAfterCursor() bool {
+	return c.hasProperty("after_cursor")
+}
+
+func (c *shippingZoneQueryImplementation) AfterCursor() string {
+	if !c.HasAfterCursor() {
+		return ""
+	}
+
+	return c.properties["after_cursor"].(string)
+}
+
+func (c *shippingZoneQueryImplementation) SetAfterCursor(cursor string) ShippingZoneQueryInterface {
+	c.properties["after_cursor"] = cursor
+
+	return c
+}
+
+func (c *shippingZoneQueryImplementation) hasProperty(name string) bool {
+	_, ok := c.properties[name]
+	return ok
+}
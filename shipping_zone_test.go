@@ -0,0 +1,99 @@
+package shopstore
+
+import "testing"
+
+func TestNewShippingZoneDefaults(t *testing.T) {
+	zone := NewShippingZone()
+	if zone == nil {
+		t.Fatal("NewShippingZone returned nil")
+	}
+
+	if zone.GetID() == "" {
+		t.Fatal("expected generated ID to be non-empty")
+	}
+
+	if !zone.IsActive() {
+		t.Fatalf("expected status %q, got %q", SHIPPING_ZONE_STATUS_ACTIVE, zone.GetStatus())
+	}
+
+	countryCodes, err := zone.GetCountryCodes()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(countryCodes) != 0 {
+		t.Fatalf("expected no countries, got %v", countryCodes)
+	}
+
+	if zone.IsSoftDeleted() {
+		t.Fatal("expected a new shipping zone not to be soft deleted")
+	}
+
+	if zone.GetVersion() != 1 {
+		t.Fatalf("expected version 1, got %d", zone.GetVersion())
+	}
+}
+
+func TestShippingZoneSetCountryCodes(t *testing.T) {
+	zone := NewShippingZone()
+	if err := zone.SetCountryCodes([]string{" us ", "ca", "US", ""}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := zone.SetRegions([]string{" NY ", "NJ", "NY"}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	countryCodes, err := zone.GetCountryCodes()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(countryCodes) != 2 || countryCodes[0] != "US" || countryCodes[1] != "CA" {
+		t.Fatalf("expected the countries uppercased once each, got %v", countryCodes)
+	}
+
+	regions, err := zone.GetRegions()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(regions) != 2 || regions[0] != "NY" || regions[1] != "NJ" {
+		t.Fatalf("expected the regions trimmed once each, got %v", regions)
+	}
+}
+
+func TestShippingZoneMatch(t *testing.T) {
+	everywhere := NewShippingZone()
+
+	country := NewShippingZone()
+	_ = country.SetCountryCodes([]string{"US"})
+
+	region := NewShippingZone()
+	_ = region.SetCountryCodes([]string{"US"})
+	_ = region.SetRegions([]string{"NY"})
+
+	cases := []struct {
+		zone    ShippingZoneInterface
+		address OrderAddress
+		match   int
+	}{
+		{everywhere, OrderAddress{CountryCode: "FR"}, shippingZoneMatchEverywhere},
+		{country, OrderAddress{CountryCode: "us"}, shippingZoneMatchCountry},
+		{country, OrderAddress{CountryCode: "CA"}, shippingZoneNoMatch},
+		{region, OrderAddress{CountryCode: "US", Region: "ny"}, shippingZoneMatchRegion},
+		{region, OrderAddress{CountryCode: "US", Region: "CA"}, shippingZoneNoMatch},
+		{region, OrderAddress{CountryCode: "US"}, shippingZoneNoMatch},
+	}
+
+	for _, c := range cases {
+		match, err := shippingZoneMatch(c.zone, c.address)
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		if match != c.match {
+			t.Fatalf("expected match %d for %v, got %d", c.match, c.address, match)
+		}
+	}
+}
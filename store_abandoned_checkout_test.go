@@ -0,0 +1,250 @@
+package shopstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dromara/carbon/v2"
+)
+
+// backdate sets the column of the row with the ID to days ago
+func backdate(t *testing.T, store StoreInterface, table, column, id string, days int) {
+	t.Helper()
+
+	_, err := store.DB().Exec("UPDATE "+table+" SET "+column+" = ? WHERE id = ?",
+		carbon.Now(carbon.UTC).SubDays(days).ToDateTimeString(carbon.UTC), id)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+}
+
+func TestStoreAbandonedCheckout(t *testing.T) {
+	store := initOutboxStore(t)
+	ctx := context.Background()
+
+	customer := NewCustomer().SetName("Jane Doe").SetEmail("jane@example.com")
+	if err := store.CustomerCreate(ctx, customer); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	book := createCartProduct(t, store, 12.50, 10)
+
+	// a pending order checked out from a cart, which reserved 2 books
+	checkedOut := NewCart().SetCustomerID(customer.GetID())
+	if err := store.CartCreate(ctx, checkedOut); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if _, err := store.CartAddItem(ctx, checkedOut.GetID(), book.GetID(), 2); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	reservedOrder, err := store.CartCheckout(ctx, checkedOut.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// a pending order created directly, which reserved no stock
+	directOrder := NewOrder().SetCustomerID("CUSTOMER02_ID").SetStatus(ORDER_STATUS_PENDING).SetQuantityInt(1).SetPriceFloat(12.50)
+	if err := store.OrderCreate(ctx, directOrder); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	lineItem := NewOrderLineItem().
+		SetOrderID(directOrder.GetID()).
+		SetProductID(book.GetID()).
+		SetQuantityInt(1).
+		SetPriceFloat(12.50)
+	if err := store.OrderLineItemCreate(ctx, lineItem); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	recentOrder := NewOrder().SetCustomerID("CUSTOMER02_ID").SetStatus(ORDER_STATUS_PENDING)
+	if err := store.OrderCreate(ctx, recentOrder); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	abandonedCart := NewCart()
+	recentCart := NewCart()
+	for _, cart := range []CartInterface{abandonedCart, recentCart} {
+		if err := store.CartCreate(ctx, cart); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		if _, err := store.CartAddItem(ctx, cart.GetID(), book.GetID(), 3); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	backdate(t, store, store.OrderTableName(), COLUMN_CREATED_AT, reservedOrder.GetID(), 3)
+	backdate(t, store, store.OrderTableName(), COLUMN_CREATED_AT, directOrder.GetID(), 2)
+	backdate(t, store, store.CartTableName(), COLUMN_UPDATED_AT, abandonedCart.GetID(), 3)
+
+	options := AbandonedCheckoutOptions{OlderThan: 24 * time.Hour}
+
+	if _, err := store.AbandonedCheckoutReport(ctx, AbandonedCheckoutOptions{}); err == nil {
+		t.Fatal("expected an error for a zero threshold")
+	}
+
+	report, err := store.AbandonedCheckoutReport(ctx, options)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(report.Orders) != 2 || len(report.Carts) != 1 {
+		t.Fatalf("expected 2 orders and 1 cart, got %d and %d", len(report.Orders), len(report.Carts))
+	}
+
+	// oldest first
+	first, second := report.Orders[0], report.Orders[1]
+	if first.Order.GetID() != reservedOrder.GetID() || !first.StockReserved || len(first.LineItems) != 1 ||
+		first.Customer == nil || first.Customer.GetID() != customer.GetID() {
+		t.Fatalf("unexpected checked out order %+v", first)
+	}
+
+	if second.Order.GetID() != directOrder.GetID() || second.StockReserved || len(second.LineItems) != 1 || second.Customer != nil {
+		t.Fatalf("unexpected direct order %+v", second)
+	}
+
+	cart := report.Carts[0]
+	if cart.Cart.GetID() != abandonedCart.GetID() || len(cart.Items) != 1 || cart.Customer != nil || cart.Value != 37.5 {
+		t.Fatalf("unexpected cart %+v", cart)
+	}
+
+	if report.OrdersValue != 37.5 || report.CartsValue != 37.5 {
+		t.Fatalf("unexpected values %v and %v", report.OrdersValue, report.CartsValue)
+	}
+
+	limited, err := store.AbandonedCheckoutReport(ctx, AbandonedCheckoutOptions{OlderThan: 24 * time.Hour, Limit: 1})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(limited.Orders) != 1 || limited.Orders[0].Order.GetID() != reservedOrder.GetID() {
+		t.Fatalf("expected the oldest order only, got %d orders", len(limited.Orders))
+	}
+
+	expired, err := store.AbandonedCheckoutExpire(ctx, options)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(expired.OrderIDs) != 2 || len(expired.CartIDs) != 1 || expired.CartIDs[0] != abandonedCart.GetID() {
+		t.Fatalf("unexpected result %+v", expired)
+	}
+
+	for _, id := range []string{reservedOrder.GetID(), directOrder.GetID()} {
+		order, err := store.OrderFindByID(ctx, id)
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		if order.GetStatus() != ORDER_STATUS_CANCELLED {
+			t.Fatalf("expected order %s cancelled, got %q", id, order.GetStatus())
+		}
+	}
+
+	recent, err := store.OrderFindByID(ctx, recentOrder.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if recent.GetStatus() != ORDER_STATUS_PENDING {
+		t.Fatalf("expected the recent order pending, got %q", recent.GetStatus())
+	}
+
+	// only the 2 books reserved by the checkout are given back
+	product, err := store.ProductFindByID(ctx, book.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if product.GetQuantityInt() != 10 {
+		t.Fatalf("expected the stock released, got quantity %d", product.GetQuantityInt())
+	}
+
+	found, err := store.CartFindByID(ctx, abandonedCart.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if !found.IsExpired() {
+		t.Fatalf("expected the abandoned cart expired, got %q", found.GetStatus())
+	}
+
+	events, err := store.OutboxFetchPending(ctx, 20)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	statusChanged := 0
+	for _, event := range events {
+		if event.GetType() == OUTBOX_EVENT_ORDER_STATUS_CHANGED {
+			statusChanged++
+		}
+	}
+
+	if statusChanged != 2 {
+		t.Fatalf("expected 2 order status changed events, got %d", statusChanged)
+	}
+
+	again, err := store.AbandonedCheckoutExpire(ctx, options)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(again.OrderIDs) != 0 || len(again.CartIDs) != 0 {
+		t.Fatalf("expected nothing left to expire, got %+v", again)
+	}
+}
+
+func TestStoreAbandonedCheckoutExpire_RunsAfterUpdateHooks(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	book := createCartProduct(t, store, 12.50, 10)
+
+	cart := NewCart()
+	if err := store.CartCreate(ctx, cart); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if _, err := store.CartAddItem(ctx, cart.GetID(), book.GetID(), 2); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	order, err := store.CartCheckout(ctx, cart.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	backdate(t, store, store.OrderTableName(), COLUMN_CREATED_AT, order.GetID(), 3)
+
+	orders := map[string]string{}
+	store.OrderHooks().AfterUpdate(func(ctx context.Context, order OrderInterface, changed map[string]string) {
+		orders[order.GetID()] = order.GetStatus() + " " + changed[COLUMN_STATUS]
+	})
+
+	products := map[string]string{}
+	store.ProductHooks().AfterUpdate(func(ctx context.Context, product ProductInterface, changed map[string]string) {
+		products[product.GetID()] = product.GetQuantity() + " " + changed[COLUMN_QUANTITY]
+	})
+
+	if _, err := store.AbandonedCheckoutExpire(ctx, AbandonedCheckoutOptions{OlderThan: 24 * time.Hour}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	cancelled := ORDER_STATUS_CANCELLED + " " + ORDER_STATUS_CANCELLED
+	if len(orders) != 1 || orders[order.GetID()] != cancelled {
+		t.Fatalf("expected the AfterUpdate hook run on the cancelled order, got %v", orders)
+	}
+
+	if len(products) != 1 || products[book.GetID()] != "10 10" {
+		t.Fatalf("expected the AfterUpdate hook run on the product given its stock back, got %v", products)
+	}
+}
@@ -0,0 +1,551 @@
+package shopstore
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"maps"
+
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+	neatquery "github.com/dracory/neat/database/query"
+	"github.com/dromara/carbon/v2"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+)
+
+func (store *Store) AddressCount(ctx context.Context, options AddressQueryInterface) (int64, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.addressQuery(ctx, options)
+	if err != nil {
+		return -1, err
+	}
+
+	var count int64
+	if err := q.Count(&count); err != nil {
+		return -1, store.operationError("address count", err)
+	}
+
+	return count, nil
+}
+
+// AddressCreate inserts the address into the address book of its customer.
+// When the address is the default shipping or billing address, the flag is
+// cleared on the other addresses of the customer in the same transaction.
+func (store *Store) AddressCreate(ctx context.Context, address AddressInterface) error {
+	if address == nil {
+		return errors.New("address is nil")
+	}
+
+	if address.GetCustomerID() == "" {
+		return errors.New("address customer id is empty")
+	}
+
+	address.SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+	address.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+	address.SetSoftDeletedAt(MAX_DATETIME)
+
+	if err := store.addressHooks.runBeforeCreate(ctx, address); err != nil {
+		return err
+	}
+
+	data := address.Data()
+	row := map[string]any{}
+	for k, v := range data {
+		row[k] = rowValue(k, v)
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	if err := store.assertReferencesExist(ctx, data, store.addressReferences()); err != nil {
+		return err
+	}
+
+	err := store.addressWrite(ctx, address, addressDefaultFlags(address, data), func(tx contractsorm.Query) (changeRecords, error) {
+		err := statement(tx).Table(store.addressTableName).Create(row)
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_ADDRESS, address.GetID(), AUDIT_OPERATION_CREATE, nil, data),
+		}, err
+	})
+	if err != nil {
+		return store.operationError("address create", err)
+	}
+
+	address.MarkAsNotDirty()
+
+	store.addressHooks.runAfterCreate(ctx, address)
+
+	return nil
+}
+
+func (store *Store) AddressDelete(ctx context.Context, address AddressInterface) error {
+	if address == nil {
+		return errors.New("address is nil")
+	}
+
+	return store.AddressDeleteByID(ctx, address.GetID())
+}
+
+// AddressDeleteByID permanently deletes the address. Orders keep their
+// snapshot of the address.
+func (store *Store) AddressDeleteByID(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("address id is empty")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		before, err := store.storedValues(tx, store.addressTableName, id, nil)
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		_, err = statement(tx).Table(store.addressTableName).Where(COLUMN_ID+" = ?", id).Delete()
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_ADDRESS, id, AUDIT_OPERATION_DELETE, before, nil),
+		}, err
+	})
+	if err != nil {
+		return store.operationError("address delete", err)
+	}
+
+	store.addressHooks.runAfterDelete(ctx, id)
+
+	return nil
+}
+
+func (store *Store) AddressFindByID(ctx context.Context, id string) (AddressInterface, error) {
+	if id == "" {
+		return nil, errors.New("address id is empty")
+	}
+
+	list, err := store.AddressList(ctx, NewAddressQuery().
+		SetID(id).
+		SetLimit(1))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) > 0 {
+		return list[0], nil
+	}
+
+	return nil, nil
+}
+
+func (store *Store) AddressList(ctx context.Context, options AddressQueryInterface) ([]AddressInterface, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.addressQuery(ctx, options)
+	if err != nil {
+		return []AddressInterface{}, err
+	}
+
+	var results []map[string]any
+	if err := q.Get(&results); err != nil {
+		return []AddressInterface{}, store.operationError("address list", err)
+	}
+
+	list := []AddressInterface{}
+
+	lo.ForEach(results, func(result map[string]any, index int) {
+		model := NewAddressFromExistingData(mapAnyToString(result))
+		list = append(list, model)
+	})
+
+	return list, nil
+}
+
+// AddressIterate streams the addresses matching the query options, fetching
+// them from the database in batches instead of loading them all at once.
+// Iteration stops with an error as soon as ctx is cancelled.
+func (store *Store) AddressIterate(ctx context.Context, options AddressQueryInterface) iter.Seq2[AddressInterface, error] {
+	if options == nil {
+		options = NewAddressQuery()
+	}
+
+	build := func(ctx context.Context) (contractsorm.Query, error) {
+		return store.addressQuery(ctx, options)
+	}
+
+	hydrate := func(data map[string]string) AddressInterface {
+		return NewAddressFromExistingData(data)
+	}
+
+	return iterateInBatches(store, ctx, "address iterate", options, build, hydrate)
+}
+
+// AddressListPage returns a single keyset (cursor) paginated page of addresses
+// matching the query options. The page size is taken from SetLimit. Pass the
+// returned NextCursor to SetAfterCursor to fetch the following page.
+func (store *Store) AddressListPage(ctx context.Context, options AddressQueryInterface) (ListPage[AddressInterface], error) {
+	if options == nil {
+		options = NewAddressQuery()
+	}
+
+	if !options.HasLimit() {
+		return ListPage[AddressInterface]{}, errors.New("address list page. limit is required")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.addressQuery(ctx, options)
+	if err != nil {
+		return ListPage[AddressInterface]{}, err
+	}
+
+	q = keysetPageQuery(q, options.Limit(), options)
+
+	var results []map[string]any
+	if err := q.Get(&results); err != nil {
+		return ListPage[AddressInterface]{}, store.operationError("address list page", err)
+	}
+
+	list := []AddressInterface{}
+
+	lo.ForEach(results, func(result map[string]any, index int) {
+		model := NewAddressFromExistingData(mapAnyToString(result))
+		list = append(list, model)
+	})
+
+	return newListPage(list, options.Limit(), options), nil
+}
+
+// AddressSoftDelete soft deletes the address, removing it from the address
+// book of its customer. Orders keep their snapshot of the address.
+func (store *Store) AddressSoftDelete(ctx context.Context, address AddressInterface) error {
+	if address == nil {
+		return errors.New("address is nil")
+	}
+
+	address.SetSoftDeletedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+
+	if err := store.addressUpdate(ctx, address); err != nil {
+		return err
+	}
+
+	store.addressHooks.runAfterSoftDelete(ctx, address.GetID())
+
+	return nil
+}
+
+func (store *Store) AddressSoftDeleteByID(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("address id is empty")
+	}
+
+	address, err := store.AddressFindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if address == nil {
+		return nil
+	}
+
+	return store.AddressSoftDelete(ctx, address)
+}
+
+// AddressSoftDeleteMany soft deletes the live addresses matching the
+// query options in a single transaction, returning how many were soft
+// deleted.
+// AfterSoftDelete hooks run for every address.
+func (store *Store) AddressSoftDeleteMany(ctx context.Context, options AddressQueryInterface) (int64, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	ids, err := store.updateMany(ctx, store.addressBulkTable(options), map[string]string{
+		COLUMN_SOFT_DELETED_AT: carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC),
+	})
+	if err != nil {
+		return 0, store.operationError("address soft delete many", err)
+	}
+
+	for _, id := range ids {
+		store.addressHooks.runAfterSoftDelete(ctx, id)
+	}
+
+	return int64(len(ids)), nil
+}
+
+// AddressUpdate writes the changed fields of the address. When the address
+// becomes the default shipping or billing address, the flag is cleared on
+// the other addresses of the customer in the same transaction.
+func (store *Store) AddressUpdate(ctx context.Context, address AddressInterface) error {
+	if address == nil {
+		return errors.New("address is nil")
+	}
+
+	if err := store.addressHooks.runBeforeUpdate(ctx, address, address.DataChanged()); err != nil {
+		return err
+	}
+
+	changed := address.DataChanged()
+	if err := store.addressUpdate(ctx, address); err != nil {
+		return err
+	}
+
+	store.addressHooks.runAfterUpdate(ctx, address, changed)
+
+	return nil
+}
+
+// AddressUpdateMany sets the fields on all the addresses matching the query
+// options in a single transaction, returning how many were updated. Only
+// the columns listed in addressUpdatableColumns can be set. Versions are
+// incremented without being checked. The AfterUpdate hooks run on the
+// addresses set, but not the Before hooks, as the addresses are not loaded.
+func (store *Store) AddressUpdateMany(ctx context.Context, options AddressQueryInterface, fields map[string]string) (int64, error) {
+	if err := assertUpdatable(fields, addressUpdatableColumns); err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	ids, rows, err := store.updateManyLoaded(ctx, store.addressBulkTable(options), fields, store.addressHooks.hasAfterUpdate())
+	if err != nil {
+		return 0, store.operationError("address update many", err)
+	}
+
+	runAfterUpdateRows(ctx, &store.addressHooks, rows, NewAddressFromExistingData, func(map[string]string) map[string]string {
+		return maps.Clone(fields)
+	})
+
+	return int64(len(ids)), nil
+}
+
+// CustomerAddressList retrieves the address book of the customer: its live
+// addresses, the default shipping and billing addresses first
+func (store *Store) CustomerAddressList(ctx context.Context, customerID string) ([]AddressInterface, error) {
+	if customerID == "" {
+		return []AddressInterface{}, errors.New("customer id is empty")
+	}
+
+	return store.AddressList(ctx, NewAddressQuery().
+		SetCustomerID(customerID).
+		AddSort(COLUMN_IS_DEFAULT_SHIPPING, "desc").
+		AddSort(COLUMN_IS_DEFAULT_BILLING, "desc").
+		AddSort(COLUMN_CREATED_AT, "asc"))
+}
+
+// addressUpdate writes the changed fields of address, without running hooks
+func (store *Store) addressUpdate(ctx context.Context, address AddressInterface) error {
+	address.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString())
+
+	dataChanged := address.DataChanged()
+
+	delete(dataChanged, COLUMN_ID)      // ID is not updateable
+	delete(dataChanged, "hash")         // Hash is not updateable
+	delete(dataChanged, "data")         // Data is not updateable
+	delete(dataChanged, COLUMN_VERSION) // Version is set by the update itself
+
+	if len(dataChanged) < 1 {
+		return nil
+	}
+
+	row := map[string]any{}
+	for k, v := range dataChanged {
+		row[k] = rowValue(k, v)
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	if err := store.assertReferencesExist(ctx, dataChanged, store.addressReferences()); err != nil {
+		return err
+	}
+
+	var version int64
+	err := store.addressWrite(ctx, address, addressDefaultFlags(address, dataChanged), func(tx contractsorm.Query) (changeRecords, error) {
+		before, err := store.storedValues(tx, store.addressTableName, address.GetID(), lo.Keys(dataChanged))
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		version, err = versionedUpdate(ctx, statement(tx).Table(store.addressTableName).Where(COLUMN_ID+" = ?", address.GetID()), address.GetVersion(), row)
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_ADDRESS, address.GetID(), updateOperation(dataChanged), before, dataChanged),
+		}, err
+	})
+
+	if err != nil {
+		return store.operationError("address update", err)
+	}
+
+	address.SetVersion(version)
+	address.MarkAsNotDirty()
+
+	return nil
+}
+
+// addressDefaultFlags returns the default flag columns the address takes
+// over from the other addresses of its customer with the data written:
+// the flags set, when they or the customer change
+func addressDefaultFlags(address AddressInterface, data map[string]string) []string {
+	_, customerChanged := data[COLUMN_CUSTOMER_ID]
+
+	flags := []string{}
+
+	for _, flag := range []string{COLUMN_IS_DEFAULT_SHIPPING, COLUMN_IS_DEFAULT_BILLING} {
+		value, flagChanged := data[flag]
+		if !flagChanged {
+			value = address.Data()[flag]
+		}
+
+		if cast.ToBool(value) && (flagChanged || customerChanged) {
+			flags = append(flags, flag)
+		}
+	}
+
+	return flags
+}
+
+// addressWrite runs write, clearing the default flags on the other live
+// addresses of the customer of address in the same transaction, so that a
+// customer never has two default shipping or billing addresses. Without
+// flags to clear write runs as a plain change (see changeTransaction).
+func (store *Store) addressWrite(ctx context.Context, address AddressInterface, flags []string, write func(tx contractsorm.Query) (changeRecords, error)) error {
+	if len(flags) == 0 || address.GetCustomerID() == "" {
+		return store.changeTransaction(ctx, write)
+	}
+
+	return store.query(ctx).Transaction(func(tx contractsorm.Query) error {
+		records, err := write(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, flag := range flags {
+			var results []map[string]any
+			err := statement(tx).
+				Table(store.addressTableName).
+				Select([]string{COLUMN_ID}).
+				Where(COLUMN_CUSTOMER_ID+" = ?", address.GetCustomerID()).
+				Where(COLUMN_ID+" <> ?", address.GetID()).
+				Where(COLUMN_SOFT_DELETED_AT+" = ?", MAX_DATETIME).
+				Where(flag+" = ?", 1).
+				LockForUpdate().
+				Get(&results)
+			if err != nil {
+				return err
+			}
+
+			ids := lo.Map(results, func(result map[string]any, _ int) string {
+				return mapAnyToString(result)[COLUMN_ID]
+			})
+
+			if len(ids) == 0 {
+				continue
+			}
+
+			_, err = statement(tx).Table(store.addressTableName).WhereIn(COLUMN_ID, lo.ToAnySlice(ids)).Update(map[string]any{
+				flag:              0,
+				COLUMN_UPDATED_AT: carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC),
+				COLUMN_VERSION:    neatquery.RawExpr(COLUMN_VERSION + " + 1"),
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, id := range ids {
+				records.auditLogs = append(records.auditLogs, store.auditLogEntries(ctx, ENTITY_TYPE_ADDRESS, id, AUDIT_OPERATION_UPDATE,
+					map[string]string{flag: flagValue(true)},
+					map[string]string{flag: flagValue(false)})...)
+			}
+		}
+
+		return store.recordChanges(tx, records)
+	})
+}
+
+// addressBulkTable describes the addresses matching the query options to
+// the bulk operations
+func (store *Store) addressBulkTable(options AddressQueryInterface) bulkTable {
+	return bulkTable{
+		entityType: ENTITY_TYPE_ADDRESS,
+		tableName:  store.addressTableName,
+		selectRows: func(q contractsorm.Query) (contractsorm.Query, error) {
+			return store.addressQueryOn(q, options)
+		},
+	}
+}
+
+func (store *Store) addressQuery(ctx context.Context, options AddressQueryInterface) (contractsorm.Query, error) {
+	return store.addressQueryOn(store.query(ctx), options)
+}
+
+// addressQueryOn applies the query options to q, which may run within a transaction
+func (store *Store) addressQueryOn(q contractsorm.Query, options AddressQueryInterface) (contractsorm.Query, error) {
+	if options == nil {
+		options = NewAddressQuery()
+	}
+
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	q = q.Table(store.addressTableName)
+
+	if options.HasID() {
+		q = q.Where(COLUMN_ID+" = ?", options.ID())
+	}
+
+	if options.HasIDIn() {
+		q = q.WhereIn(COLUMN_ID, lo.ToAnySlice(options.IDIn()))
+	}
+
+	if options.HasCustomerID() {
+		q = q.Where(COLUMN_CUSTOMER_ID+" = ?", options.CustomerID())
+	}
+
+	if options.HasIsDefaultShipping() {
+		q = q.Where(COLUMN_IS_DEFAULT_SHIPPING+" = ?", lo.Ternary(options.IsDefaultShipping(), 1, 0))
+	}
+
+	if options.HasIsDefaultBilling() {
+		q = q.Where(COLUMN_IS_DEFAULT_BILLING+" = ?", lo.Ternary(options.IsDefaultBilling(), 1, 0))
+	}
+
+	if options.HasCreatedAtGte() && options.HasCreatedAtLte() {
+		q = q.Where(COLUMN_CREATED_AT+" BETWEEN ? AND ?", options.CreatedAtGte(), options.CreatedAtLte())
+	} else if options.HasCreatedAtGte() {
+		q = q.Where(COLUMN_CREATED_AT+" >= ?", options.CreatedAtGte())
+	} else if options.HasCreatedAtLte() {
+		q = q.Where(COLUMN_CREATED_AT+" <= ?", options.CreatedAtLte())
+	}
+
+	if !options.IsCountOnly() {
+		if options.HasLimit() {
+			q = q.Limit(cast.ToInt(options.Limit()))
+		}
+
+		if options.HasOffset() {
+			q = q.Offset(cast.ToInt(options.Offset()))
+		}
+	}
+
+	if options.HasAfterCursor() {
+		var err error
+		q, err = whereAfterCursor(q, store.dialect, keysetSort(options), options.AfterCursor())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if isSorted(options) {
+		q = orderByKeyset(q, keysetSort(options))
+	}
+
+	if !options.SoftDeletedIncluded() {
+		q = q.Where(COLUMN_SOFT_DELETED_AT+" = ?", MAX_DATETIME)
+	}
+
+	return q, nil
+}
@@ -0,0 +1,172 @@
+package shopstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestStoreAddressDefaultFlags(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	home := NewAddress().SetCustomerID("CUSTOMER01").SetLine1("1 High Street").SetIsDefaultShipping(true).SetIsDefaultBilling(true)
+	work := NewAddress().SetCustomerID("CUSTOMER01").SetLine1("2 Office Road").SetIsDefaultShipping(true)
+	other := NewAddress().SetCustomerID("CUSTOMER02").SetLine1("3 Other Lane").SetIsDefaultShipping(true)
+
+	for _, address := range []AddressInterface{home, work, other} {
+		if err := store.AddressCreate(ctx, address); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	found, err := store.AddressFindByID(ctx, home.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found.IsDefaultShipping() || !found.IsDefaultBilling() || found.GetVersion() != 2 {
+		t.Fatalf("expected the shipping flag moved to the new address, got %v", found.Data())
+	}
+
+	if found, _ := store.AddressFindByID(ctx, other.GetID()); !found.IsDefaultShipping() {
+		t.Fatal("expected the default address of another customer kept")
+	}
+
+	work.SetIsDefaultBilling(true)
+	if err := store.AddressUpdate(ctx, work); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	list, err := store.CustomerAddressList(ctx, "CUSTOMER01")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(list) != 2 || list[0].GetID() != work.GetID() || list[1].IsDefaultBilling() {
+		t.Fatalf("expected the new default address first and the only default, got %v", list)
+	}
+
+	count, err := store.AddressCount(ctx, NewAddressQuery().SetCustomerID("CUSTOMER01").SetIsDefaultBilling(true))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if count != 1 {
+		t.Fatalf("expected a single default billing address, got %d", count)
+	}
+
+	if err := store.AddressCreate(ctx, NewAddress()); err == nil {
+		t.Fatal("expected an error for an address without a customer")
+	}
+}
+
+func TestStoreAddressOrderSnapshot(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	address := NewAddress().SetCustomerID("CUSTOMER01").SetName("Jane Doe").SetLine1("1 High Street").SetCity("London").SetPostalCode("SW1A 1AA").SetCountryCode("gb")
+	if err := store.AddressCreate(ctx, address); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	order := NewOrder().SetCustomerID("CUSTOMER01")
+	_ = order.SetShippingAddress(address.ToOrderAddress())
+	_ = order.SetBillingAddress(address.ToOrderAddress())
+
+	if err := store.OrderCreate(ctx, order); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// editing and removing the address leaves the order untouched
+	address.SetLine1("9 New Street").SetCity("Leeds")
+	if err := store.AddressUpdate(ctx, address); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.AddressSoftDelete(ctx, address); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	found, err := store.OrderFindByID(ctx, order.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	shipping, err := found.GetShippingAddress()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expected := OrderAddress{Name: "Jane Doe", Line1: "1 High Street", City: "London", PostalCode: "SW1A 1AA", CountryCode: "GB"}
+	if shipping != expected {
+		t.Fatalf("expected the shipping address snapshot kept, got %+v", shipping)
+	}
+
+	billing, err := found.GetBillingAddress()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if billing != expected {
+		t.Fatalf("expected the billing address snapshot kept, got %+v", billing)
+	}
+}
+
+func TestStoreAddressCustomerDeletion(t *testing.T) {
+	store := initIntegrityStore(t)
+	ctx := context.Background()
+
+	err := store.AddressCreate(ctx, NewAddress().SetCustomerID("MISSING"))
+	if !errors.Is(err, ErrReferenceNotFound) {
+		t.Fatalf("expected ErrReferenceNotFound, got %v", err)
+	}
+
+	customer := NewCustomer().SetEmail("jane@example.com")
+	if err := store.CustomerCreate(ctx, customer); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	address := NewAddress().SetCustomerID(customer.GetID()).SetLine1("1 High Street")
+	if err := store.AddressCreate(ctx, address); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.CustomerDeleteByID(ctx, customer.GetID()); !errors.Is(err, ErrCustomerHasActiveAddresses) {
+		t.Fatalf("expected ErrCustomerHasActiveAddresses, got %v", err)
+	}
+
+	if err := store.CustomerSoftDelete(ctx, customer); !errors.Is(err, ErrCustomerHasActiveAddresses) {
+		t.Fatalf("expected ErrCustomerHasActiveAddresses on soft delete, got %v", err)
+	}
+
+	_, err = store.CustomerSoftDeleteMany(ctx, NewCustomerQuery().SetID(customer.GetID()))
+	if !errors.Is(err, ErrCustomerHasActiveAddresses) {
+		t.Fatalf("expected ErrCustomerHasActiveAddresses on soft delete many, got %v", err)
+	}
+
+	if _, err := store.AddressSoftDeleteMany(ctx, NewAddressQuery().SetCustomerID(customer.GetID())); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.CustomerDeleteByID(ctx, customer.GetID()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	count, err := store.AddressCount(ctx, NewAddressQuery().SetCustomerID(customer.GetID()).SetSoftDeletedIncluded(true))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if count != 0 {
+		t.Fatalf("expected the soft deleted addresses deleted along, got %d", count)
+	}
+}
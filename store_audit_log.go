@@ -0,0 +1,184 @@
+package shopstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+)
+
+// DEFAULT_AUDIT_LOG_TABLE_NAME is the audit log table when
+// NewStoreOptions.AuditLogTableName is not set
+const DEFAULT_AUDIT_LOG_TABLE_NAME = "shop_audit_log"
+
+type actorIDContextKey struct{}
+
+// WithActorID returns a copy of ctx carrying the ID of the actor (user,
+// admin, API client...) on whose behalf the store operations run. The
+// audit log records it with every change made with the returned context.
+func WithActorID(ctx context.Context, actorID string) context.Context {
+	return context.WithValue(ctx, actorIDContextKey{}, actorID)
+}
+
+// ActorIDFromContext returns the actor ID set with WithActorID, empty if none
+func ActorIDFromContext(ctx context.Context) string {
+	actorID, _ := ctx.Value(actorIDContextKey{}).(string)
+	return actorID
+}
+
+func (store *Store) AuditLogCount(ctx context.Context, options AuditLogQueryInterface) (int64, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.auditLogQuery(ctx, options)
+	if err != nil {
+		return -1, err
+	}
+
+	var count int64
+	if err := q.Count(&count); err != nil {
+		return -1, store.operationError("audit log count", err)
+	}
+
+	return count, nil
+}
+
+// AuditLogList returns the audit log entries matching the query options,
+// oldest first unless sorted otherwise
+func (store *Store) AuditLogList(ctx context.Context, options AuditLogQueryInterface) ([]AuditLogInterface, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.auditLogQuery(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []map[string]any
+	if err := q.Get(&results); err != nil {
+		return nil, store.operationError("audit log list", err)
+	}
+
+	list := []AuditLogInterface{}
+
+	lo.ForEach(results, func(result map[string]any, index int) {
+		list = append(list, NewAuditLogFromExistingData(mapAnyToString(result)))
+	})
+
+	return list, nil
+}
+
+func (store *Store) auditLogQuery(ctx context.Context, options AuditLogQueryInterface) (contractsorm.Query, error) {
+	if options == nil {
+		return nil, errors.New("audit log options is nil")
+	}
+
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	q := store.query(ctx).Table(store.auditLogTableName)
+
+	if options.HasID() {
+		q = q.Where(COLUMN_ID+" = ?", options.ID())
+	}
+
+	if options.HasEntityType() {
+		q = q.Where(COLUMN_ENTITY_TYPE+" = ?", options.EntityType())
+	}
+
+	if options.HasEntityID() {
+		q = q.Where(COLUMN_ENTITY_ID+" = ?", options.EntityID())
+	}
+
+	if options.HasOperation() {
+		q = q.Where(COLUMN_OPERATION+" = ?", options.Operation())
+	}
+
+	if options.HasActorID() {
+		q = q.Where(COLUMN_ACTOR_ID+" = ?", options.ActorID())
+	}
+
+	if options.HasCreatedAtGte() {
+		q = q.Where(COLUMN_CREATED_AT+" >= ?", options.CreatedAtGte())
+	}
+
+	if options.HasCreatedAtLte() {
+		q = q.Where(COLUMN_CREATED_AT+" <= ?", options.CreatedAtLte())
+	}
+
+	if options.IsCountOnly() {
+		return q, nil
+	}
+
+	if options.HasLimit() {
+		q = q.Limit(cast.ToInt(options.Limit()))
+	}
+
+	if options.HasOffset() {
+		q = q.Offset(cast.ToInt(options.Offset()))
+	}
+
+	if options.HasOrderBy() || options.HasSorts() {
+		return orderByKeyset(q, keysetSort(options)), nil
+	}
+
+	return orderByKeyset(q, []QuerySort{{Column: COLUMN_CREATED_AT, Direction: SORT_DIRECTION_ASC}}), nil
+}
+
+// auditLogEntries returns the audit log entry of an operation on an entity,
+// holding the fields whose value differs between before and after. None is
+// returned with the audit log disabled or when no field changed.
+func (store *Store) auditLogEntries(ctx context.Context, entityType string, entityID string, operation string, before map[string]string, after map[string]string) []AuditLogInterface {
+	if !store.auditLogEnabled {
+		return nil
+	}
+
+	valuesBefore := map[string]string{}
+	valuesAfter := map[string]string{}
+
+	for _, column := range lo.Union(lo.Keys(before), lo.Keys(after)) {
+		if before[column] == after[column] {
+			continue
+		}
+
+		if value, ok := before[column]; ok {
+			valuesBefore[column] = value
+		}
+
+		if value, ok := after[column]; ok {
+			valuesAfter[column] = value
+		}
+	}
+
+	if len(valuesBefore) == 0 && len(valuesAfter) == 0 {
+		return nil
+	}
+
+	entry := NewAuditLog(entityType, entityID, operation).
+		SetActorID(ActorIDFromContext(ctx)).
+		SetValuesBefore(auditValuesEncode(valuesBefore)).
+		SetValuesAfter(auditValuesEncode(valuesAfter))
+
+	return []AuditLogInterface{entry}
+}
+
+// updateOperation returns the audit operation of writing the changed
+// fields: soft deletes are written as updates of the soft_deleted_at column
+func updateOperation(dataChanged map[string]string) string {
+	softDeletedAt, changed := dataChanged[COLUMN_SOFT_DELETED_AT]
+	if changed && softDeletedAt != MAX_DATETIME {
+		return AUDIT_OPERATION_SOFT_DELETE
+	}
+
+	return AUDIT_OPERATION_UPDATE
+}
+
+// auditValuesEncode encodes the values as JSON. Maps of strings always encode.
+func auditValuesEncode(values map[string]string) string {
+	jsonBytes, _ := json.Marshal(values)
+	return string(jsonBytes)
+}
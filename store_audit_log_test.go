@@ -0,0 +1,251 @@
+package shopstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dromara/carbon/v2"
+)
+
+func initAuditLogStore(t *testing.T) *Store {
+	db, err := initDB(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	options := testStoreOptions(db)
+	options.AuditLogEnabled = true
+
+	store, err := NewStore(options)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	return store
+}
+
+func TestStoreAuditLog_ProductLifecycle(t *testing.T) {
+	store := initAuditLogStore(t)
+	ctx := WithActorID(context.Background(), "ADMIN01")
+
+	product := NewProduct().SetStatus(PRODUCT_STATUS_DRAFT).SetTitle("Widget").SetQuantityInt(10).SetPriceFloat(10)
+	if err := store.ProductCreate(ctx, product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	product.SetTitle("Gadget")
+	if err := store.ProductUpdate(ctx, product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.ProductSoftDelete(ctx, product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.ProductDeleteByID(ctx, product.GetID()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	entries, err := store.AuditLogList(ctx, NewAuditLogQuery().
+		SetEntityType(ENTITY_TYPE_PRODUCT).
+		SetEntityID(product.GetID()))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 entries, got %d", len(entries))
+	}
+
+	operations := []string{AUDIT_OPERATION_CREATE, AUDIT_OPERATION_UPDATE, AUDIT_OPERATION_SOFT_DELETE, AUDIT_OPERATION_DELETE}
+	for i, entry := range entries {
+		if entry.GetOperation() != operations[i] {
+			t.Fatalf("expected entry %d to be a %s, got %s", i, operations[i], entry.GetOperation())
+		}
+
+		if entry.GetActorID() != "ADMIN01" {
+			t.Fatalf("expected actor ADMIN01, got %q", entry.GetActorID())
+		}
+	}
+
+	created, err := entries[0].GetValuesAfterMap()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if created[COLUMN_TITLE] != "Widget" || created[COLUMN_ID] != product.GetID() {
+		t.Fatalf("unexpected created values %v", created)
+	}
+
+	before, err := entries[1].GetValuesBeforeMap()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	after, err := entries[1].GetValuesAfterMap()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if before[COLUMN_TITLE] != "Widget" || after[COLUMN_TITLE] != "Gadget" {
+		t.Fatalf("unexpected update values %v -> %v", before, after)
+	}
+
+	if _, ok := after[COLUMN_PRICE]; ok {
+		t.Fatalf("expected unchanged fields to be left out, got %v", after)
+	}
+
+	softDeleted, err := entries[2].GetValuesAfterMap()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if softDeleted[COLUMN_SOFT_DELETED_AT] == MAX_DATETIME || softDeleted[COLUMN_SOFT_DELETED_AT] == "" {
+		t.Fatalf("unexpected soft delete values %v", softDeleted)
+	}
+
+	deleted, err := entries[3].GetValuesBeforeMap()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if deleted[COLUMN_TITLE] != "Gadget" {
+		t.Fatalf("unexpected deleted values %v", deleted)
+	}
+}
+
+func TestStoreAuditLog_ListFilters(t *testing.T) {
+	store := initAuditLogStore(t)
+	ctx := context.Background()
+
+	category := NewCategory().SetStatus(CATEGORY_STATUS_ACTIVE).SetTitle("Shoes")
+	if err := store.CategoryCreate(WithActorID(ctx, "ADMIN01"), category); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	order := NewOrder().SetCustomerID("CUSTOMER01_ID").SetStatus(ORDER_STATUS_PENDING)
+	if err := store.OrderCreate(WithActorID(ctx, "CUSTOMER01_ID"), order); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	order.SetStatus(ORDER_STATUS_COMPLETED)
+	if err := store.OrderUpdate(WithActorID(ctx, "ADMIN01"), order); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	count, err := store.AuditLogCount(ctx, NewAuditLogQuery().SetActorID("ADMIN01"))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if count != 2 {
+		t.Fatalf("expected 2 entries by ADMIN01, got %d", count)
+	}
+
+	entries, err := store.AuditLogList(ctx, NewAuditLogQuery().
+		SetEntityType(ENTITY_TYPE_ORDER).
+		SetOperation(AUDIT_OPERATION_UPDATE))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(entries) != 1 || entries[0].GetEntityID() != order.GetID() {
+		t.Fatalf("expected the order update entry, got %d entries", len(entries))
+	}
+
+	now := carbon.Now(carbon.UTC)
+
+	entries, err = store.AuditLogList(ctx, NewAuditLogQuery().
+		SetCreatedAtGte(now.SubHour().ToDateTimeString(carbon.UTC)).
+		SetCreatedAtLte(now.AddHour().ToDateTimeString(carbon.UTC)))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries within the last hour, got %d", len(entries))
+	}
+
+	entries, err = store.AuditLogList(ctx, NewAuditLogQuery().
+		SetCreatedAtGte(now.AddHour().ToDateTimeString(carbon.UTC)))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(entries) != 0 {
+		t.Fatalf("expected no entries in the future, got %d", len(entries))
+	}
+
+	if _, err := store.AuditLogList(ctx, NewAuditLogQuery().SetOrderBy("values_before")); err == nil {
+		t.Fatal("expected an error for a column that is not sortable")
+	}
+}
+
+func TestStoreAuditLog_DisabledByDefault(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	product := NewProduct().SetStatus(PRODUCT_STATUS_ACTIVE).SetTitle("Widget")
+	if err := store.ProductCreate(ctx, product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	count, err := store.AuditLogCount(ctx, NewAuditLogQuery())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if count != 0 {
+		t.Fatalf("expected no entries, got %d", count)
+	}
+}
+
+func TestStoreAuditLog_WithOutbox(t *testing.T) {
+	db, err := initDB(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	options := testStoreOptions(db)
+	options.AuditLogEnabled = true
+	options.OutboxEnabled = true
+
+	store, err := NewStore(options)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	order := NewOrder().SetCustomerID("CUSTOMER01_ID").SetStatus(ORDER_STATUS_PENDING)
+	if err := store.OrderCreate(ctx, order); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	order.SetStatus(ORDER_STATUS_COMPLETED)
+	if err := store.OrderUpdate(ctx, order); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	events, err := store.OutboxFetchPending(ctx, 10)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+
+	entries, err := store.AuditLogList(ctx, NewAuditLogQuery().SetEntityID(order.GetID()))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(entries) != 2 || entries[0].GetActorID() != "" {
+		t.Fatalf("expected 2 entries without an actor, got %d", len(entries))
+	}
+}
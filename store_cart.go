@@ -0,0 +1,1147 @@
+package shopstore
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"maps"
+	"time"
+
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+	neatquery "github.com/dracory/neat/database/query"
+	"github.com/dromara/carbon/v2"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+)
+
+// CartAddItem adds the quantity of the product to the active cart, taking a
+// snapshot of its title and price. A product already in the cart has its
+// quantity increased instead, keeping the price it was first added at. Only
+// active products without variants can be added (ErrProductNotPurchasable).
+func (store *Store) CartAddItem(ctx context.Context, cartID string, productID string, quantity int64) (CartItemInterface, error) {
+	if cartID == "" {
+		return nil, errors.New("cart id is empty")
+	}
+
+	if productID == "" {
+		return nil, errors.New("product id is empty")
+	}
+
+	if quantity < 1 {
+		return nil, errors.New("cart item quantity must be positive")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	var item CartItemInterface
+	err := store.cartChange(ctx, cartID, func(tx contractsorm.Query, cart CartInterface) (changeRecords, error) {
+		product, err := store.cartProduct(tx, productID)
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		items, err := store.cartItemsOn(tx, cartID)
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		existing, found := lo.Find(items, func(item CartItemInterface) bool {
+			return item.GetProductID() == productID
+		})
+		if found {
+			item = existing
+			return store.cartItemQuantitySet(ctx, tx, item, item.GetQuantityInt()+quantity)
+		}
+
+		item = NewCartItem().
+			SetCartID(cartID).
+			SetProductID(productID).
+			SetTitle(product.GetTitle()).
+			SetQuantityInt(quantity).
+			SetPriceFloat(product.GetPriceFloat())
+
+		data := item.Data()
+		err = statement(tx).Table(store.cartItemTableName).Create(lo.MapValues(data, func(value string, column string) any {
+			return rowValue(column, value)
+		}))
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_CART_ITEM, item.GetID(), AUDIT_OPERATION_CREATE, nil, data),
+		}, err
+	})
+	if err != nil {
+		return nil, store.operationError("cart add item", err)
+	}
+
+	item.MarkAsNotDirty()
+
+	return item, nil
+}
+
+// CartApplyDiscount applies the discount with the code to the active cart,
+// replacing the discount applied before. The discount must be valid now
+// (see DiscountInterface.IsValidNow), else ErrDiscountNotApplicable is
+// returned. An empty code removes the discount from the cart.
+func (store *Store) CartApplyDiscount(ctx context.Context, cartID string, code string) error {
+	if cartID == "" {
+		return errors.New("cart id is empty")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	err := store.cartChange(ctx, cartID, func(tx contractsorm.Query, cart CartInterface) (changeRecords, error) {
+		if code == "" {
+			cart.SetDiscountID("")
+			return changeRecords{}, nil
+		}
+
+		q, err := store.discountQueryOn(statement(tx), NewDiscountQuery().
+			SetStatus(DISCOUNT_STATUS_ACTIVE).
+			SetCode(code).
+			SetLimit(1))
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		var results []map[string]any
+		if err := q.Get(&results); err != nil {
+			return changeRecords{}, err
+		}
+
+		if len(results) == 0 || !NewDiscountFromExistingData(mapAnyToString(results[0])).IsValidNow() {
+			return changeRecords{}, ErrDiscountNotApplicable
+		}
+
+		cart.SetDiscountID(mapAnyToString(results[0])[COLUMN_ID])
+
+		return changeRecords{}, nil
+	})
+
+	return store.operationError("cart apply discount", err)
+}
+
+// CartCheckout converts the active cart into a pending order, in a single
+// transaction: the order is created with a line item per cart item, at the
+// prices the items were added at, the ordered quantities are taken off the
+// product stock, and the cart is marked as checked out. A product short of
+// stock fails the checkout with ErrInsufficientQuantity, leaving the cart,
+// the stock and the orders unchanged.
+//
+// The order price is the total of the items less the discount of the cart,
+// which must still be valid (ErrDiscountNotApplicable). The order and its
+// line items are recorded in the audit log and an order.created event, and
+// a discount.redeemed event for a discount, are added to the outbox.
+//
+// Once checked out, the AfterCreate hooks run on the order and its line
+// items, and the AfterUpdate hooks on the cart and on the products with
+// their quantity changed. The Before hooks do not run.
+func (store *Store) CartCheckout(ctx context.Context, cartID string) (OrderInterface, error) {
+	if cartID == "" {
+		return nil, errors.New("cart id is empty")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	var order OrderInterface
+	var lineItems []OrderLineItemInterface
+	var checkedOut CartInterface
+	var cartChanged map[string]string
+	var products []map[string]string
+	err := store.cartChange(ctx, cartID, func(tx contractsorm.Query, cart CartInterface) (changeRecords, error) {
+		items, err := store.cartItemsOn(tx, cartID)
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		if len(items) == 0 {
+			return changeRecords{}, ErrCartEmpty
+		}
+
+		subtotal := roundPrice(lo.SumBy(items, func(item CartItemInterface) float64 {
+			return item.GetSubtotalFloat()
+		}))
+
+		discount, err := store.cartDiscount(tx, cart)
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		discountAmount := 0.0
+		if discount != nil {
+			discountAmount = discountAmountOf(discount, subtotal)
+		}
+
+		order = NewOrder().
+			SetCustomerID(cart.GetCustomerID()).
+			SetQuantityInt(lo.SumBy(items, func(item CartItemInterface) int64 {
+				return item.GetQuantityInt()
+			})).
+			SetPriceFloat(roundPrice(subtotal - discountAmount)).
+			SetDiscountID(cart.GetDiscountID()).
+			SetDiscountAmountFloat(discountAmount)
+
+		lineItems = lo.Map(items, func(item CartItemInterface, _ int) OrderLineItemInterface {
+			return NewOrderLineItem().
+				SetOrderID(order.GetID()).
+				SetProductID(item.GetProductID()).
+				SetTitle(item.GetTitle()).
+				SetQuantityInt(item.GetQuantityInt()).
+				SetPriceFloat(item.GetPriceFloat())
+		})
+
+		records := changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_ORDER, order.GetID(), AUDIT_OPERATION_CREATE, nil, order.Data()),
+			events:    []OutboxEventInterface{NewOutboxEvent(OUTBOX_EVENT_ORDER_CREATED, ENTITY_TYPE_ORDER, order.GetID())},
+		}
+
+		for _, item := range items {
+			_, adjusted, err := store.productQuantityAdjust(ctx, tx, item.GetProductID(), -item.GetQuantityInt(), ProductQuantityAdjustOptions{NotBelowZero: true})
+			if err != nil {
+				return changeRecords{}, err
+			}
+
+			records.auditLogs = append(records.auditLogs, adjusted.auditLogs...)
+			records.events = append(records.events, adjusted.events...)
+		}
+
+		if store.productHooks.hasAfterUpdate() {
+			productIDs := lo.Uniq(lo.Map(items, func(item CartItemInterface, _ int) string {
+				return item.GetProductID()
+			}))
+
+			if products, err = rowsByID(tx, store.productTableName, productIDs); err != nil {
+				return changeRecords{}, err
+			}
+		}
+
+		err = statement(tx).Table(store.orderTableName).Create(lo.MapValues(order.Data(), func(value string, column string) any {
+			return rowValue(column, value)
+		}))
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		rows := lo.Map(lineItems, func(lineItem OrderLineItemInterface, _ int) map[string]string {
+			return lineItem.Data()
+		})
+
+		if err := bulkInsert(tx, store.orderLineItemTableName, rows); err != nil {
+			return changeRecords{}, err
+		}
+
+		for _, row := range rows {
+			records.auditLogs = append(records.auditLogs, store.auditLogEntries(ctx, ENTITY_TYPE_ORDER_LINE_ITEM, row[COLUMN_ID], AUDIT_OPERATION_CREATE, nil, row)...)
+		}
+
+		if discount != nil {
+			event := NewOutboxEvent(OUTBOX_EVENT_DISCOUNT_REDEEMED, ENTITY_TYPE_DISCOUNT, discount.GetID())
+			err := event.SetPayloadMap(map[string]string{
+				"order_id": order.GetID(),
+				"code":     discount.GetCode(),
+				"amount":   order.GetDiscountAmount(),
+			})
+			if err != nil {
+				return changeRecords{}, err
+			}
+
+			records.events = append(records.events, event)
+		}
+
+		cart.SetStatus(CART_STATUS_CHECKED_OUT)
+		cart.SetOrderID(order.GetID())
+
+		checkedOut = cart
+		cartChanged = cart.DataChanged()
+
+		return records, nil
+	})
+	if err != nil {
+		return nil, store.operationError("cart checkout", err)
+	}
+
+	order.MarkAsNotDirty()
+	lo.ForEach(lineItems, func(lineItem OrderLineItemInterface, _ int) {
+		lineItem.MarkAsNotDirty()
+	})
+
+	checkedOut.SetVersion(checkedOut.GetVersion() + 1) // incremented by cartChange
+	checkedOut.MarkAsNotDirty()
+
+	store.orderHooks.runAfterCreate(ctx, order)
+	for _, lineItem := range lineItems {
+		store.orderLineItemHooks.runAfterCreate(ctx, lineItem)
+	}
+	store.cartHooks.runAfterUpdate(ctx, checkedOut, cartChanged)
+	runAfterUpdateRows(ctx, &store.productHooks, products, NewProductFromExistingData, productQuantityChanged)
+
+	return order, nil
+}
+
+func (store *Store) CartCount(ctx context.Context, options CartQueryInterface) (int64, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.cartQuery(ctx, options)
+	if err != nil {
+		return -1, err
+	}
+
+	var count int64
+	if err := q.Count(&count); err != nil {
+		return -1, store.operationError("cart count", err)
+	}
+
+	return count, nil
+}
+
+// CartCreate inserts the cart, a guest cart when it has no customer ID
+func (store *Store) CartCreate(ctx context.Context, cart CartInterface) error {
+	if cart == nil {
+		return errors.New("cart is nil")
+	}
+
+	cart.SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+	cart.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+	cart.SetSoftDeletedAt(MAX_DATETIME)
+
+	if err := store.cartHooks.runBeforeCreate(ctx, cart); err != nil {
+		return err
+	}
+
+	data := cart.Data()
+	row := map[string]any{}
+	for k, v := range data {
+		row[k] = rowValue(k, v)
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	if err := store.assertReferencesExist(ctx, data, store.cartReferences()); err != nil {
+		return err
+	}
+
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		err := statement(tx).Table(store.cartTableName).Create(row)
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_CART, cart.GetID(), AUDIT_OPERATION_CREATE, nil, data),
+		}, err
+	})
+	if err != nil {
+		return store.operationError("cart create", err)
+	}
+
+	cart.MarkAsNotDirty()
+
+	store.cartHooks.runAfterCreate(ctx, cart)
+
+	return nil
+}
+
+func (store *Store) CartDelete(ctx context.Context, cart CartInterface) error {
+	if cart == nil {
+		return errors.New("cart is nil")
+	}
+
+	return store.CartDeleteByID(ctx, cart.GetID())
+}
+
+// CartDeleteByID permanently deletes the cart together with its items, in
+// a single transaction. The order a cart was checked out as is kept.
+func (store *Store) CartDeleteByID(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("cart id is empty")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	err := store.query(ctx).Transaction(func(tx contractsorm.Query) error {
+		before, err := store.storedValues(tx, store.cartTableName, id, nil)
+		if err != nil {
+			return err
+		}
+
+		items, err := store.cartItemsOn(tx, id)
+		if err != nil {
+			return err
+		}
+
+		records := changeRecords{}
+		for _, item := range items {
+			records.auditLogs = append(records.auditLogs, store.auditLogEntries(ctx, ENTITY_TYPE_CART_ITEM, item.GetID(), AUDIT_OPERATION_DELETE, item.Data(), nil)...)
+		}
+
+		if _, err := statement(tx).Table(store.cartItemTableName).Where(COLUMN_CART_ID+" = ?", id).Delete(); err != nil {
+			return err
+		}
+
+		if _, err := statement(tx).Table(store.cartTableName).Where(COLUMN_ID+" = ?", id).Delete(); err != nil {
+			return err
+		}
+
+		records.auditLogs = append(records.auditLogs, store.auditLogEntries(ctx, ENTITY_TYPE_CART, id, AUDIT_OPERATION_DELETE, before, nil)...)
+
+		return store.recordChanges(tx, records)
+	})
+	if err != nil {
+		return store.operationError("cart delete", err)
+	}
+
+	store.cartHooks.runAfterDelete(ctx, id)
+
+	return nil
+}
+
+// CartExpireAbandoned marks the active carts not changed for idleFor or
+// longer as expired, in a single transaction, returning how many expired.
+// Expired carts can no longer be changed or checked out. Hooks do not run,
+// as the carts are not loaded.
+func (store *Store) CartExpireAbandoned(ctx context.Context, idleFor time.Duration) (int64, error) {
+	if idleFor <= 0 {
+		return 0, errors.New("cart expire abandoned. idle duration must be positive")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	cutoff := carbon.CreateFromStdTime(time.Now().Add(-idleFor)).ToDateTimeString(carbon.UTC)
+
+	ids, err := store.updateMany(ctx, store.cartBulkTable(NewCartQuery().
+		SetStatus(CART_STATUS_ACTIVE).
+		SetUpdatedAtLte(cutoff)), map[string]string{
+		COLUMN_STATUS: CART_STATUS_EXPIRED,
+	})
+	if err != nil {
+		return 0, store.operationError("cart expire abandoned", err)
+	}
+
+	return int64(len(ids)), nil
+}
+
+func (store *Store) CartFindByID(ctx context.Context, id string) (CartInterface, error) {
+	if id == "" {
+		return nil, errors.New("cart id is empty")
+	}
+
+	list, err := store.CartList(ctx, NewCartQuery().
+		SetID(id).
+		SetLimit(1))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) > 0 {
+		return list[0], nil
+	}
+
+	return nil, nil
+}
+
+// CartItemList retrieves the items of the cart, in the order they were added
+func (store *Store) CartItemList(ctx context.Context, cartID string) ([]CartItemInterface, error) {
+	if cartID == "" {
+		return []CartItemInterface{}, errors.New("cart id is empty")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	items, err := store.cartItemsOn(store.query(ctx), cartID)
+	if err != nil {
+		return []CartItemInterface{}, store.operationError("cart item list", err)
+	}
+
+	return items, nil
+}
+
+func (store *Store) CartList(ctx context.Context, options CartQueryInterface) ([]CartInterface, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.cartQuery(ctx, options)
+	if err != nil {
+		return []CartInterface{}, err
+	}
+
+	var results []map[string]any
+	if err := q.Get(&results); err != nil {
+		return []CartInterface{}, store.operationError("cart list", err)
+	}
+
+	list := []CartInterface{}
+
+	lo.ForEach(results, func(result map[string]any, index int) {
+		model := NewCartFromExistingData(mapAnyToString(result))
+		list = append(list, model)
+	})
+
+	return list, nil
+}
+
+// CartIterate streams the carts matching the query options, fetching them
+// from the database in batches instead of loading them all at once.
+// Iteration stops with an error as soon as ctx is cancelled.
+func (store *Store) CartIterate(ctx context.Context, options CartQueryInterface) iter.Seq2[CartInterface, error] {
+	if options == nil {
+		options = NewCartQuery()
+	}
+
+	build := func(ctx context.Context) (contractsorm.Query, error) {
+		return store.cartQuery(ctx, options)
+	}
+
+	hydrate := func(data map[string]string) CartInterface {
+		return NewCartFromExistingData(data)
+	}
+
+	return iterateInBatches(store, ctx, "cart iterate", options, build, hydrate)
+}
+
+// CartListPage returns a single keyset (cursor) paginated page of carts
+// matching the query options. The page size is taken from SetLimit. Pass the
+// returned NextCursor to SetAfterCursor to fetch the following page.
+func (store *Store) CartListPage(ctx context.Context, options CartQueryInterface) (ListPage[CartInterface], error) {
+	if options == nil {
+		options = NewCartQuery()
+	}
+
+	if !options.HasLimit() {
+		return ListPage[CartInterface]{}, errors.New("cart list page. limit is required")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.cartQuery(ctx, options)
+	if err != nil {
+		return ListPage[CartInterface]{}, err
+	}
+
+	q = keysetPageQuery(q, options.Limit(), options)
+
+	var results []map[string]any
+	if err := q.Get(&results); err != nil {
+		return ListPage[CartInterface]{}, store.operationError("cart list page", err)
+	}
+
+	list := []CartInterface{}
+
+	lo.ForEach(results, func(result map[string]any, index int) {
+		model := NewCartFromExistingData(mapAnyToString(result))
+		list = append(list, model)
+	})
+
+	return newListPage(list, options.Limit(), options), nil
+}
+
+// CartMerge hands the active guest cart over to the customer, typically
+// when the guest signs in, and returns the active cart of the customer.
+// Without an active cart of its own the customer takes over the guest
+// cart. Otherwise the items of the guest cart are moved to the cart of
+// the customer, adding up the quantities of the same products, the guest
+// discount is kept when the customer cart has none, and the guest cart is
+// marked as merged. A cart of another customer fails with ErrCartNotGuest.
+func (store *Store) CartMerge(ctx context.Context, guestCartID string, customerID string) (CartInterface, error) {
+	if guestCartID == "" {
+		return nil, errors.New("cart id is empty")
+	}
+
+	if customerID == "" {
+		return nil, errors.New("customer id is empty")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	if err := store.assertReferencesExist(ctx, map[string]string{COLUMN_CUSTOMER_ID: customerID}, store.cartReferences()); err != nil {
+		return nil, err
+	}
+
+	customerCartID := guestCartID
+	err := store.cartChange(ctx, guestCartID, func(tx contractsorm.Query, guest CartInterface) (changeRecords, error) {
+		if guest.GetCustomerID() == customerID {
+			return changeRecords{}, nil
+		}
+
+		if !guest.IsGuest() {
+			return changeRecords{}, ErrCartNotGuest
+		}
+
+		var results []map[string]any
+		err := statement(tx).
+			Table(store.cartTableName).
+			Where(COLUMN_CUSTOMER_ID+" = ?", customerID).
+			Where(COLUMN_STATUS+" = ?", CART_STATUS_ACTIVE).
+			Where(COLUMN_SOFT_DELETED_AT+" = ?", MAX_DATETIME).
+			OrderByDesc(COLUMN_UPDATED_AT).
+			Limit(1).
+			LockForUpdate().
+			Get(&results)
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		if len(results) == 0 {
+			guest.SetCustomerID(customerID)
+			return changeRecords{}, nil
+		}
+
+		stored := mapAnyToString(results[0])
+		target := NewCartFromExistingData(maps.Clone(stored))
+		customerCartID = target.GetID()
+
+		records, err := store.cartItemsMove(ctx, tx, guest.GetID(), target.GetID())
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		if target.GetDiscountID() == "" && guest.GetDiscountID() != "" {
+			target.SetDiscountID(guest.GetDiscountID())
+		}
+
+		touched, err := store.cartTouch(ctx, tx, target, stored)
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		guest.SetStatus(CART_STATUS_MERGED)
+
+		records.auditLogs = append(records.auditLogs, touched.auditLogs...)
+
+		return records, nil
+	})
+	if err != nil {
+		return nil, store.operationError("cart merge", err)
+	}
+
+	return store.CartFindByID(ctx, customerCartID)
+}
+
+// CartRemoveItem removes the item from the active cart
+func (store *Store) CartRemoveItem(ctx context.Context, cartID string, itemID string) error {
+	if cartID == "" {
+		return errors.New("cart id is empty")
+	}
+
+	if itemID == "" {
+		return errors.New("cart item id is empty")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	err := store.cartChange(ctx, cartID, func(tx contractsorm.Query, cart CartInterface) (changeRecords, error) {
+		item, err := store.cartItemOn(tx, cartID, itemID)
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		return store.cartItemDelete(ctx, tx, item)
+	})
+
+	return store.operationError("cart remove item", err)
+}
+
+// CartSoftDelete soft deletes the cart, keeping its items
+func (store *Store) CartSoftDelete(ctx context.Context, cart CartInterface) error {
+	if cart == nil {
+		return errors.New("cart is nil")
+	}
+
+	cart.SetSoftDeletedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+
+	if err := store.cartUpdate(ctx, cart); err != nil {
+		return err
+	}
+
+	store.cartHooks.runAfterSoftDelete(ctx, cart.GetID())
+
+	return nil
+}
+
+func (store *Store) CartSoftDeleteByID(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("cart id is empty")
+	}
+
+	cart, err := store.CartFindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if cart == nil {
+		return nil
+	}
+
+	return store.CartSoftDelete(ctx, cart)
+}
+
+// CartUpdate writes the changed fields of the cart. Use the item and
+// discount operations to change the content of the cart.
+func (store *Store) CartUpdate(ctx context.Context, cart CartInterface) error {
+	if cart == nil {
+		return errors.New("cart is nil")
+	}
+
+	if err := store.cartHooks.runBeforeUpdate(ctx, cart, cart.DataChanged()); err != nil {
+		return err
+	}
+
+	changed := cart.DataChanged()
+	if err := store.cartUpdate(ctx, cart); err != nil {
+		return err
+	}
+
+	store.cartHooks.runAfterUpdate(ctx, cart, changed)
+
+	return nil
+}
+
+// CartUpdateItemQuantity sets the quantity of the item of the active cart.
+// A quantity of 0 removes the item from the cart.
+func (store *Store) CartUpdateItemQuantity(ctx context.Context, cartID string, itemID string, quantity int64) error {
+	if cartID == "" {
+		return errors.New("cart id is empty")
+	}
+
+	if itemID == "" {
+		return errors.New("cart item id is empty")
+	}
+
+	if quantity < 0 {
+		return errors.New("cart item quantity cannot be negative")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	err := store.cartChange(ctx, cartID, func(tx contractsorm.Query, cart CartInterface) (changeRecords, error) {
+		item, err := store.cartItemOn(tx, cartID, itemID)
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		if quantity == 0 {
+			return store.cartItemDelete(ctx, tx, item)
+		}
+
+		return store.cartItemQuantitySet(ctx, tx, item, quantity)
+	})
+
+	return store.operationError("cart update item quantity", err)
+}
+
+// cartUpdate writes the changed fields of cart, without running hooks
+func (store *Store) cartUpdate(ctx context.Context, cart CartInterface) error {
+	cart.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString())
+
+	dataChanged := cart.DataChanged()
+
+	delete(dataChanged, COLUMN_ID)      // ID is not updateable
+	delete(dataChanged, "hash")         // Hash is not updateable
+	delete(dataChanged, "data")         // Data is not updateable
+	delete(dataChanged, COLUMN_VERSION) // Version is set by the update itself
+
+	if len(dataChanged) < 1 {
+		return nil
+	}
+
+	row := map[string]any{}
+	for k, v := range dataChanged {
+		row[k] = rowValue(k, v)
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	if err := store.assertReferencesExist(ctx, dataChanged, store.cartReferences()); err != nil {
+		return err
+	}
+
+	var version int64
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		before, err := store.storedValues(tx, store.cartTableName, cart.GetID(), lo.Keys(dataChanged))
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		version, err = versionedUpdate(ctx, statement(tx).Table(store.cartTableName).Where(COLUMN_ID+" = ?", cart.GetID()), cart.GetVersion(), row)
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_CART, cart.GetID(), updateOperation(dataChanged), before, dataChanged),
+		}, err
+	})
+
+	if err != nil {
+		return store.operationError("cart update", err)
+	}
+
+	cart.SetVersion(version)
+	cart.MarkAsNotDirty()
+
+	return nil
+}
+
+// cartChange runs change on the active cart with the given ID in a single
+// transaction, with the cart row locked. The fields change sets on the
+// cart are written along with its updated_at, so that the cart is not
+// taken for abandoned while in use (see CartExpireAbandoned).
+func (store *Store) cartChange(ctx context.Context, cartID string, change func(tx contractsorm.Query, cart CartInterface) (changeRecords, error)) error {
+	return store.query(ctx).Transaction(func(tx contractsorm.Query) error {
+		var results []map[string]any
+		err := statement(tx).
+			Table(store.cartTableName).
+			Where(COLUMN_ID+" = ?", cartID).
+			Where(COLUMN_SOFT_DELETED_AT+" = ?", MAX_DATETIME).
+			LockForUpdate().
+			Get(&results)
+		if err != nil {
+			return err
+		}
+
+		if len(results) == 0 {
+			return ErrCartNotFound
+		}
+
+		stored := mapAnyToString(results[0])
+		cart := NewCartFromExistingData(maps.Clone(stored))
+		if !cart.IsActive() {
+			return ErrCartNotActive
+		}
+
+		records, err := change(tx, cart)
+		if err != nil {
+			return err
+		}
+
+		touched, err := store.cartTouch(ctx, tx, cart, stored)
+		if err != nil {
+			return err
+		}
+
+		records.auditLogs = append(records.auditLogs, touched.auditLogs...)
+
+		return store.recordChanges(tx, records)
+	})
+}
+
+// cartTouch writes the changed fields of the cart, loaded within the
+// transaction tx with the stored values, along with a new updated_at. Only
+// the changes besides updated_at are recorded in the audit log.
+func (store *Store) cartTouch(ctx context.Context, tx contractsorm.Query, cart CartInterface, stored map[string]string) (changeRecords, error) {
+	dataChanged := cart.DataChanged()
+
+	row := map[string]any{
+		COLUMN_UPDATED_AT: carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC),
+		COLUMN_VERSION:    neatquery.RawExpr(COLUMN_VERSION + " + 1"),
+	}
+	for k, v := range dataChanged {
+		row[k] = rowValue(k, v)
+	}
+
+	_, err := statement(tx).Table(store.cartTableName).Where(COLUMN_ID+" = ?", cart.GetID()).Update(row)
+	if err != nil || len(dataChanged) == 0 {
+		return changeRecords{}, err
+	}
+
+	return changeRecords{
+		auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_CART, cart.GetID(), updateOperation(dataChanged), lo.PickByKeys(stored, lo.Keys(dataChanged)), dataChanged),
+	}, nil
+}
+
+// cartDiscount reads the discount applied to the cart within the
+// transaction tx, nil when none is. A discount no longer valid fails with
+// ErrDiscountNotApplicable.
+func (store *Store) cartDiscount(tx contractsorm.Query, cart CartInterface) (DiscountInterface, error) {
+	if cart.GetDiscountID() == "" {
+		return nil, nil
+	}
+
+	q, err := store.discountQueryOn(statement(tx), NewDiscountQuery().
+		SetID(cart.GetDiscountID()).
+		SetLimit(1))
+	if err != nil {
+		return nil, err
+	}
+
+	var results []map[string]any
+	if err := q.Get(&results); err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, ErrDiscountNotApplicable
+	}
+
+	discount := NewDiscountFromExistingData(mapAnyToString(results[0]))
+	if !discount.IsValidNow() {
+		return nil, ErrDiscountNotApplicable
+	}
+
+	return discount, nil
+}
+
+// discountAmountOf returns the amount the discount takes off the subtotal,
+// rounded to cents. An amount discount never takes off more than the
+// subtotal.
+func discountAmountOf(discount DiscountInterface, subtotal float64) float64 {
+	amount := discount.GetAmount()
+	if discount.GetType() == DISCOUNT_TYPE_PERCENT {
+		amount = subtotal * amount / 100
+	}
+
+	return roundPrice(min(max(amount, 0), subtotal))
+}
+
+// cartProduct reads the product to add to a cart within the transaction tx,
+// failing unless it is live, active and without variants
+func (store *Store) cartProduct(tx contractsorm.Query, productID string) (ProductInterface, error) {
+	var results []map[string]any
+	err := statement(tx).
+		Table(store.productTableName).
+		Where(COLUMN_ID+" = ?", productID).
+		Where(COLUMN_SOFT_DELETED_AT+" = ?", MAX_DATETIME).
+		Get(&results)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, ErrProductNotFound
+	}
+
+	product := NewProductFromExistingData(mapAnyToString(results[0]))
+	if !product.IsActive() {
+		return nil, ErrProductNotPurchasable
+	}
+
+	// a parent product is bought through its variants
+	var variants int64
+	err = statement(tx).
+		Table(store.productTableName).
+		Where(COLUMN_PARENT_ID+" = ?", productID).
+		Where(COLUMN_SOFT_DELETED_AT+" = ?", MAX_DATETIME).
+		Count(&variants)
+	if err != nil {
+		return nil, err
+	}
+
+	if variants > 0 {
+		return nil, ErrProductNotPurchasable
+	}
+
+	return product, nil
+}
+
+// cartItemDelete deletes the cart item within the transaction tx
+func (store *Store) cartItemDelete(ctx context.Context, tx contractsorm.Query, item CartItemInterface) (changeRecords, error) {
+	_, err := statement(tx).Table(store.cartItemTableName).Where(COLUMN_ID+" = ?", item.GetID()).Delete()
+	return changeRecords{
+		auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_CART_ITEM, item.GetID(), AUDIT_OPERATION_DELETE, item.Data(), nil),
+	}, err
+}
+
+// cartItemOn reads the item of the cart within the transaction tx
+func (store *Store) cartItemOn(tx contractsorm.Query, cartID string, itemID string) (CartItemInterface, error) {
+	items, err := store.cartItemsOn(tx, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	item, found := lo.Find(items, func(item CartItemInterface) bool {
+		return item.GetID() == itemID
+	})
+	if !found {
+		return nil, ErrCartItemNotFound
+	}
+
+	return item, nil
+}
+
+// cartItemQuantitySet sets the quantity of the cart item within the transaction tx
+func (store *Store) cartItemQuantitySet(ctx context.Context, tx contractsorm.Query, item CartItemInterface, quantity int64) (changeRecords, error) {
+	before := item.GetQuantity()
+
+	_, err := statement(tx).Table(store.cartItemTableName).Where(COLUMN_ID+" = ?", item.GetID()).Update(map[string]any{
+		COLUMN_QUANTITY:   quantity,
+		COLUMN_UPDATED_AT: carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC),
+		COLUMN_VERSION:    neatquery.RawExpr(COLUMN_VERSION + " + 1"),
+	})
+	if err != nil {
+		return changeRecords{}, err
+	}
+
+	item.SetQuantityInt(quantity)
+	item.SetVersion(item.GetVersion() + 1)
+
+	return changeRecords{
+		auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_CART_ITEM, item.GetID(), AUDIT_OPERATION_UPDATE,
+			map[string]string{COLUMN_QUANTITY: before},
+			map[string]string{COLUMN_QUANTITY: item.GetQuantity()}),
+	}, nil
+}
+
+// cartItemsMove moves the items of the cart from to the cart to within the
+// transaction tx. The quantity of an item for a product already in the
+// cart to is added to the item there, and the moved item deleted.
+func (store *Store) cartItemsMove(ctx context.Context, tx contractsorm.Query, from string, to string) (changeRecords, error) {
+	items, err := store.cartItemsOn(tx, from)
+	if err != nil {
+		return changeRecords{}, err
+	}
+
+	targetItems, err := store.cartItemsOn(tx, to)
+	if err != nil {
+		return changeRecords{}, err
+	}
+
+	targets := lo.KeyBy(targetItems, func(item CartItemInterface) string {
+		return item.GetProductID()
+	})
+
+	records := changeRecords{}
+	for _, item := range items {
+		var moved changeRecords
+
+		if target, ok := targets[item.GetProductID()]; ok {
+			moved, err = store.cartItemQuantitySet(ctx, tx, target, target.GetQuantityInt()+item.GetQuantityInt())
+			if err != nil {
+				return changeRecords{}, err
+			}
+
+			deleted, err := store.cartItemDelete(ctx, tx, item)
+			if err != nil {
+				return changeRecords{}, err
+			}
+
+			moved.auditLogs = append(moved.auditLogs, deleted.auditLogs...)
+		} else {
+			_, err = statement(tx).Table(store.cartItemTableName).Where(COLUMN_ID+" = ?", item.GetID()).Update(map[string]any{
+				COLUMN_CART_ID:    to,
+				COLUMN_UPDATED_AT: carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC),
+				COLUMN_VERSION:    neatquery.RawExpr(COLUMN_VERSION + " + 1"),
+			})
+			if err != nil {
+				return changeRecords{}, err
+			}
+
+			moved.auditLogs = store.auditLogEntries(ctx, ENTITY_TYPE_CART_ITEM, item.GetID(), AUDIT_OPERATION_UPDATE,
+				map[string]string{COLUMN_CART_ID: from},
+				map[string]string{COLUMN_CART_ID: to})
+		}
+
+		records.auditLogs = append(records.auditLogs, moved.auditLogs...)
+	}
+
+	return records, nil
+}
+
+// cartItemsOn reads the items of the cart on q, which may be a
+// transaction, in the order they were added
+func (store *Store) cartItemsOn(q contractsorm.Query, cartID string) ([]CartItemInterface, error) {
+	var results []map[string]any
+	err := statement(q).
+		Table(store.cartItemTableName).
+		Where(COLUMN_CART_ID+" = ?", cartID).
+		OrderBy(COLUMN_CREATED_AT).
+		OrderBy(COLUMN_ID).
+		Get(&results)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Map(results, func(result map[string]any, _ int) CartItemInterface {
+		return NewCartItemFromExistingData(mapAnyToString(result))
+	}), nil
+}
+
+// cartBulkTable describes the carts matching the query options to the
+// bulk operations
+func (store *Store) cartBulkTable(options CartQueryInterface) bulkTable {
+	return bulkTable{
+		entityType: ENTITY_TYPE_CART,
+		tableName:  store.cartTableName,
+		selectRows: func(q contractsorm.Query) (contractsorm.Query, error) {
+			return store.cartQueryOn(q, options)
+		},
+	}
+}
+
+func (store *Store) cartQuery(ctx context.Context, options CartQueryInterface) (contractsorm.Query, error) {
+	return store.cartQueryOn(store.query(ctx), options)
+}
+
+// cartQueryOn applies the query options to q, which may run within a transaction
+func (store *Store) cartQueryOn(q contractsorm.Query, options CartQueryInterface) (contractsorm.Query, error) {
+	if options == nil {
+		options = NewCartQuery()
+	}
+
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	q = q.Table(store.cartTableName)
+
+	if options.HasID() {
+		q = q.Where(COLUMN_ID+" = ?", options.ID())
+	}
+
+	if options.HasIDIn() {
+		q = q.WhereIn(COLUMN_ID, lo.ToAnySlice(options.IDIn()))
+	}
+
+	if options.HasStatus() {
+		q = q.Where(COLUMN_STATUS+" = ?", options.Status())
+	}
+
+	if options.HasStatusIn() {
+		q = q.WhereIn(COLUMN_STATUS, lo.ToAnySlice(options.StatusIn()))
+	}
+
+	if options.HasCustomerID() {
+		q = q.Where(COLUMN_CUSTOMER_ID+" = ?", options.CustomerID())
+	}
+
+	if options.HasCreatedAtGte() && options.HasCreatedAtLte() {
+		q = q.Where(COLUMN_CREATED_AT+" BETWEEN ? AND ?", options.CreatedAtGte(), options.CreatedAtLte())
+	} else if options.HasCreatedAtGte() {
+		q = q.Where(COLUMN_CREATED_AT+" >= ?", options.CreatedAtGte())
+	} else if options.HasCreatedAtLte() {
+		q = q.Where(COLUMN_CREATED_AT+" <= ?", options.CreatedAtLte())
+	}
+
+	if options.HasUpdatedAtLte() {
+		q = q.Where(COLUMN_UPDATED_AT+" <= ?", options.UpdatedAtLte())
+	}
+
+	if !options.IsCountOnly() {
+		if options.HasLimit() {
+			q = q.Limit(cast.ToInt(options.Limit()))
+		}
+
+		if options.HasOffset() {
+			q = q.Offset(cast.ToInt(options.Offset()))
+		}
+	}
+
+	if options.HasAfterCursor() {
+		var err error
+		q, err = whereAfterCursor(q, store.dialect, keysetSort(options), options.AfterCursor())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if isSorted(options) {
+		q = orderByKeyset(q, keysetSort(options))
+	}
+
+	if !options.SoftDeletedIncluded() {
+		q = q.Where(COLUMN_SOFT_DELETED_AT+" = ?", MAX_DATETIME)
+	}
+
+	return q, nil
+}
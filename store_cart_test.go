@@ -0,0 +1,568 @@
+package shopstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dromara/carbon/v2"
+)
+
+// createCartProduct creates an active product to put in carts
+func createCartProduct(t *testing.T, store StoreInterface, price float64, quantity int64) ProductInterface {
+	t.Helper()
+
+	product := NewProduct().
+		SetStatus(PRODUCT_STATUS_ACTIVE).
+		SetTitle("Product").
+		SetPriceFloat(price).
+		SetQuantityInt(quantity)
+
+	if err := store.ProductCreate(context.Background(), product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	return product
+}
+
+// createCartDiscount creates an active discount valid since yesterday
+func createCartDiscount(t *testing.T, store StoreInterface, discountType string, amount float64) DiscountInterface {
+	t.Helper()
+
+	discount := NewDiscount().
+		SetStatus(DISCOUNT_STATUS_ACTIVE).
+		SetType(discountType).
+		SetAmount(amount).
+		SetStartsAt(carbon.Yesterday(carbon.UTC).ToDateTimeString(carbon.UTC))
+
+	if err := store.DiscountCreate(context.Background(), discount); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	return discount
+}
+
+func TestStoreCartItems(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	cart := NewCart()
+	if err := store.CartCreate(ctx, cart); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	book := createCartProduct(t, store, 12.50, 10)
+	pen := createCartProduct(t, store, 1.99, 10)
+
+	item, err := store.CartAddItem(ctx, cart.GetID(), book.GetID(), 1)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if item.GetPriceFloat() != 12.50 || item.GetTitle() != "Product" {
+		t.Fatalf("expected the price and title snapshot, got %v", item.Data())
+	}
+
+	// a price change does not alter the snapshot, and adding the product again adds up
+	book.SetPriceFloat(15)
+	if err := store.ProductUpdate(ctx, book); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	again, err := store.CartAddItem(ctx, cart.GetID(), book.GetID(), 2)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if again.GetID() != item.GetID() || again.GetQuantityInt() != 3 || again.GetPriceFloat() != 12.50 {
+		t.Fatalf("expected the quantity of the item increased, got %v", again.Data())
+	}
+
+	penItem, err := store.CartAddItem(ctx, cart.GetID(), pen.GetID(), 1)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.CartUpdateItemQuantity(ctx, cart.GetID(), penItem.GetID(), 4); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	items, err := store.CartItemList(ctx, cart.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(items) != 2 || items[1].GetQuantityInt() != 4 {
+		t.Fatalf("expected 2 items, the pens at quantity 4, got %v", items)
+	}
+
+	if err := store.CartUpdateItemQuantity(ctx, cart.GetID(), penItem.GetID(), 0); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.CartRemoveItem(ctx, cart.GetID(), penItem.GetID()); !errors.Is(err, ErrCartItemNotFound) {
+		t.Fatalf("expected ErrCartItemNotFound, got %v", err)
+	}
+
+	if err := store.CartRemoveItem(ctx, cart.GetID(), item.GetID()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	items, err = store.CartItemList(ctx, cart.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(items) != 0 {
+		t.Fatalf("expected the cart empty, got %v", items)
+	}
+
+	found, err := store.CartFindByID(ctx, cart.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found.GetVersion() <= cart.GetVersion() {
+		t.Fatalf("expected the cart touched by the item changes, got version %d", found.GetVersion())
+	}
+}
+
+func TestStoreCartAddItemRejects(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	cart := NewCart()
+	if err := store.CartCreate(ctx, cart); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	draft := NewProduct().SetTitle("Draft")
+	if err := store.ProductCreate(ctx, draft); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if _, err := store.CartAddItem(ctx, cart.GetID(), draft.GetID(), 1); !errors.Is(err, ErrProductNotPurchasable) {
+		t.Fatalf("expected ErrProductNotPurchasable, got %v", err)
+	}
+
+	if _, err := store.CartAddItem(ctx, cart.GetID(), "MISSING", 1); !errors.Is(err, ErrProductNotFound) {
+		t.Fatalf("expected ErrProductNotFound, got %v", err)
+	}
+
+	product := createCartProduct(t, store, 10, 10)
+
+	// a parent product is bought through its variants
+	variant := NewProduct().SetStatus(PRODUCT_STATUS_ACTIVE).SetParentID(product.GetID())
+	if err := store.ProductCreate(ctx, variant); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if _, err := store.CartAddItem(ctx, cart.GetID(), product.GetID(), 1); !errors.Is(err, ErrProductNotPurchasable) {
+		t.Fatalf("expected ErrProductNotPurchasable for a parent product, got %v", err)
+	}
+
+	if _, err := store.CartAddItem(ctx, cart.GetID(), variant.GetID(), 0); err == nil {
+		t.Fatal("expected an error for a zero quantity")
+	}
+
+	if _, err := store.CartAddItem(ctx, "MISSING", variant.GetID(), 1); !errors.Is(err, ErrCartNotFound) {
+		t.Fatalf("expected ErrCartNotFound, got %v", err)
+	}
+
+	cart.SetStatus(CART_STATUS_EXPIRED)
+	if err := store.CartUpdate(ctx, cart); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if _, err := store.CartAddItem(ctx, cart.GetID(), variant.GetID(), 1); !errors.Is(err, ErrCartNotActive) {
+		t.Fatalf("expected ErrCartNotActive, got %v", err)
+	}
+}
+
+func TestStoreCartApplyDiscount(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	cart := NewCart()
+	if err := store.CartCreate(ctx, cart); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	discount := createCartDiscount(t, store, DISCOUNT_TYPE_PERCENT, 10)
+
+	if err := store.CartApplyDiscount(ctx, cart.GetID(), discount.GetCode()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	found, err := store.CartFindByID(ctx, cart.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found.GetDiscountID() != discount.GetID() {
+		t.Fatalf("expected the discount applied, got %q", found.GetDiscountID())
+	}
+
+	// a discount not started yet is not applicable
+	future := NewDiscount().
+		SetStatus(DISCOUNT_STATUS_ACTIVE).
+		SetStartsAt(carbon.Tomorrow(carbon.UTC).ToDateTimeString(carbon.UTC))
+	if err := store.DiscountCreate(ctx, future); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.CartApplyDiscount(ctx, cart.GetID(), future.GetCode()); !errors.Is(err, ErrDiscountNotApplicable) {
+		t.Fatalf("expected ErrDiscountNotApplicable, got %v", err)
+	}
+
+	if err := store.CartApplyDiscount(ctx, cart.GetID(), "UNKNOWN"); !errors.Is(err, ErrDiscountNotApplicable) {
+		t.Fatalf("expected ErrDiscountNotApplicable for an unknown code, got %v", err)
+	}
+
+	if err := store.CartApplyDiscount(ctx, cart.GetID(), ""); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	found, err = store.CartFindByID(ctx, cart.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found.GetDiscountID() != "" {
+		t.Fatalf("expected the discount removed, got %q", found.GetDiscountID())
+	}
+}
+
+func TestStoreCartMerge(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	book := createCartProduct(t, store, 10, 10)
+	pen := createCartProduct(t, store, 2, 10)
+	discount := createCartDiscount(t, store, DISCOUNT_TYPE_AMOUNT, 5)
+
+	// without a cart of its own, the customer takes the guest cart over
+	guest := NewCart()
+	if err := store.CartCreate(ctx, guest); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	merged, err := store.CartMerge(ctx, guest.GetID(), "CUSTOMER01_ID")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if merged.GetID() != guest.GetID() || merged.GetCustomerID() != "CUSTOMER01_ID" {
+		t.Fatalf("expected the guest cart taken over, got %v", merged.Data())
+	}
+
+	if _, err := store.CartMerge(ctx, guest.GetID(), "CUSTOMER02_ID"); !errors.Is(err, ErrCartNotGuest) {
+		t.Fatalf("expected ErrCartNotGuest, got %v", err)
+	}
+
+	// with a cart of its own, the guest items move to the customer cart
+	if _, err := store.CartAddItem(ctx, merged.GetID(), book.GetID(), 1); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	guest = NewCart()
+	if err := store.CartCreate(ctx, guest); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	for _, product := range []ProductInterface{book, pen} {
+		if _, err := store.CartAddItem(ctx, guest.GetID(), product.GetID(), 2); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	if err := store.CartApplyDiscount(ctx, guest.GetID(), discount.GetCode()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	customerCart, err := store.CartMerge(ctx, guest.GetID(), "CUSTOMER01_ID")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if customerCart.GetID() != merged.GetID() || customerCart.GetDiscountID() != discount.GetID() {
+		t.Fatalf("expected the customer cart with the guest discount, got %v", customerCart.Data())
+	}
+
+	items, err := store.CartItemList(ctx, customerCart.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(items) != 2 || items[0].GetProductID() != book.GetID() || items[0].GetQuantityInt() != 3 || items[1].GetQuantityInt() != 2 {
+		t.Fatalf("expected the quantities added up, got %v", items)
+	}
+
+	guestItems, err := store.CartItemList(ctx, guest.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	found, err := store.CartFindByID(ctx, guest.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(guestItems) != 0 || !found.IsMerged() {
+		t.Fatalf("expected the guest cart empty and merged, got %d items and status %q", len(guestItems), found.GetStatus())
+	}
+}
+
+func TestStoreCartExpireAbandoned(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	abandoned := NewCart()
+	recent := NewCart()
+	for _, cart := range []CartInterface{abandoned, recent} {
+		if err := store.CartCreate(ctx, cart); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	// backdate the last activity of the abandoned cart
+	_, err = store.DB().Exec("UPDATE "+store.CartTableName()+" SET updated_at = ? WHERE id = ?",
+		carbon.Now(carbon.UTC).SubDays(3).ToDateTimeString(carbon.UTC), abandoned.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if _, err := store.CartExpireAbandoned(ctx, 0); err == nil {
+		t.Fatal("expected an error for a zero idle duration")
+	}
+
+	expired, err := store.CartExpireAbandoned(ctx, 48*time.Hour)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if expired != 1 {
+		t.Fatalf("expected 1 cart expired, got %d", expired)
+	}
+
+	count, err := store.CartCount(ctx, NewCartQuery().SetStatus(CART_STATUS_EXPIRED))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if count != 1 {
+		t.Fatalf("expected 1 expired cart, got %d", count)
+	}
+
+	if _, err := store.CartCheckout(ctx, abandoned.GetID()); !errors.Is(err, ErrCartNotActive) {
+		t.Fatalf("expected ErrCartNotActive, got %v", err)
+	}
+}
+
+func TestStoreCartCheckout(t *testing.T) {
+	store := initOutboxStore(t)
+	ctx := context.Background()
+
+	book := createCartProduct(t, store, 12.50, 10)
+	pen := createCartProduct(t, store, 1.99, 10)
+	discount := createCartDiscount(t, store, DISCOUNT_TYPE_PERCENT, 10)
+
+	cart := NewCart().SetCustomerID("CUSTOMER01_ID")
+	if err := store.CartCreate(ctx, cart); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if _, err := store.CartCheckout(ctx, cart.GetID()); !errors.Is(err, ErrCartEmpty) {
+		t.Fatalf("expected ErrCartEmpty, got %v", err)
+	}
+
+	if _, err := store.CartAddItem(ctx, cart.GetID(), book.GetID(), 2); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if _, err := store.CartAddItem(ctx, cart.GetID(), pen.GetID(), 5); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.CartApplyDiscount(ctx, cart.GetID(), discount.GetCode()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	order, err := store.CartCheckout(ctx, cart.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// 2 x 12.50 + 5 x 1.99 = 34.95, less 10% (3.50 rounded)
+	if order.GetCustomerID() != "CUSTOMER01_ID" || order.GetQuantityInt() != 7 || order.GetDiscountAmountFloat() != 3.5 || order.GetPriceFloat() != 31.45 {
+		t.Fatalf("unexpected order %v", order.Data())
+	}
+
+	stored, err := store.OrderFindByID(ctx, order.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if stored == nil || stored.GetStatus() != ORDER_STATUS_PENDING || stored.GetDiscountID() != discount.GetID() {
+		t.Fatalf("expected the pending order stored, got %v", stored)
+	}
+
+	lineItems, err := store.OrderLineItemList(ctx, NewOrderLineItemQuery().SetOrderID(order.GetID()))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(lineItems) != 2 {
+		t.Fatalf("expected 2 line items, got %d", len(lineItems))
+	}
+
+	product, err := store.ProductFindByID(ctx, pen.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if product.GetQuantityInt() != 5 {
+		t.Fatalf("expected the stock reserved, got quantity %d", product.GetQuantityInt())
+	}
+
+	found, err := store.CartFindByID(ctx, cart.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if !found.IsCheckedOut() || found.GetOrderID() != order.GetID() {
+		t.Fatalf("expected the cart checked out as the order, got %v", found.Data())
+	}
+
+	if _, err := store.CartCheckout(ctx, cart.GetID()); !errors.Is(err, ErrCartNotActive) {
+		t.Fatalf("expected ErrCartNotActive on a second checkout, got %v", err)
+	}
+
+	events, err := store.OutboxFetchPending(ctx, 10)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// the pens drop to the low stock threshold of the outbox store
+	if len(events) != 3 ||
+		events[0].GetType() != OUTBOX_EVENT_ORDER_CREATED ||
+		events[1].GetType() != OUTBOX_EVENT_PRODUCT_STOCK_LOW || events[1].GetEntityID() != pen.GetID() ||
+		events[2].GetType() != OUTBOX_EVENT_DISCOUNT_REDEEMED {
+		t.Fatalf("expected the order created, stock low and discount redeemed events, got %v", events)
+	}
+
+	payload, err := events[2].GetPayloadMap()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if payload["order_id"] != order.GetID() || payload["code"] != discount.GetCode() || payload["amount"] != order.GetDiscountAmount() {
+		t.Fatalf("unexpected payload %v", payload)
+	}
+}
+
+func TestStoreCartCheckoutInsufficientStock(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	book := createCartProduct(t, store, 10, 5)
+	pen := createCartProduct(t, store, 2, 1)
+
+	cart := NewCart()
+	if err := store.CartCreate(ctx, cart); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if _, err := store.CartAddItem(ctx, cart.GetID(), book.GetID(), 2); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if _, err := store.CartAddItem(ctx, cart.GetID(), pen.GetID(), 3); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if _, err := store.CartCheckout(ctx, cart.GetID()); !errors.Is(err, ErrInsufficientQuantity) {
+		t.Fatalf("expected ErrInsufficientQuantity, got %v", err)
+	}
+
+	// the checkout is rolled back as a whole
+	product, err := store.ProductFindByID(ctx, book.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if product.GetQuantityInt() != 5 {
+		t.Fatalf("expected the stock unchanged, got quantity %d", product.GetQuantityInt())
+	}
+
+	count, err := store.OrderCount(ctx, NewOrderQuery())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	found, err := store.CartFindByID(ctx, cart.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if count != 0 || !found.IsActive() {
+		t.Fatalf("expected no order and the cart still active, got %d orders and status %q", count, found.GetStatus())
+	}
+}
+
+func TestStoreCartDeleteByID(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	cart := NewCart()
+	if err := store.CartCreate(ctx, cart); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	product := createCartProduct(t, store, 10, 5)
+	if _, err := store.CartAddItem(ctx, cart.GetID(), product.GetID(), 1); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.CartDeleteByID(ctx, cart.GetID()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	items, err := store.CartItemList(ctx, cart.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found, _ := store.CartFindByID(ctx, cart.GetID()); found != nil || len(items) != 0 {
+		t.Fatalf("expected the cart and its items deleted, got %v and %d items", found, len(items))
+	}
+}
@@ -3,7 +3,8 @@ package shopstore
 import (
 	"context"
 	"errors"
-	"strings"
+	"iter"
+	"maps"
 
 	contractsorm "github.com/dracory/neat/contracts/database/orm"
 	"github.com/dromara/carbon/v2"
@@ -12,14 +13,17 @@ import (
 )
 
 func (store *Store) CategoryCount(ctx context.Context, options CategoryQueryInterface) (int64, error) {
-	q, err := store.categoryQuery(options)
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.categoryQuery(ctx, options)
 	if err != nil {
 		return -1, err
 	}
 
 	var count int64
 	if err := q.Count(&count); err != nil {
-		return -1, err
+		return -1, store.operationError("category count", err)
 	}
 
 	return count, nil
@@ -34,17 +38,35 @@ func (store *Store) CategoryCreate(ctx context.Context, category CategoryInterfa
 	category.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
 	category.SetSoftDeletedAt(MAX_DATETIME)
 
+	if err := store.categoryHooks.runBeforeCreate(ctx, category); err != nil {
+		return err
+	}
+
 	data := category.Data()
 	row := map[string]any{}
 	for k, v := range data {
-		row[k] = v
+		row[k] = rowValue(k, v)
 	}
 
-	err := store.db.Query().Table(store.categoryTableName).Create(row)
-	if err != nil {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	if err := store.assertReferencesExist(ctx, data, store.categoryReferences()); err != nil {
 		return err
 	}
 
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		err := statement(tx).Table(store.categoryTableName).Create(row)
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_CATEGORY, category.GetID(), AUDIT_OPERATION_CREATE, nil, data),
+		}, err
+	})
+	if err != nil {
+		return store.operationError("category create", err)
+	}
+
+	store.categoryHooks.runAfterCreate(ctx, category)
+
 	return nil
 }
 
@@ -65,8 +87,27 @@ func (store *Store) CategoryDeleteByID(ctx context.Context, id string) error {
 		return err
 	}
 
-	_, err := store.db.Query().Table(store.categoryTableName).Where(COLUMN_ID+" = ?", id).Delete()
-	return err
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		before, err := store.storedValues(tx, store.categoryTableName, id, nil)
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		_, err = statement(tx).Table(store.categoryTableName).Where(COLUMN_ID+" = ?", id).Delete()
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_CATEGORY, id, AUDIT_OPERATION_DELETE, before, nil),
+		}, err
+	})
+	if err != nil {
+		return store.operationError("category delete", err)
+	}
+
+	store.categoryHooks.runAfterDelete(ctx, id)
+
+	return nil
 }
 
 // assertCategoryDeletable performs a non-atomic check-then-act: the count queries and the
@@ -90,6 +131,14 @@ func (store *Store) assertCategoryDeletable(ctx context.Context, categoryID stri
 		return ErrCategoryHasActiveMedia
 	}
 
+	productCount, err := store.ProductCount(ctx, NewProductQuery().SetCategoryID(categoryID))
+	if err != nil {
+		return err
+	}
+	if productCount > 0 {
+		return ErrCategoryHasActiveProducts
+	}
+
 	return nil
 }
 
@@ -120,7 +169,10 @@ func (store *Store) CategoryList(ctx context.Context, options CategoryQueryInter
 		return nil, err
 	}
 
-	q, err := store.categoryQuery(options)
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.categoryQuery(ctx, options)
 
 	if err != nil {
 		return nil, err
@@ -128,7 +180,7 @@ func (store *Store) CategoryList(ctx context.Context, options CategoryQueryInter
 
 	var results []map[string]any
 	if err := q.Get(&results); err != nil {
-		return nil, err
+		return nil, store.operationError("category list", err)
 	}
 
 	list := []CategoryInterface{}
@@ -141,6 +193,62 @@ func (store *Store) CategoryList(ctx context.Context, options CategoryQueryInter
 	return list, nil
 }
 
+// CategoryIterate streams the categories matching the query options, fetching
+// them from the database in batches instead of loading them all at once.
+// Iteration stops with an error as soon as ctx is cancelled.
+func (store *Store) CategoryIterate(ctx context.Context, options CategoryQueryInterface) iter.Seq2[CategoryInterface, error] {
+	if options == nil {
+		return iterateError[CategoryInterface](errors.New("category options is nil"))
+	}
+
+	build := func(ctx context.Context) (contractsorm.Query, error) {
+		return store.categoryQuery(ctx, options)
+	}
+
+	hydrate := func(data map[string]string) CategoryInterface {
+		return NewCategoryFromExistingData(data)
+	}
+
+	return iterateInBatches(store, ctx, "category iterate", options, build, hydrate)
+}
+
+// CategoryListPage returns a single keyset (cursor) paginated page of categorys
+// matching the query options. The page size is taken from SetLimit. Pass the
+// returned NextCursor to SetAfterCursor to fetch the following page.
+func (store *Store) CategoryListPage(ctx context.Context, options CategoryQueryInterface) (ListPage[CategoryInterface], error) {
+	if options == nil {
+		return ListPage[CategoryInterface]{}, errors.New("category options is nil")
+	}
+
+	if !options.HasLimit() {
+		return ListPage[CategoryInterface]{}, errors.New("category list page. limit is required")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.categoryQuery(ctx, options)
+	if err != nil {
+		return ListPage[CategoryInterface]{}, err
+	}
+
+	q = keysetPageQuery(q, options.Limit(), options)
+
+	var results []map[string]any
+	if err := q.Get(&results); err != nil {
+		return ListPage[CategoryInterface]{}, store.operationError("category list page", err)
+	}
+
+	list := []CategoryInterface{}
+
+	lo.ForEach(results, func(result map[string]any, index int) {
+		model := NewCategoryFromExistingData(mapAnyToString(result))
+		list = append(list, model)
+	})
+
+	return newListPage(list, options.Limit(), options), nil
+}
+
 func (store *Store) CategorySoftDelete(ctx context.Context, category CategoryInterface) error {
 	if category == nil {
 		return errors.New("category is nil")
@@ -152,7 +260,13 @@ func (store *Store) CategorySoftDelete(ctx context.Context, category CategoryInt
 
 	category.SetSoftDeletedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
 
-	return store.CategoryUpdate(ctx, category)
+	if err := store.categoryUpdate(ctx, category); err != nil {
+		return err
+	}
+
+	store.categoryHooks.runAfterSoftDelete(ctx, category.GetID())
+
+	return nil
 }
 
 func (store *Store) CategorySoftDeleteByID(ctx context.Context, id string) error {
@@ -173,18 +287,89 @@ func (store *Store) CategorySoftDeleteByID(ctx context.Context, id string) error
 	return store.CategorySoftDelete(ctx, category)
 }
 
-func (store *Store) CategoryUpdate(ctx context.Context, category CategoryInterface) (err error) {
+// CategorySoftDeleteMany soft deletes the live categories matching the
+// query options in a single transaction, returning how many were soft
+// deleted.
+// Fails like CategorySoftDelete when a category still has live children or
+// media, unless its children are soft deleted along.
+// AfterSoftDelete hooks run for every category.
+func (store *Store) CategorySoftDeleteMany(ctx context.Context, options CategoryQueryInterface) (int64, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	ids, err := store.updateMany(ctx, store.categoryBulkTable(options), map[string]string{
+		COLUMN_SOFT_DELETED_AT: carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC),
+	})
+	if err != nil {
+		return 0, store.operationError("category soft delete many", err)
+	}
+
+	for _, id := range ids {
+		store.categoryHooks.runAfterSoftDelete(ctx, id)
+	}
+
+	return int64(len(ids)), nil
+}
+
+func (store *Store) CategoryUpdate(ctx context.Context, category CategoryInterface) error {
 	if category == nil {
 		return errors.New("category is nil")
 	}
 
+	if err := store.categoryHooks.runBeforeUpdate(ctx, category, category.DataChanged()); err != nil {
+		return err
+	}
+
+	changed := category.DataChanged()
+	if err := store.categoryUpdate(ctx, category); err != nil {
+		return err
+	}
+
+	store.categoryHooks.runAfterUpdate(ctx, category, changed)
+
+	return nil
+}
+
+// CategoryUpdateMany sets the fields on all the categories matching the
+// query options in a single transaction, returning how many were updated.
+// Only the columns listed in categoryUpdatableColumns can be set. Versions
+// are incremented without being checked. The AfterUpdate hooks run on the
+// categories set, but not the Before hooks, as the categories are not
+// loaded.
+func (store *Store) CategoryUpdateMany(ctx context.Context, options CategoryQueryInterface, fields map[string]string) (int64, error) {
+	if err := assertUpdatable(fields, categoryUpdatableColumns); err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	if err := store.assertReferencesExist(ctx, fields, store.categoryReferences()); err != nil {
+		return 0, err
+	}
+
+	ids, rows, err := store.updateManyLoaded(ctx, store.categoryBulkTable(options), fields, store.categoryHooks.hasAfterUpdate())
+	if err != nil {
+		return 0, store.operationError("category update many", err)
+	}
+
+	runAfterUpdateRows(ctx, &store.categoryHooks, rows, NewCategoryFromExistingData, func(map[string]string) map[string]string {
+		return maps.Clone(fields)
+	})
+
+	return int64(len(ids)), nil
+}
+
+// categoryUpdate writes the changed fields of category, without running hooks
+func (store *Store) categoryUpdate(ctx context.Context, category CategoryInterface) (err error) {
 	category.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString())
 
 	dataChanged := category.DataChanged()
 
-	delete(dataChanged, COLUMN_ID) // ID is not updateable
-	delete(dataChanged, "hash")    // Hash is not updateable
-	delete(dataChanged, "data")    // Data is not updateable
+	delete(dataChanged, COLUMN_ID)      // ID is not updateable
+	delete(dataChanged, "hash")         // Hash is not updateable
+	delete(dataChanged, "data")         // Data is not updateable
+	delete(dataChanged, COLUMN_VERSION) // Version is set by the update itself
 
 	if len(dataChanged) < 1 {
 		return nil
@@ -192,21 +377,62 @@ func (store *Store) CategoryUpdate(ctx context.Context, category CategoryInterfa
 
 	row := map[string]any{}
 	for k, v := range dataChanged {
-		row[k] = v
+		row[k] = rowValue(k, v)
 	}
 
-	_, err = store.db.Query().Table(store.categoryTableName).Where(COLUMN_ID+" = ?", category.GetID()).Update(row)
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
 
-	if err != nil {
+	if err := store.assertReferencesExist(ctx, dataChanged, store.categoryReferences()); err != nil {
 		return err
 	}
 
+	var version int64
+	err = store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		before, err := store.storedValues(tx, store.categoryTableName, category.GetID(), lo.Keys(dataChanged))
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		version, err = versionedUpdate(ctx, statement(tx).Table(store.categoryTableName).Where(COLUMN_ID+" = ?", category.GetID()), category.GetVersion(), row)
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_CATEGORY, category.GetID(), updateOperation(dataChanged), before, dataChanged),
+		}, err
+	})
+
+	if err != nil {
+		return store.operationError("category update", err)
+	}
+
+	category.SetVersion(version)
 	category.MarkAsNotDirty()
 
 	return nil
 }
 
-func (store *Store) categoryQuery(options CategoryQueryInterface) (contractsorm.Query, error) {
+// categoryBulkTable describes the categories matching the query options to
+// the bulk operations
+func (store *Store) categoryBulkTable(options CategoryQueryInterface) bulkTable {
+	return bulkTable{
+		entityType: ENTITY_TYPE_CATEGORY,
+		tableName:  store.categoryTableName,
+		selectRows: func(q contractsorm.Query) (contractsorm.Query, error) {
+			return store.categoryQueryOn(q, options)
+		},
+		dependents: []dependentCheck{
+			{tableName: store.categoryTableName, column: COLUMN_PARENT_ID, err: ErrCategoryHasActiveChildren},
+			{tableName: store.mediaTableName, column: COLUMN_ENTITY_ID, err: ErrCategoryHasActiveMedia},
+			{tableName: store.productTableName, column: COLUMN_CATEGORY_ID, err: ErrCategoryHasActiveProducts},
+		},
+	}
+}
+
+func (store *Store) categoryQuery(ctx context.Context, options CategoryQueryInterface) (contractsorm.Query, error) {
+	return store.categoryQueryOn(store.query(ctx), options)
+}
+
+// categoryQueryOn applies the query options to q, which may run within a transaction
+func (store *Store) categoryQueryOn(q contractsorm.Query, options CategoryQueryInterface) (contractsorm.Query, error) {
 	if options == nil {
 		return nil, errors.New("category options is nil")
 	}
@@ -215,7 +441,7 @@ func (store *Store) categoryQuery(options CategoryQueryInterface) (contractsorm.
 		return nil, err
 	}
 
-	q := store.db.Query().Table(store.categoryTableName)
+	q = q.Table(store.categoryTableName)
 
 	if options.HasID() {
 		q = q.Where(COLUMN_ID+" = ?", options.ID())
@@ -238,10 +464,7 @@ func (store *Store) categoryQuery(options CategoryQueryInterface) (contractsorm.
 	}
 
 	if options.HasTitleLike() {
-		searchTerm := strings.ReplaceAll(options.TitleLike(), "'", "''")
-		searchTerm = strings.ReplaceAll(searchTerm, "%", "\\%")
-		searchTerm = strings.ReplaceAll(searchTerm, "_", "\\_")
-		q = q.Where(COLUMN_TITLE+" LIKE ?", "%"+searchTerm+"%")
+		q = q.Where(store.dialect.like(COLUMN_TITLE), likeContains(options.TitleLike()))
 	}
 
 	if !options.IsCountOnly() {
@@ -254,10 +477,16 @@ func (store *Store) categoryQuery(options CategoryQueryInterface) (contractsorm.
 		}
 	}
 
-	sortOrder := lo.Ternary(options.HasSortDirection(), options.SortDirection(), "desc")
+	if options.HasAfterCursor() {
+		var err error
+		q, err = whereAfterCursor(q, store.dialect, keysetSort(options), options.AfterCursor())
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	if options.HasOrderBy() {
-		q = q.OrderBy(options.OrderBy(), sortOrder)
+	if isSorted(options) {
+		q = orderByKeyset(q, keysetSort(options))
 	}
 
 	if options.SoftDeletedIncluded() {
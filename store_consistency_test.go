@@ -586,6 +586,41 @@ func TestCategoryDelete_BlockedByMedia(t *testing.T) {
 	}
 }
 
+func TestCategoryDelete_BlockedByProducts(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	category := NewCategory().
+		SetStatus(CATEGORY_STATUS_DRAFT).
+		SetTitle("Category")
+
+	if err := store.CategoryCreate(ctx, category); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	product := NewProduct().
+		SetTitle("Product").
+		SetCategoryID(category.GetID())
+
+	if err := store.ProductCreate(ctx, product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	err = store.CategoryDelete(ctx, category)
+	if !errors.Is(err, ErrCategoryHasActiveProducts) {
+		t.Fatalf("expected ErrCategoryHasActiveProducts, got: %v", err)
+	}
+
+	_, err = store.CategorySoftDeleteMany(ctx, NewCategoryQuery().SetID(category.GetID()))
+	if !errors.Is(err, ErrCategoryHasActiveProducts) {
+		t.Fatalf("expected ErrCategoryHasActiveProducts on soft delete many, got: %v", err)
+	}
+}
+
 func TestCategorySoftDelete_BlockedByChildren(t *testing.T) {
 	store, err := initStore(":memory:")
 	if err != nil {
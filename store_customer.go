@@ -0,0 +1,558 @@
+package shopstore
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"maps"
+
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+	"github.com/dromara/carbon/v2"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+)
+
+func (store *Store) CustomerCount(ctx context.Context, options CustomerQueryInterface) (int64, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.customerQuery(ctx, options)
+	if err != nil {
+		return -1, err
+	}
+
+	var count int64
+	if err := q.Count(&count); err != nil {
+		return -1, store.operationError("customer count", err)
+	}
+
+	return count, nil
+}
+
+func (store *Store) CustomerCreate(ctx context.Context, customer CustomerInterface) error {
+	if customer == nil {
+		return errors.New("customer is nil")
+	}
+
+	customer.SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+	customer.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+	customer.SetSoftDeletedAt(MAX_DATETIME)
+
+	if err := store.customerHooks.runBeforeCreate(ctx, customer); err != nil {
+		return err
+	}
+
+	data := customer.Data()
+	row := map[string]any{}
+	for k, v := range data {
+		row[k] = rowValue(k, v)
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		err := statement(tx).Table(store.customerTableName).Create(row)
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_CUSTOMER, customer.GetID(), AUDIT_OPERATION_CREATE, nil, data),
+		}, err
+	})
+	if err != nil && store.customerEmailTaken(ctx, customer) {
+		return ErrCustomerEmailExists
+	}
+	if err != nil {
+		return store.operationError("customer create", err)
+	}
+
+	customer.MarkAsNotDirty()
+
+	store.customerHooks.runAfterCreate(ctx, customer)
+
+	return nil
+}
+
+func (store *Store) CustomerDelete(ctx context.Context, customer CustomerInterface) error {
+	if customer == nil {
+		return errors.New("customer is nil")
+	}
+
+	return store.CustomerDeleteByID(ctx, customer.GetID())
+}
+
+// assertCustomerDeletable performs a non-atomic check-then-act: the count queries and the
+// subsequent delete/softdelete are not wrapped in a transaction. A concurrent insert
+// of a child row between the check and the delete could create an orphaned reference.
+// Transaction wrapping is a future improvement if the concurrency model requires it.
+func (store *Store) assertCustomerDeletable(ctx context.Context, customerID string) error {
+	addressCount, err := store.AddressCount(ctx, NewAddressQuery().SetCustomerID(customerID))
+	if err != nil {
+		return err
+	}
+	if addressCount > 0 {
+		return ErrCustomerHasActiveAddresses
+	}
+
+	return nil
+}
+
+// CustomerDeleteByID permanently deletes the customer, together with its
+// soft deleted addresses, in a single transaction. The orders of the
+// customer are kept, with their customer_id unchanged. Fails with
+// ErrCustomerHasActiveAddresses while the address book is not empty.
+func (store *Store) CustomerDeleteByID(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("customer id is empty")
+	}
+
+	if err := store.assertCustomerDeletable(ctx, id); err != nil {
+		return err
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	err := store.query(ctx).Transaction(func(tx contractsorm.Query) error {
+		before, err := store.storedValues(tx, store.customerTableName, id, nil)
+		if err != nil {
+			return err
+		}
+
+		// the soft deleted addresses would be left pointing at no customer
+		addresses := func() contractsorm.Query {
+			return statement(tx).
+				Table(store.addressTableName).
+				Where(COLUMN_CUSTOMER_ID+" = ?", id).
+				Where(COLUMN_SOFT_DELETED_AT+" <> ?", MAX_DATETIME)
+		}
+
+		records := changeRecords{}
+
+		if store.changesRecorded() {
+			var results []map[string]any
+			if err := addresses().Get(&results); err != nil {
+				return err
+			}
+
+			for _, result := range results {
+				address := mapAnyToString(result)
+				records.auditLogs = append(records.auditLogs, store.auditLogEntries(ctx, ENTITY_TYPE_ADDRESS, address[COLUMN_ID], AUDIT_OPERATION_DELETE, address, nil)...)
+			}
+		}
+
+		if _, err := addresses().Delete(); err != nil {
+			return err
+		}
+
+		if _, err := statement(tx).Table(store.customerTableName).Where(COLUMN_ID+" = ?", id).Delete(); err != nil {
+			return err
+		}
+
+		records.auditLogs = append(records.auditLogs, store.auditLogEntries(ctx, ENTITY_TYPE_CUSTOMER, id, AUDIT_OPERATION_DELETE, before, nil)...)
+
+		return store.recordChanges(tx, records)
+	})
+	if err != nil {
+		return store.operationError("customer delete", err)
+	}
+
+	store.customerHooks.runAfterDelete(ctx, id)
+
+	return nil
+}
+
+func (store *Store) CustomerFindByID(ctx context.Context, id string) (CustomerInterface, error) {
+	if id == "" {
+		return nil, errors.New("customer id is empty")
+	}
+
+	list, err := store.CustomerList(ctx, NewCustomerQuery().
+		SetID(id).
+		SetLimit(1))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) > 0 {
+		return list[0], nil
+	}
+
+	return nil, nil
+}
+
+// CustomerFindByEmail retrieves the live customer with the email, compared
+// case insensitively
+func (store *Store) CustomerFindByEmail(ctx context.Context, email string) (CustomerInterface, error) {
+	if normalizeEmail(email) == "" {
+		return nil, errors.New("customer email is empty")
+	}
+
+	list, err := store.CustomerList(ctx, NewCustomerQuery().
+		SetEmail(email).
+		SetLimit(1))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) > 0 {
+		return list[0], nil
+	}
+
+	return nil, nil
+}
+
+func (store *Store) CustomerList(ctx context.Context, options CustomerQueryInterface) ([]CustomerInterface, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.customerQuery(ctx, options)
+	if err != nil {
+		return []CustomerInterface{}, err
+	}
+
+	var results []map[string]any
+	if err := q.Get(&results); err != nil {
+		return []CustomerInterface{}, store.operationError("customer list", err)
+	}
+
+	list := []CustomerInterface{}
+
+	lo.ForEach(results, func(result map[string]any, index int) {
+		model := NewCustomerFromExistingData(mapAnyToString(result))
+		list = append(list, model)
+	})
+
+	return list, nil
+}
+
+// CustomerIterate streams the customers matching the query options, fetching
+// them from the database in batches instead of loading them all at once.
+// Iteration stops with an error as soon as ctx is cancelled.
+func (store *Store) CustomerIterate(ctx context.Context, options CustomerQueryInterface) iter.Seq2[CustomerInterface, error] {
+	if options == nil {
+		options = NewCustomerQuery()
+	}
+
+	build := func(ctx context.Context) (contractsorm.Query, error) {
+		return store.customerQuery(ctx, options)
+	}
+
+	hydrate := func(data map[string]string) CustomerInterface {
+		return NewCustomerFromExistingData(data)
+	}
+
+	return iterateInBatches(store, ctx, "customer iterate", options, build, hydrate)
+}
+
+// CustomerListPage returns a single keyset (cursor) paginated page of customers
+// matching the query options. The page size is taken from SetLimit. Pass the
+// returned NextCursor to SetAfterCursor to fetch the following page.
+func (store *Store) CustomerListPage(ctx context.Context, options CustomerQueryInterface) (ListPage[CustomerInterface], error) {
+	if options == nil {
+		options = NewCustomerQuery()
+	}
+
+	if !options.HasLimit() {
+		return ListPage[CustomerInterface]{}, errors.New("customer list page. limit is required")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.customerQuery(ctx, options)
+	if err != nil {
+		return ListPage[CustomerInterface]{}, err
+	}
+
+	q = keysetPageQuery(q, options.Limit(), options)
+
+	var results []map[string]any
+	if err := q.Get(&results); err != nil {
+		return ListPage[CustomerInterface]{}, store.operationError("customer list page", err)
+	}
+
+	list := []CustomerInterface{}
+
+	lo.ForEach(results, func(result map[string]any, index int) {
+		model := NewCustomerFromExistingData(mapAnyToString(result))
+		list = append(list, model)
+	})
+
+	return newListPage(list, options.Limit(), options), nil
+}
+
+// CustomerOrderList retrieves the orders of the customer matching the query
+// options, the orders with the customer ID when options is nil
+func (store *Store) CustomerOrderList(ctx context.Context, customerID string, options OrderQueryInterface) ([]OrderInterface, error) {
+	if customerID == "" {
+		return []OrderInterface{}, errors.New("customer id is empty")
+	}
+
+	if options == nil {
+		options = NewOrderQuery()
+	}
+
+	return store.OrderList(ctx, options.SetCustomerID(customerID))
+}
+
+// CustomerSoftDelete soft deletes the customer. The orders of the customer
+// are kept, so a soft deleted customer still shows in order history. Fails
+// with ErrCustomerHasActiveAddresses while the address book is not empty.
+func (store *Store) CustomerSoftDelete(ctx context.Context, customer CustomerInterface) error {
+	if customer == nil {
+		return errors.New("customer is nil")
+	}
+
+	if err := store.assertCustomerDeletable(ctx, customer.GetID()); err != nil {
+		return err
+	}
+
+	customer.SetSoftDeletedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+
+	if err := store.customerUpdate(ctx, customer); err != nil {
+		return err
+	}
+
+	store.customerHooks.runAfterSoftDelete(ctx, customer.GetID())
+
+	return nil
+}
+
+func (store *Store) CustomerSoftDeleteByID(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("customer id is empty")
+	}
+
+	customer, err := store.CustomerFindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if customer == nil {
+		return nil
+	}
+
+	return store.CustomerSoftDelete(ctx, customer)
+}
+
+// CustomerSoftDeleteMany soft deletes the live customers matching the
+// query options in a single transaction, returning how many were soft
+// deleted.
+// AfterSoftDelete hooks run for every customer.
+func (store *Store) CustomerSoftDeleteMany(ctx context.Context, options CustomerQueryInterface) (int64, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	ids, err := store.updateMany(ctx, store.customerBulkTable(options), map[string]string{
+		COLUMN_SOFT_DELETED_AT: carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC),
+	})
+	if err != nil {
+		return 0, store.operationError("customer soft delete many", err)
+	}
+
+	for _, id := range ids {
+		store.customerHooks.runAfterSoftDelete(ctx, id)
+	}
+
+	return int64(len(ids)), nil
+}
+
+func (store *Store) CustomerUpdate(ctx context.Context, customer CustomerInterface) error {
+	if customer == nil {
+		return errors.New("customer is nil")
+	}
+
+	if err := store.customerHooks.runBeforeUpdate(ctx, customer, customer.DataChanged()); err != nil {
+		return err
+	}
+
+	changed := customer.DataChanged()
+	if err := store.customerUpdate(ctx, customer); err != nil {
+		return err
+	}
+
+	store.customerHooks.runAfterUpdate(ctx, customer, changed)
+
+	return nil
+}
+
+// CustomerUpdateMany sets the fields on all the customers matching the
+// query options in a single transaction, returning how many were updated.
+// Only the columns listed in customerUpdatableColumns can be set. Versions
+// are incremented without being checked. The AfterUpdate hooks run on the
+// customers set, but not the Before hooks, as the customers are not loaded.
+func (store *Store) CustomerUpdateMany(ctx context.Context, options CustomerQueryInterface, fields map[string]string) (int64, error) {
+	if err := assertUpdatable(fields, customerUpdatableColumns); err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	ids, rows, err := store.updateManyLoaded(ctx, store.customerBulkTable(options), fields, store.customerHooks.hasAfterUpdate())
+	if err != nil {
+		return 0, store.operationError("customer update many", err)
+	}
+
+	runAfterUpdateRows(ctx, &store.customerHooks, rows, NewCustomerFromExistingData, func(map[string]string) map[string]string {
+		return maps.Clone(fields)
+	})
+
+	return int64(len(ids)), nil
+}
+
+// customerUpdate writes the changed fields of customer, without running hooks
+func (store *Store) customerUpdate(ctx context.Context, customer CustomerInterface) error {
+	customer.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString())
+
+	dataChanged := customer.DataChanged()
+
+	delete(dataChanged, COLUMN_ID)      // ID is not updateable
+	delete(dataChanged, "hash")         // Hash is not updateable
+	delete(dataChanged, "data")         // Data is not updateable
+	delete(dataChanged, COLUMN_VERSION) // Version is set by the update itself
+
+	if len(dataChanged) < 1 {
+		return nil
+	}
+
+	row := map[string]any{}
+	for k, v := range dataChanged {
+		row[k] = rowValue(k, v)
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	var version int64
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		before, err := store.storedValues(tx, store.customerTableName, customer.GetID(), lo.Keys(dataChanged))
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		version, err = versionedUpdate(ctx, statement(tx).Table(store.customerTableName).Where(COLUMN_ID+" = ?", customer.GetID()), customer.GetVersion(), row)
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_CUSTOMER, customer.GetID(), updateOperation(dataChanged), before, dataChanged),
+		}, err
+	})
+
+	if err == nil {
+		customer.SetVersion(version)
+		customer.MarkAsNotDirty()
+	}
+
+	if err != nil && store.customerEmailTaken(ctx, customer) {
+		return ErrCustomerEmailExists
+	}
+
+	return store.operationError("customer update", err)
+}
+
+// customerEmailTaken reports whether another live customer uses the email
+// of the customer. It tells a violation of the unique email index apart
+// from other write failures, as the database error details are not exposed.
+func (store *Store) customerEmailTaken(ctx context.Context, customer CustomerInterface) bool {
+	if customer.GetEmail() == "" {
+		return false
+	}
+
+	var count int64
+
+	err := store.query(ctx).
+		Table(store.customerTableName).
+		Where(COLUMN_EMAIL+" = ?", customer.GetEmail()).
+		Where(COLUMN_ID+" <> ?", customer.GetID()).
+		Where(COLUMN_SOFT_DELETED_AT+" = ?", MAX_DATETIME).
+		Count(&count)
+
+	return err == nil && count > 0
+}
+
+// customerBulkTable describes the customers matching the query options to
+// the bulk operations
+func (store *Store) customerBulkTable(options CustomerQueryInterface) bulkTable {
+	return bulkTable{
+		entityType: ENTITY_TYPE_CUSTOMER,
+		tableName:  store.customerTableName,
+		selectRows: func(q contractsorm.Query) (contractsorm.Query, error) {
+			return store.customerQueryOn(q, options)
+		},
+		dependents: []dependentCheck{
+			{tableName: store.addressTableName, column: COLUMN_CUSTOMER_ID, err: ErrCustomerHasActiveAddresses},
+		},
+	}
+}
+
+func (store *Store) customerQuery(ctx context.Context, options CustomerQueryInterface) (contractsorm.Query, error) {
+	return store.customerQueryOn(store.query(ctx), options)
+}
+
+// customerQueryOn applies the query options to q, which may run within a transaction
+func (store *Store) customerQueryOn(q contractsorm.Query, options CustomerQueryInterface) (contractsorm.Query, error) {
+	if options == nil {
+		options = NewCustomerQuery()
+	}
+
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	q = q.Table(store.customerTableName)
+
+	if options.HasID() {
+		q = q.Where(COLUMN_ID+" = ?", options.ID())
+	}
+
+	if options.HasIDIn() {
+		q = q.WhereIn(COLUMN_ID, lo.ToAnySlice(options.IDIn()))
+	}
+
+	if options.HasStatus() {
+		q = q.Where(COLUMN_STATUS+" = ?", options.Status())
+	}
+
+	if options.HasStatusIn() {
+		q = q.WhereIn(COLUMN_STATUS, lo.ToAnySlice(options.StatusIn()))
+	}
+
+	if options.HasEmail() {
+		q = q.Where(COLUMN_EMAIL+" = ?", normalizeEmail(options.Email()))
+	}
+
+	if options.HasCreatedAtGte() && options.HasCreatedAtLte() {
+		q = q.Where(COLUMN_CREATED_AT+" BETWEEN ? AND ?", options.CreatedAtGte(), options.CreatedAtLte())
+	} else if options.HasCreatedAtGte() {
+		q = q.Where(COLUMN_CREATED_AT+" >= ?", options.CreatedAtGte())
+	} else if options.HasCreatedAtLte() {
+		q = q.Where(COLUMN_CREATED_AT+" <= ?", options.CreatedAtLte())
+	}
+
+	if !options.IsCountOnly() {
+		if options.HasLimit() {
+			q = q.Limit(cast.ToInt(options.Limit()))
+		}
+
+		if options.HasOffset() {
+			q = q.Offset(cast.ToInt(options.Offset()))
+		}
+	}
+
+	if options.HasAfterCursor() {
+		var err error
+		q, err = whereAfterCursor(q, store.dialect, keysetSort(options), options.AfterCursor())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if isSorted(options) {
+		q = orderByKeyset(q, keysetSort(options))
+	}
+
+	if !options.SoftDeletedIncluded() {
+		q = q.Where(COLUMN_SOFT_DELETED_AT+" = ?", MAX_DATETIME)
+	}
+
+	return q, nil
+}
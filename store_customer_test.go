@@ -0,0 +1,226 @@
+package shopstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestStoreCustomerCreateAndFind(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	customer := NewCustomer().SetName("Jane Doe").SetEmail("Jane@Example.com").SetPhone("+44 20 7946 0000")
+	_ = customer.SetMeta("locale", "en-GB")
+
+	if err := store.CustomerCreate(ctx, customer); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// customers without an email do not collide on the unique email index
+	for range 2 {
+		if err := store.CustomerCreate(ctx, NewCustomer().SetName("Guest")); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	found, err := store.CustomerFindByID(ctx, customer.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found == nil || found.GetName() != "Jane Doe" || found.GetPhone() != "+44 20 7946 0000" || found.GetMeta("locale") != "en-GB" {
+		t.Fatalf("unexpected customer %v", found)
+	}
+
+	found, err = store.CustomerFindByEmail(ctx, " JANE@example.com")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found == nil || found.GetID() != customer.GetID() {
+		t.Fatalf("expected the customer found by email, got %v", found)
+	}
+
+	if _, err := store.CustomerFindByEmail(ctx, " "); err == nil {
+		t.Fatal("expected an error for an empty email")
+	}
+
+	count, err := store.CustomerCount(ctx, NewCustomerQuery().SetStatus(CUSTOMER_STATUS_ACTIVE))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if count != 3 {
+		t.Fatalf("expected 3 active customers, got %d", count)
+	}
+
+	found.SetStatus(CUSTOMER_STATUS_INACTIVE)
+	if err := store.CustomerUpdate(ctx, found); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	list, err := store.CustomerList(ctx, NewCustomerQuery().SetStatus(CUSTOMER_STATUS_INACTIVE))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(list) != 1 || list[0].GetVersion() != 2 {
+		t.Fatalf("expected the updated customer at version 2, got %v", list)
+	}
+}
+
+func TestStoreCustomerEmailExists(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	first := NewCustomer().SetEmail("jane@example.com")
+	if err := store.CustomerCreate(ctx, first); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	err = store.CustomerCreate(ctx, NewCustomer().SetEmail("JANE@example.com"))
+	if !errors.Is(err, ErrCustomerEmailExists) {
+		t.Fatalf("expected ErrCustomerEmailExists, got %v", err)
+	}
+
+	second := NewCustomer().SetEmail("john@example.com")
+	if err := store.CustomerCreate(ctx, second); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	second.SetEmail("jane@example.com")
+	if err := store.CustomerUpdate(ctx, second); !errors.Is(err, ErrCustomerEmailExists) {
+		t.Fatalf("expected ErrCustomerEmailExists on update, got %v", err)
+	}
+
+	// a soft deleted customer frees its email
+	if err := store.CustomerSoftDelete(ctx, first); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found, _ := store.CustomerFindByEmail(ctx, "jane@example.com"); found != nil {
+		t.Fatal("expected the soft deleted customer not to be found")
+	}
+
+	if err := store.CustomerCreate(ctx, NewCustomer().SetEmail("jane@example.com")); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+}
+
+func TestStoreCustomerOrderList(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	customer := NewCustomer().SetEmail("jane@example.com")
+	if err := store.CustomerCreate(ctx, customer); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	orders := []OrderInterface{
+		NewOrder().SetCustomerID(customer.GetID()).SetStatus(ORDER_STATUS_COMPLETED),
+		NewOrder().SetCustomerID(customer.GetID()).SetStatus(ORDER_STATUS_PENDING),
+		NewOrder().SetCustomerID("OTHER"),
+	}
+
+	for _, order := range orders {
+		if err := store.OrderCreate(ctx, order); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	list, err := store.CustomerOrderList(ctx, customer.GetID(), nil)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(list) != 2 {
+		t.Fatalf("expected the 2 orders of the customer, got %d", len(list))
+	}
+
+	list, err = store.CustomerOrderList(ctx, customer.GetID(), NewOrderQuery().SetStatus(ORDER_STATUS_PENDING))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(list) != 1 || list[0].GetID() != orders[1].GetID() {
+		t.Fatalf("expected the pending order of the customer, got %v", list)
+	}
+
+	// soft deleting the customer keeps its orders
+	if err := store.CustomerSoftDeleteByID(ctx, customer.GetID()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	list, err = store.CustomerOrderList(ctx, customer.GetID(), nil)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(list) != 2 {
+		t.Fatalf("expected the orders kept, got %d", len(list))
+	}
+
+	if _, err := store.CustomerOrderList(ctx, "", nil); err == nil {
+		t.Fatal("expected an error for an empty customer id")
+	}
+}
+
+func TestStoreCustomerListPage_OrderByEmailWithNulls(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	// customers without an email store NULL, which sorts apart from the values
+	for _, email := range []string{"b@example.com", "", "a@example.com", ""} {
+		if err := store.CustomerCreate(ctx, NewCustomer().SetEmail(email)); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	for _, direction := range []string{SORT_DIRECTION_ASC, SORT_DIRECTION_DESC} {
+		seen := map[string]bool{}
+		cursor := ""
+
+		for {
+			query := NewCustomerQuery().SetOrderBy(COLUMN_EMAIL).SetSortDirection(direction).SetLimit(1)
+			if cursor != "" {
+				query.SetAfterCursor(cursor)
+			}
+
+			page, err := store.CustomerListPage(ctx, query)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			for _, customer := range page.Items {
+				seen[customer.GetID()] = true
+			}
+
+			if !page.HasMore {
+				break
+			}
+
+			cursor = page.NextCursor
+		}
+
+		if len(seen) != 4 {
+			t.Fatalf("%s: expected the 4 customers, got %d", direction, len(seen))
+		}
+	}
+}
@@ -3,6 +3,8 @@ package shopstore
 import (
 	"context"
 	"errors"
+	"iter"
+	"maps"
 
 	contractsorm "github.com/dracory/neat/contracts/database/orm"
 	"github.com/dromara/carbon/v2"
@@ -11,14 +13,17 @@ import (
 )
 
 func (store *Store) DiscountCount(ctx context.Context, options DiscountQueryInterface) (int64, error) {
-	q, err := store.discountQuery(options)
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.discountQuery(ctx, options)
 	if err != nil {
 		return -1, err
 	}
 
 	var count int64
 	if err := q.Count(&count); err != nil {
-		return -1, err
+		return -1, store.operationError("discount count", err)
 	}
 
 	return count, nil
@@ -29,19 +34,36 @@ func (store *Store) DiscountCreate(ctx context.Context, discount DiscountInterfa
 	discount.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
 	discount.SetSoftDeletedAt(MAX_DATETIME)
 
+	if err := store.discountHooks.runBeforeCreate(ctx, discount); err != nil {
+		return err
+	}
+
 	data := discount.Data()
 	row := map[string]any{}
 	for k, v := range data {
-		row[k] = v
+		row[k] = rowValue(k, v)
 	}
 
-	err := store.db.Query().Table(store.discountTableName).Create(row)
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		err := statement(tx).Table(store.discountTableName).Create(row)
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_DISCOUNT, discount.GetID(), AUDIT_OPERATION_CREATE, nil, data),
+		}, err
+	})
+	if err != nil && store.discountCodeTaken(ctx, discount) {
+		return ErrDiscountCodeExists
+	}
 	if err != nil {
-		return err
+		return store.operationError("discount create", err)
 	}
 
 	discount.MarkAsNotDirty()
 
+	store.discountHooks.runAfterCreate(ctx, discount)
+
 	return nil
 }
 
@@ -58,8 +80,27 @@ func (store *Store) DiscountDeleteByID(ctx context.Context, id string) error {
 		return errors.New("discount id is empty")
 	}
 
-	_, err := store.db.Query().Table(store.discountTableName).Where(COLUMN_ID+" = ?", id).Delete()
-	return err
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		before, err := store.storedValues(tx, store.discountTableName, id, nil)
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		_, err = statement(tx).Table(store.discountTableName).Where(COLUMN_ID+" = ?", id).Delete()
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_DISCOUNT, id, AUDIT_OPERATION_DELETE, before, nil),
+		}, err
+	})
+	if err != nil {
+		return store.operationError("discount delete", err)
+	}
+
+	store.discountHooks.runAfterDelete(ctx, id)
+
+	return nil
 }
 
 func (store *Store) DiscountFindByID(ctx context.Context, id string) (DiscountInterface, error) {
@@ -104,14 +145,17 @@ func (store *Store) DiscountFindByCode(ctx context.Context, code string) (Discou
 }
 
 func (store *Store) DiscountList(ctx context.Context, options DiscountQueryInterface) ([]DiscountInterface, error) {
-	q, err := store.discountQuery(options)
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.discountQuery(ctx, options)
 	if err != nil {
 		return []DiscountInterface{}, err
 	}
 
 	var results []map[string]any
 	if err := q.Get(&results); err != nil {
-		return []DiscountInterface{}, err
+		return []DiscountInterface{}, store.operationError("discount list", err)
 	}
 
 	list := []DiscountInterface{}
@@ -124,6 +168,62 @@ func (store *Store) DiscountList(ctx context.Context, options DiscountQueryInter
 	return list, nil
 }
 
+// DiscountIterate streams the discounts matching the query options, fetching
+// them from the database in batches instead of loading them all at once.
+// Iteration stops with an error as soon as ctx is cancelled.
+func (store *Store) DiscountIterate(ctx context.Context, options DiscountQueryInterface) iter.Seq2[DiscountInterface, error] {
+	if options == nil {
+		options = NewDiscountQuery()
+	}
+
+	build := func(ctx context.Context) (contractsorm.Query, error) {
+		return store.discountQuery(ctx, options)
+	}
+
+	hydrate := func(data map[string]string) DiscountInterface {
+		return NewDiscountFromExistingData(data)
+	}
+
+	return iterateInBatches(store, ctx, "discount iterate", options, build, hydrate)
+}
+
+// DiscountListPage returns a single keyset (cursor) paginated page of discounts
+// matching the query options. The page size is taken from SetLimit. Pass the
+// returned NextCursor to SetAfterCursor to fetch the following page.
+func (store *Store) DiscountListPage(ctx context.Context, options DiscountQueryInterface) (ListPage[DiscountInterface], error) {
+	if options == nil {
+		options = NewDiscountQuery()
+	}
+
+	if !options.HasLimit() {
+		return ListPage[DiscountInterface]{}, errors.New("discount list page. limit is required")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.discountQuery(ctx, options)
+	if err != nil {
+		return ListPage[DiscountInterface]{}, err
+	}
+
+	q = keysetPageQuery(q, options.Limit(), options)
+
+	var results []map[string]any
+	if err := q.Get(&results); err != nil {
+		return ListPage[DiscountInterface]{}, store.operationError("discount list page", err)
+	}
+
+	list := []DiscountInterface{}
+
+	lo.ForEach(results, func(result map[string]any, index int) {
+		model := NewDiscountFromExistingData(mapAnyToString(result))
+		list = append(list, model)
+	})
+
+	return newListPage(list, options.Limit(), options), nil
+}
+
 func (store *Store) DiscountSoftDelete(ctx context.Context, discount DiscountInterface) error {
 	if discount == nil {
 		return errors.New("discount is nil")
@@ -131,7 +231,13 @@ func (store *Store) DiscountSoftDelete(ctx context.Context, discount DiscountInt
 
 	discount.SetSoftDeletedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
 
-	return store.DiscountUpdate(ctx, discount)
+	if err := store.discountUpdate(ctx, discount); err != nil {
+		return err
+	}
+
+	store.discountHooks.runAfterSoftDelete(ctx, discount.GetID())
+
+	return nil
 }
 
 func (store *Store) DiscountSoftDeleteByID(ctx context.Context, id string) error {
@@ -150,18 +256,82 @@ func (store *Store) DiscountSoftDeleteByID(ctx context.Context, id string) error
 	return store.DiscountSoftDelete(ctx, discount)
 }
 
+// DiscountSoftDeleteMany soft deletes the live discounts matching the
+// query options in a single transaction, returning how many were soft
+// deleted.
+// AfterSoftDelete hooks run for every discount.
+func (store *Store) DiscountSoftDeleteMany(ctx context.Context, options DiscountQueryInterface) (int64, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	ids, err := store.updateMany(ctx, store.discountBulkTable(options), map[string]string{
+		COLUMN_SOFT_DELETED_AT: carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC),
+	})
+	if err != nil {
+		return 0, store.operationError("discount soft delete many", err)
+	}
+
+	for _, id := range ids {
+		store.discountHooks.runAfterSoftDelete(ctx, id)
+	}
+
+	return int64(len(ids)), nil
+}
+
 func (store *Store) DiscountUpdate(ctx context.Context, discount DiscountInterface) error {
 	if discount == nil {
 		return errors.New("discount is nil")
 	}
 
+	if err := store.discountHooks.runBeforeUpdate(ctx, discount, discount.DataChanged()); err != nil {
+		return err
+	}
+
+	changed := discount.DataChanged()
+	if err := store.discountUpdate(ctx, discount); err != nil {
+		return err
+	}
+
+	store.discountHooks.runAfterUpdate(ctx, discount, changed)
+
+	return nil
+}
+
+// DiscountUpdateMany sets the fields on all the discounts matching the
+// query options in a single transaction, returning how many were updated.
+// Only the columns listed in discountUpdatableColumns can be set. Versions
+// are incremented without being checked. The AfterUpdate hooks run on the
+// discounts set, but not the Before hooks, as the discounts are not loaded.
+func (store *Store) DiscountUpdateMany(ctx context.Context, options DiscountQueryInterface, fields map[string]string) (int64, error) {
+	if err := assertUpdatable(fields, discountUpdatableColumns); err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	ids, rows, err := store.updateManyLoaded(ctx, store.discountBulkTable(options), fields, store.discountHooks.hasAfterUpdate())
+	if err != nil {
+		return 0, store.operationError("discount update many", err)
+	}
+
+	runAfterUpdateRows(ctx, &store.discountHooks, rows, NewDiscountFromExistingData, func(map[string]string) map[string]string {
+		return maps.Clone(fields)
+	})
+
+	return int64(len(ids)), nil
+}
+
+// discountUpdate writes the changed fields of discount, without running hooks
+func (store *Store) discountUpdate(ctx context.Context, discount DiscountInterface) error {
 	discount.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString())
 
 	dataChanged := discount.DataChanged()
 
-	delete(dataChanged, COLUMN_ID) // ID is not updateable
-	delete(dataChanged, "hash")    // Hash is not updateable
-	delete(dataChanged, "data")    // Data is not updateable
+	delete(dataChanged, COLUMN_ID)      // ID is not updateable
+	delete(dataChanged, "hash")         // Hash is not updateable
+	delete(dataChanged, "data")         // Data is not updateable
+	delete(dataChanged, COLUMN_VERSION) // Version is set by the update itself
 
 	if len(dataChanged) < 1 {
 		return nil
@@ -169,17 +339,71 @@ func (store *Store) DiscountUpdate(ctx context.Context, discount DiscountInterfa
 
 	row := map[string]any{}
 	for k, v := range dataChanged {
-		row[k] = v
+		row[k] = rowValue(k, v)
 	}
 
-	_, err := store.db.Query().Table(store.discountTableName).Where(COLUMN_ID+" = ?", discount.GetID()).Update(row)
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
 
-	discount.MarkAsNotDirty()
+	var version int64
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		before, err := store.storedValues(tx, store.discountTableName, discount.GetID(), lo.Keys(dataChanged))
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		version, err = versionedUpdate(ctx, statement(tx).Table(store.discountTableName).Where(COLUMN_ID+" = ?", discount.GetID()), discount.GetVersion(), row)
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_DISCOUNT, discount.GetID(), updateOperation(dataChanged), before, dataChanged),
+		}, err
+	})
 
-	return err
+	if err == nil {
+		discount.SetVersion(version)
+		discount.MarkAsNotDirty()
+	}
+
+	if err != nil && store.discountCodeTaken(ctx, discount) {
+		return ErrDiscountCodeExists
+	}
+
+	return store.operationError("discount update", err)
 }
 
-func (store *Store) discountQuery(options DiscountQueryInterface) (contractsorm.Query, error) {
+// discountCodeTaken reports whether another live discount uses the code of
+// the discount. It tells a violation of the unique code index apart from
+// other write failures, as the database error details are not exposed.
+func (store *Store) discountCodeTaken(ctx context.Context, discount DiscountInterface) bool {
+	var count int64
+
+	err := store.query(ctx).
+		Table(store.discountTableName).
+		Where(COLUMN_CODE+" = ?", discount.GetCode()).
+		Where(COLUMN_ID+" <> ?", discount.GetID()).
+		Where(COLUMN_SOFT_DELETED_AT+" = ?", MAX_DATETIME).
+		Count(&count)
+
+	return err == nil && count > 0
+}
+
+// discountBulkTable describes the discounts matching the query options to
+// the bulk operations
+func (store *Store) discountBulkTable(options DiscountQueryInterface) bulkTable {
+	return bulkTable{
+		entityType: ENTITY_TYPE_DISCOUNT,
+		tableName:  store.discountTableName,
+		selectRows: func(q contractsorm.Query) (contractsorm.Query, error) {
+			return store.discountQueryOn(q, options)
+		},
+	}
+}
+
+func (store *Store) discountQuery(ctx context.Context, options DiscountQueryInterface) (contractsorm.Query, error) {
+	return store.discountQueryOn(store.query(ctx), options)
+}
+
+// discountQueryOn applies the query options to q, which may run within a transaction
+func (store *Store) discountQueryOn(q contractsorm.Query, options DiscountQueryInterface) (contractsorm.Query, error) {
 	if options == nil {
 		options = NewDiscountQuery()
 	}
@@ -188,7 +412,7 @@ func (store *Store) discountQuery(options DiscountQueryInterface) (contractsorm.
 		return nil, err
 	}
 
-	q := store.db.Query().Table(store.discountTableName)
+	q = q.Table(store.discountTableName)
 
 	if options.HasID() {
 		q = q.Where(COLUMN_ID+" = ?", options.ID())
@@ -256,10 +480,16 @@ func (store *Store) discountQuery(options DiscountQueryInterface) (contractsorm.
 		}
 	}
 
-	sortOrder := lo.Ternary(options.HasSortDirection(), options.SortDirection(), "desc")
+	if options.HasAfterCursor() {
+		var err error
+		q, err = whereAfterCursor(q, store.dialect, keysetSort(options), options.AfterCursor())
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	if options.HasOrderBy() {
-		q = q.OrderBy(options.OrderBy(), sortOrder)
+	if isSorted(options) {
+		q = orderByKeyset(q, keysetSort(options))
 	}
 
 	if !options.SoftDeletedIncluded() {
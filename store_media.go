@@ -3,7 +3,8 @@ package shopstore
 import (
 	"context"
 	"errors"
-	"strings"
+	"iter"
+	"maps"
 
 	contractsorm "github.com/dracory/neat/contracts/database/orm"
 	"github.com/dromara/carbon/v2"
@@ -12,14 +13,17 @@ import (
 )
 
 func (store *Store) MediaCount(ctx context.Context, options MediaQueryInterface) (int64, error) {
-	q, err := store.mediaQuery(options)
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.mediaQuery(ctx, options)
 	if err != nil {
 		return -1, err
 	}
 
 	var count int64
 	if err := q.Count(&count); err != nil {
-		return -1, err
+		return -1, store.operationError("media count", err)
 	}
 
 	return count, nil
@@ -34,17 +38,79 @@ func (store *Store) MediaCreate(ctx context.Context, media MediaInterface) error
 	media.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
 	media.SetSoftDeletedAt(MAX_DATETIME)
 
+	if err := store.mediaHooks.runBeforeCreate(ctx, media); err != nil {
+		return err
+	}
+
 	data := media.Data()
 	row := map[string]any{}
 	for k, v := range data {
-		row[k] = v
+		row[k] = rowValue(k, v)
 	}
 
-	err := store.db.Query().Table(store.mediaTableName).Create(row)
-	if err != nil {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	if err := store.assertReferencesExist(ctx, data, store.mediaReferences()); err != nil {
 		return err
 	}
 
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		err := statement(tx).Table(store.mediaTableName).Create(row)
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_MEDIA, media.GetID(), AUDIT_OPERATION_CREATE, nil, data),
+		}, err
+	})
+	if err != nil {
+		return store.operationError("media create", err)
+	}
+
+	store.mediaHooks.runAfterCreate(ctx, media)
+
+	return nil
+}
+
+// MediaCreateMany creates the media in a single transaction, with
+// batched multi-row inserts: either all of them are created or none is.
+// Hooks run for every media like on MediaCreate.
+func (store *Store) MediaCreateMany(ctx context.Context, mediaList []MediaInterface) error {
+	if lo.Contains(mediaList, nil) {
+		return errors.New("media is nil")
+	}
+
+	if len(mediaList) == 0 {
+		return nil
+	}
+
+	now := carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)
+
+	for _, media := range mediaList {
+		media.SetCreatedAt(now)
+		media.SetUpdatedAt(now)
+		media.SetSoftDeletedAt(MAX_DATETIME)
+
+		if err := store.mediaHooks.runBeforeCreate(ctx, media); err != nil {
+			return err
+		}
+	}
+
+	rows := lo.Map(mediaList, func(media MediaInterface, _ int) map[string]string {
+		return media.Data()
+	})
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	err := store.createMany(ctx, ENTITY_TYPE_MEDIA, store.mediaTableName, store.mediaReferences(), rows)
+	if err != nil {
+		return store.operationError("media create many", err)
+	}
+
+	for _, media := range mediaList {
+		media.MarkAsNotDirty()
+		store.mediaHooks.runAfterCreate(ctx, media)
+	}
+
 	return nil
 }
 
@@ -61,8 +127,27 @@ func (store *Store) MediaDeleteByID(ctx context.Context, id string) error {
 		return errors.New("id is empty")
 	}
 
-	_, err := store.db.Query().Table(store.mediaTableName).Where(COLUMN_ID+" = ?", id).Delete()
-	return err
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		before, err := store.storedValues(tx, store.mediaTableName, id, nil)
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		_, err = statement(tx).Table(store.mediaTableName).Where(COLUMN_ID+" = ?", id).Delete()
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_MEDIA, id, AUDIT_OPERATION_DELETE, before, nil),
+		}, err
+	})
+	if err != nil {
+		return store.operationError("media delete", err)
+	}
+
+	store.mediaHooks.runAfterDelete(ctx, id)
+
+	return nil
 }
 
 func (store *Store) MediaFindByID(ctx context.Context, id string) (MediaInterface, error) {
@@ -92,7 +177,10 @@ func (store *Store) MediaList(ctx context.Context, options MediaQueryInterface)
 		return nil, err
 	}
 
-	q, err := store.mediaQuery(options)
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.mediaQuery(ctx, options)
 
 	if err != nil {
 		return nil, err
@@ -100,7 +188,7 @@ func (store *Store) MediaList(ctx context.Context, options MediaQueryInterface)
 
 	var results []map[string]any
 	if err := q.Get(&results); err != nil {
-		return nil, err
+		return nil, store.operationError("media list", err)
 	}
 
 	list := []MediaInterface{}
@@ -113,6 +201,62 @@ func (store *Store) MediaList(ctx context.Context, options MediaQueryInterface)
 	return list, nil
 }
 
+// MediaIterate streams the media matching the query options, fetching
+// them from the database in batches instead of loading them all at once.
+// Iteration stops with an error as soon as ctx is cancelled.
+func (store *Store) MediaIterate(ctx context.Context, options MediaQueryInterface) iter.Seq2[MediaInterface, error] {
+	if options == nil {
+		return iterateError[MediaInterface](errors.New("media options is nil"))
+	}
+
+	build := func(ctx context.Context) (contractsorm.Query, error) {
+		return store.mediaQuery(ctx, options)
+	}
+
+	hydrate := func(data map[string]string) MediaInterface {
+		return NewMediaFromExistingData(data)
+	}
+
+	return iterateInBatches(store, ctx, "media iterate", options, build, hydrate)
+}
+
+// MediaListPage returns a single keyset (cursor) paginated page of medias
+// matching the query options. The page size is taken from SetLimit. Pass the
+// returned NextCursor to SetAfterCursor to fetch the following page.
+func (store *Store) MediaListPage(ctx context.Context, options MediaQueryInterface) (ListPage[MediaInterface], error) {
+	if options == nil {
+		return ListPage[MediaInterface]{}, errors.New("media options is nil")
+	}
+
+	if !options.HasLimit() {
+		return ListPage[MediaInterface]{}, errors.New("media list page. limit is required")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.mediaQuery(ctx, options)
+	if err != nil {
+		return ListPage[MediaInterface]{}, err
+	}
+
+	q = keysetPageQuery(q, options.Limit(), options)
+
+	var results []map[string]any
+	if err := q.Get(&results); err != nil {
+		return ListPage[MediaInterface]{}, store.operationError("media list page", err)
+	}
+
+	list := []MediaInterface{}
+
+	lo.ForEach(results, func(result map[string]any, index int) {
+		model := NewMediaFromExistingData(mapAnyToString(result))
+		list = append(list, model)
+	})
+
+	return newListPage(list, options.Limit(), options), nil
+}
+
 func (store *Store) MediaSoftDelete(ctx context.Context, media MediaInterface) error {
 	if media == nil {
 		return errors.New("media is nil")
@@ -120,7 +264,13 @@ func (store *Store) MediaSoftDelete(ctx context.Context, media MediaInterface) e
 
 	media.SetSoftDeletedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
 
-	return store.MediaUpdate(ctx, media)
+	if err := store.mediaUpdate(ctx, media); err != nil {
+		return err
+	}
+
+	store.mediaHooks.runAfterSoftDelete(ctx, media.GetID())
+
+	return nil
 }
 
 func (store *Store) MediaSoftDeleteByID(ctx context.Context, id string) error {
@@ -141,18 +291,86 @@ func (store *Store) MediaSoftDeleteByID(ctx context.Context, id string) error {
 	return store.MediaSoftDelete(ctx, media)
 }
 
-func (store *Store) MediaUpdate(ctx context.Context, media MediaInterface) (err error) {
+// MediaSoftDeleteMany soft deletes the live media matching the
+// query options in a single transaction, returning how many were soft
+// deleted.
+// AfterSoftDelete hooks run for every media.
+func (store *Store) MediaSoftDeleteMany(ctx context.Context, options MediaQueryInterface) (int64, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	ids, err := store.updateMany(ctx, store.mediaBulkTable(options), map[string]string{
+		COLUMN_SOFT_DELETED_AT: carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC),
+	})
+	if err != nil {
+		return 0, store.operationError("media soft delete many", err)
+	}
+
+	for _, id := range ids {
+		store.mediaHooks.runAfterSoftDelete(ctx, id)
+	}
+
+	return int64(len(ids)), nil
+}
+
+func (store *Store) MediaUpdate(ctx context.Context, media MediaInterface) error {
 	if media == nil {
 		return errors.New("media is nil")
 	}
 
+	if err := store.mediaHooks.runBeforeUpdate(ctx, media, media.DataChanged()); err != nil {
+		return err
+	}
+
+	changed := media.DataChanged()
+	if err := store.mediaUpdate(ctx, media); err != nil {
+		return err
+	}
+
+	store.mediaHooks.runAfterUpdate(ctx, media, changed)
+
+	return nil
+}
+
+// MediaUpdateMany sets the fields on all the media matching the query
+// options in a single transaction, returning how many were updated. Only
+// the columns listed in mediaUpdatableColumns can be set. Versions are
+// incremented without being checked. The AfterUpdate hooks run on the media
+// set, but not the Before hooks, as the media are not loaded.
+func (store *Store) MediaUpdateMany(ctx context.Context, options MediaQueryInterface, fields map[string]string) (int64, error) {
+	if err := assertUpdatable(fields, mediaUpdatableColumns); err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	if err := store.assertReferencesExist(ctx, fields, store.mediaReferences()); err != nil {
+		return 0, err
+	}
+
+	ids, rows, err := store.updateManyLoaded(ctx, store.mediaBulkTable(options), fields, store.mediaHooks.hasAfterUpdate())
+	if err != nil {
+		return 0, store.operationError("media update many", err)
+	}
+
+	runAfterUpdateRows(ctx, &store.mediaHooks, rows, NewMediaFromExistingData, func(map[string]string) map[string]string {
+		return maps.Clone(fields)
+	})
+
+	return int64(len(ids)), nil
+}
+
+// mediaUpdate writes the changed fields of media, without running hooks
+func (store *Store) mediaUpdate(ctx context.Context, media MediaInterface) (err error) {
 	media.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString())
 
 	dataChanged := media.DataChanged()
 
-	delete(dataChanged, COLUMN_ID) // ID is not updateable
-	delete(dataChanged, "hash")    // Hash is not updateable
-	delete(dataChanged, "data")    // Data is not updateable
+	delete(dataChanged, COLUMN_ID)      // ID is not updateable
+	delete(dataChanged, "hash")         // Hash is not updateable
+	delete(dataChanged, "data")         // Data is not updateable
+	delete(dataChanged, COLUMN_VERSION) // Version is set by the update itself
 
 	if len(dataChanged) < 1 {
 		return nil
@@ -160,21 +378,57 @@ func (store *Store) MediaUpdate(ctx context.Context, media MediaInterface) (err
 
 	row := map[string]any{}
 	for k, v := range dataChanged {
-		row[k] = v
+		row[k] = rowValue(k, v)
 	}
 
-	_, err = store.db.Query().Table(store.mediaTableName).Where(COLUMN_ID+" = ?", media.GetID()).Update(row)
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
 
-	if err != nil {
+	if err := store.assertReferencesExist(ctx, dataChanged, store.mediaReferences()); err != nil {
 		return err
 	}
 
+	var version int64
+	err = store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		before, err := store.storedValues(tx, store.mediaTableName, media.GetID(), lo.Keys(dataChanged))
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		version, err = versionedUpdate(ctx, statement(tx).Table(store.mediaTableName).Where(COLUMN_ID+" = ?", media.GetID()), media.GetVersion(), row)
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_MEDIA, media.GetID(), updateOperation(dataChanged), before, dataChanged),
+		}, err
+	})
+
+	if err != nil {
+		return store.operationError("media update", err)
+	}
+
+	media.SetVersion(version)
 	media.MarkAsNotDirty()
 
 	return nil
 }
 
-func (store *Store) mediaQuery(options MediaQueryInterface) (contractsorm.Query, error) {
+// mediaBulkTable describes the media matching the query options to
+// the bulk operations
+func (store *Store) mediaBulkTable(options MediaQueryInterface) bulkTable {
+	return bulkTable{
+		entityType: ENTITY_TYPE_MEDIA,
+		tableName:  store.mediaTableName,
+		selectRows: func(q contractsorm.Query) (contractsorm.Query, error) {
+			return store.mediaQueryOn(q, options)
+		},
+	}
+}
+
+func (store *Store) mediaQuery(ctx context.Context, options MediaQueryInterface) (contractsorm.Query, error) {
+	return store.mediaQueryOn(store.query(ctx), options)
+}
+
+// mediaQueryOn applies the query options to q, which may run within a transaction
+func (store *Store) mediaQueryOn(q contractsorm.Query, options MediaQueryInterface) (contractsorm.Query, error) {
 	if options == nil {
 		return nil, errors.New("category options is nil")
 	}
@@ -183,7 +437,7 @@ func (store *Store) mediaQuery(options MediaQueryInterface) (contractsorm.Query,
 		return nil, err
 	}
 
-	q := store.db.Query().Table(store.mediaTableName)
+	q = q.Table(store.mediaTableName)
 
 	if options.HasID() {
 		q = q.Where(COLUMN_ID+" = ?", options.ID())
@@ -201,15 +455,16 @@ func (store *Store) mediaQuery(options MediaQueryInterface) (contractsorm.Query,
 		q = q.Where(COLUMN_ENTITY_ID+" = ?", options.EntityID())
 	}
 
+	if options.HasEntityIDIn() {
+		q = q.WhereIn(COLUMN_ENTITY_ID, lo.ToAnySlice(options.EntityIDIn()))
+	}
+
 	if options.HasStatus() {
 		q = q.Where(COLUMN_STATUS+" = ?", options.Status())
 	}
 
 	if options.HasTitleLike() {
-		searchTerm := strings.ReplaceAll(options.TitleLike(), "'", "''")
-		searchTerm = strings.ReplaceAll(searchTerm, "%", "\\%")
-		searchTerm = strings.ReplaceAll(searchTerm, "_", "\\_")
-		q = q.Where(COLUMN_TITLE+" LIKE ?", "%"+searchTerm+"%")
+		q = q.Where(store.dialect.like(COLUMN_TITLE), likeContains(options.TitleLike()))
 	}
 
 	if options.HasType() {
@@ -226,10 +481,16 @@ func (store *Store) mediaQuery(options MediaQueryInterface) (contractsorm.Query,
 		}
 	}
 
-	sortOrder := lo.Ternary(options.HasSortDirection(), options.SortDirection(), "desc")
+	if options.HasAfterCursor() {
+		var err error
+		q, err = whereAfterCursor(q, store.dialect, keysetSort(options), options.AfterCursor())
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	if options.HasOrderBy() {
-		q = q.OrderBy(options.OrderBy(), sortOrder)
+	if isSorted(options) {
+		q = orderByKeyset(q, keysetSort(options))
 	}
 
 	if options.SoftDeletedIncluded() {
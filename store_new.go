@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/dracory/neat"
+	"github.com/samber/lo"
 )
 
 // NewStoreOptions define the options for creating a new block store
@@ -19,6 +21,88 @@ type NewStoreOptions struct {
 	DB                     *sql.DB
 	AutomigrateEnabled     bool
 	DebugEnabled           bool
+
+	// CustomerTableName is the table the customers are stored in.
+	// Defaults to DEFAULT_CUSTOMER_TABLE_NAME.
+	CustomerTableName string
+
+	// AddressTableName is the table the customer addresses are stored in.
+	// Defaults to DEFAULT_ADDRESS_TABLE_NAME.
+	AddressTableName string
+
+	// CartTableName is the table the shopping carts are stored in.
+	// Defaults to DEFAULT_CART_TABLE_NAME.
+	CartTableName string
+
+	// CartItemTableName is the table the items of the shopping carts are
+	// stored in. Defaults to DEFAULT_CART_ITEM_TABLE_NAME.
+	CartItemTableName string
+
+	// ShippingMethodTableName is the table the shipping methods are stored
+	// in. Defaults to DEFAULT_SHIPPING_METHOD_TABLE_NAME.
+	ShippingMethodTableName string
+
+	// ShippingZoneTableName is the table the shipping zones are stored in.
+	// Defaults to DEFAULT_SHIPPING_ZONE_TABLE_NAME.
+	ShippingZoneTableName string
+
+	// TaxRateTableName is the table the tax rates are stored in.
+	// Defaults to DEFAULT_TAX_RATE_TABLE_NAME.
+	TaxRateTableName string
+
+	// PricesIncludeTax tells that the product prices include the tax, which
+	// OrderCalculateTax then takes out of them. When false, the tax is added
+	// on top of the prices.
+	PricesIncludeTax bool
+
+	// TaxCalculator calculates the tax of the orders. Defaults to a
+	// TableTaxCalculator, reading the rates from the tax rate table.
+	TaxCalculator TaxCalculator
+
+	// MigrationTableName is the table recording the applied schema
+	// migrations. Defaults to DEFAULT_MIGRATION_TABLE_NAME.
+	MigrationTableName string
+
+	// OutboxTableName is the table the outbox events are recorded in.
+	// Defaults to DEFAULT_OUTBOX_TABLE_NAME.
+	OutboxTableName string
+
+	// OutboxEnabled records domain events (see the OUTBOX_EVENT_ constants)
+	// in the outbox table, in the same transaction as the change causing
+	// them, for OutboxFetchPending to relay.
+	OutboxEnabled bool
+
+	// LowStockThreshold is the product quantity at or below which the
+	// product.stock_low outbox event is recorded. Defaults to 0, recording
+	// it when a product runs out of stock.
+	LowStockThreshold int
+
+	// AuditLogTableName is the table the audit log is recorded in.
+	// Defaults to DEFAULT_AUDIT_LOG_TABLE_NAME.
+	AuditLogTableName string
+
+	// AuditLogEnabled records an audit log entry for every create, update,
+	// delete and soft delete, in the same transaction as the change, for
+	// AuditLogList to query.
+	AuditLogEnabled bool
+
+	// ReferentialIntegrityEnabled makes creates and updates check that the
+	// referenced IDs (order_id, product_id, parent_id, entity_id,
+	// customer_id, discount_id, category_id, shipping_zone_id) point at
+	// existing, not soft deleted rows, failing with ErrReferenceNotFound.
+	ReferentialIntegrityEnabled bool
+
+	// ForeignKeysEnabled adds database foreign keys on MigrateUp, on the
+	// dialects able to add them to existing tables (not SQLite): from
+	// addresses to customers, cart items to carts, order line items to
+	// orders and products, and shipping methods to shipping zones. Carts
+	// get none, as guest carts store an empty customer ID.
+	ForeignKeysEnabled bool
+
+	// OperationTimeout bounds every store operation, on top of any deadline
+	// of the passed in context. Defaults to DEFAULT_OPERATION_TIMEOUT when
+	// zero; a negative value disables it.
+	OperationTimeout time.Duration
 }
 
 // NewStore creates a new block store
@@ -57,18 +141,40 @@ func NewStore(opts NewStoreOptions) (*Store, error) {
 	}
 
 	store := &Store{
-		categoryTableName:      opts.CategoryTableName,
-		discountTableName:      opts.DiscountTableName,
-		mediaTableName:         opts.MediaTableName,
-		orderTableName:         opts.OrderTableName,
-		orderLineItemTableName: opts.OrderLineItemTableName,
-		productTableName:       opts.ProductTableName,
-		automigrateEnabled:     opts.AutomigrateEnabled,
-		db:                     neatDB,
-		debugEnabled:           opts.DebugEnabled,
+		addressTableName:            lo.Ternary(opts.AddressTableName != "", opts.AddressTableName, DEFAULT_ADDRESS_TABLE_NAME),
+		cartTableName:               lo.Ternary(opts.CartTableName != "", opts.CartTableName, DEFAULT_CART_TABLE_NAME),
+		cartItemTableName:           lo.Ternary(opts.CartItemTableName != "", opts.CartItemTableName, DEFAULT_CART_ITEM_TABLE_NAME),
+		categoryTableName:           opts.CategoryTableName,
+		customerTableName:           lo.Ternary(opts.CustomerTableName != "", opts.CustomerTableName, DEFAULT_CUSTOMER_TABLE_NAME),
+		discountTableName:           opts.DiscountTableName,
+		mediaTableName:              opts.MediaTableName,
+		orderTableName:              opts.OrderTableName,
+		orderLineItemTableName:      opts.OrderLineItemTableName,
+		productTableName:            opts.ProductTableName,
+		shippingMethodTableName:     lo.Ternary(opts.ShippingMethodTableName != "", opts.ShippingMethodTableName, DEFAULT_SHIPPING_METHOD_TABLE_NAME),
+		shippingZoneTableName:       lo.Ternary(opts.ShippingZoneTableName != "", opts.ShippingZoneTableName, DEFAULT_SHIPPING_ZONE_TABLE_NAME),
+		taxRateTableName:            lo.Ternary(opts.TaxRateTableName != "", opts.TaxRateTableName, DEFAULT_TAX_RATE_TABLE_NAME),
+		migrationTableName:          lo.Ternary(opts.MigrationTableName != "", opts.MigrationTableName, DEFAULT_MIGRATION_TABLE_NAME),
+		outboxTableName:             lo.Ternary(opts.OutboxTableName != "", opts.OutboxTableName, DEFAULT_OUTBOX_TABLE_NAME),
+		outboxEnabled:               opts.OutboxEnabled,
+		lowStockThreshold:           opts.LowStockThreshold,
+		pricesIncludeTax:            opts.PricesIncludeTax,
+		auditLogTableName:           lo.Ternary(opts.AuditLogTableName != "", opts.AuditLogTableName, DEFAULT_AUDIT_LOG_TABLE_NAME),
+		auditLogEnabled:             opts.AuditLogEnabled,
+		automigrateEnabled:          opts.AutomigrateEnabled,
+		referentialIntegrityEnabled: opts.ReferentialIntegrityEnabled,
+		foreignKeysEnabled:          opts.ForeignKeysEnabled,
+		db:                          neatDB,
+		dialect:                     dialectFromDriver(neatDB.Query().Driver()),
+		debugEnabled:                opts.DebugEnabled,
 	}
 
-	store.timeoutSeconds = 2 * 60 * 60 // 2 hours
+	store.operationTimeout = lo.Ternary(opts.OperationTimeout != 0, opts.OperationTimeout, DEFAULT_OPERATION_TIMEOUT)
+
+	store.taxCalculator = opts.TaxCalculator
+	if store.taxCalculator == nil {
+		store.taxCalculator = NewTableTaxCalculator(store)
+	}
 
 	if store.automigrateEnabled {
 		err := store.MigrateUp(context.Background())
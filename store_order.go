@@ -3,6 +3,8 @@ package shopstore
 import (
 	"context"
 	"errors"
+	"iter"
+	"maps"
 
 	contractsorm "github.com/dracory/neat/contracts/database/orm"
 	"github.com/dromara/carbon/v2"
@@ -11,14 +13,17 @@ import (
 )
 
 func (store *Store) OrderCount(ctx context.Context, options OrderQueryInterface) (int64, error) {
-	q, err := store.orderQuery(options)
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.orderQuery(ctx, options)
 	if err != nil {
 		return -1, err
 	}
 
 	var count int64
 	if err := q.Count(&count); err != nil {
-		return -1, err
+		return -1, store.operationError("order count", err)
 	}
 
 	return count, nil
@@ -29,19 +34,34 @@ func (store *Store) OrderCreate(ctx context.Context, order OrderInterface) error
 	order.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
 	order.SetSoftDeletedAt(MAX_DATETIME)
 
+	if err := store.orderHooks.runBeforeCreate(ctx, order); err != nil {
+		return err
+	}
+
 	data := order.Data()
 	row := map[string]any{}
 	for k, v := range data {
-		row[k] = v
+		row[k] = rowValue(k, v)
 	}
 
-	err := store.db.Query().Table(store.orderTableName).Create(row)
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		err := statement(tx).Table(store.orderTableName).Create(row)
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_ORDER, order.GetID(), AUDIT_OPERATION_CREATE, nil, data),
+			events:    []OutboxEventInterface{NewOutboxEvent(OUTBOX_EVENT_ORDER_CREATED, ENTITY_TYPE_ORDER, order.GetID())},
+		}, err
+	})
 	if err != nil {
-		return err
+		return store.operationError("order create", err)
 	}
 
 	order.MarkAsNotDirty()
 
+	store.orderHooks.runAfterCreate(ctx, order)
+
 	return nil
 }
 
@@ -62,8 +82,27 @@ func (store *Store) OrderDeleteByID(ctx context.Context, id string) error {
 		return err
 	}
 
-	_, err := store.db.Query().Table(store.orderTableName).Where(COLUMN_ID+" = ?", id).Delete()
-	return err
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		before, err := store.storedValues(tx, store.orderTableName, id, nil)
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		_, err = statement(tx).Table(store.orderTableName).Where(COLUMN_ID+" = ?", id).Delete()
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_ORDER, id, AUDIT_OPERATION_DELETE, before, nil),
+		}, err
+	})
+	if err != nil {
+		return store.operationError("order delete", err)
+	}
+
+	store.orderHooks.runAfterDelete(ctx, id)
+
+	return nil
 }
 
 // assertOrderDeletable performs a non-atomic check-then-act: the count queries and the
@@ -90,6 +129,62 @@ func (store *Store) assertOrderDeletable(ctx context.Context, orderID string) er
 	return nil
 }
 
+// OrderIterate streams the orders matching the query options, fetching
+// them from the database in batches instead of loading them all at once.
+// Iteration stops with an error as soon as ctx is cancelled.
+func (store *Store) OrderIterate(ctx context.Context, options OrderQueryInterface) iter.Seq2[OrderInterface, error] {
+	if options == nil {
+		return iterateError[OrderInterface](errors.New("order options cannot be nil"))
+	}
+
+	build := func(ctx context.Context) (contractsorm.Query, error) {
+		return store.orderQuery(ctx, options)
+	}
+
+	hydrate := func(data map[string]string) OrderInterface {
+		return NewOrderFromExistingData(data)
+	}
+
+	return iterateInBatches(store, ctx, "order iterate", options, build, hydrate)
+}
+
+// OrderListPage returns a single keyset (cursor) paginated page of orders
+// matching the query options. The page size is taken from SetLimit. Pass the
+// returned NextCursor to SetAfterCursor to fetch the following page.
+func (store *Store) OrderListPage(ctx context.Context, options OrderQueryInterface) (ListPage[OrderInterface], error) {
+	if options == nil {
+		return ListPage[OrderInterface]{}, errors.New("order options cannot be nil")
+	}
+
+	if !options.HasLimit() {
+		return ListPage[OrderInterface]{}, errors.New("order list page. limit is required")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.orderQuery(ctx, options)
+	if err != nil {
+		return ListPage[OrderInterface]{}, err
+	}
+
+	q = keysetPageQuery(q, options.Limit(), options)
+
+	var results []map[string]any
+	if err := q.Get(&results); err != nil {
+		return ListPage[OrderInterface]{}, store.operationError("order list page", err)
+	}
+
+	list := []OrderInterface{}
+
+	lo.ForEach(results, func(result map[string]any, index int) {
+		model := NewOrderFromExistingData(mapAnyToString(result))
+		list = append(list, model)
+	})
+
+	return newListPage(list, options.Limit(), options), nil
+}
+
 func (store *Store) OrderSoftDelete(ctx context.Context, order OrderInterface) error {
 	if order == nil {
 		return errors.New("order is nil")
@@ -101,7 +196,13 @@ func (store *Store) OrderSoftDelete(ctx context.Context, order OrderInterface) e
 
 	order.SetSoftDeletedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
 
-	return store.OrderUpdate(ctx, order)
+	if err := store.orderUpdate(ctx, order); err != nil {
+		return err
+	}
+
+	store.orderHooks.runAfterSoftDelete(ctx, order.GetID())
+
+	return nil
 }
 
 func (store *Store) OrderSoftDeleteByID(ctx context.Context, id string) error {
@@ -120,6 +221,29 @@ func (store *Store) OrderSoftDeleteByID(ctx context.Context, id string) error {
 	return store.OrderSoftDelete(ctx, order)
 }
 
+// OrderSoftDeleteMany soft deletes the live orders matching the
+// query options in a single transaction, returning how many were soft
+// deleted.
+// Fails like OrderSoftDelete when an order still has live line items or media.
+// AfterSoftDelete hooks run for every order.
+func (store *Store) OrderSoftDeleteMany(ctx context.Context, options OrderQueryInterface) (int64, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	ids, err := store.updateMany(ctx, store.orderBulkTable(options), map[string]string{
+		COLUMN_SOFT_DELETED_AT: carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC),
+	})
+	if err != nil {
+		return 0, store.operationError("order soft delete many", err)
+	}
+
+	for _, id := range ids {
+		store.orderHooks.runAfterSoftDelete(ctx, id)
+	}
+
+	return int64(len(ids)), nil
+}
+
 func (store *Store) OrderFindByID(ctx context.Context, id string) (OrderInterface, error) {
 	if id == "" {
 		return nil, errors.New("order id is empty")
@@ -140,14 +264,17 @@ func (store *Store) OrderFindByID(ctx context.Context, id string) (OrderInterfac
 }
 
 func (store *Store) OrderList(ctx context.Context, options OrderQueryInterface) ([]OrderInterface, error) {
-	q, err := store.orderQuery(options)
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.orderQuery(ctx, options)
 	if err != nil {
 		return []OrderInterface{}, err
 	}
 
 	var results []map[string]any
 	if err := q.Get(&results); err != nil {
-		return []OrderInterface{}, err
+		return []OrderInterface{}, store.operationError("order list", err)
 	}
 
 	list := []OrderInterface{}
@@ -165,13 +292,55 @@ func (store *Store) OrderUpdate(ctx context.Context, order OrderInterface) error
 		return errors.New("order is nil")
 	}
 
+	if err := store.orderHooks.runBeforeUpdate(ctx, order, order.DataChanged()); err != nil {
+		return err
+	}
+
+	changed := order.DataChanged()
+	if err := store.orderUpdate(ctx, order); err != nil {
+		return err
+	}
+
+	store.orderHooks.runAfterUpdate(ctx, order, changed)
+
+	return nil
+}
+
+// OrderUpdateMany sets the fields on all the orders matching the query
+// options in a single transaction, returning how many were updated. Only
+// the columns listed in orderUpdatableColumns can be set. Versions are
+// incremented without being checked. The AfterUpdate hooks run on the
+// orders set, but not the Before hooks, as the orders are not loaded.
+func (store *Store) OrderUpdateMany(ctx context.Context, options OrderQueryInterface, fields map[string]string) (int64, error) {
+	if err := assertUpdatable(fields, orderUpdatableColumns); err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	ids, rows, err := store.updateManyLoaded(ctx, store.orderBulkTable(options), fields, store.orderHooks.hasAfterUpdate())
+	if err != nil {
+		return 0, store.operationError("order update many", err)
+	}
+
+	runAfterUpdateRows(ctx, &store.orderHooks, rows, NewOrderFromExistingData, func(map[string]string) map[string]string {
+		return maps.Clone(fields)
+	})
+
+	return int64(len(ids)), nil
+}
+
+// orderUpdate writes the changed fields of order, without running hooks
+func (store *Store) orderUpdate(ctx context.Context, order OrderInterface) error {
 	order.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString())
 
 	dataChanged := order.DataChanged()
 
-	delete(dataChanged, "id")   // ID is not updateable
-	delete(dataChanged, "hash") // Hash is not updateable
-	delete(dataChanged, "data") // Data is not updateable
+	delete(dataChanged, "id")           // ID is not updateable
+	delete(dataChanged, "hash")         // Hash is not updateable
+	delete(dataChanged, "data")         // Data is not updateable
+	delete(dataChanged, COLUMN_VERSION) // Version is set by the update itself
 
 	if len(dataChanged) < 1 {
 		return nil
@@ -179,17 +348,62 @@ func (store *Store) OrderUpdate(ctx context.Context, order OrderInterface) error
 
 	row := map[string]any{}
 	for k, v := range dataChanged {
-		row[k] = v
+		row[k] = rowValue(k, v)
 	}
 
-	_, err := store.db.Query().Table(store.orderTableName).Where(COLUMN_ID+" = ?", order.GetID()).Update(row)
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
 
-	order.MarkAsNotDirty()
+	var version int64
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		before, err := store.storedValues(tx, store.orderTableName, order.GetID(), lo.Keys(dataChanged))
+		if err != nil {
+			return changeRecords{}, err
+		}
 
-	return err
+		events, err := store.orderOutboxEvents(order.GetID(), before, dataChanged)
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		version, err = versionedUpdate(ctx, statement(tx).Table(store.orderTableName).Where(COLUMN_ID+" = ?", order.GetID()), order.GetVersion(), row)
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_ORDER, order.GetID(), updateOperation(dataChanged), before, dataChanged),
+			events:    events,
+		}, err
+	})
+
+	if err == nil {
+		order.SetVersion(version)
+		order.MarkAsNotDirty()
+	}
+
+	return store.operationError("order update", err)
+}
+
+// orderBulkTable describes the orders matching the query options to
+// the bulk operations
+func (store *Store) orderBulkTable(options OrderQueryInterface) bulkTable {
+	return bulkTable{
+		entityType: ENTITY_TYPE_ORDER,
+		tableName:  store.orderTableName,
+		selectRows: func(q contractsorm.Query) (contractsorm.Query, error) {
+			return store.orderQueryOn(q, options)
+		},
+		events: store.orderOutboxEvents,
+		dependents: []dependentCheck{
+			{tableName: store.orderLineItemTableName, column: COLUMN_ORDER_ID, err: ErrOrderHasActiveLineItems},
+			{tableName: store.mediaTableName, column: COLUMN_ENTITY_ID, err: ErrOrderHasActiveMedia},
+		},
+	}
+}
+
+func (store *Store) orderQuery(ctx context.Context, options OrderQueryInterface) (contractsorm.Query, error) {
+	return store.orderQueryOn(store.query(ctx), options)
 }
 
-func (store *Store) orderQuery(options OrderQueryInterface) (contractsorm.Query, error) {
+// orderQueryOn applies the query options to q, which may run within a transaction
+func (store *Store) orderQueryOn(q contractsorm.Query, options OrderQueryInterface) (contractsorm.Query, error) {
 	if options == nil {
 		return nil, errors.New("order options cannot be nil")
 	}
@@ -198,7 +412,7 @@ func (store *Store) orderQuery(options OrderQueryInterface) (contractsorm.Query,
 		return nil, err
 	}
 
-	q := store.db.Query().Table(store.orderTableName)
+	q = q.Table(store.orderTableName)
 
 	if options.HasID() {
 		q = q.Where(COLUMN_ID+" = ?", options.ID())
@@ -246,10 +460,16 @@ func (store *Store) orderQuery(options OrderQueryInterface) (contractsorm.Query,
 		}
 	}
 
-	sortOrder := lo.Ternary(options.HasSortDirection(), options.SortDirection(), "desc")
+	if options.HasAfterCursor() {
+		var err error
+		q, err = whereAfterCursor(q, store.dialect, keysetSort(options), options.AfterCursor())
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	if options.HasOrderBy() {
-		q = q.OrderBy(options.OrderBy(), sortOrder)
+	if isSorted(options) {
+		q = orderByKeyset(q, keysetSort(options))
 	}
 
 	if !options.SoftDeletedIncluded() {
@@ -260,14 +480,17 @@ func (store *Store) orderQuery(options OrderQueryInterface) (contractsorm.Query,
 }
 
 func (store *Store) OrderLineItemCount(ctx context.Context, options OrderLineItemQueryInterface) (int64, error) {
-	q, err := store.orderLineItemQuery(options)
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.orderLineItemQuery(ctx, options)
 	if err != nil {
 		return -1, err
 	}
 
 	var count int64
 	if err := q.Count(&count); err != nil {
-		return -1, err
+		return -1, store.operationError("order line item count", err)
 	}
 
 	return count, nil
@@ -282,19 +505,82 @@ func (store *Store) OrderLineItemCreate(ctx context.Context, orderLineItem Order
 	orderLineItem.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
 	orderLineItem.SetSoftDeletedAt(MAX_DATETIME)
 
+	if err := store.orderLineItemHooks.runBeforeCreate(ctx, orderLineItem); err != nil {
+		return err
+	}
+
 	data := orderLineItem.Data()
 	row := map[string]any{}
 	for k, v := range data {
-		row[k] = v
+		row[k] = rowValue(k, v)
 	}
 
-	err := store.db.Query().Table(store.orderLineItemTableName).Create(row)
-	if err != nil {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	if err := store.assertReferencesExist(ctx, data, store.orderLineItemReferences()); err != nil {
 		return err
 	}
 
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		err := statement(tx).Table(store.orderLineItemTableName).Create(row)
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_ORDER_LINE_ITEM, orderLineItem.GetID(), AUDIT_OPERATION_CREATE, nil, data),
+		}, err
+	})
+	if err != nil {
+		return store.operationError("order line item create", err)
+	}
+
 	orderLineItem.MarkAsNotDirty()
 
+	store.orderLineItemHooks.runAfterCreate(ctx, orderLineItem)
+
+	return nil
+}
+
+// OrderLineItemCreateMany creates the order line items in a single
+// transaction, with batched multi-row inserts: either all of them are
+// created or none is. Hooks run for every line item like on
+// OrderLineItemCreate.
+func (store *Store) OrderLineItemCreateMany(ctx context.Context, orderLineItems []OrderLineItemInterface) error {
+	if lo.Contains(orderLineItems, nil) {
+		return errors.New("orderLineItem is nil")
+	}
+
+	if len(orderLineItems) == 0 {
+		return nil
+	}
+
+	now := carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)
+
+	for _, orderLineItem := range orderLineItems {
+		orderLineItem.SetCreatedAt(now)
+		orderLineItem.SetUpdatedAt(now)
+		orderLineItem.SetSoftDeletedAt(MAX_DATETIME)
+
+		if err := store.orderLineItemHooks.runBeforeCreate(ctx, orderLineItem); err != nil {
+			return err
+		}
+	}
+
+	rows := lo.Map(orderLineItems, func(orderLineItem OrderLineItemInterface, _ int) map[string]string {
+		return orderLineItem.Data()
+	})
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	err := store.createMany(ctx, ENTITY_TYPE_ORDER_LINE_ITEM, store.orderLineItemTableName, store.orderLineItemReferences(), rows)
+	if err != nil {
+		return store.operationError("order line item create many", err)
+	}
+
+	for _, orderLineItem := range orderLineItems {
+		orderLineItem.MarkAsNotDirty()
+		store.orderLineItemHooks.runAfterCreate(ctx, orderLineItem)
+	}
+
 	return nil
 }
 
@@ -303,8 +589,27 @@ func (store *Store) OrderLineItemDeleteByID(ctx context.Context, id string) erro
 		return errors.New("order line id is empty")
 	}
 
-	_, err := store.db.Query().Table(store.orderLineItemTableName).Where(COLUMN_ID+" = ?", id).Delete()
-	return err
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		before, err := store.storedValues(tx, store.orderLineItemTableName, id, nil)
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		_, err = statement(tx).Table(store.orderLineItemTableName).Where(COLUMN_ID+" = ?", id).Delete()
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_ORDER_LINE_ITEM, id, AUDIT_OPERATION_DELETE, before, nil),
+		}, err
+	})
+	if err != nil {
+		return store.operationError("order line item delete", err)
+	}
+
+	store.orderLineItemHooks.runAfterDelete(ctx, id)
+
+	return nil
 }
 
 func (store *Store) OrderLineItemDelete(ctx context.Context, orderLineItem OrderLineItemInterface) error {
@@ -332,14 +637,17 @@ func (store *Store) OrderLineItemFindByID(ctx context.Context, id string) (Order
 }
 
 func (store *Store) OrderLineItemList(ctx context.Context, options OrderLineItemQueryInterface) ([]OrderLineItemInterface, error) {
-	q, err := store.orderLineItemQuery(options)
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.orderLineItemQuery(ctx, options)
 	if err != nil {
 		return []OrderLineItemInterface{}, err
 	}
 
 	var results []map[string]any
 	if err := q.Get(&results); err != nil {
-		return []OrderLineItemInterface{}, err
+		return []OrderLineItemInterface{}, store.operationError("order line item list", err)
 	}
 
 	list := []OrderLineItemInterface{}
@@ -352,6 +660,62 @@ func (store *Store) OrderLineItemList(ctx context.Context, options OrderLineItem
 	return list, nil
 }
 
+// OrderLineItemIterate streams the order line items matching the query options, fetching
+// them from the database in batches instead of loading them all at once.
+// Iteration stops with an error as soon as ctx is cancelled.
+func (store *Store) OrderLineItemIterate(ctx context.Context, options OrderLineItemQueryInterface) iter.Seq2[OrderLineItemInterface, error] {
+	if options == nil {
+		return iterateError[OrderLineItemInterface](errors.New("options is nil"))
+	}
+
+	build := func(ctx context.Context) (contractsorm.Query, error) {
+		return store.orderLineItemQuery(ctx, options)
+	}
+
+	hydrate := func(data map[string]string) OrderLineItemInterface {
+		return NewOrderLineItemFromExistingData(data)
+	}
+
+	return iterateInBatches(store, ctx, "order line item iterate", options, build, hydrate)
+}
+
+// OrderLineItemListPage returns a single keyset (cursor) paginated page of order line items
+// matching the query options. The page size is taken from SetLimit. Pass the
+// returned NextCursor to SetAfterCursor to fetch the following page.
+func (store *Store) OrderLineItemListPage(ctx context.Context, options OrderLineItemQueryInterface) (ListPage[OrderLineItemInterface], error) {
+	if options == nil {
+		return ListPage[OrderLineItemInterface]{}, errors.New("options is nil")
+	}
+
+	if !options.HasLimit() {
+		return ListPage[OrderLineItemInterface]{}, errors.New("order line item list page. limit is required")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.orderLineItemQuery(ctx, options)
+	if err != nil {
+		return ListPage[OrderLineItemInterface]{}, err
+	}
+
+	q = keysetPageQuery(q, options.Limit(), options)
+
+	var results []map[string]any
+	if err := q.Get(&results); err != nil {
+		return ListPage[OrderLineItemInterface]{}, store.operationError("order line item list page", err)
+	}
+
+	list := []OrderLineItemInterface{}
+
+	lo.ForEach(results, func(result map[string]any, index int) {
+		model := NewOrderLineItemFromExistingData(mapAnyToString(result))
+		list = append(list, model)
+	})
+
+	return newListPage(list, options.Limit(), options), nil
+}
+
 func (store *Store) OrderLineItemSoftDelete(ctx context.Context, orderLineItem OrderLineItemInterface) error {
 	if orderLineItem == nil {
 		return errors.New("order line is empty")
@@ -359,7 +723,13 @@ func (store *Store) OrderLineItemSoftDelete(ctx context.Context, orderLineItem O
 
 	orderLineItem.SetSoftDeletedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
 
-	return store.OrderLineItemUpdate(ctx, orderLineItem)
+	if err := store.orderLineItemUpdate(ctx, orderLineItem); err != nil {
+		return err
+	}
+
+	store.orderLineItemHooks.runAfterSoftDelete(ctx, orderLineItem.GetID())
+
+	return nil
 }
 
 func (store *Store) OrderLineItemSoftDeleteByID(ctx context.Context, id string) error {
@@ -378,18 +748,87 @@ func (store *Store) OrderLineItemSoftDeleteByID(ctx context.Context, id string)
 	return store.OrderLineItemSoftDelete(ctx, item)
 }
 
+// OrderLineItemSoftDeleteMany soft deletes the live order line items
+// matching the query options in a single transaction, returning how many
+// were soft deleted.
+// AfterSoftDelete hooks run for every order line item.
+func (store *Store) OrderLineItemSoftDeleteMany(ctx context.Context, options OrderLineItemQueryInterface) (int64, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	ids, err := store.updateMany(ctx, store.orderLineItemBulkTable(options), map[string]string{
+		COLUMN_SOFT_DELETED_AT: carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC),
+	})
+	if err != nil {
+		return 0, store.operationError("order line item soft delete many", err)
+	}
+
+	for _, id := range ids {
+		store.orderLineItemHooks.runAfterSoftDelete(ctx, id)
+	}
+
+	return int64(len(ids)), nil
+}
+
 func (store *Store) OrderLineItemUpdate(ctx context.Context, orderLineItem OrderLineItemInterface) error {
 	if orderLineItem == nil {
 		return errors.New("orderLineItem is nil")
 	}
 
+	if err := store.orderLineItemHooks.runBeforeUpdate(ctx, orderLineItem, orderLineItem.DataChanged()); err != nil {
+		return err
+	}
+
+	changed := orderLineItem.DataChanged()
+	if err := store.orderLineItemUpdate(ctx, orderLineItem); err != nil {
+		return err
+	}
+
+	store.orderLineItemHooks.runAfterUpdate(ctx, orderLineItem, changed)
+
+	return nil
+}
+
+// OrderLineItemUpdateMany sets the fields on all the order line items
+// matching the query options in a single transaction, returning how many
+// were updated. Only the columns listed in orderLineItemUpdatableColumns
+// can be set. Versions are incremented without being checked. The
+// AfterUpdate hooks run on the line items set, but not the Before hooks, as
+// the line items are not loaded.
+func (store *Store) OrderLineItemUpdateMany(ctx context.Context, options OrderLineItemQueryInterface, fields map[string]string) (int64, error) {
+	if err := assertUpdatable(fields, orderLineItemUpdatableColumns); err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	if err := store.assertReferencesExist(ctx, fields, store.orderLineItemReferences()); err != nil {
+		return 0, err
+	}
+
+	ids, rows, err := store.updateManyLoaded(ctx, store.orderLineItemBulkTable(options), fields, store.orderLineItemHooks.hasAfterUpdate())
+	if err != nil {
+		return 0, store.operationError("order line item update many", err)
+	}
+
+	runAfterUpdateRows(ctx, &store.orderLineItemHooks, rows, NewOrderLineItemFromExistingData, func(map[string]string) map[string]string {
+		return maps.Clone(fields)
+	})
+
+	return int64(len(ids)), nil
+}
+
+// orderLineItemUpdate writes the changed fields of orderLineItem, without running hooks
+func (store *Store) orderLineItemUpdate(ctx context.Context, orderLineItem OrderLineItemInterface) error {
 	orderLineItem.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
 
 	dataChanged := orderLineItem.DataChanged()
 
-	delete(dataChanged, COLUMN_ID) // ID is not updateable
-	delete(dataChanged, "hash")    // Hash is not updateable
-	delete(dataChanged, "data")    // Data is not updateable
+	delete(dataChanged, COLUMN_ID)      // ID is not updateable
+	delete(dataChanged, "hash")         // Hash is not updateable
+	delete(dataChanged, "data")         // Data is not updateable
+	delete(dataChanged, COLUMN_VERSION) // Version is set by the update itself
 
 	if len(dataChanged) < 1 {
 		return nil
@@ -397,17 +836,55 @@ func (store *Store) OrderLineItemUpdate(ctx context.Context, orderLineItem Order
 
 	row := map[string]any{}
 	for k, v := range dataChanged {
-		row[k] = v
+		row[k] = rowValue(k, v)
 	}
 
-	_, err := store.db.Query().Table(store.orderLineItemTableName).Where(COLUMN_ID+" = ?", orderLineItem.GetID()).Update(row)
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
 
-	orderLineItem.MarkAsNotDirty()
+	if err := store.assertReferencesExist(ctx, dataChanged, store.orderLineItemReferences()); err != nil {
+		return err
+	}
+
+	var version int64
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		before, err := store.storedValues(tx, store.orderLineItemTableName, orderLineItem.GetID(), lo.Keys(dataChanged))
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		version, err = versionedUpdate(ctx, statement(tx).Table(store.orderLineItemTableName).Where(COLUMN_ID+" = ?", orderLineItem.GetID()), orderLineItem.GetVersion(), row)
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_ORDER_LINE_ITEM, orderLineItem.GetID(), updateOperation(dataChanged), before, dataChanged),
+		}, err
+	})
+
+	if err == nil {
+		orderLineItem.SetVersion(version)
+		orderLineItem.MarkAsNotDirty()
+	}
 
-	return err
+	return store.operationError("order line item update", err)
 }
 
-func (store *Store) orderLineItemQuery(options OrderLineItemQueryInterface) (contractsorm.Query, error) {
+// orderLineItemBulkTable describes the order line items matching the query
+// options to the bulk operations
+func (store *Store) orderLineItemBulkTable(options OrderLineItemQueryInterface) bulkTable {
+	return bulkTable{
+		entityType: ENTITY_TYPE_ORDER_LINE_ITEM,
+		tableName:  store.orderLineItemTableName,
+		selectRows: func(q contractsorm.Query) (contractsorm.Query, error) {
+			return store.orderLineItemQueryOn(q, options)
+		},
+	}
+}
+
+func (store *Store) orderLineItemQuery(ctx context.Context, options OrderLineItemQueryInterface) (contractsorm.Query, error) {
+	return store.orderLineItemQueryOn(store.query(ctx), options)
+}
+
+// orderLineItemQueryOn applies the query options to q, which may run within a transaction
+func (store *Store) orderLineItemQueryOn(q contractsorm.Query, options OrderLineItemQueryInterface) (contractsorm.Query, error) {
 	if options == nil {
 		return nil, errors.New("options is nil")
 	}
@@ -416,7 +893,7 @@ func (store *Store) orderLineItemQuery(options OrderLineItemQueryInterface) (con
 		return nil, err
 	}
 
-	q := store.db.Query().Table(store.orderLineItemTableName)
+	q = q.Table(store.orderLineItemTableName)
 
 	if options.HasID() {
 		q = q.Where(COLUMN_ID+" = ?", options.ID())
@@ -468,10 +945,16 @@ func (store *Store) orderLineItemQuery(options OrderLineItemQueryInterface) (con
 		}
 	}
 
-	sortOrder := lo.Ternary(options.HasSortDirection(), options.SortDirection(), "desc")
+	if options.HasAfterCursor() {
+		var err error
+		q, err = whereAfterCursor(q, store.dialect, keysetSort(options), options.AfterCursor())
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	if options.HasOrderBy() {
-		q = q.OrderBy(options.OrderBy(), sortOrder)
+	if isSorted(options) {
+		q = orderByKeyset(q, keysetSort(options))
 	}
 
 	if !options.SoftDeletedIncluded() {
@@ -0,0 +1,229 @@
+package shopstore
+
+import (
+	"context"
+	"errors"
+
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+	"github.com/dromara/carbon/v2"
+	"github.com/samber/lo"
+)
+
+// OrderCalculateTax calculates the tax of the live line items of the order
+// with the TaxCalculator of the store, and stores it: the rate and the tax
+// of each line item, and the tax total of the order.
+//
+// The tax is calculated for the shipping address of the order, or for its
+// billing address when it has no shipping address (ErrOrderAddressMissing
+// when it has neither). Each line item is taxed at the rate of the tax
+// class of its product, on its price times its quantity less its share of
+// the order discount.
+//
+// The order price is set to the line item total less the discount, plus
+// the shipping cost, plus the tax when the prices exclude it (see
+// NewStoreOptions.PricesIncludeTax). Shipping is not taxed.
+// Calculating the tax again, say after changing the address, replaces it.
+//
+// The changes are written in a single transaction, failing with
+// ErrConcurrentModification when the order or a line item changed since
+// they were read. The AfterUpdate hooks run on the line items and the
+// order once written, but not the BeforeUpdate hooks.
+func (store *Store) OrderCalculateTax(ctx context.Context, orderID string) (OrderInterface, error) {
+	if orderID == "" {
+		return nil, errors.New("order id is empty")
+	}
+
+	order, err := store.OrderFindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+
+	lineItems, err := store.OrderLineItemList(ctx, NewOrderLineItemQuery().
+		SetOrderID(orderID).
+		AddSort(COLUMN_CREATED_AT, SORT_DIRECTION_ASC).
+		AddSort(COLUMN_ID, SORT_DIRECTION_ASC))
+	if err != nil {
+		return nil, err
+	}
+	if len(lineItems) == 0 {
+		return nil, ErrOrderHasNoLineItems
+	}
+
+	address, err := order.GetShippingAddress()
+	if err != nil {
+		return nil, err
+	}
+	if address.IsEmpty() {
+		if address, err = order.GetBillingAddress(); err != nil {
+			return nil, err
+		}
+	}
+	if address.IsEmpty() {
+		return nil, ErrOrderAddressMissing
+	}
+
+	products, err := store.productsByID(ctx, lo.Map(lineItems, func(lineItem OrderLineItemInterface, _ int) string {
+		return lineItem.GetProductID()
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	amounts := lo.Map(lineItems, func(lineItem OrderLineItemInterface, _ int) float64 {
+		return roundPrice(lineItem.GetPriceFloat() * float64(lineItem.GetQuantityInt()))
+	})
+
+	shares := allocateDiscount(amounts, order.GetDiscountAmountFloat())
+
+	request := TaxRequest{
+		Address:          address,
+		PricesIncludeTax: store.pricesIncludeTax,
+		Lines: lo.Map(lineItems, func(lineItem OrderLineItemInterface, i int) TaxLine {
+			return TaxLine{
+				ProductID: lineItem.GetProductID(),
+				TaxClass:  productTaxClass(products, lineItem.GetProductID()),
+				Amount:    roundPrice(amounts[i] - shares[i]),
+			}
+		}),
+	}
+
+	results, err := store.taxCalculator.CalculateTax(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) != len(lineItems) {
+		return nil, errors.New("order calculate tax. the tax calculator did not return a result per line")
+	}
+
+	taxAmount := 0.0
+	for i, lineItem := range lineItems {
+		lineItem.SetTaxRateFloat(results[i].Rate)
+		lineItem.SetTaxAmountFloat(roundPrice(results[i].Amount))
+		taxAmount += lineItem.GetTaxAmountFloat()
+	}
+	taxAmount = roundPrice(taxAmount)
+
+	price := lo.Sum(amounts) - lo.Sum(shares) + order.GetShippingAmountFloat()
+	if !store.pricesIncludeTax {
+		price += taxAmount
+	}
+
+	order.SetTaxAmountFloat(taxAmount)
+	order.SetPricesIncludeTax(store.pricesIncludeTax)
+	order.SetPriceFloat(roundPrice(price))
+
+	updatedAt := carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)
+	for _, lineItem := range lineItems {
+		lineItem.SetUpdatedAt(updatedAt)
+	}
+	order.SetUpdatedAt(updatedAt)
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	versions := map[string]int64{}
+	err = store.query(ctx).Transaction(func(tx contractsorm.Query) error {
+		records := changeRecords{}
+
+		for _, lineItem := range lineItems {
+			version, lineItemRecords, err := store.changedFieldsUpdate(ctx, tx, ENTITY_TYPE_ORDER_LINE_ITEM, store.orderLineItemTableName, lineItem.GetID(), lineItem.GetVersion(), lineItem.DataChanged())
+			if err != nil {
+				return err
+			}
+
+			versions[lineItem.GetID()] = version
+			records.auditLogs = append(records.auditLogs, lineItemRecords.auditLogs...)
+		}
+
+		version, orderRecords, err := store.changedFieldsUpdate(ctx, tx, ENTITY_TYPE_ORDER, store.orderTableName, order.GetID(), order.GetVersion(), order.DataChanged())
+		if err != nil {
+			return err
+		}
+
+		versions[order.GetID()] = version
+		records.auditLogs = append(records.auditLogs, orderRecords.auditLogs...)
+
+		return store.recordChanges(tx, records)
+	})
+	if err != nil {
+		return nil, store.operationError("order calculate tax", err)
+	}
+
+	for _, lineItem := range lineItems {
+		changed := lo.OmitByKeys(lineItem.DataChanged(), []string{COLUMN_UPDATED_AT})
+
+		lineItem.SetVersion(versions[lineItem.GetID()])
+		lineItem.MarkAsNotDirty()
+		store.orderLineItemHooks.runAfterUpdate(ctx, lineItem, changed)
+	}
+
+	changed := lo.OmitByKeys(order.DataChanged(), []string{COLUMN_UPDATED_AT})
+
+	order.SetVersion(versions[order.GetID()])
+	order.MarkAsNotDirty()
+	store.orderHooks.runAfterUpdate(ctx, order, changed)
+
+	return order, nil
+}
+
+// productsByID returns the products with the IDs, by ID. Soft deleted
+// products are included, so the line items of an order keep the tax class
+// and weight of a product removed since.
+func (store *Store) productsByID(ctx context.Context, productIDs []string) (map[string]ProductInterface, error) {
+	products := map[string]ProductInterface{}
+	for _, batch := range lo.Chunk(lo.Uniq(lo.Compact(productIDs)), bulkBatchSize) {
+		list, err := store.ProductList(ctx, NewProductQuery().
+			SetIDIn(batch).
+			SetSoftDeletedIncluded(true))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, product := range list {
+			products[product.GetID()] = product
+		}
+	}
+
+	return products, nil
+}
+
+// changedFieldsUpdate writes the changed fields of the entity with the ID,
+// loaded at version, within the transaction tx. It returns the new version
+// and the audit log entries of the change.
+func (store *Store) changedFieldsUpdate(ctx context.Context, tx contractsorm.Query, entityType string, tableName string, id string, version int64, dataChanged map[string]string) (int64, changeRecords, error) {
+	delete(dataChanged, COLUMN_ID)
+	delete(dataChanged, COLUMN_VERSION)
+
+	row := map[string]any{}
+	for k, v := range dataChanged {
+		row[k] = rowValue(k, v)
+	}
+
+	before, err := store.storedValues(tx, tableName, id, lo.Keys(dataChanged))
+	if err != nil {
+		return version, changeRecords{}, err
+	}
+
+	version, err = versionedUpdate(ctx, statement(tx).Table(tableName).Where(COLUMN_ID+" = ?", id), version, row)
+	if err != nil {
+		return version, changeRecords{}, err
+	}
+
+	return version, changeRecords{
+		auditLogs: store.auditLogEntries(ctx, entityType, id, updateOperation(dataChanged), before, dataChanged),
+	}, nil
+}
+
+// productTaxClass returns the tax class of the product, the standard class
+// when it is unknown
+func productTaxClass(products map[string]ProductInterface, productID string) string {
+	product, ok := products[productID]
+	if !ok || product.GetTaxClass() == "" {
+		return TAX_CLASS_STANDARD
+	}
+
+	return product.GetTaxClass()
+}
@@ -0,0 +1,329 @@
+package shopstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/samber/lo"
+)
+
+func initTaxStore(t *testing.T, pricesIncludeTax bool, calculator TaxCalculator) *Store {
+	db, err := initDB(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	options := testStoreOptions(db)
+	options.PricesIncludeTax = pricesIncludeTax
+	options.TaxCalculator = calculator
+
+	store, err := NewStore(options)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	return store
+}
+
+// seedTaxOrder creates the UK and US rates, a standard and a reduced
+// product, and an order of two of each shipped to the address
+func seedTaxOrder(t *testing.T, store StoreInterface, address OrderAddress) OrderInterface {
+	t.Helper()
+	ctx := context.Background()
+
+	taxRates := []TaxRateInterface{
+		NewTaxRate().SetCountryCode("GB").SetRateFloat(20).SetName("VAT"),
+		NewTaxRate().SetCountryCode("GB").SetTaxClass("reduced").SetRateFloat(5).SetName("Reduced VAT"),
+		NewTaxRate().SetCountryCode("US").SetRateFloat(5).SetName("Federal"),
+		NewTaxRate().SetCountryCode("US").SetRegion("NY").SetRateFloat(8.875).SetName("New York"),
+	}
+
+	for _, taxRate := range taxRates {
+		if err := store.TaxRateCreate(ctx, taxRate); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	shirt := NewProduct().SetTitle("Shirt").SetPriceFloat(10)
+	book := NewProduct().SetTitle("Book").SetPriceFloat(5).SetTaxClass("reduced")
+	if err := store.ProductCreateMany(ctx, []ProductInterface{shirt, book}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	order := NewOrder().SetCustomerID("CUSTOMER01").SetPriceFloat(30).SetQuantityInt(4)
+	_ = order.SetShippingAddress(address)
+	if err := store.OrderCreate(ctx, order); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	for _, product := range []ProductInterface{shirt, book} {
+		lineItem := NewOrderLineItem().
+			SetOrderID(order.GetID()).
+			SetProductID(product.GetID()).
+			SetTitle(product.GetTitle()).
+			SetPriceFloat(product.GetPriceFloat()).
+			SetQuantityInt(2)
+
+		if err := store.OrderLineItemCreate(ctx, lineItem); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	return order
+}
+
+func TestStoreTaxRateCRUD(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	taxRate := NewTaxRate().SetCountryCode("us").SetRegion("NY").SetRateFloat(8.875).SetName("New York")
+	if err := store.TaxRateCreate(ctx, taxRate); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	found, err := store.TaxRateFindByID(ctx, taxRate.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found == nil || found.GetCountryCode() != "US" || found.GetRateFloat() != 8.875 {
+		t.Fatalf("expected the stored tax rate, got %v", found)
+	}
+
+	found.SetRateFloat(4.5)
+	if err := store.TaxRateUpdate(ctx, found); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.TaxRateCreate(ctx, NewTaxRate().SetCountryCode("GB").SetRateFloat(20)); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	list, err := store.TaxRateList(ctx, NewTaxRateQuery().SetCountryCode("US").SetRegion("NY"))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(list) != 1 || list[0].GetRateFloat() != 4.5 || list[0].GetVersion() != 2 {
+		t.Fatalf("expected the updated New York rate, got %v", list)
+	}
+
+	if err := store.TaxRateSoftDeleteByID(ctx, taxRate.GetID()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	count, err := store.TaxRateCount(ctx, NewTaxRateQuery().SetTaxClass(TAX_CLASS_STANDARD))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if count != 1 {
+		t.Fatalf("expected the soft deleted rate excluded, got %d rates", count)
+	}
+
+	if err := NewTaxRateQuery().SetOrderBy(COLUMN_METAS).Validate(); err == nil {
+		t.Fatal("expected an error for a sort on metas")
+	}
+}
+
+func TestStoreOrderCalculateTax_Exclusive(t *testing.T) {
+	store := initTaxStore(t, false, nil)
+	ctx := context.Background()
+
+	order := seedTaxOrder(t, store, OrderAddress{Line1: "1 High Street", City: "London", CountryCode: "GB"})
+
+	calculated, err := store.OrderCalculateTax(ctx, order.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// 20 at 20% and 10 at 5%, on top of the prices
+	if calculated.GetTaxAmountFloat() != 4.5 || calculated.GetPriceFloat() != 34.5 || calculated.PricesIncludeTax() {
+		t.Fatalf("expected 4.50 of tax on top of 30, got %v", calculated.Data())
+	}
+
+	lineItems, err := store.OrderLineItemList(ctx, NewOrderLineItemQuery().
+		SetOrderID(order.GetID()).
+		SetOrderBy(COLUMN_TITLE).
+		SetSortDirection(SORT_DIRECTION_ASC))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if lineItems[0].GetTaxRateFloat() != 5 || lineItems[0].GetTaxAmountFloat() != 0.5 {
+		t.Fatalf("expected the book taxed at the reduced rate, got %v", lineItems[0].Data())
+	}
+
+	if lineItems[1].GetTaxRateFloat() != 20 || lineItems[1].GetTaxAmountFloat() != 4 {
+		t.Fatalf("expected the shirt taxed at the standard rate, got %v", lineItems[1].Data())
+	}
+
+	found, err := store.OrderFindByID(ctx, order.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found.GetTaxAmountFloat() != 4.5 || found.GetVersion() != calculated.GetVersion() {
+		t.Fatalf("expected the tax stored, got %v", found.Data())
+	}
+}
+
+func TestStoreOrderCalculateTax_Inclusive(t *testing.T) {
+	store := initTaxStore(t, true, nil)
+	ctx := context.Background()
+
+	order := seedTaxOrder(t, store, OrderAddress{Line1: "1 High Street", CountryCode: "GB"})
+
+	calculated, err := store.OrderCalculateTax(ctx, order.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// 20 holds 3.33 at 20%, 10 holds 0.48 at 5%
+	if calculated.GetTaxAmountFloat() != 3.81 || calculated.GetPriceFloat() != 30 || !calculated.PricesIncludeTax() {
+		t.Fatalf("expected 3.81 of tax within 30, got %v", calculated.Data())
+	}
+}
+
+func TestStoreOrderCalculateTax_Region(t *testing.T) {
+	store := initTaxStore(t, false, nil)
+	ctx := context.Background()
+
+	newYork := seedTaxOrder(t, store, OrderAddress{Line1: "1 Broadway", Region: "ny", CountryCode: "US"})
+
+	calculated, err := store.OrderCalculateTax(ctx, newYork.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// the shirts at the New York rate, the book without a US reduced rate
+	if calculated.GetTaxAmountFloat() != 1.78 {
+		t.Fatalf("expected the region rate over the country rate, got %v", calculated.Data())
+	}
+
+	// the address changes to another state, the tax is replaced
+	_ = calculated.SetShippingAddress(OrderAddress{Line1: "1 Main Street", Region: "NJ", CountryCode: "US"})
+	if err := store.OrderUpdate(ctx, calculated); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	calculated, err = store.OrderCalculateTax(ctx, newYork.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if calculated.GetTaxAmountFloat() != 1 || calculated.GetPriceFloat() != 31 {
+		t.Fatalf("expected the country rate, got %v", calculated.Data())
+	}
+}
+
+func TestStoreOrderCalculateTax_Discount(t *testing.T) {
+	store := initTaxStore(t, false, nil)
+	ctx := context.Background()
+
+	order := seedTaxOrder(t, store, OrderAddress{Line1: "1 High Street", CountryCode: "GB"})
+	order.SetDiscountAmountFloat(6)
+	if err := store.OrderUpdate(ctx, order); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	calculated, err := store.OrderCalculateTax(ctx, order.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// 4 of the discount off the shirts, 2 off the book: 16 at 20% and 8 at 5%
+	if calculated.GetTaxAmountFloat() != 3.6 || calculated.GetPriceFloat() != 27.6 {
+		t.Fatalf("expected the tax of the discounted lines, got %v", calculated.Data())
+	}
+}
+
+func TestStoreOrderCalculateTax_Errors(t *testing.T) {
+	store := initTaxStore(t, false, nil)
+	ctx := context.Background()
+
+	if _, err := store.OrderCalculateTax(ctx, "MISSING"); !errors.Is(err, ErrOrderNotFound) {
+		t.Fatalf("expected ErrOrderNotFound, got %v", err)
+	}
+
+	empty := NewOrder().SetCustomerID("CUSTOMER01")
+	_ = empty.SetShippingAddress(OrderAddress{CountryCode: "GB"})
+	if err := store.OrderCreate(ctx, empty); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if _, err := store.OrderCalculateTax(ctx, empty.GetID()); !errors.Is(err, ErrOrderHasNoLineItems) {
+		t.Fatalf("expected ErrOrderHasNoLineItems, got %v", err)
+	}
+
+	order := seedTaxOrder(t, store, OrderAddress{})
+	if _, err := store.OrderCalculateTax(ctx, order.GetID()); !errors.Is(err, ErrOrderAddressMissing) {
+		t.Fatalf("expected ErrOrderAddressMissing, got %v", err)
+	}
+
+	// the billing address is used without a shipping address
+	_ = order.SetBillingAddress(OrderAddress{Line1: "1 High Street", CountryCode: "GB"})
+	if err := store.OrderUpdate(ctx, order); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	calculated, err := store.OrderCalculateTax(ctx, order.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if calculated.GetTaxAmountFloat() != 4.5 {
+		t.Fatalf("expected the tax for the billing address, got %v", calculated.Data())
+	}
+}
+
+// flatTaxCalculator taxes every line at a fixed rate
+type flatTaxCalculator struct {
+	rate     float64
+	requests []TaxRequest
+}
+
+func (calculator *flatTaxCalculator) CalculateTax(_ context.Context, request TaxRequest) ([]TaxLineResult, error) {
+	calculator.requests = append(calculator.requests, request)
+
+	results := []TaxLineResult{}
+	for _, line := range request.Lines {
+		results = append(results, TaxLineResult{Rate: calculator.rate, Amount: line.Amount * calculator.rate / 100})
+	}
+
+	return results, nil
+}
+
+func TestStoreOrderCalculateTax_CustomCalculator(t *testing.T) {
+	calculator := &flatTaxCalculator{rate: 10}
+	store := initTaxStore(t, false, calculator)
+	ctx := context.Background()
+
+	order := seedTaxOrder(t, store, OrderAddress{Line1: "1 Rue de Rivoli", CountryCode: "FR"})
+
+	calculated, err := store.OrderCalculateTax(ctx, order.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if calculated.GetTaxAmountFloat() != 3 || calculated.GetPriceFloat() != 33 {
+		t.Fatalf("expected the tax of the custom calculator, got %v", calculated.Data())
+	}
+
+	if len(calculator.requests) != 1 || calculator.requests[0].Address.CountryCode != "FR" || len(calculator.requests[0].Lines) != 2 {
+		t.Fatalf("expected a request for the two lines shipped to France, got %v", calculator.requests)
+	}
+
+	taxClasses := lo.Map(calculator.requests[0].Lines, func(line TaxLine, _ int) string {
+		return line.TaxClass
+	})
+
+	if !lo.ElementsMatch(taxClasses, []string{TAX_CLASS_STANDARD, "reduced"}) {
+		t.Fatalf("expected the tax class of the product, got %v", calculator.requests[0].Lines)
+	}
+}
@@ -2,6 +2,7 @@ package shopstore
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
 )
@@ -496,3 +497,186 @@ func TestStoreOrderLineItemSoftDeleteByID(t *testing.T) {
 		t.Fatal("OrderLineItem MUST be deleted")
 	}
 }
+
+func TestStoreOrderListPage(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		if err := store.OrderCreate(ctx, NewOrder().SetCustomerID("CUSTOMER01_ID")); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	seen := map[string]bool{}
+	pageSizes := []int{}
+	cursor := ""
+
+	for {
+		query := NewOrderQuery().SetLimit(2)
+		if cursor != "" {
+			query.SetAfterCursor(cursor)
+		}
+
+		page, err := store.OrderListPage(ctx, query)
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		pageSizes = append(pageSizes, len(page.Items))
+
+		for _, order := range page.Items {
+			if seen[order.GetID()] {
+				t.Fatalf("order %s returned twice", order.GetID())
+			}
+			seen[order.GetID()] = true
+		}
+
+		if page.HasMore != (page.NextCursor != "") {
+			t.Fatalf("HasMore %v inconsistent with NextCursor %q", page.HasMore, page.NextCursor)
+		}
+
+		if !page.HasMore {
+			break
+		}
+
+		// an order arriving mid-scroll must not shift the following pages
+		if len(pageSizes) == 1 {
+			if err := store.OrderCreate(ctx, NewOrder().SetCustomerID("CUSTOMER02_ID")); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+		}
+
+		cursor = page.NextCursor
+	}
+
+	if len(seen) < 5 {
+		t.Fatalf("expected at least 5 orders across pages, got %d", len(seen))
+	}
+
+	if pageSizes[0] != 2 || pageSizes[1] != 2 {
+		t.Fatalf("unexpected page sizes %v", pageSizes)
+	}
+}
+
+func TestStoreOrderListPage_InvalidCursor(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	_, err = store.OrderListPage(ctx, NewOrderQuery().SetLimit(2).SetAfterCursor("not-a-cursor"))
+	if err == nil || !strings.Contains(err.Error(), "after_cursor") {
+		t.Fatalf("expected after_cursor error, got %v", err)
+	}
+
+	_, err = store.OrderListPage(ctx, NewOrderQuery())
+	if err == nil {
+		t.Fatal("expected error when limit is missing")
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := store.OrderCreate(ctx, NewOrder().SetCustomerID("CUSTOMER01_ID")); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	page, err := store.OrderListPage(ctx, NewOrderQuery().SetLimit(1))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	_, err = store.OrderList(ctx, NewOrderQuery().SetAfterCursor(page.NextCursor).SetOffset(1))
+	if err == nil {
+		t.Fatal("expected error when combining after_cursor with offset")
+	}
+
+	_, err = store.OrderList(ctx, NewOrderQuery().SetAfterCursor(page.NextCursor).SetOrderBy(COLUMN_STATUS))
+	if err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Fatalf("expected sort mismatch error, got %v", err)
+	}
+}
+
+func TestStoreOrderIterate(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	total := iterateBatchSize*2 + 10
+
+	for i := 0; i < total; i++ {
+		if err := store.OrderCreate(ctx, NewOrder().SetCustomerID("CUSTOMER01_ID")); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	seen := map[string]bool{}
+
+	for order, err := range store.OrderIterate(ctx, NewOrderQuery()) {
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		if seen[order.GetID()] {
+			t.Fatalf("order %s yielded twice", order.GetID())
+		}
+
+		seen[order.GetID()] = true
+	}
+
+	if len(seen) != total {
+		t.Fatalf("expected %d orders, got %d", total, len(seen))
+	}
+
+	count := 0
+
+	for _, err := range store.OrderIterate(ctx, NewOrderQuery().SetOffset(5).SetLimit(iterateBatchSize+3)) {
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		count++
+	}
+
+	if count != iterateBatchSize+3 {
+		t.Fatalf("expected %d orders with limit, got %d", iterateBatchSize+3, count)
+	}
+}
+
+func TestStoreOrderIterate_ContextCancelled(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.OrderCreate(context.Background(), NewOrder().SetCustomerID("CUSTOMER01_ID")); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for order, err := range store.OrderIterate(ctx, NewOrderQuery()) {
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+
+		if order != nil {
+			t.Fatal("expected no order on error")
+		}
+	}
+
+	for _, err := range store.OrderIterate(context.Background(), nil) {
+		if err == nil {
+			t.Fatal("expected error for nil options")
+		}
+	}
+}
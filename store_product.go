@@ -3,23 +3,31 @@ package shopstore
 import (
 	"context"
 	"errors"
+	"iter"
+	"maps"
+	"slices"
 	"strings"
+	"time"
 
 	contractsorm "github.com/dracory/neat/contracts/database/orm"
+	neatquery "github.com/dracory/neat/database/query"
 	"github.com/dromara/carbon/v2"
 	"github.com/samber/lo"
 	"github.com/spf13/cast"
 )
 
 func (store *Store) ProductCount(ctx context.Context, options ProductQueryInterface) (int64, error) {
-	q, err := store.productQuery(options)
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.productQuery(ctx, options)
 	if err != nil {
 		return -1, err
 	}
 
 	var count int64
 	if err := q.Count(&count); err != nil {
-		return -1, err
+		return -1, store.operationError("product count", err)
 	}
 
 	return count, nil
@@ -34,19 +42,85 @@ func (store *Store) ProductCreate(ctx context.Context, product ProductInterface)
 	product.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
 	product.SetSoftDeletedAt(MAX_DATETIME)
 
+	if err := store.productHooks.runBeforeCreate(ctx, product); err != nil {
+		return err
+	}
+
 	data := product.Data()
 	row := map[string]any{}
 	for k, v := range data {
-		row[k] = v
+		row[k] = rowValue(k, v)
 	}
 
-	err := store.db.Query().Table(store.productTableName).Create(row)
-	if err != nil {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	if err := store.assertReferencesExist(ctx, data, store.productReferences()); err != nil {
 		return err
 	}
 
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		err := statement(tx).Table(store.productTableName).Create(row)
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_PRODUCT, product.GetID(), AUDIT_OPERATION_CREATE, nil, data),
+		}, err
+	})
+	if err != nil && store.productSKUTaken(ctx, product) {
+		return ErrProductSKUExists
+	}
+	if err != nil {
+		return store.operationError("product create", err)
+	}
+
 	product.MarkAsNotDirty()
 
+	store.productHooks.runAfterCreate(ctx, product)
+
+	return nil
+}
+
+// ProductCreateMany creates the products in a single transaction, with
+// batched multi-row inserts: either all of them are created or none is.
+// Hooks run for every product like on ProductCreate. Variants may be
+// created along with their parent.
+func (store *Store) ProductCreateMany(ctx context.Context, products []ProductInterface) error {
+	if lo.Contains(products, nil) {
+		return errors.New("product is nil")
+	}
+
+	if len(products) == 0 {
+		return nil
+	}
+
+	now := carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)
+
+	for _, product := range products {
+		product.SetCreatedAt(now)
+		product.SetUpdatedAt(now)
+		product.SetSoftDeletedAt(MAX_DATETIME)
+
+		if err := store.productHooks.runBeforeCreate(ctx, product); err != nil {
+			return err
+		}
+	}
+
+	rows := lo.Map(products, func(product ProductInterface, _ int) map[string]string {
+		return product.Data()
+	})
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	err := store.createMany(ctx, ENTITY_TYPE_PRODUCT, store.productTableName, store.productReferences(), rows)
+	if err != nil {
+		return store.operationError("product create many", err)
+	}
+
+	for _, product := range products {
+		product.MarkAsNotDirty()
+		store.productHooks.runAfterCreate(ctx, product)
+	}
+
 	return nil
 }
 
@@ -67,8 +141,27 @@ func (store *Store) ProductDeleteByID(ctx context.Context, id string) error {
 		return err
 	}
 
-	_, err := store.db.Query().Table(store.productTableName).Where(COLUMN_ID+" = ?", id).Delete()
-	return err
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		before, err := store.storedValues(tx, store.productTableName, id, nil)
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		_, err = statement(tx).Table(store.productTableName).Where(COLUMN_ID+" = ?", id).Delete()
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_PRODUCT, id, AUDIT_OPERATION_DELETE, before, nil),
+		}, err
+	})
+	if err != nil {
+		return store.operationError("product delete", err)
+	}
+
+	store.productHooks.runAfterDelete(ctx, id)
+
+	return nil
 }
 
 // assertProductDeletable performs a non-atomic check-then-act: the count queries and the
@@ -103,6 +196,62 @@ func (store *Store) assertProductDeletable(ctx context.Context, productID string
 	return nil
 }
 
+// ProductIterate streams the products matching the query options, fetching
+// them from the database in batches instead of loading them all at once.
+// Iteration stops with an error as soon as ctx is cancelled.
+func (store *Store) ProductIterate(ctx context.Context, options ProductQueryInterface) iter.Seq2[ProductInterface, error] {
+	if options == nil {
+		return iterateError[ProductInterface](errors.New("product options cannot be nil"))
+	}
+
+	build := func(ctx context.Context) (contractsorm.Query, error) {
+		return store.productQuery(ctx, options)
+	}
+
+	hydrate := func(data map[string]string) ProductInterface {
+		return NewProductFromExistingData(data)
+	}
+
+	return iterateInBatches(store, ctx, "product iterate", options, build, hydrate)
+}
+
+// ProductListPage returns a single keyset (cursor) paginated page of products
+// matching the query options. The page size is taken from SetLimit. Pass the
+// returned NextCursor to SetAfterCursor to fetch the following page.
+func (store *Store) ProductListPage(ctx context.Context, options ProductQueryInterface) (ListPage[ProductInterface], error) {
+	if options == nil {
+		return ListPage[ProductInterface]{}, errors.New("product options cannot be nil")
+	}
+
+	if !options.HasLimit() {
+		return ListPage[ProductInterface]{}, errors.New("product list page. limit is required")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.productQuery(ctx, options)
+	if err != nil {
+		return ListPage[ProductInterface]{}, err
+	}
+
+	q = keysetPageQuery(q, options.Limit(), options)
+
+	var results []map[string]any
+	if err := q.Get(&results); err != nil {
+		return ListPage[ProductInterface]{}, store.operationError("product list page", err)
+	}
+
+	list := []ProductInterface{}
+
+	lo.ForEach(results, func(result map[string]any, index int) {
+		model := NewProductFromExistingData(mapAnyToString(result))
+		list = append(list, model)
+	})
+
+	return newListPage(list, options.Limit(), options), nil
+}
+
 func (store *Store) ProductSoftDelete(ctx context.Context, product ProductInterface) error {
 	if product == nil {
 		return errors.New("product is nil")
@@ -114,7 +263,13 @@ func (store *Store) ProductSoftDelete(ctx context.Context, product ProductInterf
 
 	product.SetSoftDeletedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
 
-	return store.ProductUpdate(ctx, product)
+	if err := store.productUpdate(ctx, product); err != nil {
+		return err
+	}
+
+	store.productHooks.runAfterSoftDelete(ctx, product.GetID())
+
+	return nil
 }
 
 func (store *Store) ProductSoftDeleteByID(ctx context.Context, id string) error {
@@ -133,6 +288,29 @@ func (store *Store) ProductSoftDeleteByID(ctx context.Context, id string) error
 	return store.ProductSoftDelete(ctx, product)
 }
 
+// ProductSoftDeleteMany soft deletes the live products matching the query
+// options in a single transaction, returning how many were soft deleted.
+// Fails like ProductSoftDelete when a product still has live variants,
+// line items or media, unless its variants are soft deleted along.
+// AfterSoftDelete hooks run for every product.
+func (store *Store) ProductSoftDeleteMany(ctx context.Context, options ProductQueryInterface) (int64, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	ids, err := store.updateMany(ctx, store.productBulkTable(options), map[string]string{
+		COLUMN_SOFT_DELETED_AT: carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC),
+	})
+	if err != nil {
+		return 0, store.operationError("product soft delete many", err)
+	}
+
+	for _, id := range ids {
+		store.productHooks.runAfterSoftDelete(ctx, id)
+	}
+
+	return int64(len(ids)), nil
+}
+
 func (store *Store) ProductFindByID(ctx context.Context, id string) (ProductInterface, error) {
 	if id == "" {
 		return nil, errors.New("product id is empty")
@@ -153,15 +331,39 @@ func (store *Store) ProductFindByID(ctx context.Context, id string) (ProductInte
 	return nil, nil
 }
 
+// ProductFindBySKU returns the live product with the SKU, nil if none
+func (store *Store) ProductFindBySKU(ctx context.Context, sku string) (ProductInterface, error) {
+	if sku == "" {
+		return nil, errors.New("product sku is empty")
+	}
+
+	list, err := store.ProductList(ctx, NewProductQuery().
+		SetSKU(sku).
+		SetLimit(1))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) > 0 {
+		return list[0], nil
+	}
+
+	return nil, nil
+}
+
 func (store *Store) ProductList(ctx context.Context, options ProductQueryInterface) ([]ProductInterface, error) {
-	q, err := store.productQuery(options)
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.productQuery(ctx, options)
 	if err != nil {
 		return []ProductInterface{}, err
 	}
 
 	var results []map[string]any
 	if err := q.Get(&results); err != nil {
-		return []ProductInterface{}, err
+		return []ProductInterface{}, store.operationError("product list", err)
 	}
 
 	list := []ProductInterface{}
@@ -174,36 +376,287 @@ func (store *Store) ProductList(ctx context.Context, options ProductQueryInterfa
 	return list, nil
 }
 
+// ProductQuantityAdjustOptions are the options of ProductQuantityAdjust
+type ProductQuantityAdjustOptions struct {
+	// NotBelowZero refuses adjustments taking the quantity below zero,
+	// failing with ErrInsufficientQuantity instead
+	NotBelowZero bool
+}
+
+// ProductQuantityAdjust adds delta, negative to take stock, to the quantity
+// of the product in a single SQL update and returns the new quantity.
+// Unlike loading the product and saving it with ProductUpdate, concurrent
+// adjustments (e.g. checkouts) never lose each other's changes.
+//
+// The product version is incremented, so loaded copies of the product go
+// stale. The change is audited and may record the product.stock_low outbox
+// event. The AfterUpdate hooks run with the quantity changed, but not the
+// BeforeUpdate hooks, as the product is not loaded.
+func (store *Store) ProductQuantityAdjust(ctx context.Context, productID string, delta int64, opts ProductQuantityAdjustOptions) (int64, error) {
+	if productID == "" {
+		return 0, errors.New("product id is empty")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	var quantity int64
+	var rows []map[string]string
+	err := store.query(ctx).Transaction(func(tx contractsorm.Query) error {
+		var records changeRecords
+		var err error
+
+		quantity, records, err = store.productQuantityAdjust(ctx, tx, productID, delta, opts)
+		if err != nil {
+			return err
+		}
+
+		if store.productHooks.hasAfterUpdate() {
+			if rows, err = rowsByID(tx, store.productTableName, []string{productID}); err != nil {
+				return err
+			}
+		}
+
+		return store.recordChanges(tx, records)
+	})
+	if err != nil {
+		return 0, store.operationError("product quantity adjust", err)
+	}
+
+	runAfterUpdateRows(ctx, &store.productHooks, rows, NewProductFromExistingData, productQuantityChanged)
+
+	return quantity, nil
+}
+
+// productQuantityChanged returns the field written to a product row by a
+// quantity adjustment, for the AfterUpdate hooks
+func productQuantityChanged(row map[string]string) map[string]string {
+	return map[string]string{COLUMN_QUANTITY: row[COLUMN_QUANTITY]}
+}
+
+// productQuantityAdjust is ProductQuantityAdjust within the transaction tx,
+// returning the new quantity and the records of the change to record
+func (store *Store) productQuantityAdjust(ctx context.Context, tx contractsorm.Query, productID string, delta int64, opts ProductQuantityAdjustOptions) (int64, changeRecords, error) {
+	q := statement(tx).
+		Table(store.productTableName).
+		Where(COLUMN_ID+" = ?", productID).
+		Where(COLUMN_SOFT_DELETED_AT+" = ?", MAX_DATETIME)
+
+	if opts.NotBelowZero {
+		q = q.Where(COLUMN_QUANTITY+" + ? >= 0", delta)
+	}
+
+	result, err := q.Update(map[string]any{
+		COLUMN_QUANTITY:   neatquery.RawExpr(COLUMN_QUANTITY+" + ?", delta),
+		COLUMN_UPDATED_AT: carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC),
+		COLUMN_VERSION:    neatquery.RawExpr(COLUMN_VERSION + " + 1"),
+	})
+	if err != nil {
+		return 0, changeRecords{}, err
+	}
+
+	// read back in the transaction, still holding the row lock taken by the update
+	var results []map[string]any
+	err = statement(tx).
+		Table(store.productTableName).
+		Select([]string{COLUMN_QUANTITY}).
+		Where(COLUMN_ID+" = ?", productID).
+		Where(COLUMN_SOFT_DELETED_AT+" = ?", MAX_DATETIME).
+		Get(&results)
+	if err != nil {
+		return 0, changeRecords{}, err
+	}
+
+	if len(results) == 0 {
+		return 0, changeRecords{}, ErrProductNotFound
+	}
+
+	if result.RowsAffected == 0 {
+		return 0, changeRecords{}, ErrInsufficientQuantity
+	}
+
+	quantity := cast.ToInt64(mapAnyToString(results[0])[COLUMN_QUANTITY])
+
+	events, err := store.productStockLowEvents(productID, int(quantity-delta), int(quantity))
+	if err != nil {
+		return 0, changeRecords{}, err
+	}
+
+	return quantity, changeRecords{
+		auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_PRODUCT, productID, AUDIT_OPERATION_UPDATE,
+			map[string]string{COLUMN_QUANTITY: cast.ToString(quantity - delta)},
+			map[string]string{COLUMN_QUANTITY: cast.ToString(quantity)}),
+		events: events,
+	}, nil
+}
+
 func (store *Store) ProductUpdate(ctx context.Context, product ProductInterface) error {
 	if product == nil {
 		return errors.New("product is nil")
 	}
 
-	product.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString())
+	if err := store.productHooks.runBeforeUpdate(ctx, product, product.DataChanged()); err != nil {
+		return err
+	}
 
-	dataChanged := product.DataChanged()
+	changed := product.DataChanged()
+	if err := store.productUpdate(ctx, product); err != nil {
+		return err
+	}
+
+	store.productHooks.runAfterUpdate(ctx, product, changed)
 
-	delete(dataChanged, COLUMN_ID) // ID is not updateable
-	delete(dataChanged, "hash")    // Hash is not updateable
-	delete(dataChanged, "data")    // Data is not updateable
+	return nil
+}
+
+// ProductUpdateMany sets the fields on all the products matching the query
+// options in a single transaction, returning how many were updated. Only
+// the columns listed in productUpdatableColumns can be set. Versions are
+// incremented without being checked. The AfterUpdate hooks run on the
+// products set, but not the Before hooks, as the products are not loaded.
+func (store *Store) ProductUpdateMany(ctx context.Context, options ProductQueryInterface, fields map[string]string) (int64, error) {
+	if err := assertUpdatable(fields, productUpdatableColumns); err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	if err := store.assertReferencesExist(ctx, fields, store.productReferences()); err != nil {
+		return 0, err
+	}
+
+	ids, rows, err := store.updateManyLoaded(ctx, store.productBulkTable(options), fields, store.productHooks.hasAfterUpdate())
+	if err != nil {
+		return 0, store.operationError("product update many", err)
+	}
+
+	runAfterUpdateRows(ctx, &store.productHooks, rows, NewProductFromExistingData, func(map[string]string) map[string]string {
+		return maps.Clone(fields)
+	})
 
+	return int64(len(ids)), nil
+}
+
+// productUpdate writes the changed fields of product, without running hooks
+func (store *Store) productUpdate(ctx context.Context, product ProductInterface) error {
+	dataChanged := productUpdateData(product)
 	if len(dataChanged) < 1 {
 		return nil
 	}
 
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	if err := store.assertReferencesExist(ctx, dataChanged, store.productReferences()); err != nil {
+		return err
+	}
+
+	var version int64
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		var records changeRecords
+		var err error
+
+		version, records, err = store.productUpdateOn(ctx, tx, product, dataChanged)
+		return records, err
+	})
+
+	if err == nil {
+		product.SetVersion(version)
+		product.MarkAsNotDirty()
+	}
+
+	if err != nil && store.productSKUTaken(ctx, product) {
+		return ErrProductSKUExists
+	}
+
+	return store.operationError("product update", err)
+}
+
+// productUpdateData stamps product as updated and returns its changed
+// fields to write, empty when nothing but the timestamp changed
+func productUpdateData(product ProductInterface) map[string]string {
+	product.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString())
+
+	dataChanged := product.DataChanged()
+
+	delete(dataChanged, COLUMN_ID)      // ID is not updateable
+	delete(dataChanged, "hash")         // Hash is not updateable
+	delete(dataChanged, "data")         // Data is not updateable
+	delete(dataChanged, COLUMN_VERSION) // Version is set by the update itself
+
+	return dataChanged
+}
+
+// productUpdateOn writes the changed fields of product within the
+// transaction tx, returning the new version and the records of the change
+func (store *Store) productUpdateOn(ctx context.Context, tx contractsorm.Query, product ProductInterface, dataChanged map[string]string) (int64, changeRecords, error) {
 	row := map[string]any{}
 	for k, v := range dataChanged {
-		row[k] = v
+		row[k] = rowValue(k, v)
 	}
 
-	_, err := store.db.Query().Table(store.productTableName).Where(COLUMN_ID+" = ?", product.GetID()).Update(row)
+	before, err := store.storedValues(tx, store.productTableName, product.GetID(), lo.Keys(dataChanged))
+	if err != nil {
+		return 0, changeRecords{}, err
+	}
 
-	product.MarkAsNotDirty()
+	events, err := store.productOutboxEvents(product.GetID(), before, dataChanged)
+	if err != nil {
+		return 0, changeRecords{}, err
+	}
 
-	return err
+	version, err := versionedUpdate(ctx, statement(tx).Table(store.productTableName).Where(COLUMN_ID+" = ?", product.GetID()), product.GetVersion(), row)
+	return version, changeRecords{
+		auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_PRODUCT, product.GetID(), updateOperation(dataChanged), before, dataChanged),
+		events:    events,
+	}, err
 }
 
-func (store *Store) productQuery(options ProductQueryInterface) (contractsorm.Query, error) {
+// productSKUTaken reports whether another live product uses the SKU of the
+// product. It tells a violation of the unique SKU index apart from other
+// write failures, as the database error details are not exposed.
+func (store *Store) productSKUTaken(ctx context.Context, product ProductInterface) bool {
+	if product.GetSKU() == "" {
+		return false
+	}
+
+	var count int64
+
+	err := store.query(ctx).
+		Table(store.productTableName).
+		Where(COLUMN_SKU+" = ?", product.GetSKU()).
+		Where(COLUMN_ID+" <> ?", product.GetID()).
+		Where(COLUMN_SOFT_DELETED_AT+" = ?", MAX_DATETIME).
+		Count(&count)
+
+	return err == nil && count > 0
+}
+
+// productBulkTable describes the products matching the query options to
+// the bulk operations
+func (store *Store) productBulkTable(options ProductQueryInterface) bulkTable {
+	return bulkTable{
+		entityType: ENTITY_TYPE_PRODUCT,
+		tableName:  store.productTableName,
+		selectRows: func(q contractsorm.Query) (contractsorm.Query, error) {
+			return store.productQueryOn(q, options)
+		},
+		events: store.productOutboxEvents,
+		dependents: []dependentCheck{
+			{tableName: store.productTableName, column: COLUMN_PARENT_ID, err: ErrProductHasActiveVariants},
+			{tableName: store.orderLineItemTableName, column: COLUMN_PRODUCT_ID, err: ErrProductHasActiveLineItems},
+			{tableName: store.mediaTableName, column: COLUMN_ENTITY_ID, err: ErrProductHasActiveMedia},
+		},
+	}
+}
+
+func (store *Store) productQuery(ctx context.Context, options ProductQueryInterface) (contractsorm.Query, error) {
+	return store.productQueryOn(store.query(ctx), options)
+}
+
+// productQueryOn applies the query options to q, which may run within a transaction
+func (store *Store) productQueryOn(q contractsorm.Query, options ProductQueryInterface) (contractsorm.Query, error) {
 	if options == nil {
 		return nil, errors.New("product options cannot be nil")
 	}
@@ -212,7 +665,7 @@ func (store *Store) productQuery(options ProductQueryInterface) (contractsorm.Qu
 		return nil, err
 	}
 
-	q := store.db.Query().Table(store.productTableName)
+	q = q.Table(store.productTableName)
 
 	if options.HasID() {
 		q = q.Where(COLUMN_ID+" = ?", options.ID())
@@ -235,10 +688,7 @@ func (store *Store) productQuery(options ProductQueryInterface) (contractsorm.Qu
 	}
 
 	if options.HasTitleLike() {
-		searchTerm := strings.ReplaceAll(options.TitleLike(), "'", "''")
-		searchTerm = strings.ReplaceAll(searchTerm, "%", "\\%")
-		searchTerm = strings.ReplaceAll(searchTerm, "_", "\\_")
-		q = q.Where(COLUMN_TITLE+" LIKE ?", "%"+searchTerm+"%")
+		q = q.Where(store.dialect.like(COLUMN_TITLE), likeContains(options.TitleLike()))
 	}
 
 	if options.HasStatus() {
@@ -257,17 +707,41 @@ func (store *Store) productQuery(options ProductQueryInterface) (contractsorm.Qu
 		q = q.Where(COLUMN_PARENT_ID+" = ?", options.ParentID())
 	}
 
+	if options.HasParentIDIn() {
+		parentIDs := make([]any, len(options.ParentIDIn()))
+		for i, parentID := range options.ParentIDIn() {
+			parentIDs[i] = parentID
+		}
+		q = q.WhereIn(COLUMN_PARENT_ID, parentIDs)
+	}
+
+	if options.HasSKU() {
+		q = q.Where(COLUMN_SKU+" = ?", options.SKU())
+	}
+
+	if options.HasSKUIn() {
+		skus := make([]any, len(options.SKUIn()))
+		for i, sku := range options.SKUIn() {
+			skus[i] = sku
+		}
+		q = q.WhereIn(COLUMN_SKU, skus)
+	}
+
+	if options.HasCategoryID() {
+		q = q.Where(COLUMN_CATEGORY_ID+" = ?", options.CategoryID())
+	}
+
 	if options.HasMetasIn() {
 		for key, value := range options.MetasIn() {
-			jsonPath := buildJsonPath(key)
-			q = q.Where("json_extract("+COLUMN_METAS+", '"+jsonPath+"') = ?", value)
+			meta, arg := store.dialect.jsonValue(COLUMN_METAS, key)
+			q = q.Where(meta+" = ?", arg, value)
 		}
 	}
 
 	if options.HasMetasNotIn() {
 		for key, value := range options.MetasNotIn() {
-			jsonPath := buildJsonPath(key)
-			q = q.Where("IFNULL(json_extract("+COLUMN_METAS+", '"+jsonPath+"'), '') != ?", value)
+			meta, arg := store.dialect.jsonValue(COLUMN_METAS, key)
+			q = q.Where("COALESCE("+meta+", '') != ?", arg, value)
 		}
 	}
 
@@ -279,6 +753,52 @@ func (store *Store) productQuery(options ProductQueryInterface) (contractsorm.Qu
 		q = q.Where(COLUMN_CREATED_AT+" <= ?", options.CreatedAtLte())
 	}
 
+	if options.HasUpdatedAtGte() && options.HasUpdatedAtLte() {
+		q = q.Where(COLUMN_UPDATED_AT+" BETWEEN ? AND ?", options.UpdatedAtGte(), options.UpdatedAtLte())
+	} else if options.HasUpdatedAtGte() {
+		q = q.Where(COLUMN_UPDATED_AT+" >= ?", options.UpdatedAtGte())
+	} else if options.HasUpdatedAtLte() {
+		q = q.Where(COLUMN_UPDATED_AT+" <= ?", options.UpdatedAtLte())
+	}
+
+	if options.HasPriceGte() {
+		q = q.Where(COLUMN_PRICE+" >= ?", options.PriceGte())
+	}
+
+	if options.HasPriceLte() {
+		q = q.Where(COLUMN_PRICE+" <= ?", options.PriceLte())
+	}
+
+	if options.HasQuantityGte() {
+		q = q.Where(COLUMN_QUANTITY+" >= ?", options.QuantityGte())
+	}
+
+	if options.HasQuantityLte() {
+		q = q.Where(COLUMN_QUANTITY+" <= ?", options.QuantityLte())
+	}
+
+	if options.InStockOnly() {
+		q = q.Where(COLUMN_QUANTITY+" > ?", 0)
+	}
+
+	// A variant is any product pointing at a parent. Older rows use "0" as
+	// the "no parent" marker (see migration_007), newer rows use "".
+	variantCondition := COLUMN_PARENT_ID + " NOT IN ('', '0')"
+	parentCondition := COLUMN_ID + " IN (SELECT " + COLUMN_PARENT_ID + " FROM " + store.productTableName +
+		" WHERE " + COLUMN_SOFT_DELETED_AT + " = ?)"
+
+	if options.IsVariant() {
+		q = q.Where(variantCondition)
+	}
+
+	if options.IsParent() {
+		q = q.Where("NOT ("+variantCondition+")").Where(parentCondition, MAX_DATETIME)
+	}
+
+	if options.IsSimple() {
+		q = q.Where("NOT ("+variantCondition+")").Where("NOT "+parentCondition, MAX_DATETIME)
+	}
+
 	if !options.IsCountOnly() {
 		if options.HasLimit() {
 			q = q.Limit(cast.ToInt(options.Limit()))
@@ -289,10 +809,16 @@ func (store *Store) productQuery(options ProductQueryInterface) (contractsorm.Qu
 		}
 	}
 
-	sortOrder := lo.Ternary(options.HasSortDirection(), options.SortDirection(), "desc")
+	if options.HasAfterCursor() {
+		var err error
+		q, err = whereAfterCursor(q, store.dialect, keysetSort(options), options.AfterCursor())
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	if options.HasOrderBy() {
-		q = q.OrderBy(options.OrderBy(), sortOrder)
+	if isSorted(options) {
+		q = orderByKeyset(q, keysetSort(options))
 	}
 
 	if !options.SoftDeletedIncluded() {
@@ -351,21 +877,50 @@ func (store *Store) ProductGetParent(ctx context.Context, productID string) (Pro
 	return store.ProductFindByID(ctx, product.GetParentID())
 }
 
+// productParentID returns the ID of the parent of a variant, empty for a
+// product without parent. "0" is the "no parent" marker of older rows.
+func productParentID(product ProductInterface) string {
+	parentID := product.GetParentID()
+	if parentID == "0" {
+		return ""
+	}
+
+	return parentID
+}
+
 // mapAnyToString converts a map[string]any to map[string]string
+// Drivers scanning datetime columns into time.Time (PostgreSQL, MySQL with
+// parseTime) are formatted back to the stored "2006-01-02 15:04:05" form.
 func mapAnyToString(m map[string]any) map[string]string {
 	result := make(map[string]string, len(m))
 	for k, v := range m {
+		if t, ok := v.(time.Time); ok {
+			result[k] = t.Format(time.DateTime)
+			continue
+		}
 		result[k] = cast.ToString(v)
 	}
 	return result
 }
 
-// buildJsonPath builds a SQLite JSON path for a top-level key in the metas column.
+// nullableColumns are written as NULL when empty, as their unique indexes
+// only apply to the rows setting a value
+var nullableColumns = []string{COLUMN_EMAIL, COLUMN_SKU}
+
+// rowValue returns the value to write to the column for the entity data value
+func rowValue(column string, value string) any {
+	if value == "" && slices.Contains(nullableColumns, column) {
+		return nil
+	}
+
+	return value
+}
+
+// buildJsonPath builds a SQLite / MySQL JSON path for a top-level key in the metas column.
 // The key is wrapped in double-quotes inside the path so that special characters
 // (e.g. '.', '"', ']') are treated as literal key names rather than path syntax.
-// Single quotes are doubled to prevent breaking out of the surrounding SQL string literal.
+// The path is bound as a query argument, so it needs no SQL escaping.
 func buildJsonPath(key string) string {
-	escaped := strings.ReplaceAll(key, "'", "''")
-	escaped = strings.ReplaceAll(escaped, `"`, `\"`)
+	escaped := strings.ReplaceAll(key, `"`, `\"`)
 	return "$.\"" + escaped + "\""
 }
@@ -0,0 +1,733 @@
+package shopstore
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// Prefixes of the CSV columns of the product import/export
+const (
+	productCSVParentPrefix  = "parent_"
+	productCSVMetaPrefix    = "meta:"
+	productCSVVariantPrefix = "variant:"
+
+	// productCSVOptionSeparator joins the options of a variant matrix schema
+	// in a single cell
+	productCSVOptionSeparator = "|"
+)
+
+// Columns of the parent's variant matrix schema
+const (
+	productCSVColumnVariantName     = productCSVParentPrefix + "variant_name"
+	productCSVColumnVariantRequired = productCSVParentPrefix + "variant_required"
+	productCSVColumnVariantOptions  = productCSVParentPrefix + "variant_options"
+)
+
+// productCSVFields are the product columns written to CSV, once for the
+// parent (prefixed with parent_) and once for the variant of a row.
+// Timestamps and versions are maintained by the store and not exported.
+var productCSVFields = []string{
+	COLUMN_ID,
+	COLUMN_SKU,
+	COLUMN_TITLE,
+	COLUMN_STATUS,
+	COLUMN_PRICE,
+	COLUMN_QUANTITY,
+	COLUMN_SHORT_DESCRIPTION,
+	COLUMN_DESCRIPTION,
+	COLUMN_MEMO,
+	COLUMN_CATEGORY_ID,
+	COLUMN_TAX_CLASS,
+	COLUMN_WEIGHT,
+	COLUMN_LENGTH,
+	COLUMN_WIDTH,
+	COLUMN_HEIGHT,
+}
+
+// ProductCSVImportOptions configures ProductImportCSV
+type ProductCSVImportOptions struct {
+	// DryRun parses and validates the CSV, reporting the row errors, without
+	// creating any product
+	DryRun bool
+}
+
+// ProductCSVImportReport is the outcome of a CSV import
+type ProductCSVImportReport struct {
+	// Rows is the number of data rows read, the header excluded
+	Rows int
+
+	// Products are the parsed products, parents before their variants
+	Products []ProductInterface
+
+	// Created is the number of products created, 0 on a dry run
+	Created int
+
+	// Errors lists the problems found, by row
+	Errors []ProductCSVRowError
+}
+
+// HasErrors returns true if any row failed to parse or validate
+func (report ProductCSVImportReport) HasErrors() bool {
+	return len(report.Errors) > 0
+}
+
+// ProductCSVRowError is a problem found with a cell of a CSV import. Rows
+// are numbered like in a spreadsheet: the header is row 1.
+type ProductCSVRowError struct {
+	Row     int
+	Column  string
+	Message string
+}
+
+// Error implements the error interface
+func (e ProductCSVRowError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("row %d: %s", e.Row, e.Message)
+	}
+
+	return fmt.Sprintf("row %d, column %s: %s", e.Row, e.Column, e.Message)
+}
+
+// ProductExportCSV writes the products matching the query options to w as
+// CSV, one row per variant. Every row has the columns of the parent
+// (prefixed with parent_, its variant matrix schema included), the columns
+// of the variant, a variant:<name> column per variant matrix value and a
+// meta:<key> column per meta. Products without variants get a single row
+// with the variant columns left empty.
+//
+// A parent is written with all its variants, and variants matched without
+// their parent are written with their parent and its other variants. Products not fitting the two levels of the layout, like
+// variants of variants, fail the export.
+func (store *Store) ProductExportCSV(ctx context.Context, w io.Writer, options ProductQueryInterface) error {
+	if options == nil {
+		return errors.New("product options cannot be nil")
+	}
+
+	products, err := store.ProductList(ctx, options)
+	if err != nil {
+		return err
+	}
+
+	parents := map[string]ProductInterface{}
+	order := []string{}
+	ordered := map[string]struct{}{}
+
+	for _, product := range products {
+		parentID := productParentID(product)
+		if parentID == "" {
+			parentID = product.GetID()
+			parents[parentID] = product
+		}
+
+		if _, ok := ordered[parentID]; !ok {
+			ordered[parentID] = struct{}{}
+			order = append(order, parentID)
+		}
+	}
+
+	missing := lo.Filter(order, func(id string, _ int) bool {
+		_, ok := parents[id]
+		return !ok
+	})
+
+	for _, batch := range lo.Chunk(missing, bulkBatchSize) {
+		list, err := store.ProductList(ctx, NewProductQuery().SetIDIn(batch))
+		if err != nil {
+			return err
+		}
+
+		for _, parent := range list {
+			parents[parent.GetID()] = parent
+		}
+	}
+
+	// a parent is exported with all its variants, matched by options or not
+	variants := map[string][]ProductInterface{}
+
+	for _, batch := range lo.Chunk(order, bulkBatchSize) {
+		list, err := store.ProductList(ctx, NewProductQuery().SetParentIDIn(batch))
+		if err != nil {
+			return err
+		}
+
+		for _, variant := range list {
+			variants[variant.GetParentID()] = append(variants[variant.GetParentID()], variant)
+		}
+	}
+
+	layout := productCSVLayout{}
+	for _, parentID := range order {
+		parent, ok := parents[parentID]
+		if !ok {
+			return fmt.Errorf("product export csv. parent %s not found", parentID)
+		}
+
+		if err := layout.addParent(parent); err != nil {
+			return err
+		}
+
+		for _, variant := range variants[parentID] {
+			if err := layout.addVariant(variant); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer := csv.NewWriter(w)
+
+	header := layout.header()
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, parentID := range order {
+		rows := variants[parentID]
+		if len(rows) == 0 {
+			rows = []ProductInterface{nil}
+		}
+
+		for _, variant := range rows {
+			record, err := productCSVRecord(header, parents[parentID], variant)
+			if err != nil {
+				return err
+			}
+
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ProductImportCSV reads products from CSV in the layout written by
+// ProductExportCSV and creates them, in a single transaction.
+//
+// The rows of a parent are grouped by parent_id or, for a new catalog, by
+// parent_sku. The parent columns are read from the first row of a parent;
+// the following rows may leave them empty, but not set them to other values.
+// Empty variant columns make a row of a parent without variants. Products
+// without an ID get a new one.
+//
+// Every cell is validated first: statuses, prices and quantities, the
+// variant matrix values against the schema of their parent, and IDs and
+// SKUs, which must be unique and not taken by a stored product. Any row
+// error fails the import with ErrInvalidProductCSV, before anything is
+// written. A dry run only validates, reporting the row errors with a nil
+// error. Malformed CSV fails either way.
+func (store *Store) ProductImportCSV(ctx context.Context, r io.Reader, options ProductCSVImportOptions) (ProductCSVImportReport, error) {
+	report := ProductCSVImportReport{
+		Products: []ProductInterface{},
+		Errors:   []ProductCSVRowError{},
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return report, errors.New("product import csv. header is missing")
+	}
+	if err != nil {
+		return report, err
+	}
+
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff") // spreadsheet byte order mark
+	}
+
+	parser := newProductCSVParser(header)
+	headerValid := len(parser.errors) == 0
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return report, err
+		}
+
+		report.Rows++
+
+		if headerValid {
+			parser.parse(report.Rows+1, record)
+		}
+	}
+
+	if headerValid {
+		if err := store.productCSVAssertNew(ctx, parser); err != nil {
+			return report, err
+		}
+	}
+
+	report.Errors = append(report.Errors, parser.errors...)
+	report.Products = parser.products()
+
+	if options.DryRun {
+		return report, nil
+	}
+
+	if report.HasErrors() {
+		return report, fmt.Errorf("%w: %d row errors, first %s", ErrInvalidProductCSV, len(report.Errors), report.Errors[0].Error())
+	}
+
+	if err := store.ProductCreateMany(ctx, report.Products); err != nil {
+		return report, err
+	}
+
+	report.Created = len(report.Products)
+
+	return report, nil
+}
+
+// productCSVAssertNew reports the parsed products whose ID or SKU is taken
+// by a stored product
+func (store *Store) productCSVAssertNew(ctx context.Context, parser *productCSVParser) error {
+	checks := []struct {
+		column string
+		query  func(values []string) ProductQueryInterface
+		value  func(product ProductInterface) string
+	}{
+		// soft deleted rows keep their ID
+		{COLUMN_ID, func(values []string) ProductQueryInterface {
+			return NewProductQuery().SetIDIn(values).SetSoftDeletedIncluded(true)
+		}, ProductInterface.GetID},
+		{COLUMN_SKU, func(values []string) ProductQueryInterface {
+			return NewProductQuery().SetSKUIn(values)
+		}, ProductInterface.GetSKU},
+	}
+
+	for _, check := range checks {
+		values := lo.Compact(lo.Map(parser.entries, func(entry productCSVEntry, _ int) string {
+			return check.value(entry.product)
+		}))
+
+		taken := map[string]struct{}{}
+		for _, batch := range lo.Chunk(values, bulkBatchSize) {
+			list, err := store.ProductList(ctx, check.query(batch))
+			if err != nil {
+				return err
+			}
+
+			for _, product := range list {
+				taken[check.value(product)] = struct{}{}
+			}
+		}
+
+		for _, entry := range parser.entries {
+			if _, ok := taken[check.value(entry.product)]; ok {
+				parser.addError(entry.row, entry.prefix+check.column, "a product with this "+check.column+" already exists")
+			}
+		}
+	}
+
+	return nil
+}
+
+// == EXPORT ===================================================================
+
+// productCSVLayout collects the dynamic columns of an export
+type productCSVLayout struct {
+	parentMetas  []string
+	variantNames []string
+	variantMetas []string
+}
+
+// addParent checks that the parent fits the layout and collects its columns
+func (layout *productCSVLayout) addParent(parent ProductInterface) error {
+	if productParentID(parent) != "" {
+		return fmt.Errorf("product export csv. product %s is a variant of a variant", parent.GetID())
+	}
+
+	values, err := parent.GetVariantMatrixValues()
+	if err != nil {
+		return err
+	}
+
+	if len(values) > 0 {
+		return fmt.Errorf("product export csv. product %s has variant matrix values but no parent", parent.GetID())
+	}
+
+	schema, err := parent.GetVariantMatrixSchema()
+	if err != nil {
+		return err
+	}
+
+	for _, option := range schema.Options {
+		if strings.Contains(option, productCSVOptionSeparator) {
+			return fmt.Errorf("product export csv. product %s has a variant option containing %q", parent.GetID(), productCSVOptionSeparator)
+		}
+	}
+
+	metas, err := parent.GetMetas()
+	if err != nil {
+		return err
+	}
+
+	layout.parentMetas = append(layout.parentMetas, slices.Collect(maps.Keys(metas))...)
+
+	return nil
+}
+
+// addVariant checks that the variant fits the layout and collects its columns
+func (layout *productCSVLayout) addVariant(variant ProductInterface) error {
+	schema, err := variant.GetVariantMatrixSchema()
+	if err != nil {
+		return err
+	}
+
+	if schema.Name != "" || schema.Required || len(schema.Options) > 0 {
+		return fmt.Errorf("product export csv. variant %s has a variant matrix schema", variant.GetID())
+	}
+
+	values, err := variant.GetVariantMatrixValues()
+	if err != nil {
+		return err
+	}
+
+	metas, err := variant.GetMetas()
+	if err != nil {
+		return err
+	}
+
+	layout.variantNames = append(layout.variantNames, slices.Collect(maps.Keys(values))...)
+	layout.variantMetas = append(layout.variantMetas, slices.Collect(maps.Keys(metas))...)
+
+	return nil
+}
+
+// header returns the columns of the export: the parent, its schema and
+// metas, then the variant, its matrix values and metas
+func (layout *productCSVLayout) header() []string {
+	prefixed := func(prefix string, names []string) []string {
+		names = lo.Uniq(names)
+		slices.Sort(names)
+
+		return lo.Map(names, func(name string, _ int) string {
+			return prefix + name
+		})
+	}
+
+	header := prefixed(productCSVParentPrefix, productCSVFields)
+	header = append(header, productCSVColumnVariantName, productCSVColumnVariantRequired, productCSVColumnVariantOptions)
+	header = append(header, prefixed(productCSVParentPrefix+productCSVMetaPrefix, layout.parentMetas)...)
+	header = append(header, productCSVFields...)
+	header = append(header, prefixed(productCSVVariantPrefix, layout.variantNames)...)
+	header = append(header, prefixed(productCSVMetaPrefix, layout.variantMetas)...)
+
+	return header
+}
+
+// productCSVRecord returns the cells of the row of a variant, or of a
+// parent without variants when variant is nil
+func productCSVRecord(header []string, parent ProductInterface, variant ProductInterface) ([]string, error) {
+	cells := map[string]string{}
+
+	schema, err := parent.GetVariantMatrixSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	cells[productCSVColumnVariantName] = schema.Name
+	cells[productCSVColumnVariantRequired] = strconv.FormatBool(schema.Required)
+	cells[productCSVColumnVariantOptions] = strings.Join(schema.Options, productCSVOptionSeparator)
+
+	if err := productCSVCells(cells, productCSVParentPrefix, parent); err != nil {
+		return nil, err
+	}
+
+	if variant != nil {
+		if err := productCSVCells(cells, "", variant); err != nil {
+			return nil, err
+		}
+
+		values, err := variant.GetVariantMatrixValues()
+		if err != nil {
+			return nil, err
+		}
+
+		for name, value := range values {
+			cells[productCSVVariantPrefix+name] = value
+		}
+	}
+
+	return lo.Map(header, func(column string, _ int) string {
+		return cells[column]
+	}), nil
+}
+
+// productCSVCells sets the cells of the fields and metas of a product, with
+// the prefix of its columns
+func productCSVCells(cells map[string]string, prefix string, product ProductInterface) error {
+	data := product.Data()
+	for _, field := range productCSVFields {
+		cells[prefix+field] = data[field]
+	}
+
+	metas, err := product.GetMetas()
+	if err != nil {
+		return err
+	}
+
+	for key, value := range metas {
+		cells[prefix+productCSVMetaPrefix+key] = value
+	}
+
+	return nil
+}
+
+// == IMPORT ===================================================================
+
+// productCSVEntry is a product parsed from CSV, with the first row setting it
+type productCSVEntry struct {
+	product ProductInterface
+	row     int
+
+	// prefix of the columns of the product, parent_ for a parent
+	prefix string
+}
+
+// productCSVParent is a parent parsed from CSV, with the cells it was read from
+type productCSVParent struct {
+	productCSVEntry
+	schema   VariantMatrixSchema
+	cells    map[string]string
+	variants []productCSVEntry
+}
+
+// productCSVParser builds the products of the rows of a CSV import
+type productCSVParser struct {
+	header  []string
+	parents map[string]*productCSVParent
+	order   []string
+	entries []productCSVEntry
+	ids     map[string]int
+	skus    map[string]int
+	errors  []ProductCSVRowError
+}
+
+// newProductCSVParser returns a parser of the rows following the header,
+// reporting the unknown and duplicate columns of the header
+func newProductCSVParser(header []string) *productCSVParser {
+	parser := &productCSVParser{
+		header:  header,
+		parents: map[string]*productCSVParent{},
+		ids:     map[string]int{},
+		skus:    map[string]int{},
+	}
+
+	known := append(lo.Map(productCSVFields, func(field string, _ int) string {
+		return productCSVParentPrefix + field
+	}), productCSVFields...)
+	known = append(known, productCSVColumnVariantName, productCSVColumnVariantRequired, productCSVColumnVariantOptions)
+
+	prefixes := []string{productCSVParentPrefix + productCSVMetaPrefix, productCSVMetaPrefix, productCSVVariantPrefix}
+
+	for _, column := range lo.FindDuplicates(header) {
+		parser.addError(1, column, "duplicate column")
+	}
+
+	for _, column := range header {
+		if slices.Contains(known, column) {
+			continue
+		}
+
+		prefix, ok := lo.Find(prefixes, func(prefix string) bool {
+			return strings.HasPrefix(column, prefix)
+		})
+
+		if !ok || column == prefix {
+			parser.addError(1, column, "unknown column")
+		}
+	}
+
+	return parser
+}
+
+// addError reports a problem with a cell
+func (parser *productCSVParser) addError(row int, column string, message string) {
+	parser.errors = append(parser.errors, ProductCSVRowError{Row: row, Column: column, Message: message})
+}
+
+// products returns the parsed products, parents before their variants
+func (parser *productCSVParser) products() []ProductInterface {
+	products := []ProductInterface{}
+
+	for _, key := range parser.order {
+		parent := parser.parents[key]
+		products = append(products, parent.product)
+
+		for _, variant := range parent.variants {
+			products = append(products, variant.product)
+		}
+	}
+
+	return products
+}
+
+// parse parses the data row of the record
+func (parser *productCSVParser) parse(row int, record []string) {
+	if len(record) != len(parser.header) {
+		parser.addError(row, "", fmt.Sprintf("expected %d cells, got %d", len(parser.header), len(record)))
+		return
+	}
+
+	parentCells := map[string]string{}
+	variantCells := map[string]string{}
+
+	for i, column := range parser.header {
+		if strings.HasPrefix(column, productCSVParentPrefix) {
+			parentCells[column] = record[i]
+		} else {
+			variantCells[column] = record[i]
+		}
+	}
+
+	parent := parser.parent(row, parentCells)
+
+	if lo.EveryBy(lo.Values(variantCells), func(cell string) bool { return cell == "" }) {
+		return // a parent without variants
+	}
+
+	variant := parser.product(row, "", variantCells)
+	variant.product.SetParentID(parent.product.GetID())
+
+	values := map[string]string{}
+	for column, cell := range variantCells {
+		if name, ok := strings.CutPrefix(column, productCSVVariantPrefix); ok && cell != "" {
+			values[name] = cell
+		}
+	}
+
+	if parent.schema.Name != "" {
+		value, column := values[parent.schema.Name], productCSVVariantPrefix+parent.schema.Name
+
+		switch {
+		case value == "" && parent.schema.Required:
+			parser.addError(row, column, "is required by the variant matrix schema of the parent")
+		case value != "" && len(parent.schema.Options) > 0 && !slices.Contains(parent.schema.Options, value):
+			parser.addError(row, column, fmt.Sprintf("%q is not one of the options %s", value, strings.Join(parent.schema.Options, ", ")))
+		}
+	}
+
+	_ = variant.product.SetVariantMatrixValues(values)
+
+	parent.variants = append(parent.variants, variant)
+	parser.entries = append(parser.entries, variant)
+}
+
+// parent returns the parent of the row, parsing it on its first row and
+// checking on the following rows that its cells are left empty or unchanged
+func (parser *productCSVParser) parent(row int, cells map[string]string) *productCSVParent {
+	key := "id:" + cells[productCSVParentPrefix+COLUMN_ID]
+	if cells[productCSVParentPrefix+COLUMN_ID] == "" {
+		key = "sku:" + cells[productCSVParentPrefix+COLUMN_SKU]
+	}
+	if key == "sku:" {
+		key = "row:" + strconv.Itoa(row) // nothing to group the rows by
+	}
+
+	if parent, ok := parser.parents[key]; ok {
+		for _, column := range parser.header {
+			cell, ok := cells[column]
+			if ok && cell != "" && cell != parent.cells[column] {
+				parser.addError(row, column, fmt.Sprintf("differs from row %d of the same parent", parent.row))
+			}
+		}
+
+		return parent
+	}
+
+	entry := parser.product(row, productCSVParentPrefix, cells)
+	parent := &productCSVParent{productCSVEntry: entry, cells: cells}
+
+	options := lo.Compact(strings.Split(cells[productCSVColumnVariantOptions], productCSVOptionSeparator))
+
+	required := false
+	if cell := cells[productCSVColumnVariantRequired]; cell != "" {
+		var err error
+		if required, err = strconv.ParseBool(cell); err != nil {
+			parser.addError(row, productCSVColumnVariantRequired, fmt.Sprintf("%q is not true or false", cell))
+		}
+	}
+
+	parent.schema = VariantMatrixSchema{Name: cells[productCSVColumnVariantName], Required: required, Options: options}
+	if parent.schema.Name == "" && (required || len(options) > 0) {
+		parser.addError(row, productCSVColumnVariantName, "is required by the options and required flag of the variant matrix schema")
+	}
+
+	_ = parent.product.SetVariantMatrixSchema(parent.schema)
+
+	parser.parents[key] = parent
+	parser.order = append(parser.order, key)
+	parser.entries = append(parser.entries, parent.productCSVEntry)
+
+	return parent
+}
+
+// product builds a product from the cells of its columns, with the prefix,
+// reporting the invalid cells
+func (parser *productCSVParser) product(row int, prefix string, cells map[string]string) productCSVEntry {
+	data := NewProduct().Data()
+	for _, field := range productCSVFields {
+		if cell := cells[prefix+field]; cell != "" {
+			data[field] = cell
+		}
+	}
+
+	metas := map[string]string{}
+	for column, cell := range cells {
+		if key, ok := strings.CutPrefix(column, prefix+productCSVMetaPrefix); ok && cell != "" {
+			metas[key] = cell
+		}
+	}
+
+	product := NewProductFromExistingData(data)
+	_ = product.SetMetas(metas)
+
+	if status := product.GetStatus(); !slices.Contains([]string{PRODUCT_STATUS_ACTIVE, PRODUCT_STATUS_DISABLED, PRODUCT_STATUS_DRAFT}, status) {
+		parser.addError(row, prefix+COLUMN_STATUS, fmt.Sprintf("%q is not a product status", status))
+	}
+
+	if _, err := strconv.ParseFloat(product.GetPrice(), 64); err != nil {
+		parser.addError(row, prefix+COLUMN_PRICE, fmt.Sprintf("%q is not a number", product.GetPrice()))
+	}
+
+	if _, err := strconv.ParseInt(product.GetQuantity(), 10, 64); err != nil {
+		parser.addError(row, prefix+COLUMN_QUANTITY, fmt.Sprintf("%q is not a whole number", product.GetQuantity()))
+	}
+
+	for _, column := range []string{COLUMN_WEIGHT, COLUMN_LENGTH, COLUMN_WIDTH, COLUMN_HEIGHT} {
+		if _, err := strconv.ParseFloat(data[column], 64); err != nil {
+			parser.addError(row, prefix+column, fmt.Sprintf("%q is not a number", data[column]))
+		}
+	}
+
+	if previous, ok := parser.ids[product.GetID()]; ok {
+		parser.addError(row, prefix+COLUMN_ID, fmt.Sprintf("is already used on row %d", previous))
+	} else {
+		parser.ids[product.GetID()] = row
+	}
+
+	if sku := product.GetSKU(); sku != "" {
+		if previous, ok := parser.skus[sku]; ok {
+			parser.addError(row, prefix+COLUMN_SKU, fmt.Sprintf("is already used on row %d", previous))
+		} else {
+			parser.skus[sku] = row
+		}
+	}
+
+	return productCSVEntry{product: product, row: row, prefix: prefix}
+}
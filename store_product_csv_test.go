@@ -0,0 +1,199 @@
+package shopstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"maps"
+	"strings"
+	"testing"
+)
+
+func TestStoreProductCSV_RoundTrip(t *testing.T) {
+	store := initIntegrityStore(t)
+	ctx := context.Background()
+
+	parent := NewProduct().SetTitle("Shirt").SetSKU("SHIRT").SetStatus(PRODUCT_STATUS_ACTIVE).SetDescription("Cotton, \"soft\"\nwashable")
+	_ = parent.SetVariantMatrixSchema(VariantMatrixSchema{Name: "size", Required: true, Options: []string{"S", "M"}})
+	_ = parent.SetMeta("brand", "Acme")
+
+	small := NewProduct().SetTitle("Shirt S").SetSKU("SHIRT-S").SetParentID(parent.GetID()).SetPriceFloat(19.99).SetQuantityInt(3)
+	_ = small.SetVariantMatrixValues(map[string]string{"size": "S", "fit": "slim"})
+	_ = small.SetMeta("barcode", "0001")
+
+	medium := NewProduct().SetTitle("Shirt M").SetSKU("SHIRT-M").SetParentID(parent.GetID()).SetPriceFloat(21).SetMemo("restock")
+	_ = medium.SetVariantMatrixValues(map[string]string{"size": "M"})
+
+	mug := NewProduct().SetTitle("Mug").SetPriceFloat(8.5).SetQuantityInt(40)
+
+	if err := store.ProductCreateMany(ctx, []ProductInterface{parent, small, medium, mug}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	var buffer bytes.Buffer
+	if err := store.ProductExportCSV(ctx, &buffer, NewProductQuery()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if !strings.Contains(lines[0], "parent_variant_options,parent_meta:brand,id,") || !strings.HasSuffix(lines[0], "variant:fit,variant:size,meta:barcode") {
+		t.Fatalf("unexpected header %q", lines[0])
+	}
+
+	imported := initIntegrityStore(t)
+
+	report, err := imported.ProductImportCSV(ctx, bytes.NewReader(buffer.Bytes()), ProductCSVImportOptions{})
+	if err != nil {
+		t.Fatal("unexpected error:", err, report.Errors)
+	}
+
+	if report.Rows != 3 || report.Created != 4 {
+		t.Fatalf("expected 3 rows creating 4 products, got %+v", report)
+	}
+
+	for _, product := range []ProductInterface{parent, small, medium, mug} {
+		found, err := imported.ProductFindByID(ctx, product.GetID())
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		if found == nil {
+			t.Fatalf("product %s not imported", product.GetTitle())
+		}
+
+		for column, value := range product.Data() {
+			if column == COLUMN_CREATED_AT || column == COLUMN_UPDATED_AT {
+				continue
+			}
+
+			if !productValuesEqual(column, found.Data()[column], value) {
+				t.Fatalf("product %s: expected %s %q, got %q", product.GetTitle(), column, value, found.Data()[column])
+			}
+		}
+	}
+
+	schema, _ := mustFindProduct(t, imported, parent.GetID()).GetVariantMatrixSchema()
+	if schema.Name != "size" || !schema.Required || len(schema.Options) != 2 {
+		t.Fatalf("unexpected variant matrix schema %+v", schema)
+	}
+
+	values, _ := mustFindProduct(t, imported, small.GetID()).GetVariantMatrixValues()
+	if !maps.Equal(values, map[string]string{"size": "S", "fit": "slim"}) {
+		t.Fatalf("unexpected variant matrix values %v", values)
+	}
+
+	// the products now exist, so importing them again fails
+	_, err = imported.ProductImportCSV(ctx, bytes.NewReader(buffer.Bytes()), ProductCSVImportOptions{})
+	if !errors.Is(err, ErrInvalidProductCSV) {
+		t.Fatalf("expected ErrInvalidProductCSV, got %v", err)
+	}
+}
+
+func TestStoreProductExportCSV_ExportsAllVariantsOfParents(t *testing.T) {
+	store := initIntegrityStore(t)
+	ctx := context.Background()
+
+	parent := NewProduct().SetTitle("Shirt").SetSKU("SHIRT")
+	small := NewProduct().SetTitle("Shirt S").SetSKU("SHIRT-S").SetParentID(parent.GetID())
+	medium := NewProduct().SetTitle("Shirt M").SetSKU("SHIRT-M").SetParentID(parent.GetID())
+	mug := NewProduct().SetTitle("Mug").SetSKU("MUG")
+
+	if err := store.ProductCreateMany(ctx, []ProductInterface{parent, small, medium, mug}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	queries := map[string]ProductQueryInterface{
+		"parent":  NewProductQuery().SetID(parent.GetID()),
+		"variant": NewProductQuery().SetSKU("SHIRT-S"),
+	}
+
+	for name, query := range queries {
+		var buffer bytes.Buffer
+		if err := store.ProductExportCSV(ctx, &buffer, query); err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		csv := buffer.String()
+		lines := strings.Split(strings.TrimSpace(csv), "\n")
+		if len(lines) != 3 || !strings.Contains(csv, "SHIRT-S") || !strings.Contains(csv, "SHIRT-M") || strings.Contains(csv, "MUG") {
+			t.Fatalf("%s: expected the header and a row per shirt variant, got %q", name, csv)
+		}
+	}
+}
+
+func TestStoreProductImportCSV_DryRun(t *testing.T) {
+	store := initIntegrityStore(t)
+	ctx := context.Background()
+
+	if err := store.ProductCreate(ctx, NewProduct().SetTitle("Cap").SetSKU("CAP")); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	csv := strings.Join([]string{
+		"parent_sku,parent_title,parent_variant_name,parent_variant_required,parent_variant_options,sku,title,status,price,variant:size",
+		"TEE,Tee,size,true,S|M,TEE-S,Tee S,active,10,S",
+		"TEE,,,,,TEE-M,Tee M,active,10,XL",
+		"TEE,,,,,TEE-L,Tee L,published,ten,",
+		"TEE,Other tee,,,,TEE-S,Tee S again,active,10,M",
+		"MUG,Mug,,,,,,,,",
+		"CAP,Cap,,,,,,,,",
+	}, "\n")
+
+	report, err := store.ProductImportCSV(ctx, strings.NewReader(csv), ProductCSVImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if report.Rows != 6 || report.Created != 0 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	expected := []ProductCSVRowError{
+		{Row: 3, Column: "variant:size"},
+		{Row: 4, Column: "status"},
+		{Row: 4, Column: "price"},
+		{Row: 4, Column: "variant:size"},
+		{Row: 5, Column: "parent_title"},
+		{Row: 5, Column: "sku"},
+		{Row: 7, Column: "parent_sku"},
+	}
+
+	if len(report.Errors) != len(expected) {
+		t.Fatalf("expected %d errors, got %v", len(expected), report.Errors)
+	}
+
+	for i, e := range expected {
+		if report.Errors[i].Row != e.Row || report.Errors[i].Column != e.Column {
+			t.Fatalf("expected error %d on row %d, column %s, got %v", i, e.Row, e.Column, report.Errors[i])
+		}
+	}
+
+	count, err := store.ProductCount(ctx, NewProductQuery())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if count != 1 {
+		t.Fatalf("expected the dry run to create nothing, got %d products", count)
+	}
+
+	report, err = store.ProductImportCSV(ctx, strings.NewReader("sku,colour\nA,red"), ProductCSVImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(report.Errors) != 1 || report.Errors[0].Row != 1 || report.Errors[0].Column != "colour" {
+		t.Fatalf("expected the unknown column reported, got %v", report.Errors)
+	}
+}
+
+func mustFindProduct(t *testing.T, store *Store, id string) ProductInterface {
+	t.Helper()
+
+	product, err := store.ProductFindByID(context.Background(), id)
+	if err != nil || product == nil {
+		t.Fatalf("product %s not found: %v", id, err)
+	}
+
+	return product
+}
@@ -0,0 +1,416 @@
+package shopstore
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// PRODUCT_FEED_NAMESPACE is the XML namespace of the Google Merchant attributes
+const PRODUCT_FEED_NAMESPACE = "http://base.google.com/ns/1.0"
+
+// Availability of the items of a product feed
+const (
+	PRODUCT_FEED_AVAILABILITY_IN_STOCK     = "in_stock"
+	PRODUCT_FEED_AVAILABILITY_OUT_OF_STOCK = "out_of_stock"
+)
+
+// productFeedCategorySeparator joins the titles of a category path
+const productFeedCategorySeparator = " > "
+
+// productFeedAttributes maps the variant matrix value names to the variant
+// attributes of the feeds. Other variant matrix values are left out.
+var productFeedAttributes = map[string]string{
+	"age_group": "age_group",
+	"color":     "color",
+	"colour":    "color",
+	"gender":    "gender",
+	"material":  "material",
+	"pattern":   "pattern",
+	"size":      "size",
+}
+
+// ProductFeedOptions configures the product feeds
+type ProductFeedOptions struct {
+	// Query selects the products of the feed, the active products when nil
+	Query ProductQueryInterface
+
+	// Currency is the ISO 4217 code of the prices, like "USD"
+	Currency string
+
+	// Link returns the URL of the page of a product in the shop
+	Link func(product ProductInterface) string
+
+	// Title, link and description of the channel of the XML feed
+	ChannelTitle       string
+	ChannelLink        string
+	ChannelDescription string
+}
+
+// ProductFeedItem is an item of a product feed: a product without variants,
+// or a variant of a product, which shares the ItemGroupID of its siblings
+type ProductFeedItem struct {
+	ID           string
+	ItemGroupID  string
+	Title        string
+	Description  string
+	Link         string
+	ImageLink    string
+	Availability string
+	Price        string
+
+	// ProductType is the path of the category, like "Apparel > Shirts"
+	ProductType string
+
+	// Attributes are the variant attributes, like "color" and "size"
+	Attributes map[string]string
+}
+
+// ProductFeed returns the items of the feed of the products matching the
+// feed query. Products with variants are not items themselves: each of
+// their variants is, with the ID of the parent as item group ID, falling
+// back to the title, description, image and category of the parent.
+//
+// Items are identified by SKU, or by ID for products without one. The image
+// is the active image media of the product with the lowest sequence.
+func (store *Store) ProductFeed(ctx context.Context, options ProductFeedOptions) ([]ProductFeedItem, error) {
+	if options.Currency == "" {
+		return nil, errors.New("product feed. currency is required")
+	}
+
+	if options.Link == nil {
+		return nil, errors.New("product feed. link is required")
+	}
+
+	query := options.Query
+	if query == nil {
+		query = NewProductQuery().SetStatus(PRODUCT_STATUS_ACTIVE)
+	}
+
+	products, err := store.ProductList(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := lo.Map(products, func(product ProductInterface, _ int) string {
+		return product.GetID()
+	})
+
+	withVariants, err := store.productFeedList(ctx, ids, func(q ProductQueryInterface) ProductQueryInterface {
+		return q.SetIsParent(true)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	products = lo.Reject(products, func(product ProductInterface, _ int) bool {
+		_, ok := withVariants[product.GetID()]
+		return ok
+	})
+
+	parentIDs := lo.Uniq(lo.FilterMap(products, func(product ProductInterface, _ int) (string, bool) {
+		return productParentID(product), productParentID(product) != ""
+	}))
+
+	parents, err := store.productFeedList(ctx, parentIDs, func(q ProductQueryInterface) ProductQueryInterface {
+		return q
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := store.productFeedImages(ctx, append(lo.Keys(parents), lo.Map(products, func(product ProductInterface, _ int) string {
+		return product.GetID()
+	})...))
+	if err != nil {
+		return nil, err
+	}
+
+	categoryIDs := lo.Uniq(lo.FilterMap(slices.Concat(products, lo.Values(parents)), func(product ProductInterface, _ int) (string, bool) {
+		return product.GetCategoryID(), product.GetCategoryID() != ""
+	}))
+
+	categoryPaths, err := store.productFeedCategoryPaths(ctx, categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []ProductFeedItem{}
+
+	for _, product := range products {
+		parent := parents[productParentID(product)]
+
+		// fallback returns the value of the product, or of its parent when empty
+		fallback := func(value func(product ProductInterface) string) string {
+			if value(product) != "" || parent == nil {
+				return value(product)
+			}
+
+			return value(parent)
+		}
+
+		item := ProductFeedItem{
+			ID:    productFeedID(product),
+			Title: fallback(ProductInterface.GetTitle),
+			Description: fallback(func(product ProductInterface) string {
+				return lo.CoalesceOrEmpty(product.GetDescription(), product.GetShortDescription())
+			}),
+			Link:         options.Link(product),
+			ImageLink:    lo.CoalesceOrEmpty(images[product.GetID()], images[productParentID(product)]),
+			Availability: lo.Ternary(product.HasStock(), PRODUCT_FEED_AVAILABILITY_IN_STOCK, PRODUCT_FEED_AVAILABILITY_OUT_OF_STOCK),
+			Price:        fmt.Sprintf("%.2f %s", product.GetPriceFloat(), options.Currency),
+			ProductType:  categoryPaths[fallback(ProductInterface.GetCategoryID)],
+			Attributes:   map[string]string{},
+		}
+
+		if parent != nil {
+			item.ItemGroupID = productFeedID(parent)
+		}
+
+		values, err := product.GetVariantMatrixValues()
+		if err != nil {
+			return nil, err
+		}
+
+		for name, value := range values {
+			if attribute, ok := productFeedAttributes[strings.ToLower(name)]; ok && value != "" {
+				item.Attributes[attribute] = value
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// ProductFeedXML writes the product feed as a Google Merchant RSS 2.0 feed.
+// See ProductFeed.
+func (store *Store) ProductFeedXML(ctx context.Context, w io.Writer, options ProductFeedOptions) error {
+	items, err := store.ProductFeed(ctx, options)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	rss := xml.StartElement{Name: xml.Name{Local: "rss"}, Attr: []xml.Attr{
+		{Name: xml.Name{Local: "version"}, Value: "2.0"},
+		{Name: xml.Name{Local: "xmlns:g"}, Value: PRODUCT_FEED_NAMESPACE},
+	}}
+	channel := xml.StartElement{Name: xml.Name{Local: "channel"}}
+
+	tokens := []xml.Token{rss, channel}
+	tokens = append(tokens, xmlElement("title", options.ChannelTitle)...)
+	tokens = append(tokens, xmlElement("link", options.ChannelLink)...)
+	tokens = append(tokens, xmlElement("description", options.ChannelDescription)...)
+
+	for _, item := range items {
+		element := xml.StartElement{Name: xml.Name{Local: "item"}}
+		tokens = append(tokens, element)
+
+		for _, field := range productFeedFields(item) {
+			if field[1] != "" {
+				tokens = append(tokens, xmlElement("g:"+field[0], field[1])...)
+			}
+		}
+
+		tokens = append(tokens, element.End())
+	}
+
+	tokens = append(tokens, channel.End(), rss.End())
+
+	for _, token := range tokens {
+		if err := encoder.EncodeToken(token); err != nil {
+			return err
+		}
+	}
+
+	return encoder.Flush()
+}
+
+// ProductFeedCSV writes the product feed as CSV, with a column per Google
+// Merchant attribute. See ProductFeed.
+func (store *Store) ProductFeedCSV(ctx context.Context, w io.Writer, options ProductFeedOptions) error {
+	items, err := store.ProductFeed(ctx, options)
+	if err != nil {
+		return err
+	}
+
+	attributes := lo.Uniq(lo.FlatMap(items, func(item ProductFeedItem, _ int) []string {
+		return lo.Keys(item.Attributes)
+	}))
+	slices.Sort(attributes)
+
+	header := lo.Map(productFeedFields(ProductFeedItem{}), func(field [2]string, _ int) string {
+		return field[0]
+	})
+	header = append(header, attributes...)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		record := lo.Map(productFeedFields(item), func(field [2]string, _ int) string {
+			return field[1]
+		})
+
+		for _, attribute := range attributes {
+			record = append(record, item.Attributes[attribute])
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// productFeedFields returns the attribute names and values of the item, in
+// feed order, the variant attributes last, sorted by name
+func productFeedFields(item ProductFeedItem) [][2]string {
+	fields := [][2]string{
+		{"id", item.ID},
+		{"title", item.Title},
+		{"description", item.Description},
+		{"link", item.Link},
+		{"image_link", item.ImageLink},
+		{"availability", item.Availability},
+		{"price", item.Price},
+		{"item_group_id", item.ItemGroupID},
+		{"product_type", item.ProductType},
+	}
+
+	names := lo.Keys(item.Attributes)
+	slices.Sort(names)
+
+	for _, name := range names {
+		fields = append(fields, [2]string{name, item.Attributes[name]})
+	}
+
+	return fields
+}
+
+// productFeedID returns the ID of the item of a product in the feeds
+func productFeedID(product ProductInterface) string {
+	return lo.CoalesceOrEmpty(product.GetSKU(), product.GetID())
+}
+
+// productFeedList returns the products with the IDs matching the query
+// options set by filter, by ID
+func (store *Store) productFeedList(ctx context.Context, ids []string, filter func(q ProductQueryInterface) ProductQueryInterface) (map[string]ProductInterface, error) {
+	products := map[string]ProductInterface{}
+
+	for _, batch := range lo.Chunk(ids, bulkBatchSize) {
+		list, err := store.ProductList(ctx, filter(NewProductQuery().SetIDIn(batch)))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, product := range list {
+			products[product.GetID()] = product
+		}
+	}
+
+	return products, nil
+}
+
+// productFeedImages returns the URL of the primary image of each of the
+// products: its active image media with the lowest sequence
+func (store *Store) productFeedImages(ctx context.Context, productIDs []string) (map[string]string, error) {
+	primary := map[string]MediaInterface{}
+
+	for _, batch := range lo.Chunk(lo.Uniq(productIDs), bulkBatchSize) {
+		list, err := store.MediaList(ctx, NewMediaQuery().SetEntityIDIn(batch).SetStatus(MEDIA_STATUS_ACTIVE))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, media := range list {
+			if !media.IsImage() {
+				continue
+			}
+
+			current, ok := primary[media.GetEntityID()]
+			if !ok || media.GetSequence() < current.GetSequence() {
+				primary[media.GetEntityID()] = media
+			}
+		}
+	}
+
+	return lo.MapValues(primary, func(media MediaInterface, _ string) string {
+		return media.GetURL()
+	}), nil
+}
+
+// productFeedCategoryPaths returns the path of each of the categories, the
+// titles of its ancestors and its own, joined with " > "
+func (store *Store) productFeedCategoryPaths(ctx context.Context, categoryIDs []string) (map[string]string, error) {
+	categories := map[string]CategoryInterface{}
+
+	// load the categories, then their parents, up to the roots
+	missing := categoryIDs
+	for len(missing) > 0 {
+		for _, batch := range lo.Chunk(missing, bulkBatchSize) {
+			list, err := store.CategoryList(ctx, NewCategoryQuery().SetIDIn(batch))
+			if err != nil {
+				return nil, err
+			}
+
+			for _, category := range list {
+				categories[category.GetID()] = category
+			}
+		}
+
+		loaded := missing
+		missing = lo.Uniq(lo.FilterMap(loaded, func(id string, _ int) (string, bool) {
+			category, ok := categories[id]
+			if !ok {
+				return "", false
+			}
+
+			parentID := category.GetParentID()
+			_, known := categories[parentID]
+			return parentID, parentID != "" && parentID != "0" && !known
+		}))
+	}
+
+	paths := map[string]string{}
+
+	for _, id := range categoryIDs {
+		titles := []string{}
+		visited := map[string]bool{}
+
+		for category, ok := categories[id]; ok && !visited[category.GetID()]; category, ok = categories[category.GetParentID()] {
+			visited[category.GetID()] = true
+			titles = append(titles, category.GetTitle())
+		}
+
+		slices.Reverse(titles)
+		paths[id] = strings.Join(titles, productFeedCategorySeparator)
+	}
+
+	return paths, nil
+}
+
+// xmlElement returns the tokens of an element holding the text
+func xmlElement(name string, text string) []xml.Token {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	return []xml.Token{start, xml.CharData(text), start.End()}
+}
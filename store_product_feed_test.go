@@ -0,0 +1,133 @@
+package shopstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestStoreProductFeed(t *testing.T) {
+	store := initIntegrityStore(t)
+	ctx := context.Background()
+
+	apparel := NewCategory().SetTitle("Apparel")
+	shirts := NewCategory().SetTitle("Shirts").SetParentID(apparel.GetID())
+
+	for _, category := range []CategoryInterface{apparel, shirts} {
+		if err := store.CategoryCreate(ctx, category); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	parent := NewProduct().SetTitle("Shirt").SetSKU("SHIRT").SetDescription("Cotton shirt").
+		SetCategoryID(shirts.GetID()).SetStatus(PRODUCT_STATUS_ACTIVE)
+	small := NewProduct().SetSKU("SHIRT-S").SetParentID(parent.GetID()).
+		SetPriceFloat(19.9).SetQuantityInt(3).SetStatus(PRODUCT_STATUS_ACTIVE)
+	_ = small.SetVariantMatrixValues(map[string]string{"Size": "S", "fit": "slim"})
+	medium := NewProduct().SetTitle("Shirt M").SetSKU("SHIRT-M").SetParentID(parent.GetID()).
+		SetPriceFloat(21).SetStatus(PRODUCT_STATUS_ACTIVE)
+	_ = medium.SetVariantMatrixValues(map[string]string{"size": "M"})
+	mug := NewProduct().SetTitle("Mug").SetShortDescription("A mug").SetPriceFloat(8).SetQuantityInt(1).SetStatus(PRODUCT_STATUS_ACTIVE)
+	draft := NewProduct().SetTitle("Draft")
+
+	if err := store.ProductCreateMany(ctx, []ProductInterface{parent, small, medium, mug, draft}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	media := []MediaInterface{
+		NewMedia().SetEntityID(parent.GetID()).SetType(MEDIA_TYPE_IMAGE_PNG).SetURL("https://example.com/shirt.png").SetStatus(MEDIA_STATUS_ACTIVE).SetSequence(1),
+		NewMedia().SetEntityID(mug.GetID()).SetType(MEDIA_TYPE_IMAGE_PNG).SetURL("https://example.com/mug-2.png").SetStatus(MEDIA_STATUS_ACTIVE).SetSequence(2),
+		NewMedia().SetEntityID(mug.GetID()).SetType(MEDIA_TYPE_IMAGE_JPG).SetURL("https://example.com/mug-1.jpg").SetStatus(MEDIA_STATUS_ACTIVE).SetSequence(1),
+		NewMedia().SetEntityID(mug.GetID()).SetType(MEDIA_TYPE_VIDEO_MP4).SetURL("https://example.com/mug.mp4").SetStatus(MEDIA_STATUS_ACTIVE).SetSequence(0),
+	}
+
+	if err := store.MediaCreateMany(ctx, media); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	options := ProductFeedOptions{
+		Currency: "USD",
+		Link: func(product ProductInterface) string {
+			return "https://example.com/products/" + product.GetID()
+		},
+		ChannelTitle: "Example shop",
+	}
+
+	items, err := store.ProductFeed(ctx, options)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(items) != 3 {
+		t.Fatalf("expected the 2 variants and the mug, got %d items", len(items))
+	}
+
+	byID := map[string]ProductFeedItem{}
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+
+	s := byID["SHIRT-S"]
+	if s.ItemGroupID != "SHIRT" || s.Title != "Shirt" || s.Description != "Cotton shirt" {
+		t.Fatalf("expected the variant to fall back to its parent, got %+v", s)
+	}
+
+	if s.ImageLink != "https://example.com/shirt.png" || s.ProductType != "Apparel > Shirts" {
+		t.Fatalf("expected the image and category path of the parent, got %+v", s)
+	}
+
+	if s.Price != "19.90 USD" || s.Availability != PRODUCT_FEED_AVAILABILITY_IN_STOCK || len(s.Attributes) != 1 || s.Attributes["size"] != "S" {
+		t.Fatalf("unexpected variant item %+v", s)
+	}
+
+	if m := byID["SHIRT-M"]; m.Title != "Shirt M" || m.Availability != PRODUCT_FEED_AVAILABILITY_OUT_OF_STOCK {
+		t.Fatalf("unexpected variant item %+v", m)
+	}
+
+	if mugItem := byID[mug.GetID()]; mugItem.ImageLink != "https://example.com/mug-1.jpg" || mugItem.Description != "A mug" || mugItem.ItemGroupID != "" {
+		t.Fatalf("unexpected simple product item %+v", mugItem)
+	}
+
+	var buffer bytes.Buffer
+	if err := store.ProductFeedXML(ctx, &buffer, options); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	var rss struct {
+		Items []struct {
+			ID          string `xml:"http://base.google.com/ns/1.0 id"`
+			ItemGroupID string `xml:"http://base.google.com/ns/1.0 item_group_id"`
+			Size        string `xml:"http://base.google.com/ns/1.0 size"`
+		} `xml:"channel>item"`
+	}
+
+	if err := xml.Unmarshal(buffer.Bytes(), &rss); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(rss.Items) != 3 {
+		t.Fatalf("expected 3 XML items, got %d", len(rss.Items))
+	}
+
+	for _, item := range rss.Items {
+		if item.ID == "SHIRT-S" && (item.ItemGroupID != "SHIRT" || item.Size != "S") {
+			t.Fatalf("unexpected XML item %+v", item)
+		}
+	}
+
+	buffer.Reset()
+	if err := store.ProductFeedCSV(ctx, &buffer, options); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	header := strings.SplitN(buffer.String(), "\n", 2)[0]
+	if header != "id,title,description,link,image_link,availability,price,item_group_id,product_type,size" {
+		t.Fatalf("unexpected CSV header %q", header)
+	}
+
+	if _, err := store.ProductFeed(ctx, ProductFeedOptions{Link: options.Link}); err == nil {
+		t.Fatal("expected an error without a currency")
+	}
+}
@@ -2,7 +2,11 @@ package shopstore
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 )
 
@@ -356,3 +360,565 @@ func TestStoreProductList_MetasNotIn(t *testing.T) {
 		t.Errorf("expected product %s (missing key) in results", p4.GetID())
 	}
 }
+
+func TestStoreProductList_PriceAndStockFilters(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	cheapOutOfStock := NewProduct().SetTitle("Cheap, out of stock").SetStatus(PRODUCT_STATUS_ACTIVE).SetPriceFloat(9.99).SetQuantityInt(0)
+	cheapInStock := NewProduct().SetTitle("Cheap, in stock").SetStatus(PRODUCT_STATUS_ACTIVE).SetPriceFloat(15).SetQuantityInt(3)
+	expensive := NewProduct().SetTitle("Expensive").SetStatus(PRODUCT_STATUS_ACTIVE).SetPriceFloat(120).SetQuantityInt(0)
+	draft := NewProduct().SetTitle("Draft").SetStatus(PRODUCT_STATUS_DRAFT).SetPriceFloat(5).SetQuantityInt(0)
+
+	for _, p := range []ProductInterface{cheapOutOfStock, cheapInStock, expensive, draft} {
+		if err := store.ProductCreate(ctx, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Active products under 20 that are out of stock
+	list, err := store.ProductList(ctx, NewProductQuery().
+		SetStatus(PRODUCT_STATUS_ACTIVE).
+		SetPriceLte(20).
+		SetQuantityLte(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].GetID() != cheapOutOfStock.GetID() {
+		t.Fatalf("expected only %s, got %d products", cheapOutOfStock.GetID(), len(list))
+	}
+
+	list, err = store.ProductList(ctx, NewProductQuery().SetPriceGte(10).SetPriceLte(200))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("expected 2 products priced between 10 and 200, got %d", len(list))
+	}
+
+	count, err := store.ProductCount(ctx, NewProductQuery().SetInStockOnly(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 product in stock, got %d", count)
+	}
+
+	count, err = store.ProductCount(ctx, NewProductQuery().SetQuantityGte(1).SetQuantityLte(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 product with quantity between 1 and 5, got %d", count)
+	}
+}
+
+func TestStoreProductList_ProductKindFilters(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	parent := NewProduct().SetTitle("Parent")
+	simple := NewProduct().SetTitle("Simple")
+	if err := store.ProductCreate(ctx, parent); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.ProductCreate(ctx, simple); err != nil {
+		t.Fatal(err)
+	}
+
+	variant := NewProduct().SetTitle("Variant").SetParentID(parent.GetID())
+	if err := store.ProductCreate(ctx, variant); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		query    ProductQueryInterface
+		expected string
+	}{
+		{"parent", NewProductQuery().SetIsParent(true), parent.GetID()},
+		{"variant", NewProductQuery().SetIsVariant(true), variant.GetID()},
+		{"simple", NewProductQuery().SetIsSimple(true), simple.GetID()},
+	}
+
+	for _, tc := range cases {
+		list, err := store.ProductList(ctx, tc.query)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if len(list) != 1 || list[0].GetID() != tc.expected {
+			t.Fatalf("%s: expected only %s, got %d products", tc.name, tc.expected, len(list))
+		}
+	}
+}
+
+func TestStoreProductList_UpdatedAtFilters(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	product := NewProduct().SetTitle("Product")
+	if err := store.ProductCreate(ctx, product); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := store.ProductList(ctx, NewProductQuery().SetUpdatedAtGte("2000-01-01 00:00:00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("expected 1 product, got %d", len(list))
+	}
+
+	list, err = store.ProductList(ctx, NewProductQuery().SetUpdatedAtLte("2000-01-01 00:00:00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 0 {
+		t.Fatalf("expected 0 products, got %d", len(list))
+	}
+}
+
+func TestProductQueryValidate_RangeFilters(t *testing.T) {
+	invalid := map[string]ProductQueryInterface{
+		"negative price":       NewProductQuery().SetPriceGte(-1),
+		"inverted price range": NewProductQuery().SetPriceGte(20).SetPriceLte(10),
+		"inverted stock range": NewProductQuery().SetQuantityGte(5).SetQuantityLte(1),
+		"in stock with lte 0":  NewProductQuery().SetInStockOnly(true).SetQuantityLte(0),
+		"parent and variant":   NewProductQuery().SetIsParent(true).SetIsVariant(true),
+		"empty updated_at_gte": NewProductQuery().SetUpdatedAtGte(""),
+		"empty updated_at_lte": NewProductQuery().SetUpdatedAtLte(""),
+	}
+
+	for name, query := range invalid {
+		if err := query.Validate(); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+
+	valid := NewProductQuery().
+		SetPriceGte(0).
+		SetPriceLte(20).
+		SetQuantityLte(0).
+		SetIsSimple(true).
+		SetIsParent(false)
+
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+}
+
+func TestStoreProductListPage_OrderByPrice(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	prices := []float64{30, 10, 20, 10, 40}
+	for _, price := range prices {
+		if err := store.ProductCreate(ctx, NewProduct().SetPriceFloat(price)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := []float64{}
+	cursor := ""
+
+	for {
+		query := NewProductQuery().
+			SetOrderBy(COLUMN_PRICE).
+			SetSortDirection("asc").
+			SetLimit(2)
+		if cursor != "" {
+			query.SetAfterCursor(cursor)
+		}
+
+		page, err := store.ProductListPage(ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, product := range page.Items {
+			got = append(got, product.GetPriceFloat())
+		}
+
+		if !page.HasMore {
+			break
+		}
+
+		cursor = page.NextCursor
+	}
+
+	expected := []float64{10, 10, 20, 30, 40}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d products, got %d", len(expected), len(got))
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("expected prices %v, got %v", expected, got)
+		}
+	}
+}
+
+func TestStoreProductListPage_MultiColumnSort(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	products := []struct {
+		status string
+		price  float64
+	}{
+		{PRODUCT_STATUS_DRAFT, 10},
+		{PRODUCT_STATUS_ACTIVE, 20},
+		{PRODUCT_STATUS_DRAFT, 30},
+		{PRODUCT_STATUS_ACTIVE, 40},
+		{PRODUCT_STATUS_ACTIVE, 20},
+	}
+	for _, p := range products {
+		if err := store.ProductCreate(ctx, NewProduct().SetStatus(p.status).SetPriceFloat(p.price)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := []string{}
+	cursor := ""
+
+	for {
+		query := NewProductQuery().
+			AddSort(COLUMN_STATUS, "asc").
+			AddSort(COLUMN_PRICE, "DESC").
+			SetLimit(2)
+		if cursor != "" {
+			query.SetAfterCursor(cursor)
+		}
+
+		page, err := store.ProductListPage(ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, product := range page.Items {
+			// decimal columns read back as "40.00" on MySQL and PostgreSQL
+			got = append(got, product.GetStatus()+":"+fmt.Sprint(product.GetPriceFloat()))
+		}
+
+		if !page.HasMore {
+			break
+		}
+
+		cursor = page.NextCursor
+	}
+
+	expected := []string{"active:40", "active:20", "active:20", "draft:30", "draft:10"}
+	if strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestProductQueryValidate_Sort(t *testing.T) {
+	invalid := map[string]ProductQueryInterface{
+		"unknown order_by":    NewProductQuery().SetOrderBy("title; DROP TABLE products"),
+		"unknown sort column": NewProductQuery().AddSort("metas", "asc"),
+		"bad sort direction":  NewProductQuery().AddSort(COLUMN_TITLE, "sideways"),
+		"bad sort_direction":  NewProductQuery().SetOrderBy(COLUMN_TITLE).SetSortDirection("up"),
+	}
+
+	for name, query := range invalid {
+		if err := query.Validate(); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+
+	valid := NewProductQuery().
+		SetOrderBy(COLUMN_TITLE).
+		SetSortDirection("ASC").
+		AddSort(COLUMN_PRICE, "desc")
+	if err := valid.Validate(); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	_, err = store.ProductList(context.Background(), NewProductQuery().AddSort("unknown", "asc"))
+	if err == nil || !strings.Contains(err.Error(), "not a sortable column") {
+		t.Fatalf("expected sortable column error, got %v", err)
+	}
+}
+
+func TestStoreProductQuantityAdjust(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	product := NewProduct().SetStatus(PRODUCT_STATUS_ACTIVE).SetTitle("Widget").SetQuantityInt(5)
+	if err := store.ProductCreate(ctx, product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	quantity, err := store.ProductQuantityAdjust(ctx, product.GetID(), 3, ProductQuantityAdjustOptions{})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if quantity != 8 {
+		t.Fatalf("expected quantity 8, got %d", quantity)
+	}
+
+	_, err = store.ProductQuantityAdjust(ctx, product.GetID(), -9, ProductQuantityAdjustOptions{NotBelowZero: true})
+	if !errors.Is(err, ErrInsufficientQuantity) {
+		t.Fatalf("expected ErrInsufficientQuantity, got %v", err)
+	}
+
+	quantity, err = store.ProductQuantityAdjust(ctx, product.GetID(), -9, ProductQuantityAdjustOptions{})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if quantity != -1 {
+		t.Fatalf("expected backorders to go below zero, got %d", quantity)
+	}
+
+	found, err := store.ProductFindByID(ctx, product.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found.GetQuantityInt() != -1 || found.GetVersion() != 3 {
+		t.Fatalf("expected quantity -1 at version 3, got %d at version %d", found.GetQuantityInt(), found.GetVersion())
+	}
+
+	// the loaded product went stale
+	product.SetTitle("Gadget")
+	if err := store.ProductUpdate(ctx, product); !errors.Is(err, ErrConcurrentModification) {
+		t.Fatalf("expected ErrConcurrentModification, got %v", err)
+	}
+
+	_, err = store.ProductQuantityAdjust(ctx, "missing", 1, ProductQuantityAdjustOptions{})
+	if !errors.Is(err, ErrProductNotFound) {
+		t.Fatalf("expected ErrProductNotFound, got %v", err)
+	}
+}
+
+func TestStoreProductQuantityAdjust_Concurrent(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// an in-memory SQLite database lives in a single connection
+	store.DB().SetMaxOpenConns(1)
+
+	ctx := context.Background()
+
+	product := NewProduct().SetStatus(PRODUCT_STATUS_ACTIVE).SetTitle("Widget").SetQuantityInt(10)
+	if err := store.ProductCreate(ctx, product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	var wg sync.WaitGroup
+	var sold, refused atomic.Int64
+
+	for range 12 {
+		wg.Go(func() {
+			_, err := store.ProductQuantityAdjust(ctx, product.GetID(), -1, ProductQuantityAdjustOptions{NotBelowZero: true})
+			switch {
+			case err == nil:
+				sold.Add(1)
+			case errors.Is(err, ErrInsufficientQuantity):
+				refused.Add(1)
+			default:
+				t.Error("unexpected error:", err)
+			}
+		})
+	}
+
+	wg.Wait()
+
+	if sold.Load() != 10 || refused.Load() != 2 {
+		t.Fatalf("expected 10 sold and 2 refused, got %d and %d", sold.Load(), refused.Load())
+	}
+
+	found, err := store.ProductFindByID(ctx, product.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found.GetQuantityInt() != 0 {
+		t.Fatalf("expected quantity 0, got %d", found.GetQuantityInt())
+	}
+}
+
+func TestStoreProductFindBySKU(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	// products without a SKU do not collide
+	for range 2 {
+		if err := store.ProductCreate(ctx, NewProduct().SetTitle("No SKU")); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	product := NewProduct().SetTitle("Cascade T-Shirt").SetSKU("TS-CAS-001")
+	if err := store.ProductCreate(ctx, product); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	found, err := store.ProductFindBySKU(ctx, "TS-CAS-001")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found == nil || found.GetID() != product.GetID() {
+		t.Fatal("expected to find the product by its SKU")
+	}
+
+	missing, err := store.ProductFindBySKU(ctx, "TS-CAS-002")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if missing != nil {
+		t.Fatal("expected no product for an unknown SKU")
+	}
+
+	if _, err := store.ProductFindBySKU(ctx, ""); err == nil {
+		t.Fatal("expected an error for an empty SKU")
+	}
+
+	noSKU, err := store.ProductList(ctx, NewProductQuery().SetTitleLike("No SKU"))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(noSKU) != 2 || noSKU[0].GetSKU() != "" {
+		t.Fatalf("expected 2 products without a SKU, got %d", len(noSKU))
+	}
+}
+
+func TestStoreProductCreate_DuplicateSKU(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	first := NewProduct().SetTitle("First").SetSKU("SKU-1")
+	if err := store.ProductCreate(ctx, first); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	err = store.ProductCreate(ctx, NewProduct().SetTitle("Copy").SetSKU("SKU-1"))
+	if !errors.Is(err, ErrProductSKUExists) {
+		t.Fatalf("expected ErrProductSKUExists, got %v", err)
+	}
+
+	second := NewProduct().SetTitle("Second").SetSKU("SKU-2")
+	if err := store.ProductCreate(ctx, second); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	second.SetSKU("SKU-1")
+	if err := store.ProductUpdate(ctx, second); !errors.Is(err, ErrProductSKUExists) {
+		t.Fatalf("expected ErrProductSKUExists on update, got %v", err)
+	}
+
+	// the SKU of a soft deleted product can be reused
+	if err := store.ProductSoftDelete(ctx, first); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.ProductCreate(ctx, NewProduct().SetTitle("Again").SetSKU("SKU-1")); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+}
+
+func TestStoreProductListPage_OrderBySKUWithNulls(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	// products without a SKU store NULL, which sorts apart from the values
+	for _, sku := range []string{"B", "", "A", "", "C"} {
+		if err := store.ProductCreate(ctx, NewProduct().SetSKU(sku)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, direction := range []string{SORT_DIRECTION_ASC, SORT_DIRECTION_DESC} {
+		for _, limit := range []int{1, 2} {
+			seen := map[string]bool{}
+			skus := []string{}
+			cursor := ""
+
+			for {
+				query := NewProductQuery().AddSort(COLUMN_SKU, direction).SetLimit(limit)
+				if cursor != "" {
+					query.SetAfterCursor(cursor)
+				}
+
+				page, err := store.ProductListPage(ctx, query)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				for _, product := range page.Items {
+					seen[product.GetID()] = true
+					if product.GetSKU() != "" {
+						skus = append(skus, product.GetSKU())
+					}
+				}
+
+				if !page.HasMore {
+					break
+				}
+
+				cursor = page.NextCursor
+			}
+
+			if len(seen) != 5 {
+				t.Fatalf("%s by %d: expected the 5 products, got %d", direction, limit, len(seen))
+			}
+
+			expected := "A,B,C"
+			if direction == SORT_DIRECTION_DESC {
+				expected = "C,B,A"
+			}
+
+			if strings.Join(skus, ",") != expected {
+				t.Fatalf("%s by %d: expected SKUs %s, got %v", direction, limit, expected, skus)
+			}
+		}
+	}
+}
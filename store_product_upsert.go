@@ -0,0 +1,342 @@
+package shopstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+	"github.com/dromara/carbon/v2"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+)
+
+// errProductUpsertStale fails an upsert planned with products since
+// created or deleted with the SKUs
+var errProductUpsertStale = fmt.Errorf("%w: products with the skus were created or deleted", ErrConcurrentModification)
+
+// ProductUpsertResult counts the products of an upsert by outcome
+type ProductUpsertResult struct {
+	Created   int
+	Updated   int
+	Unchanged int
+}
+
+// upsertIgnoredColumns are neither compared nor written by the upserts, as
+// the store maintains them
+var upsertIgnoredColumns = []string{
+	COLUMN_ID,
+	COLUMN_CREATED_AT,
+	COLUMN_UPDATED_AT,
+	COLUMN_SOFT_DELETED_AT,
+	COLUMN_VERSION,
+}
+
+// ProductUpsertBySKU creates the product, unless a live product has its SKU.
+// Then only the fields of the stored product differing from the product
+// are updated, leaving an unchanged product alone. See ProductUpsertManyBySKU.
+func (store *Store) ProductUpsertBySKU(ctx context.Context, product ProductInterface, columns ...string) (ProductUpsertResult, error) {
+	return store.ProductUpsertManyBySKU(ctx, []ProductInterface{product}, columns...)
+}
+
+// ProductUpsertManyBySKU upserts the products by SKU, the natural key of
+// catalog syncs: products with a new SKU are created and the stored
+// products differing from the given ones are updated, all in a single
+// transaction, so a failed upsert applies none of the products.
+//
+// Every product must have a SKU, unique within the call. Once upserted,
+// the products carry the ID and version of their stored row. Variants may
+// point at the ID of a parent passed in the same call, which is replaced
+// with the ID of the stored parent.
+//
+// The columns limit the fields compared with, and written to, the stored
+// products, so a sync owning only some fields leaves the others alone.
+// Without columns every field is compared but the quantity, as the stock is
+// kept by the store: it is only upserted when listed.
+func (store *Store) ProductUpsertManyBySKU(ctx context.Context, products []ProductInterface, columns ...string) (ProductUpsertResult, error) {
+	if lo.Contains(products, nil) {
+		return ProductUpsertResult{}, errors.New("product is nil")
+	}
+
+	skus := lo.Map(products, func(product ProductInterface, _ int) string {
+		return product.GetSKU()
+	})
+
+	if lo.Contains(skus, "") {
+		return ProductUpsertResult{}, errors.New("product sku is empty")
+	}
+
+	if duplicates := lo.FindDuplicates(skus); len(duplicates) > 0 {
+		return ProductUpsertResult{}, errors.New("product sku is not unique: " + duplicates[0])
+	}
+
+	// a product created or deleted with one of the SKUs since the lookup
+	// changes what is to be created, so the upsert is planned once more
+	result, err := store.productUpsertMany(ctx, products, skus, columns)
+	if errors.Is(err, errProductUpsertStale) {
+		result, err = store.productUpsertMany(ctx, products, skus, columns)
+	}
+
+	return result, err
+}
+
+// productUpsertMany upserts the products with the SKUs, looked up before
+// the transaction to run the hooks and the reference checks. Within the
+// transaction the products with the SKUs are locked and looked up again,
+// failing with errProductUpsertStale when they are no longer the ones the
+// upsert was planned with.
+func (store *Store) productUpsertMany(ctx context.Context, products []ProductInterface, skus []string, columns []string) (ProductUpsertResult, error) {
+	result := ProductUpsertResult{}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	stored := map[string]ProductInterface{}
+	for _, batch := range lo.Chunk(skus, bulkBatchSize) {
+		list, err := store.ProductList(ctx, NewProductQuery().SetSKUIn(batch))
+		if err != nil {
+			return result, err
+		}
+
+		for _, product := range list {
+			stored[product.GetSKU()] = product
+		}
+	}
+
+	storedIDs := map[string]string{}
+	for _, product := range products {
+		if existing, ok := stored[product.GetSKU()]; ok {
+			storedIDs[product.GetID()] = existing.GetID()
+		}
+	}
+
+	created := []ProductInterface{}
+	for _, product := range products {
+		if parentID, ok := storedIDs[product.GetParentID()]; ok {
+			product.SetParentID(parentID)
+		}
+
+		if _, ok := stored[product.GetSKU()]; !ok {
+			created = append(created, product)
+		}
+	}
+
+	now := carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)
+
+	for _, product := range created {
+		product.SetCreatedAt(now)
+		product.SetUpdatedAt(now)
+		product.SetSoftDeletedAt(MAX_DATETIME)
+
+		if err := store.productHooks.runBeforeCreate(ctx, product); err != nil {
+			return result, err
+		}
+	}
+
+	// updates maps the given products to the stored ones with their changes applied
+	updates := map[ProductInterface]ProductInterface{}
+	updated := map[ProductInterface]map[string]string{}
+
+	for _, product := range products {
+		existing, ok := stored[product.GetSKU()]
+		if !ok {
+			continue
+		}
+
+		update := &Product{}
+		update.Hydrate(existing.Data())
+		for column, value := range productUpsertChanges(existing, product, columns) {
+			update.Set(column, value)
+		}
+
+		updates[product] = update
+
+		if len(update.DataChanged()) == 0 {
+			continue
+		}
+
+		if err := store.productHooks.runBeforeUpdate(ctx, update, update.DataChanged()); err != nil {
+			return result, err
+		}
+
+		updated[update] = update.DataChanged()
+	}
+
+	rows := lo.Map(created, func(product ProductInterface, _ int) map[string]string {
+		return product.Data()
+	})
+
+	if err := store.assertManyReferencesExist(ctx, store.productTableName, rows, store.productReferences()); err != nil {
+		return result, err
+	}
+
+	writes := map[ProductInterface]map[string]string{}
+	for update := range updated {
+		dataChanged := productUpdateData(update)
+		if err := store.assertReferencesExist(ctx, dataChanged, store.productReferences()); err != nil {
+			return result, err
+		}
+
+		writes[update] = dataChanged
+	}
+
+	versions := map[ProductInterface]int64{}
+
+	skuIDs := lo.MapValues(stored, func(product ProductInterface, _ string) string {
+		return product.GetID()
+	})
+
+	err := store.query(ctx).Transaction(func(tx contractsorm.Query) error {
+		locked, err := store.productIDsBySKU(tx, skus)
+		if err != nil {
+			return err
+		}
+
+		if !maps.Equal(locked, skuIDs) {
+			return errProductUpsertStale
+		}
+
+		records := changeRecords{}
+
+		if len(rows) > 0 {
+			if err := bulkInsert(tx, store.productTableName, rows); err != nil {
+				return err
+			}
+
+			for _, row := range rows {
+				records.auditLogs = append(records.auditLogs, store.auditLogEntries(ctx, ENTITY_TYPE_PRODUCT, row[COLUMN_ID], AUDIT_OPERATION_CREATE, nil, row)...)
+			}
+		}
+
+		for _, product := range products {
+			update, ok := updates[product]
+			if !ok || writes[update] == nil {
+				continue
+			}
+
+			version, updateRecords, err := store.productUpdateOn(ctx, tx, update, writes[update])
+			if err != nil {
+				return err
+			}
+
+			versions[update] = version
+			records.auditLogs = append(records.auditLogs, updateRecords.auditLogs...)
+			records.events = append(records.events, updateRecords.events...)
+		}
+
+		return store.recordChanges(tx, records)
+	})
+	if err != nil && lo.SomeBy(created, func(product ProductInterface) bool { return store.productSKUTaken(ctx, product) }) {
+		return result, errProductUpsertStale
+	}
+	if err != nil {
+		return result, store.operationError("product upsert many", err)
+	}
+
+	for _, product := range created {
+		product.MarkAsNotDirty()
+		store.productHooks.runAfterCreate(ctx, product)
+	}
+
+	result.Created = len(created)
+
+	for _, product := range products {
+		update, ok := updates[product]
+		if !ok {
+			continue
+		}
+
+		if changed, ok := updated[update]; ok {
+			update.SetVersion(versions[update])
+			update.MarkAsNotDirty()
+			store.productHooks.runAfterUpdate(ctx, update, changed)
+			result.Updated++
+		} else {
+			result.Unchanged++
+		}
+
+		product.SetID(update.GetID())
+		product.SetCreatedAt(update.GetCreatedAt())
+		product.SetUpdatedAt(update.GetUpdatedAt())
+		product.SetSoftDeletedAt(update.GetSoftDeletedAt())
+		product.SetVersion(update.GetVersion())
+		product.MarkAsNotDirty()
+	}
+
+	return result, nil
+}
+
+// productIDsBySKU maps the SKUs of the live products among skus to their
+// IDs, read within the transaction tx and locked for update
+func (store *Store) productIDsBySKU(tx contractsorm.Query, skus []string) (map[string]string, error) {
+	ids := map[string]string{}
+
+	for _, batch := range lo.Chunk(skus, bulkBatchSize) {
+		var results []map[string]any
+		err := statement(tx).
+			Table(store.productTableName).
+			Select([]string{COLUMN_ID, COLUMN_SKU}).
+			WhereIn(COLUMN_SKU, lo.ToAnySlice(batch)).
+			Where(COLUMN_SOFT_DELETED_AT+" = ?", MAX_DATETIME).
+			LockForUpdate().
+			Get(&results)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, result := range results {
+			row := mapAnyToString(result)
+			ids[row[COLUMN_SKU]] = row[COLUMN_ID]
+		}
+	}
+
+	return ids, nil
+}
+
+// productUpsertChanges returns the fields of product differing from the
+// stored product, among the columns or, without columns, every column but
+// the quantity. The columns maintained by the store are left out.
+func productUpsertChanges(stored ProductInterface, product ProductInterface, columns []string) map[string]string {
+	storedData := stored.Data()
+	changed := map[string]string{}
+
+	for column, value := range product.Data() {
+		if slices.Contains(upsertIgnoredColumns, column) {
+			continue
+		}
+
+		if len(columns) > 0 && !slices.Contains(columns, column) {
+			continue
+		}
+
+		if len(columns) == 0 && column == COLUMN_QUANTITY {
+			continue
+		}
+
+		if productValuesEqual(column, storedData[column], value) {
+			continue
+		}
+
+		changed[column] = value
+	}
+
+	return changed
+}
+
+// productValuesEqual compares two values of a product column, as read back
+// from the database and as set on an entity
+func productValuesEqual(column string, a string, b string) bool {
+	switch column {
+	case COLUMN_PRICE, COLUMN_WEIGHT, COLUMN_LENGTH, COLUMN_WIDTH, COLUMN_HEIGHT:
+		return cast.ToFloat64(a) == cast.ToFloat64(b)
+	case COLUMN_QUANTITY:
+		return cast.ToInt64(a) == cast.ToInt64(b)
+	case COLUMN_PARENT_ID:
+		// "0" is the "no parent" marker of older rows, see migration_007
+		return lo.Ternary(a == "0", "", a) == lo.Ternary(b == "0", "", b)
+	}
+
+	return a == b
+}
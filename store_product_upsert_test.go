@@ -0,0 +1,270 @@
+package shopstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestStoreProductUpsertBySKU(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	product := NewProduct().SetTitle("Widget").SetSKU("WID-1").SetPriceFloat(10).SetQuantityInt(5)
+
+	result, err := store.ProductUpsertBySKU(ctx, product)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if result.Created != 1 || result.Updated != 0 || result.Unchanged != 0 {
+		t.Fatalf("expected the product created, got %+v", result)
+	}
+
+	id := product.GetID()
+
+	// the same state pushed again, by a fresh entity
+	result, err = store.ProductUpsertBySKU(ctx, NewProduct().SetTitle("Widget").SetSKU("WID-1").SetPriceFloat(10).SetQuantityInt(5))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if result.Unchanged != 1 {
+		t.Fatalf("expected the product unchanged, got %+v", result)
+	}
+
+	changed := NewProduct().SetTitle("Widget").SetSKU("WID-1").SetPriceFloat(12.5).SetQuantityInt(5)
+
+	result, err = store.ProductUpsertBySKU(ctx, changed)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if result.Updated != 1 {
+		t.Fatalf("expected the product updated, got %+v", result)
+	}
+
+	if changed.GetID() != id || changed.GetVersion() != 2 {
+		t.Fatalf("expected the upserted product to carry the stored ID at version 2, got %s at %d", changed.GetID(), changed.GetVersion())
+	}
+
+	found, err := store.ProductFindBySKU(ctx, "WID-1")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found.GetID() != id || found.GetPriceFloat() != 12.5 {
+		t.Fatalf("unexpected stored product %s at %v", found.GetID(), found.GetPriceFloat())
+	}
+
+	count, err := store.ProductCount(ctx, NewProductQuery())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if count != 1 {
+		t.Fatalf("expected a single product, got %d", count)
+	}
+
+	if _, err := store.ProductUpsertBySKU(ctx, NewProduct().SetTitle("No SKU")); err == nil {
+		t.Fatal("expected an error for a product without a SKU")
+	}
+}
+
+func TestStoreProductUpsertManyBySKU(t *testing.T) {
+	store := initIntegrityStore(t)
+	ctx := context.Background()
+
+	parent := NewProduct().SetTitle("Shirt").SetSKU("SHIRT")
+	small := NewProduct().SetTitle("Shirt S").SetSKU("SHIRT-S").SetParentID(parent.GetID())
+
+	result, err := store.ProductUpsertManyBySKU(ctx, []ProductInterface{parent, small})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if result.Created != 2 {
+		t.Fatalf("expected 2 products created, got %+v", result)
+	}
+
+	// the next sync knows nothing about the stored IDs
+	nextParent := NewProduct().SetTitle("Shirt").SetSKU("SHIRT")
+	nextSmall := NewProduct().SetTitle("Shirt Small").SetSKU("SHIRT-S").SetParentID(nextParent.GetID())
+	nextLarge := NewProduct().SetTitle("Shirt L").SetSKU("SHIRT-L").SetParentID(nextParent.GetID())
+
+	result, err = store.ProductUpsertManyBySKU(ctx, []ProductInterface{nextParent, nextSmall, nextLarge})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if result.Created != 1 || result.Updated != 1 || result.Unchanged != 1 {
+		t.Fatalf("expected 1 created, 1 updated and 1 unchanged, got %+v", result)
+	}
+
+	variants, err := store.ProductVariantList(ctx, parent.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(variants) != 2 {
+		t.Fatalf("expected both variants under the stored parent, got %d", len(variants))
+	}
+
+	_, err = store.ProductUpsertManyBySKU(ctx, []ProductInterface{
+		NewProduct().SetSKU("DUP"),
+		NewProduct().SetSKU("DUP"),
+	})
+	if err == nil {
+		t.Fatal("expected an error for duplicate SKUs")
+	}
+}
+
+func TestStoreProductUpsertBySKU_LeavesUnsetFields(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	stored := NewProduct().
+		SetTitle("Lamp").
+		SetSKU("LAMP").
+		SetStatus(PRODUCT_STATUS_ACTIVE).
+		SetCategoryID("LIGHTING").
+		SetPriceFloat(20).
+		SetQuantityInt(7)
+	if err := store.ProductCreate(ctx, stored); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// a price feed, knowing nothing but the SKU and the price
+	result, err := store.ProductUpsertBySKU(ctx, NewProduct().SetSKU("LAMP").SetPriceFloat(25), COLUMN_PRICE)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if result.Updated != 1 {
+		t.Fatalf("expected the product updated, got %+v", result)
+	}
+
+	// without columns, the quantity is left to the store
+	result, err = store.ProductUpsertBySKU(ctx, NewProduct().
+		SetTitle("Lamp").
+		SetSKU("LAMP").
+		SetStatus(PRODUCT_STATUS_ACTIVE).
+		SetCategoryID("LIGHTING").
+		SetPriceFloat(25))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if result.Unchanged != 1 {
+		t.Fatalf("expected the product unchanged, got %+v", result)
+	}
+
+	found, err := store.ProductFindByID(ctx, stored.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found.GetPriceFloat() != 25 {
+		t.Fatalf("expected the price upserted, got %v", found.GetPriceFloat())
+	}
+
+	if found.GetTitle() != "Lamp" || found.GetStatus() != PRODUCT_STATUS_ACTIVE || found.GetCategoryID() != "LIGHTING" || found.GetQuantityInt() != 7 {
+		t.Fatalf("expected the fields not upserted unchanged, got %q %q %q %d",
+			found.GetTitle(), found.GetStatus(), found.GetCategoryID(), found.GetQuantityInt())
+	}
+
+	if _, err := store.ProductUpsertBySKU(ctx, NewProduct().SetSKU("LAMP").SetQuantityInt(3), COLUMN_QUANTITY); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	found, err = store.ProductFindByID(ctx, stored.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found.GetQuantityInt() != 3 {
+		t.Fatalf("expected the listed quantity upserted, got %d", found.GetQuantityInt())
+	}
+}
+
+func TestStoreProductUpsertManyBySKU_ConcurrentCreate(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	// another sync creates the product once the upsert looked the SKU up
+	concurrent := false
+	store.ProductHooks().BeforeCreate(func(ctx context.Context, product ProductInterface) error {
+		if concurrent {
+			return nil
+		}
+
+		concurrent = true
+		return store.ProductCreate(ctx, NewProduct().SetTitle("Old lamp").SetSKU("LAMP"))
+	})
+
+	result, err := store.ProductUpsertBySKU(ctx, NewProduct().SetTitle("Lamp").SetSKU("LAMP"))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if result.Created != 0 || result.Updated != 1 {
+		t.Fatalf("expected the concurrently created product updated, got %+v", result)
+	}
+
+	lamps, err := store.ProductList(ctx, NewProductQuery().SetSKU("LAMP"))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(lamps) != 1 || lamps[0].GetTitle() != "Lamp" {
+		t.Fatalf("expected a single upserted product, got %d", len(lamps))
+	}
+}
+
+func TestStoreProductUpsertManyBySKU_FailedUpdateCreatesNothing(t *testing.T) {
+	store := initIntegrityStore(t)
+	ctx := context.Background()
+
+	if _, err := store.ProductUpsertBySKU(ctx, NewProduct().SetTitle("Mug").SetSKU("MUG")); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// a concurrent write makes the update of the loaded product conflict
+	store.ProductHooks().BeforeUpdate(func(ctx context.Context, product ProductInterface, changed map[string]string) error {
+		_, err := store.ProductUpdateMany(ctx, NewProductQuery().SetID(product.GetID()), map[string]string{COLUMN_TITLE: "Concurrent"})
+		return err
+	})
+
+	result, err := store.ProductUpsertManyBySKU(ctx, []ProductInterface{
+		NewProduct().SetTitle("Plate").SetSKU("PLATE"),
+		NewProduct().SetTitle("Big mug").SetSKU("MUG"),
+	})
+	if !errors.Is(err, ErrConcurrentModification) {
+		t.Fatalf("expected ErrConcurrentModification, got %v", err)
+	}
+
+	if result != (ProductUpsertResult{}) {
+		t.Fatalf("expected nothing upserted, got %+v", result)
+	}
+
+	plates, err := store.ProductList(ctx, NewProductQuery().SetSKU("PLATE"))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(plates) != 0 {
+		t.Fatal("expected the product created with the failed update to be rolled back")
+	}
+}
@@ -0,0 +1,284 @@
+package shopstore
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+	"github.com/dromara/carbon/v2"
+	"github.com/samber/lo"
+)
+
+// ShippingQuote returns the shipping methods delivering the live line items
+// of an order, or the items of a cart, to the address of the request, with
+// their costs, cheapest first.
+//
+// The methods are those of the active shipping zone covering the address
+// most specifically: a zone listing the region of the address, else a
+// zone listing its country, else a zone without countries. Between zones
+// as specific, the oldest wins. Only the active methods delivering the
+// weight and subtotal of the items are returned; none is not an error.
+//
+// The weight is the weight of the products times their quantities, the
+// subtotal the item total less the order discount, or less the discount of
+// the cart while it is valid. ShippingQuote fails with
+// ErrShippingAddressMissing without an address to deliver to.
+func (store *Store) ShippingQuote(ctx context.Context, request ShippingQuoteRequest) ([]ShippingOption, error) {
+	if (request.OrderID == "") == (request.CartID == "") {
+		return nil, errors.New("shipping quote. set either an order id or a cart id")
+	}
+
+	if request.OrderID != "" {
+		order, err := store.OrderFindByID(ctx, request.OrderID)
+		if err != nil {
+			return nil, err
+		}
+		if order == nil {
+			return nil, ErrOrderNotFound
+		}
+
+		return store.orderShippingOptions(ctx, order, request.Address)
+	}
+
+	cart, err := store.CartFindByID(ctx, request.CartID)
+	if err != nil {
+		return nil, err
+	}
+	if cart == nil {
+		return nil, ErrCartNotFound
+	}
+
+	address := request.Address
+	if address.IsEmpty() && cart.GetCustomerID() != "" {
+		addresses, err := store.AddressList(ctx, NewAddressQuery().
+			SetCustomerID(cart.GetCustomerID()).
+			SetIsDefaultShipping(true).
+			SetLimit(1))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(addresses) > 0 {
+			address = addresses[0].ToOrderAddress()
+		}
+	}
+
+	if address.IsEmpty() {
+		return nil, ErrShippingAddressMissing
+	}
+
+	items, err := store.CartItemList(ctx, cart.GetID())
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, ErrCartEmpty
+	}
+
+	products, err := store.productsByID(ctx, lo.Map(items, func(item CartItemInterface, _ int) string {
+		return item.GetProductID()
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	parcel := shippingParcel{}
+	for _, item := range items {
+		parcel.weight += productWeight(products, item.GetProductID()) * float64(item.GetQuantityInt())
+		parcel.subtotal += item.GetSubtotalFloat()
+	}
+
+	discount, err := store.cartDiscount(store.query(ctx), cart)
+	if err != nil && !errors.Is(err, ErrDiscountNotApplicable) {
+		return nil, err
+	}
+	if discount != nil {
+		parcel.subtotal -= discountAmountOf(discount, roundPrice(parcel.subtotal))
+	}
+
+	parcel.subtotal = roundPrice(parcel.subtotal)
+
+	return store.shippingOptions(ctx, address, parcel)
+}
+
+// OrderSetShippingMethod stores the shipping method chosen for the order:
+// its ID and name, and its cost as quoted by ShippingQuote for the
+// shipping address of the order. The order price is adjusted by the
+// difference with the previous shipping cost. Fails with
+// ErrShippingMethodNotAvailable when the method is not quoted for the
+// order.
+//
+// The order is written in a single transaction, failing with
+// ErrConcurrentModification when it changed since it was read. The
+// AfterUpdate hooks run once it is written, but not the BeforeUpdate hooks.
+func (store *Store) OrderSetShippingMethod(ctx context.Context, orderID string, shippingMethodID string) (OrderInterface, error) {
+	if orderID == "" {
+		return nil, errors.New("order id is empty")
+	}
+
+	if shippingMethodID == "" {
+		return nil, errors.New("shipping method id is empty")
+	}
+
+	order, err := store.OrderFindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+
+	options, err := store.orderShippingOptions(ctx, order, OrderAddress{})
+	if err != nil {
+		return nil, err
+	}
+
+	option, found := lo.Find(options, func(option ShippingOption) bool {
+		return option.Method.GetID() == shippingMethodID
+	})
+	if !found {
+		return nil, ErrShippingMethodNotAvailable
+	}
+
+	order.SetPriceFloat(roundPrice(order.GetPriceFloat() - order.GetShippingAmountFloat() + option.Cost))
+	order.SetShippingMethodID(option.Method.GetID())
+	order.SetShippingMethodName(option.Method.GetName())
+	order.SetShippingAmountFloat(option.Cost)
+	order.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	var version int64
+	err = store.query(ctx).Transaction(func(tx contractsorm.Query) error {
+		var records changeRecords
+		version, records, err = store.changedFieldsUpdate(ctx, tx, ENTITY_TYPE_ORDER, store.orderTableName, order.GetID(), order.GetVersion(), order.DataChanged())
+		if err != nil {
+			return err
+		}
+
+		return store.recordChanges(tx, records)
+	})
+	if err != nil {
+		return nil, store.operationError("order set shipping method", err)
+	}
+
+	changed := lo.OmitByKeys(order.DataChanged(), []string{COLUMN_UPDATED_AT})
+
+	order.SetVersion(version)
+	order.MarkAsNotDirty()
+	store.orderHooks.runAfterUpdate(ctx, order, changed)
+
+	return order, nil
+}
+
+// orderShippingOptions returns the shipping options of the live line items
+// of the order, delivered to the address or, when it is empty, to the
+// shipping address of the order
+func (store *Store) orderShippingOptions(ctx context.Context, order OrderInterface, address OrderAddress) ([]ShippingOption, error) {
+	if address.IsEmpty() {
+		var err error
+		if address, err = order.GetShippingAddress(); err != nil {
+			return nil, err
+		}
+	}
+
+	if address.IsEmpty() {
+		return nil, ErrShippingAddressMissing
+	}
+
+	lineItems, err := store.OrderLineItemList(ctx, NewOrderLineItemQuery().SetOrderID(order.GetID()))
+	if err != nil {
+		return nil, err
+	}
+	if len(lineItems) == 0 {
+		return nil, ErrOrderHasNoLineItems
+	}
+
+	products, err := store.productsByID(ctx, lo.Map(lineItems, func(lineItem OrderLineItemInterface, _ int) string {
+		return lineItem.GetProductID()
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	parcel := shippingParcel{}
+	for _, lineItem := range lineItems {
+		quantity := float64(lineItem.GetQuantityInt())
+		parcel.weight += productWeight(products, lineItem.GetProductID()) * quantity
+		parcel.subtotal += lineItem.GetPriceFloat() * quantity
+	}
+
+	parcel.subtotal = roundPrice(max(parcel.subtotal-order.GetDiscountAmountFloat(), 0))
+
+	return store.shippingOptions(ctx, address, parcel)
+}
+
+// shippingOptions returns the methods of the active zone covering the
+// address most specifically, with their costs for the parcel, cheapest
+// first
+func (store *Store) shippingOptions(ctx context.Context, address OrderAddress, parcel shippingParcel) ([]ShippingOption, error) {
+	zones, err := store.ShippingZoneList(ctx, NewShippingZoneQuery().
+		SetStatus(SHIPPING_ZONE_STATUS_ACTIVE).
+		AddSort(COLUMN_CREATED_AT, SORT_DIRECTION_ASC).
+		AddSort(COLUMN_ID, SORT_DIRECTION_ASC))
+	if err != nil {
+		return nil, err
+	}
+
+	var zone ShippingZoneInterface
+	bestMatch := shippingZoneNoMatch
+	for _, candidate := range zones {
+		match, err := shippingZoneMatch(candidate, address)
+		if err != nil {
+			return nil, err
+		}
+
+		if match > bestMatch {
+			zone, bestMatch = candidate, match
+		}
+	}
+
+	options := []ShippingOption{}
+	if zone == nil {
+		return options, nil
+	}
+
+	methods, err := store.ShippingMethodList(ctx, NewShippingMethodQuery().
+		SetShippingZoneID(zone.GetID()).
+		SetStatus(SHIPPING_METHOD_STATUS_ACTIVE).
+		AddSort(COLUMN_CREATED_AT, SORT_DIRECTION_ASC).
+		AddSort(COLUMN_ID, SORT_DIRECTION_ASC))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, method := range methods {
+		cost, available, err := shippingCostOf(method, parcel)
+		if err != nil {
+			return nil, err
+		}
+
+		if available {
+			options = append(options, ShippingOption{Method: method, Zone: zone, Cost: cost})
+		}
+	}
+
+	slices.SortStableFunc(options, func(a, b ShippingOption) int {
+		return cmp.Compare(a.Cost, b.Cost)
+	})
+
+	return options, nil
+}
+
+// productWeight returns the weight of the product, 0 when it is unknown
+func productWeight(products map[string]ProductInterface, productID string) float64 {
+	product, ok := products[productID]
+	if !ok {
+		return 0
+	}
+
+	return product.GetWeightFloat()
+}
@@ -0,0 +1,451 @@
+package shopstore
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"maps"
+
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+	"github.com/dromara/carbon/v2"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+)
+
+func (store *Store) ShippingMethodCount(ctx context.Context, options ShippingMethodQueryInterface) (int64, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.shippingMethodQuery(ctx, options)
+	if err != nil {
+		return -1, err
+	}
+
+	var count int64
+	if err := q.Count(&count); err != nil {
+		return -1, store.operationError("shipping method count", err)
+	}
+
+	return count, nil
+}
+
+// ShippingMethodCreate inserts the shipping method, which must belong to a
+// shipping zone.
+func (store *Store) ShippingMethodCreate(ctx context.Context, shippingMethod ShippingMethodInterface) error {
+	if shippingMethod == nil {
+		return errors.New("shipping method is nil")
+	}
+
+	if shippingMethod.GetShippingZoneID() == "" {
+		return errors.New("shipping method zone id is empty")
+	}
+
+	shippingMethod.SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+	shippingMethod.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+	shippingMethod.SetSoftDeletedAt(MAX_DATETIME)
+
+	if err := store.shippingMethodHooks.runBeforeCreate(ctx, shippingMethod); err != nil {
+		return err
+	}
+
+	data := shippingMethod.Data()
+	row := map[string]any{}
+	for k, v := range data {
+		row[k] = rowValue(k, v)
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	if err := store.assertReferencesExist(ctx, data, store.shippingMethodReferences()); err != nil {
+		return err
+	}
+
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		err := statement(tx).Table(store.shippingMethodTableName).Create(row)
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_SHIPPING_METHOD, shippingMethod.GetID(), AUDIT_OPERATION_CREATE, nil, data),
+		}, err
+	})
+	if err != nil {
+		return store.operationError("shipping method create", err)
+	}
+
+	shippingMethod.MarkAsNotDirty()
+
+	store.shippingMethodHooks.runAfterCreate(ctx, shippingMethod)
+
+	return nil
+}
+
+func (store *Store) ShippingMethodDelete(ctx context.Context, shippingMethod ShippingMethodInterface) error {
+	if shippingMethod == nil {
+		return errors.New("shipping method is nil")
+	}
+
+	return store.ShippingMethodDeleteByID(ctx, shippingMethod.GetID())
+}
+
+func (store *Store) ShippingMethodDeleteByID(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("shipping method id is empty")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		before, err := store.storedValues(tx, store.shippingMethodTableName, id, nil)
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		_, err = statement(tx).Table(store.shippingMethodTableName).Where(COLUMN_ID+" = ?", id).Delete()
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_SHIPPING_METHOD, id, AUDIT_OPERATION_DELETE, before, nil),
+		}, err
+	})
+	if err != nil {
+		return store.operationError("shipping method delete", err)
+	}
+
+	store.shippingMethodHooks.runAfterDelete(ctx, id)
+
+	return nil
+}
+
+func (store *Store) ShippingMethodFindByID(ctx context.Context, id string) (ShippingMethodInterface, error) {
+	if id == "" {
+		return nil, errors.New("shipping method id is empty")
+	}
+
+	list, err := store.ShippingMethodList(ctx, NewShippingMethodQuery().
+		SetID(id).
+		SetLimit(1))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) > 0 {
+		return list[0], nil
+	}
+
+	return nil, nil
+}
+
+func (store *Store) ShippingMethodList(ctx context.Context, options ShippingMethodQueryInterface) ([]ShippingMethodInterface, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.shippingMethodQuery(ctx, options)
+	if err != nil {
+		return []ShippingMethodInterface{}, err
+	}
+
+	var results []map[string]any
+	if err := q.Get(&results); err != nil {
+		return []ShippingMethodInterface{}, store.operationError("shipping method list", err)
+	}
+
+	list := []ShippingMethodInterface{}
+
+	lo.ForEach(results, func(result map[string]any, index int) {
+		model := NewShippingMethodFromExistingData(mapAnyToString(result))
+		list = append(list, model)
+	})
+
+	return list, nil
+}
+
+// ShippingMethodIterate streams the shipping methods matching the query options, fetching
+// them from the database in batches instead of loading them all at once.
+// Iteration stops with an error as soon as ctx is cancelled.
+func (store *Store) ShippingMethodIterate(ctx context.Context, options ShippingMethodQueryInterface) iter.Seq2[ShippingMethodInterface, error] {
+	if options == nil {
+		options = NewShippingMethodQuery()
+	}
+
+	build := func(ctx context.Context) (contractsorm.Query, error) {
+		return store.shippingMethodQuery(ctx, options)
+	}
+
+	hydrate := func(data map[string]string) ShippingMethodInterface {
+		return NewShippingMethodFromExistingData(data)
+	}
+
+	return iterateInBatches(store, ctx, "shipping method iterate", options, build, hydrate)
+}
+
+// ShippingMethodListPage returns a single keyset (cursor) paginated page of shipping methods
+// matching the query options. The page size is taken from SetLimit. Pass the
+// returned NextCursor to SetAfterCursor to fetch the following page.
+func (store *Store) ShippingMethodListPage(ctx context.Context, options ShippingMethodQueryInterface) (ListPage[ShippingMethodInterface], error) {
+	if options == nil {
+		options = NewShippingMethodQuery()
+	}
+
+	if !options.HasLimit() {
+		return ListPage[ShippingMethodInterface]{}, errors.New("shipping method list page. limit is required")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.shippingMethodQuery(ctx, options)
+	if err != nil {
+		return ListPage[ShippingMethodInterface]{}, err
+	}
+
+	q = keysetPageQuery(q, options.Limit(), options)
+
+	var results []map[string]any
+	if err := q.Get(&results); err != nil {
+		return ListPage[ShippingMethodInterface]{}, store.operationError("shipping method list page", err)
+	}
+
+	list := []ShippingMethodInterface{}
+
+	lo.ForEach(results, func(result map[string]any, index int) {
+		model := NewShippingMethodFromExistingData(mapAnyToString(result))
+		list = append(list, model)
+	})
+
+	return newListPage(list, options.Limit(), options), nil
+}
+
+func (store *Store) ShippingMethodSoftDelete(ctx context.Context, shippingMethod ShippingMethodInterface) error {
+	if shippingMethod == nil {
+		return errors.New("shipping method is nil")
+	}
+
+	shippingMethod.SetSoftDeletedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+
+	if err := store.shippingMethodUpdate(ctx, shippingMethod); err != nil {
+		return err
+	}
+
+	store.shippingMethodHooks.runAfterSoftDelete(ctx, shippingMethod.GetID())
+
+	return nil
+}
+
+func (store *Store) ShippingMethodSoftDeleteByID(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("shipping method id is empty")
+	}
+
+	shippingMethod, err := store.ShippingMethodFindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if shippingMethod == nil {
+		return nil
+	}
+
+	return store.ShippingMethodSoftDelete(ctx, shippingMethod)
+}
+
+// ShippingMethodSoftDeleteMany soft deletes the live shipping methods matching the
+// query options in a single transaction, returning how many were soft
+// deleted.
+// AfterSoftDelete hooks run for every shipping method.
+func (store *Store) ShippingMethodSoftDeleteMany(ctx context.Context, options ShippingMethodQueryInterface) (int64, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	ids, err := store.updateMany(ctx, store.shippingMethodBulkTable(options), map[string]string{
+		COLUMN_SOFT_DELETED_AT: carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC),
+	})
+	if err != nil {
+		return 0, store.operationError("shipping method soft delete many", err)
+	}
+
+	for _, id := range ids {
+		store.shippingMethodHooks.runAfterSoftDelete(ctx, id)
+	}
+
+	return int64(len(ids)), nil
+}
+
+func (store *Store) ShippingMethodUpdate(ctx context.Context, shippingMethod ShippingMethodInterface) error {
+	if shippingMethod == nil {
+		return errors.New("shipping method is nil")
+	}
+
+	if err := store.shippingMethodHooks.runBeforeUpdate(ctx, shippingMethod, shippingMethod.DataChanged()); err != nil {
+		return err
+	}
+
+	changed := shippingMethod.DataChanged()
+	if err := store.shippingMethodUpdate(ctx, shippingMethod); err != nil {
+		return err
+	}
+
+	store.shippingMethodHooks.runAfterUpdate(ctx, shippingMethod, changed)
+
+	return nil
+}
+
+// ShippingMethodUpdateMany sets the fields on all the shipping methods
+// matching the query options in a single transaction, returning how many
+// were updated. Only the columns listed in shippingMethodUpdatableColumns
+// can be set. Versions are incremented without being checked. The
+// AfterUpdate hooks run on the shipping methods set, but not the Before
+// hooks, as the shipping methods are not loaded.
+func (store *Store) ShippingMethodUpdateMany(ctx context.Context, options ShippingMethodQueryInterface, fields map[string]string) (int64, error) {
+	if err := assertUpdatable(fields, shippingMethodUpdatableColumns); err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	if err := store.assertReferencesExist(ctx, fields, store.shippingMethodReferences()); err != nil {
+		return 0, err
+	}
+
+	ids, rows, err := store.updateManyLoaded(ctx, store.shippingMethodBulkTable(options), fields, store.shippingMethodHooks.hasAfterUpdate())
+	if err != nil {
+		return 0, store.operationError("shipping method update many", err)
+	}
+
+	runAfterUpdateRows(ctx, &store.shippingMethodHooks, rows, NewShippingMethodFromExistingData, func(map[string]string) map[string]string {
+		return maps.Clone(fields)
+	})
+
+	return int64(len(ids)), nil
+}
+
+// shippingMethodUpdate writes the changed fields of shippingMethod, without running hooks
+func (store *Store) shippingMethodUpdate(ctx context.Context, shippingMethod ShippingMethodInterface) error {
+	shippingMethod.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString())
+
+	dataChanged := shippingMethod.DataChanged()
+
+	delete(dataChanged, COLUMN_ID)      // ID is not updateable
+	delete(dataChanged, "hash")         // Hash is not updateable
+	delete(dataChanged, "data")         // Data is not updateable
+	delete(dataChanged, COLUMN_VERSION) // Version is set by the update itself
+
+	if len(dataChanged) < 1 {
+		return nil
+	}
+
+	row := map[string]any{}
+	for k, v := range dataChanged {
+		row[k] = rowValue(k, v)
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	if err := store.assertReferencesExist(ctx, dataChanged, store.shippingMethodReferences()); err != nil {
+		return err
+	}
+
+	var version int64
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		before, err := store.storedValues(tx, store.shippingMethodTableName, shippingMethod.GetID(), lo.Keys(dataChanged))
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		version, err = versionedUpdate(ctx, statement(tx).Table(store.shippingMethodTableName).Where(COLUMN_ID+" = ?", shippingMethod.GetID()), shippingMethod.GetVersion(), row)
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_SHIPPING_METHOD, shippingMethod.GetID(), updateOperation(dataChanged), before, dataChanged),
+		}, err
+	})
+
+	if err == nil {
+		shippingMethod.SetVersion(version)
+		shippingMethod.MarkAsNotDirty()
+	}
+
+	return store.operationError("shipping method update", err)
+}
+
+// shippingMethodBulkTable describes the shipping methods matching the query options to
+// the bulk operations
+func (store *Store) shippingMethodBulkTable(options ShippingMethodQueryInterface) bulkTable {
+	return bulkTable{
+		entityType: ENTITY_TYPE_SHIPPING_METHOD,
+		tableName:  store.shippingMethodTableName,
+		selectRows: func(q contractsorm.Query) (contractsorm.Query, error) {
+			return store.shippingMethodQueryOn(q, options)
+		},
+	}
+}
+
+func (store *Store) shippingMethodQuery(ctx context.Context, options ShippingMethodQueryInterface) (contractsorm.Query, error) {
+	return store.shippingMethodQueryOn(store.query(ctx), options)
+}
+
+// shippingMethodQueryOn applies the query options to q, which may run within a transaction
+func (store *Store) shippingMethodQueryOn(q contractsorm.Query, options ShippingMethodQueryInterface) (contractsorm.Query, error) {
+	if options == nil {
+		options = NewShippingMethodQuery()
+	}
+
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	q = q.Table(store.shippingMethodTableName)
+
+	if options.HasID() {
+		q = q.Where(COLUMN_ID+" = ?", options.ID())
+	}
+
+	if options.HasIDIn() {
+		ids := make([]any, len(options.IDIn()))
+		for i, id := range options.IDIn() {
+			ids[i] = id
+		}
+		q = q.WhereIn(COLUMN_ID, ids)
+	}
+
+	if options.HasShippingZoneID() {
+		q = q.Where(COLUMN_SHIPPING_ZONE_ID+" = ?", options.ShippingZoneID())
+	}
+
+	if options.HasStatus() {
+		q = q.Where(COLUMN_STATUS+" = ?", options.Status())
+	}
+
+	if options.HasCreatedAtGte() && options.HasCreatedAtLte() {
+		q = q.Where(COLUMN_CREATED_AT+" BETWEEN ? AND ?", options.CreatedAtGte(), options.CreatedAtLte())
+	} else if options.HasCreatedAtGte() {
+		q = q.Where(COLUMN_CREATED_AT+" >= ?", options.CreatedAtGte())
+	} else if options.HasCreatedAtLte() {
+		q = q.Where(COLUMN_CREATED_AT+" <= ?", options.CreatedAtLte())
+	}
+
+	if !options.IsCountOnly() {
+		if options.HasLimit() {
+			q = q.Limit(cast.ToInt(options.Limit()))
+		}
+
+		if options.HasOffset() {
+			q = q.Offset(cast.ToInt(options.Offset()))
+		}
+	}
+
+	if options.HasAfterCursor() {
+		var err error
+		q, err = whereAfterCursor(q, store.dialect, keysetSort(options), options.AfterCursor())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if isSorted(options) {
+		q = orderByKeyset(q, keysetSort(options))
+	}
+
+	if !options.SoftDeletedIncluded() {
+		q = q.Where(COLUMN_SOFT_DELETED_AT+" = ?", MAX_DATETIME)
+	}
+
+	return q, nil
+}
@@ -0,0 +1,423 @@
+package shopstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// seedShipping creates a US zone with a flat rate free over 50 and a
+// weight based rate, a New York zone with a courier, and a rest of the
+// world zone with a price based rate and an inactive method
+func seedShipping(t *testing.T, store StoreInterface) {
+	t.Helper()
+	ctx := context.Background()
+
+	us := NewShippingZone().SetName("United States")
+	_ = us.SetCountryCodes([]string{"US"})
+
+	newYork := NewShippingZone().SetName("New York")
+	_ = newYork.SetCountryCodes([]string{"US"})
+	_ = newYork.SetRegions([]string{"NY"})
+
+	world := NewShippingZone().SetName("Rest of the world")
+
+	for _, zone := range []ShippingZoneInterface{us, newYork, world} {
+		if err := store.ShippingZoneCreate(ctx, zone); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	express := NewShippingMethod().SetShippingZoneID(us.GetID()).SetName("Express").SetRateType(SHIPPING_RATE_TYPE_WEIGHT)
+	_ = express.SetRateRules([]ShippingRateRule{{Min: 0, Max: 5, Cost: 12}, {Min: 5, Cost: 20}})
+
+	international := NewShippingMethod().SetShippingZoneID(world.GetID()).SetName("International").SetRateType(SHIPPING_RATE_TYPE_PRICE)
+	_ = international.SetRateRules([]ShippingRateRule{{Min: 0, Max: 100, Cost: 25}, {Min: 100, Cost: 40}})
+
+	methods := []ShippingMethodInterface{
+		NewShippingMethod().SetShippingZoneID(us.GetID()).SetName("Standard").SetAmountFloat(5.99).SetFreeOverAmountFloat(50),
+		express,
+		NewShippingMethod().SetShippingZoneID(newYork.GetID()).SetName("Courier").SetAmountFloat(3),
+		international,
+		NewShippingMethod().SetShippingZoneID(world.GetID()).SetName("Boat").SetStatus(SHIPPING_METHOD_STATUS_INACTIVE),
+	}
+
+	for _, method := range methods {
+		if err := store.ShippingMethodCreate(ctx, method); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+}
+
+// createShippingProducts creates a 0.5 kg shirt at 10 and a 1.2 kg book
+// at 5
+func createShippingProducts(t *testing.T, store StoreInterface) (ProductInterface, ProductInterface) {
+	t.Helper()
+
+	shirt := NewProduct().SetStatus(PRODUCT_STATUS_ACTIVE).SetTitle("Shirt").SetPriceFloat(10).SetQuantityInt(100).SetWeightFloat(0.5)
+	book := NewProduct().SetStatus(PRODUCT_STATUS_ACTIVE).SetTitle("Book").SetPriceFloat(5).SetQuantityInt(100).SetWeightFloat(1.2)
+	if err := store.ProductCreateMany(context.Background(), []ProductInterface{shirt, book}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	return shirt, book
+}
+
+// seedShippingOrder creates an order of two shirts and two books, 3.4 kg
+// for 30, shipped to the address
+func seedShippingOrder(t *testing.T, store StoreInterface, address OrderAddress) OrderInterface {
+	t.Helper()
+	ctx := context.Background()
+
+	shirt, book := createShippingProducts(t, store)
+
+	order := NewOrder().SetCustomerID("CUSTOMER01").SetPriceFloat(30).SetQuantityInt(4)
+	_ = order.SetShippingAddress(address)
+	if err := store.OrderCreate(ctx, order); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	for _, product := range []ProductInterface{shirt, book} {
+		lineItem := NewOrderLineItem().
+			SetOrderID(order.GetID()).
+			SetProductID(product.GetID()).
+			SetTitle(product.GetTitle()).
+			SetPriceFloat(product.GetPriceFloat()).
+			SetQuantityInt(2)
+
+		if err := store.OrderLineItemCreate(ctx, lineItem); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	return order
+}
+
+// shippingOptionNames returns the names of the methods of the options
+func shippingOptionNames(options []ShippingOption) []string {
+	names := []string{}
+	for _, option := range options {
+		names = append(names, option.Method.GetName())
+	}
+
+	return names
+}
+
+func TestStoreShippingZoneAndMethodCRUD(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	zone := NewShippingZone().SetName("Europe")
+	_ = zone.SetCountryCodes([]string{"fr", "de"})
+	if err := store.ShippingZoneCreate(ctx, zone); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.ShippingMethodCreate(ctx, NewShippingMethod().SetName("Orphan")); err == nil {
+		t.Fatal("expected an error for a shipping method without a zone")
+	}
+
+	method := NewShippingMethod().SetShippingZoneID(zone.GetID()).SetName("Post").SetRateType(SHIPPING_RATE_TYPE_WEIGHT)
+	_ = method.SetRateRules([]ShippingRateRule{{Min: 0, Max: 2, Cost: 6.5}})
+	if err := store.ShippingMethodCreate(ctx, method); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	found, err := store.ShippingMethodFindByID(ctx, method.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	rules, err := found.GetRateRules()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(rules) != 1 || rules[0].Max != 2 || rules[0].Cost != 6.5 {
+		t.Fatalf("expected the stored rate rules, got %v", rules)
+	}
+
+	found.SetFreeOverAmountFloat(80)
+	if err := store.ShippingMethodUpdate(ctx, found); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	list, err := store.ShippingMethodList(ctx, NewShippingMethodQuery().SetShippingZoneID(zone.GetID()))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(list) != 1 || list[0].GetFreeOverAmountFloat() != 80 || list[0].GetVersion() != 2 {
+		t.Fatalf("expected the updated method, got %v", list)
+	}
+
+	foundZone, err := store.ShippingZoneFindByID(ctx, zone.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	countryCodes, err := foundZone.GetCountryCodes()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(countryCodes) != 2 || countryCodes[0] != "FR" || countryCodes[1] != "DE" {
+		t.Fatalf("expected the stored countries, got %v", countryCodes)
+	}
+
+	if err := store.ShippingZoneDeleteByID(ctx, zone.GetID()); !errors.Is(err, ErrShippingZoneHasActiveMethods) {
+		t.Fatalf("expected ErrShippingZoneHasActiveMethods, got %v", err)
+	}
+
+	if err := store.ShippingMethodSoftDeleteByID(ctx, method.GetID()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.ShippingZoneSoftDeleteByID(ctx, zone.GetID()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	count, err := store.ShippingZoneCount(ctx, NewShippingZoneQuery())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if count != 0 {
+		t.Fatalf("expected the soft deleted zone excluded, got %d zones", count)
+	}
+
+	if err := NewShippingMethodQuery().SetOrderBy(COLUMN_RATE_RULES).Validate(); err == nil {
+		t.Fatal("expected an error for a sort on rate rules")
+	}
+}
+
+func TestStoreShippingQuote_Order(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	seedShipping(t, store)
+	order := seedShippingOrder(t, store, OrderAddress{Line1: "1 Main Street", CountryCode: "US", Region: "CA"})
+
+	options, err := store.ShippingQuote(ctx, ShippingQuoteRequest{OrderID: order.GetID()})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// 3.4 kg for 30, cheapest first
+	if len(options) != 2 || options[0].Cost != 5.99 || options[1].Cost != 12 {
+		t.Fatalf("expected Standard at 5.99 and Express at 12, got %v", shippingOptionNames(options))
+	}
+
+	if options[0].Method.GetName() != "Standard" || options[0].Zone.GetName() != "United States" {
+		t.Fatalf("expected Standard of the US zone first, got %v", options[0])
+	}
+
+	options, err = store.ShippingQuote(ctx, ShippingQuoteRequest{
+		OrderID: order.GetID(),
+		Address: OrderAddress{Line1: "1 Broadway", CountryCode: "US", Region: "NY"},
+	})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(options) != 1 || options[0].Method.GetName() != "Courier" || options[0].Cost != 3 {
+		t.Fatalf("expected the New York zone over the US zone, got %v", shippingOptionNames(options))
+	}
+
+	options, err = store.ShippingQuote(ctx, ShippingQuoteRequest{
+		OrderID: order.GetID(),
+		Address: OrderAddress{Line1: "1 Rue de Rivoli", CountryCode: "FR"},
+	})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(options) != 1 || options[0].Method.GetName() != "International" || options[0].Cost != 25 {
+		t.Fatalf("expected the active method of the rest of the world, got %v", shippingOptionNames(options))
+	}
+
+	if _, err := store.ShippingQuote(ctx, ShippingQuoteRequest{OrderID: "MISSING"}); !errors.Is(err, ErrOrderNotFound) {
+		t.Fatalf("expected ErrOrderNotFound, got %v", err)
+	}
+
+	if _, err := store.ShippingQuote(ctx, ShippingQuoteRequest{OrderID: order.GetID(), CartID: "CART01"}); err == nil {
+		t.Fatal("expected an error for both an order and a cart")
+	}
+}
+
+func TestStoreShippingQuote_Cart(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	seedShipping(t, store)
+	shirt, book := createShippingProducts(t, store)
+
+	cart := NewCart().SetCustomerID("CUSTOMER01")
+	if err := store.CartCreate(ctx, cart); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if _, err := store.ShippingQuote(ctx, ShippingQuoteRequest{CartID: cart.GetID()}); !errors.Is(err, ErrShippingAddressMissing) {
+		t.Fatalf("expected ErrShippingAddressMissing, got %v", err)
+	}
+
+	address := NewAddress().SetCustomerID("CUSTOMER01").SetLine1("1 Main Street").SetCountryCode("US").SetRegion("CA").SetIsDefaultShipping(true)
+	if err := store.AddressCreate(ctx, address); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if _, err := store.ShippingQuote(ctx, ShippingQuoteRequest{CartID: cart.GetID()}); !errors.Is(err, ErrCartEmpty) {
+		t.Fatalf("expected ErrCartEmpty, got %v", err)
+	}
+
+	if _, err := store.CartAddItem(ctx, cart.GetID(), shirt.GetID(), 4); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if _, err := store.CartAddItem(ctx, cart.GetID(), book.GetID(), 2); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// 4.4 kg for 50, Standard free from 50
+	options, err := store.ShippingQuote(ctx, ShippingQuoteRequest{CartID: cart.GetID()})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(options) != 2 || options[0].Method.GetName() != "Standard" || options[0].Cost != 0 || options[1].Cost != 12 {
+		t.Fatalf("expected Standard free and Express at 12, got %v", shippingOptionNames(options))
+	}
+
+	// 10% off takes the cart under the free shipping threshold
+	discount := createCartDiscount(t, store, DISCOUNT_TYPE_PERCENT, 10)
+	if err := store.CartApplyDiscount(ctx, cart.GetID(), discount.GetCode()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	options, err = store.ShippingQuote(ctx, ShippingQuoteRequest{CartID: cart.GetID()})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(options) != 2 || options[0].Cost != 5.99 {
+		t.Fatalf("expected Standard at 5.99 after the discount, got %v", shippingOptionNames(options))
+	}
+
+	if _, err := store.ShippingQuote(ctx, ShippingQuoteRequest{CartID: "MISSING"}); !errors.Is(err, ErrCartNotFound) {
+		t.Fatalf("expected ErrCartNotFound, got %v", err)
+	}
+}
+
+func TestStoreOrderSetShippingMethod(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	seedShipping(t, store)
+	order := seedShippingOrder(t, store, OrderAddress{Line1: "1 Main Street", CountryCode: "US", Region: "CA"})
+
+	options, err := store.ShippingQuote(ctx, ShippingQuoteRequest{OrderID: order.GetID()})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	standard, express := options[0].Method, options[1].Method
+
+	updated, err := store.OrderSetShippingMethod(ctx, order.GetID(), standard.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if updated.GetPriceFloat() != 35.99 || updated.GetShippingAmountFloat() != 5.99 || updated.GetShippingMethodName() != "Standard" {
+		t.Fatalf("expected 5.99 of shipping on top of 30, got %v", updated.Data())
+	}
+
+	// switching replaces the previous shipping cost
+	updated, err = store.OrderSetShippingMethod(ctx, order.GetID(), express.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	found, err := store.OrderFindByID(ctx, order.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found.GetPriceFloat() != 42 || found.GetShippingMethodID() != express.GetID() || found.GetVersion() != updated.GetVersion() {
+		t.Fatalf("expected Express stored at 12 on top of 30, got %v", found.Data())
+	}
+
+	methods, err := store.ShippingMethodList(ctx, NewShippingMethodQuery().SetStatus(SHIPPING_METHOD_STATUS_ACTIVE))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	for _, method := range methods {
+		if method.GetName() != "Courier" {
+			continue
+		}
+
+		if _, err := store.OrderSetShippingMethod(ctx, order.GetID(), method.GetID()); !errors.Is(err, ErrShippingMethodNotAvailable) {
+			t.Fatalf("expected ErrShippingMethodNotAvailable for the New York courier, got %v", err)
+		}
+	}
+
+	unaddressed := NewOrder().SetCustomerID("CUSTOMER01").SetPriceFloat(10).SetQuantityInt(1)
+	if err := store.OrderCreate(ctx, unaddressed); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if _, err := store.OrderSetShippingMethod(ctx, unaddressed.GetID(), standard.GetID()); !errors.Is(err, ErrShippingAddressMissing) {
+		t.Fatalf("expected ErrShippingAddressMissing, got %v", err)
+	}
+
+	if _, err := store.OrderSetShippingMethod(ctx, "MISSING", standard.GetID()); !errors.Is(err, ErrOrderNotFound) {
+		t.Fatalf("expected ErrOrderNotFound, got %v", err)
+	}
+}
+
+func TestStoreOrderCalculateTax_Shipping(t *testing.T) {
+	store := initTaxStore(t, false, nil)
+	ctx := context.Background()
+
+	seedShipping(t, store)
+	order := seedShippingOrder(t, store, OrderAddress{Line1: "1 Main Street", CountryCode: "US", Region: "CA"})
+
+	if err := store.TaxRateCreate(ctx, NewTaxRate().SetCountryCode("US").SetRateFloat(5).SetName("Federal")); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	options, err := store.ShippingQuote(ctx, ShippingQuoteRequest{OrderID: order.GetID()})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if _, err := store.OrderSetShippingMethod(ctx, order.GetID(), options[1].Method.GetID()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	calculated, err := store.OrderCalculateTax(ctx, order.GetID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// 1.50 of tax on the 30 of items, the 12 of shipping untaxed
+	if calculated.GetTaxAmountFloat() != 1.5 || calculated.GetPriceFloat() != 43.5 {
+		t.Fatalf("expected a price of 43.50 with shipping, got %v", calculated.Data())
+	}
+}
@@ -0,0 +1,455 @@
+package shopstore
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"maps"
+
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+	"github.com/dromara/carbon/v2"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+)
+
+func (store *Store) ShippingZoneCount(ctx context.Context, options ShippingZoneQueryInterface) (int64, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.shippingZoneQuery(ctx, options)
+	if err != nil {
+		return -1, err
+	}
+
+	var count int64
+	if err := q.Count(&count); err != nil {
+		return -1, store.operationError("shipping zone count", err)
+	}
+
+	return count, nil
+}
+
+func (store *Store) ShippingZoneCreate(ctx context.Context, shippingZone ShippingZoneInterface) error {
+	if shippingZone == nil {
+		return errors.New("shipping zone is nil")
+	}
+
+	shippingZone.SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+	shippingZone.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+	shippingZone.SetSoftDeletedAt(MAX_DATETIME)
+
+	if err := store.shippingZoneHooks.runBeforeCreate(ctx, shippingZone); err != nil {
+		return err
+	}
+
+	data := shippingZone.Data()
+	row := map[string]any{}
+	for k, v := range data {
+		row[k] = rowValue(k, v)
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		err := statement(tx).Table(store.shippingZoneTableName).Create(row)
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_SHIPPING_ZONE, shippingZone.GetID(), AUDIT_OPERATION_CREATE, nil, data),
+		}, err
+	})
+	if err != nil {
+		return store.operationError("shipping zone create", err)
+	}
+
+	shippingZone.MarkAsNotDirty()
+
+	store.shippingZoneHooks.runAfterCreate(ctx, shippingZone)
+
+	return nil
+}
+
+func (store *Store) ShippingZoneDelete(ctx context.Context, shippingZone ShippingZoneInterface) error {
+	if shippingZone == nil {
+		return errors.New("shipping zone is nil")
+	}
+
+	return store.ShippingZoneDeleteByID(ctx, shippingZone.GetID())
+}
+
+// assertShippingZoneDeletable performs a non-atomic check-then-act, like
+// assertCustomerDeletable
+func (store *Store) assertShippingZoneDeletable(ctx context.Context, shippingZoneID string) error {
+	methodCount, err := store.ShippingMethodCount(ctx, NewShippingMethodQuery().SetShippingZoneID(shippingZoneID))
+	if err != nil {
+		return err
+	}
+	if methodCount > 0 {
+		return ErrShippingZoneHasActiveMethods
+	}
+
+	return nil
+}
+
+// ShippingZoneDeleteByID permanently deletes the shipping zone. Fails with
+// ErrShippingZoneHasActiveMethods while methods deliver to it.
+func (store *Store) ShippingZoneDeleteByID(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("shipping zone id is empty")
+	}
+
+	if err := store.assertShippingZoneDeletable(ctx, id); err != nil {
+		return err
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		before, err := store.storedValues(tx, store.shippingZoneTableName, id, nil)
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		_, err = statement(tx).Table(store.shippingZoneTableName).Where(COLUMN_ID+" = ?", id).Delete()
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_SHIPPING_ZONE, id, AUDIT_OPERATION_DELETE, before, nil),
+		}, err
+	})
+	if err != nil {
+		return store.operationError("shipping zone delete", err)
+	}
+
+	store.shippingZoneHooks.runAfterDelete(ctx, id)
+
+	return nil
+}
+
+func (store *Store) ShippingZoneFindByID(ctx context.Context, id string) (ShippingZoneInterface, error) {
+	if id == "" {
+		return nil, errors.New("shipping zone id is empty")
+	}
+
+	list, err := store.ShippingZoneList(ctx, NewShippingZoneQuery().
+		SetID(id).
+		SetLimit(1))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) > 0 {
+		return list[0], nil
+	}
+
+	return nil, nil
+}
+
+func (store *Store) ShippingZoneList(ctx context.Context, options ShippingZoneQueryInterface) ([]ShippingZoneInterface, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.shippingZoneQuery(ctx, options)
+	if err != nil {
+		return []ShippingZoneInterface{}, err
+	}
+
+	var results []map[string]any
+	if err := q.Get(&results); err != nil {
+		return []ShippingZoneInterface{}, store.operationError("shipping zone list", err)
+	}
+
+	list := []ShippingZoneInterface{}
+
+	lo.ForEach(results, func(result map[string]any, index int) {
+		model := NewShippingZoneFromExistingData(mapAnyToString(result))
+		list = append(list, model)
+	})
+
+	return list, nil
+}
+
+// ShippingZoneIterate streams the shipping zones matching the query options, fetching
+// them from the database in batches instead of loading them all at once.
+// Iteration stops with an error as soon as ctx is cancelled.
+func (store *Store) ShippingZoneIterate(ctx context.Context, options ShippingZoneQueryInterface) iter.Seq2[ShippingZoneInterface, error] {
+	if options == nil {
+		options = NewShippingZoneQuery()
+	}
+
+	build := func(ctx context.Context) (contractsorm.Query, error) {
+		return store.shippingZoneQuery(ctx, options)
+	}
+
+	hydrate := func(data map[string]string) ShippingZoneInterface {
+		return NewShippingZoneFromExistingData(data)
+	}
+
+	return iterateInBatches(store, ctx, "shipping zone iterate", options, build, hydrate)
+}
+
+// ShippingZoneListPage returns a single keyset (cursor) paginated page of shipping zones
+// matching the query options. The page size is taken from SetLimit. Pass the
+// returned NextCursor to SetAfterCursor to fetch the following page.
+func (store *Store) ShippingZoneListPage(ctx context.Context, options ShippingZoneQueryInterface) (ListPage[ShippingZoneInterface], error) {
+	if options == nil {
+		options = NewShippingZoneQuery()
+	}
+
+	if !options.HasLimit() {
+		return ListPage[ShippingZoneInterface]{}, errors.New("shipping zone list page. limit is required")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.shippingZoneQuery(ctx, options)
+	if err != nil {
+		return ListPage[ShippingZoneInterface]{}, err
+	}
+
+	q = keysetPageQuery(q, options.Limit(), options)
+
+	var results []map[string]any
+	if err := q.Get(&results); err != nil {
+		return ListPage[ShippingZoneInterface]{}, store.operationError("shipping zone list page", err)
+	}
+
+	list := []ShippingZoneInterface{}
+
+	lo.ForEach(results, func(result map[string]any, index int) {
+		model := NewShippingZoneFromExistingData(mapAnyToString(result))
+		list = append(list, model)
+	})
+
+	return newListPage(list, options.Limit(), options), nil
+}
+
+// ShippingZoneSoftDelete soft deletes the shipping zone. Fails with
+// ErrShippingZoneHasActiveMethods while methods deliver to it.
+func (store *Store) ShippingZoneSoftDelete(ctx context.Context, shippingZone ShippingZoneInterface) error {
+	if shippingZone == nil {
+		return errors.New("shipping zone is nil")
+	}
+
+	if err := store.assertShippingZoneDeletable(ctx, shippingZone.GetID()); err != nil {
+		return err
+	}
+
+	shippingZone.SetSoftDeletedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+
+	if err := store.shippingZoneUpdate(ctx, shippingZone); err != nil {
+		return err
+	}
+
+	store.shippingZoneHooks.runAfterSoftDelete(ctx, shippingZone.GetID())
+
+	return nil
+}
+
+func (store *Store) ShippingZoneSoftDeleteByID(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("shipping zone id is empty")
+	}
+
+	shippingZone, err := store.ShippingZoneFindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if shippingZone == nil {
+		return nil
+	}
+
+	return store.ShippingZoneSoftDelete(ctx, shippingZone)
+}
+
+// ShippingZoneSoftDeleteMany soft deletes the live shipping zones matching the
+// query options in a single transaction, returning how many were soft
+// deleted.
+// AfterSoftDelete hooks run for every shipping zone.
+func (store *Store) ShippingZoneSoftDeleteMany(ctx context.Context, options ShippingZoneQueryInterface) (int64, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	ids, err := store.updateMany(ctx, store.shippingZoneBulkTable(options), map[string]string{
+		COLUMN_SOFT_DELETED_AT: carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC),
+	})
+	if err != nil {
+		return 0, store.operationError("shipping zone soft delete many", err)
+	}
+
+	for _, id := range ids {
+		store.shippingZoneHooks.runAfterSoftDelete(ctx, id)
+	}
+
+	return int64(len(ids)), nil
+}
+
+func (store *Store) ShippingZoneUpdate(ctx context.Context, shippingZone ShippingZoneInterface) error {
+	if shippingZone == nil {
+		return errors.New("shipping zone is nil")
+	}
+
+	if err := store.shippingZoneHooks.runBeforeUpdate(ctx, shippingZone, shippingZone.DataChanged()); err != nil {
+		return err
+	}
+
+	changed := shippingZone.DataChanged()
+	if err := store.shippingZoneUpdate(ctx, shippingZone); err != nil {
+		return err
+	}
+
+	store.shippingZoneHooks.runAfterUpdate(ctx, shippingZone, changed)
+
+	return nil
+}
+
+// ShippingZoneUpdateMany sets the fields on all the shipping zones matching
+// the query options in a single transaction, returning how many were
+// updated. Only the columns listed in shippingZoneUpdatableColumns can be
+// set. Versions are incremented without being checked. The AfterUpdate
+// hooks run on the shipping zones set, but not the Before hooks, as the
+// shipping zones are not loaded.
+func (store *Store) ShippingZoneUpdateMany(ctx context.Context, options ShippingZoneQueryInterface, fields map[string]string) (int64, error) {
+	if err := assertUpdatable(fields, shippingZoneUpdatableColumns); err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	ids, rows, err := store.updateManyLoaded(ctx, store.shippingZoneBulkTable(options), fields, store.shippingZoneHooks.hasAfterUpdate())
+	if err != nil {
+		return 0, store.operationError("shipping zone update many", err)
+	}
+
+	runAfterUpdateRows(ctx, &store.shippingZoneHooks, rows, NewShippingZoneFromExistingData, func(map[string]string) map[string]string {
+		return maps.Clone(fields)
+	})
+
+	return int64(len(ids)), nil
+}
+
+// shippingZoneUpdate writes the changed fields of shippingZone, without running hooks
+func (store *Store) shippingZoneUpdate(ctx context.Context, shippingZone ShippingZoneInterface) error {
+	shippingZone.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString())
+
+	dataChanged := shippingZone.DataChanged()
+
+	delete(dataChanged, COLUMN_ID)      // ID is not updateable
+	delete(dataChanged, "hash")         // Hash is not updateable
+	delete(dataChanged, "data")         // Data is not updateable
+	delete(dataChanged, COLUMN_VERSION) // Version is set by the update itself
+
+	if len(dataChanged) < 1 {
+		return nil
+	}
+
+	row := map[string]any{}
+	for k, v := range dataChanged {
+		row[k] = rowValue(k, v)
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	var version int64
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		before, err := store.storedValues(tx, store.shippingZoneTableName, shippingZone.GetID(), lo.Keys(dataChanged))
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		version, err = versionedUpdate(ctx, statement(tx).Table(store.shippingZoneTableName).Where(COLUMN_ID+" = ?", shippingZone.GetID()), shippingZone.GetVersion(), row)
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_SHIPPING_ZONE, shippingZone.GetID(), updateOperation(dataChanged), before, dataChanged),
+		}, err
+	})
+
+	if err == nil {
+		shippingZone.SetVersion(version)
+		shippingZone.MarkAsNotDirty()
+	}
+
+	return store.operationError("shipping zone update", err)
+}
+
+// shippingZoneBulkTable describes the shipping zones matching the query options to
+// the bulk operations
+func (store *Store) shippingZoneBulkTable(options ShippingZoneQueryInterface) bulkTable {
+	return bulkTable{
+		entityType: ENTITY_TYPE_SHIPPING_ZONE,
+		tableName:  store.shippingZoneTableName,
+		selectRows: func(q contractsorm.Query) (contractsorm.Query, error) {
+			return store.shippingZoneQueryOn(q, options)
+		},
+	}
+}
+
+func (store *Store) shippingZoneQuery(ctx context.Context, options ShippingZoneQueryInterface) (contractsorm.Query, error) {
+	return store.shippingZoneQueryOn(store.query(ctx), options)
+}
+
+// shippingZoneQueryOn applies the query options to q, which may run within a transaction
+func (store *Store) shippingZoneQueryOn(q contractsorm.Query, options ShippingZoneQueryInterface) (contractsorm.Query, error) {
+	if options == nil {
+		options = NewShippingZoneQuery()
+	}
+
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	q = q.Table(store.shippingZoneTableName)
+
+	if options.HasID() {
+		q = q.Where(COLUMN_ID+" = ?", options.ID())
+	}
+
+	if options.HasIDIn() {
+		ids := make([]any, len(options.IDIn()))
+		for i, id := range options.IDIn() {
+			ids[i] = id
+		}
+		q = q.WhereIn(COLUMN_ID, ids)
+	}
+
+	if options.HasStatus() {
+		q = q.Where(COLUMN_STATUS+" = ?", options.Status())
+	}
+
+	if options.HasCreatedAtGte() && options.HasCreatedAtLte() {
+		q = q.Where(COLUMN_CREATED_AT+" BETWEEN ? AND ?", options.CreatedAtGte(), options.CreatedAtLte())
+	} else if options.HasCreatedAtGte() {
+		q = q.Where(COLUMN_CREATED_AT+" >= ?", options.CreatedAtGte())
+	} else if options.HasCreatedAtLte() {
+		q = q.Where(COLUMN_CREATED_AT+" <= ?", options.CreatedAtLte())
+	}
+
+	if !options.IsCountOnly() {
+		if options.HasLimit() {
+			q = q.Limit(cast.ToInt(options.Limit()))
+		}
+
+		if options.HasOffset() {
+			q = q.Offset(cast.ToInt(options.Offset()))
+		}
+	}
+
+	if options.HasAfterCursor() {
+		var err error
+		q, err = whereAfterCursor(q, store.dialect, keysetSort(options), options.AfterCursor())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if isSorted(options) {
+		q = orderByKeyset(q, keysetSort(options))
+	}
+
+	if !options.SoftDeletedIncluded() {
+		q = q.Where(COLUMN_SOFT_DELETED_AT+" = ?", MAX_DATETIME)
+	}
+
+	return q, nil
+}
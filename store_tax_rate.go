@@ -0,0 +1,440 @@
+package shopstore
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"maps"
+
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+	"github.com/dromara/carbon/v2"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+)
+
+func (store *Store) TaxRateCount(ctx context.Context, options TaxRateQueryInterface) (int64, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.taxRateQuery(ctx, options)
+	if err != nil {
+		return -1, err
+	}
+
+	var count int64
+	if err := q.Count(&count); err != nil {
+		return -1, store.operationError("tax rate count", err)
+	}
+
+	return count, nil
+}
+
+func (store *Store) TaxRateCreate(ctx context.Context, taxRate TaxRateInterface) error {
+	taxRate.SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+	taxRate.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+	taxRate.SetSoftDeletedAt(MAX_DATETIME)
+
+	if err := store.taxRateHooks.runBeforeCreate(ctx, taxRate); err != nil {
+		return err
+	}
+
+	data := taxRate.Data()
+	row := map[string]any{}
+	for k, v := range data {
+		row[k] = rowValue(k, v)
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		err := statement(tx).Table(store.taxRateTableName).Create(row)
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_TAX_RATE, taxRate.GetID(), AUDIT_OPERATION_CREATE, nil, data),
+		}, err
+	})
+	if err != nil {
+		return store.operationError("tax rate create", err)
+	}
+
+	taxRate.MarkAsNotDirty()
+
+	store.taxRateHooks.runAfterCreate(ctx, taxRate)
+
+	return nil
+}
+
+func (store *Store) TaxRateDelete(ctx context.Context, taxRate TaxRateInterface) error {
+	if taxRate == nil {
+		return errors.New("tax rate is nil")
+	}
+
+	return store.TaxRateDeleteByID(ctx, taxRate.GetID())
+}
+
+func (store *Store) TaxRateDeleteByID(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("tax rate id is empty")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		before, err := store.storedValues(tx, store.taxRateTableName, id, nil)
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		_, err = statement(tx).Table(store.taxRateTableName).Where(COLUMN_ID+" = ?", id).Delete()
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_TAX_RATE, id, AUDIT_OPERATION_DELETE, before, nil),
+		}, err
+	})
+	if err != nil {
+		return store.operationError("tax rate delete", err)
+	}
+
+	store.taxRateHooks.runAfterDelete(ctx, id)
+
+	return nil
+}
+
+func (store *Store) TaxRateFindByID(ctx context.Context, id string) (TaxRateInterface, error) {
+	if id == "" {
+		return nil, errors.New("tax rate id is empty")
+	}
+
+	list, err := store.TaxRateList(ctx, NewTaxRateQuery().
+		SetID(id).
+		SetLimit(1))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) > 0 {
+		return list[0], nil
+	}
+
+	return nil, nil
+}
+
+func (store *Store) TaxRateList(ctx context.Context, options TaxRateQueryInterface) ([]TaxRateInterface, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.taxRateQuery(ctx, options)
+	if err != nil {
+		return []TaxRateInterface{}, err
+	}
+
+	var results []map[string]any
+	if err := q.Get(&results); err != nil {
+		return []TaxRateInterface{}, store.operationError("tax rate list", err)
+	}
+
+	list := []TaxRateInterface{}
+
+	lo.ForEach(results, func(result map[string]any, index int) {
+		model := NewTaxRateFromExistingData(mapAnyToString(result))
+		list = append(list, model)
+	})
+
+	return list, nil
+}
+
+// TaxRateIterate streams the tax rates matching the query options, fetching
+// them from the database in batches instead of loading them all at once.
+// Iteration stops with an error as soon as ctx is cancelled.
+func (store *Store) TaxRateIterate(ctx context.Context, options TaxRateQueryInterface) iter.Seq2[TaxRateInterface, error] {
+	if options == nil {
+		options = NewTaxRateQuery()
+	}
+
+	build := func(ctx context.Context) (contractsorm.Query, error) {
+		return store.taxRateQuery(ctx, options)
+	}
+
+	hydrate := func(data map[string]string) TaxRateInterface {
+		return NewTaxRateFromExistingData(data)
+	}
+
+	return iterateInBatches(store, ctx, "tax rate iterate", options, build, hydrate)
+}
+
+// TaxRateListPage returns a single keyset (cursor) paginated page of tax rates
+// matching the query options. The page size is taken from SetLimit. Pass the
+// returned NextCursor to SetAfterCursor to fetch the following page.
+func (store *Store) TaxRateListPage(ctx context.Context, options TaxRateQueryInterface) (ListPage[TaxRateInterface], error) {
+	if options == nil {
+		options = NewTaxRateQuery()
+	}
+
+	if !options.HasLimit() {
+		return ListPage[TaxRateInterface]{}, errors.New("tax rate list page. limit is required")
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	q, err := store.taxRateQuery(ctx, options)
+	if err != nil {
+		return ListPage[TaxRateInterface]{}, err
+	}
+
+	q = keysetPageQuery(q, options.Limit(), options)
+
+	var results []map[string]any
+	if err := q.Get(&results); err != nil {
+		return ListPage[TaxRateInterface]{}, store.operationError("tax rate list page", err)
+	}
+
+	list := []TaxRateInterface{}
+
+	lo.ForEach(results, func(result map[string]any, index int) {
+		model := NewTaxRateFromExistingData(mapAnyToString(result))
+		list = append(list, model)
+	})
+
+	return newListPage(list, options.Limit(), options), nil
+}
+
+func (store *Store) TaxRateSoftDelete(ctx context.Context, taxRate TaxRateInterface) error {
+	if taxRate == nil {
+		return errors.New("tax rate is nil")
+	}
+
+	taxRate.SetSoftDeletedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+
+	if err := store.taxRateUpdate(ctx, taxRate); err != nil {
+		return err
+	}
+
+	store.taxRateHooks.runAfterSoftDelete(ctx, taxRate.GetID())
+
+	return nil
+}
+
+func (store *Store) TaxRateSoftDeleteByID(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("tax rate id is empty")
+	}
+
+	taxRate, err := store.TaxRateFindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if taxRate == nil {
+		return nil
+	}
+
+	return store.TaxRateSoftDelete(ctx, taxRate)
+}
+
+// TaxRateSoftDeleteMany soft deletes the live tax rates matching the
+// query options in a single transaction, returning how many were soft
+// deleted.
+// AfterSoftDelete hooks run for every tax rate.
+func (store *Store) TaxRateSoftDeleteMany(ctx context.Context, options TaxRateQueryInterface) (int64, error) {
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	ids, err := store.updateMany(ctx, store.taxRateBulkTable(options), map[string]string{
+		COLUMN_SOFT_DELETED_AT: carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC),
+	})
+	if err != nil {
+		return 0, store.operationError("tax rate soft delete many", err)
+	}
+
+	for _, id := range ids {
+		store.taxRateHooks.runAfterSoftDelete(ctx, id)
+	}
+
+	return int64(len(ids)), nil
+}
+
+func (store *Store) TaxRateUpdate(ctx context.Context, taxRate TaxRateInterface) error {
+	if taxRate == nil {
+		return errors.New("tax rate is nil")
+	}
+
+	if err := store.taxRateHooks.runBeforeUpdate(ctx, taxRate, taxRate.DataChanged()); err != nil {
+		return err
+	}
+
+	changed := taxRate.DataChanged()
+	if err := store.taxRateUpdate(ctx, taxRate); err != nil {
+		return err
+	}
+
+	store.taxRateHooks.runAfterUpdate(ctx, taxRate, changed)
+
+	return nil
+}
+
+// TaxRateUpdateMany sets the fields on all the tax rates matching the query
+// options in a single transaction, returning how many were updated. Only
+// the columns listed in taxRateUpdatableColumns can be set. Versions are
+// incremented without being checked. The AfterUpdate hooks run on the tax
+// rates set, but not the Before hooks, as the tax rates are not loaded.
+func (store *Store) TaxRateUpdateMany(ctx context.Context, options TaxRateQueryInterface, fields map[string]string) (int64, error) {
+	if err := assertUpdatable(fields, taxRateUpdatableColumns); err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	ids, rows, err := store.updateManyLoaded(ctx, store.taxRateBulkTable(options), fields, store.taxRateHooks.hasAfterUpdate())
+	if err != nil {
+		return 0, store.operationError("tax rate update many", err)
+	}
+
+	runAfterUpdateRows(ctx, &store.taxRateHooks, rows, NewTaxRateFromExistingData, func(map[string]string) map[string]string {
+		return maps.Clone(fields)
+	})
+
+	return int64(len(ids)), nil
+}
+
+// taxRateUpdate writes the changed fields of taxRate, without running hooks
+func (store *Store) taxRateUpdate(ctx context.Context, taxRate TaxRateInterface) error {
+	taxRate.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString())
+
+	dataChanged := taxRate.DataChanged()
+
+	delete(dataChanged, COLUMN_ID)      // ID is not updateable
+	delete(dataChanged, "hash")         // Hash is not updateable
+	delete(dataChanged, "data")         // Data is not updateable
+	delete(dataChanged, COLUMN_VERSION) // Version is set by the update itself
+
+	if len(dataChanged) < 1 {
+		return nil
+	}
+
+	row := map[string]any{}
+	for k, v := range dataChanged {
+		row[k] = rowValue(k, v)
+	}
+
+	ctx, cancel := store.operationContext(ctx)
+	defer cancel()
+
+	var version int64
+	err := store.changeTransaction(ctx, func(tx contractsorm.Query) (changeRecords, error) {
+		before, err := store.storedValues(tx, store.taxRateTableName, taxRate.GetID(), lo.Keys(dataChanged))
+		if err != nil {
+			return changeRecords{}, err
+		}
+
+		version, err = versionedUpdate(ctx, statement(tx).Table(store.taxRateTableName).Where(COLUMN_ID+" = ?", taxRate.GetID()), taxRate.GetVersion(), row)
+		return changeRecords{
+			auditLogs: store.auditLogEntries(ctx, ENTITY_TYPE_TAX_RATE, taxRate.GetID(), updateOperation(dataChanged), before, dataChanged),
+		}, err
+	})
+
+	if err == nil {
+		taxRate.SetVersion(version)
+		taxRate.MarkAsNotDirty()
+	}
+
+	return store.operationError("tax rate update", err)
+}
+
+// taxRateBulkTable describes the tax rates matching the query options to
+// the bulk operations
+func (store *Store) taxRateBulkTable(options TaxRateQueryInterface) bulkTable {
+	return bulkTable{
+		entityType: ENTITY_TYPE_TAX_RATE,
+		tableName:  store.taxRateTableName,
+		selectRows: func(q contractsorm.Query) (contractsorm.Query, error) {
+			return store.taxRateQueryOn(q, options)
+		},
+	}
+}
+
+func (store *Store) taxRateQuery(ctx context.Context, options TaxRateQueryInterface) (contractsorm.Query, error) {
+	return store.taxRateQueryOn(store.query(ctx), options)
+}
+
+// taxRateQueryOn applies the query options to q, which may run within a transaction
+func (store *Store) taxRateQueryOn(q contractsorm.Query, options TaxRateQueryInterface) (contractsorm.Query, error) {
+	if options == nil {
+		options = NewTaxRateQuery()
+	}
+
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	q = q.Table(store.taxRateTableName)
+
+	if options.HasID() {
+		q = q.Where(COLUMN_ID+" = ?", options.ID())
+	}
+
+	if options.HasIDIn() {
+		ids := make([]any, len(options.IDIn()))
+		for i, id := range options.IDIn() {
+			ids[i] = id
+		}
+		q = q.WhereIn(COLUMN_ID, ids)
+	}
+
+	if options.HasTaxClass() {
+		q = q.Where(COLUMN_TAX_CLASS+" = ?", options.TaxClass())
+	}
+
+	if options.HasTaxClassIn() {
+		taxClasses := make([]any, len(options.TaxClassIn()))
+		for i, taxClass := range options.TaxClassIn() {
+			taxClasses[i] = taxClass
+		}
+		q = q.WhereIn(COLUMN_TAX_CLASS, taxClasses)
+	}
+
+	if options.HasCountryCode() {
+		q = q.Where(COLUMN_COUNTRY_CODE+" = ?", options.CountryCode())
+	}
+
+	if options.HasRegion() {
+		q = q.Where(COLUMN_REGION+" = ?", options.Region())
+	}
+
+	if options.HasCreatedAtGte() && options.HasCreatedAtLte() {
+		q = q.Where(COLUMN_CREATED_AT+" BETWEEN ? AND ?", options.CreatedAtGte(), options.CreatedAtLte())
+	} else if options.HasCreatedAtGte() {
+		q = q.Where(COLUMN_CREATED_AT+" >= ?", options.CreatedAtGte())
+	} else if options.HasCreatedAtLte() {
+		q = q.Where(COLUMN_CREATED_AT+" <= ?", options.CreatedAtLte())
+	}
+
+	if !options.IsCountOnly() {
+		if options.HasLimit() {
+			q = q.Limit(cast.ToInt(options.Limit()))
+		}
+
+		if options.HasOffset() {
+			q = q.Offset(cast.ToInt(options.Offset()))
+		}
+	}
+
+	if options.HasAfterCursor() {
+		var err error
+		q, err = whereAfterCursor(q, store.dialect, keysetSort(options), options.AfterCursor())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if isSorted(options) {
+		q = orderByKeyset(q, keysetSort(options))
+	}
+
+	if !options.SoftDeletedIncluded() {
+		q = q.Where(COLUMN_SOFT_DELETED_AT+" = ?", MAX_DATETIME)
+	}
+
+	return q, nil
+}
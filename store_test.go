@@ -7,11 +7,21 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
 	_ "modernc.org/sqlite"
 )
 
+// initDB opens the test database. The tests run against SQLite, unless
+// SHOPSTORE_TEST_DRIVER ("mysql" or "postgres") and SHOPSTORE_TEST_DSN
+// point them at a database server instead (see docker-compose.yml).
 func initDB(filepath string) (*sql.DB, error) {
+	if driver := os.Getenv("SHOPSTORE_TEST_DRIVER"); driver != "" && driver != "sqlite" {
+		return initServerDB(driver, os.Getenv("SHOPSTORE_TEST_DSN"))
+	}
+
 	if filepath != ":memory:" {
 		err := os.Remove(filepath) // remove database
 
@@ -30,14 +40,52 @@ func initDB(filepath string) (*sql.DB, error) {
 	return db, nil
 }
 
-func initStore(filepath string) (StoreInterface, error) {
-	db, err := initDB(filepath)
+// initServerDB connects to a MySQL or PostgreSQL test database and drops
+// the tables left behind by the previous test, so every test starts from
+// an empty schema like it does on an in-memory SQLite database.
+func initServerDB(driver string, dsn string) (*sql.DB, error) {
+	if dsn == "" {
+		return nil, errors.New("SHOPSTORE_TEST_DSN is required for driver " + driver)
+	}
 
+	db, err := sql.Open(driver, dsn)
 	if err != nil {
 		return nil, err
 	}
 
-	store, err := NewStore(NewStoreOptions{
+	options := testStoreOptions(db)
+	tables := []string{
+		// the tables referencing others by foreign keys first
+		options.OrderLineItemTableName,
+		DEFAULT_ADDRESS_TABLE_NAME,
+		DEFAULT_CART_ITEM_TABLE_NAME,
+		DEFAULT_SHIPPING_METHOD_TABLE_NAME,
+		options.CategoryTableName,
+		options.DiscountTableName,
+		options.MediaTableName,
+		options.OrderTableName,
+		options.ProductTableName,
+		DEFAULT_MIGRATION_TABLE_NAME,
+		DEFAULT_OUTBOX_TABLE_NAME,
+		DEFAULT_AUDIT_LOG_TABLE_NAME,
+		DEFAULT_CUSTOMER_TABLE_NAME,
+		DEFAULT_CART_TABLE_NAME,
+		DEFAULT_TAX_RATE_TABLE_NAME,
+		DEFAULT_SHIPPING_ZONE_TABLE_NAME,
+	}
+
+	for _, table := range tables {
+		if _, err := db.Exec("DROP TABLE IF EXISTS " + table); err != nil {
+			return nil, err
+		}
+	}
+
+	return db, nil
+}
+
+// testStoreOptions returns the store options used by the tests
+func testStoreOptions(db *sql.DB) NewStoreOptions {
+	return NewStoreOptions{
 		DB:                     db,
 		CategoryTableName:      "shop_category",
 		DiscountTableName:      "shop_discount",
@@ -46,7 +94,17 @@ func initStore(filepath string) (StoreInterface, error) {
 		OrderLineItemTableName: "shop_order_line_item",
 		ProductTableName:       "shop_product",
 		AutomigrateEnabled:     true,
-	})
+	}
+}
+
+func initStore(filepath string) (StoreInterface, error) {
+	db, err := initDB(filepath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := NewStore(testStoreOptions(db))
 
 	if err != nil {
 		return nil, err
@@ -546,11 +604,11 @@ func TestStoreDiscountFindByID(t *testing.T) {
 		return
 	}
 
-	if discountFound.GetStartsAt() != "2022-01-01 00:00:00 +0000 UTC" {
+	if discountFound.GetStartsAt() != "2022-01-01 00:00:00" {
 		t.Fatal("Exam start date MUST BE '2022-01-01 00:00:00', found: ", discountFound.GetStartsAt())
 	}
 
-	if discountFound.GetEndsAt() != "2022-01-01 23:59:59 +0000 UTC" {
+	if discountFound.GetEndsAt() != "2022-01-01 23:59:59" {
 		t.Fatal("Exam end date MUST BE '2022-01-01 23:59:59', found: ", discountFound.GetEndsAt())
 	}
 
@@ -1010,3 +1068,105 @@ func TestStoreMediaUpdate(t *testing.T) {
 		t.Fatal("unexpected media title: ", mediaFound.GetTitle())
 	}
 }
+
+func TestStoreOperationTimeout(t *testing.T) {
+	db, err := initDB(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	options := testStoreOptions(db)
+	options.OperationTimeout = time.Nanosecond
+
+	store, err := NewStore(options)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	_, err = store.OrderList(context.Background(), NewOrderQuery())
+
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected *TimeoutError, got %v", err)
+	}
+
+	if timeoutErr.Operation != "order list" {
+		t.Fatalf("expected operation order list, got %q", timeoutErr.Operation)
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("expected the timeout error to unwrap to context.DeadlineExceeded")
+	}
+
+	err = store.ProductCreate(context.Background(), NewProduct())
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected *TimeoutError on create, got %v", err)
+	}
+}
+
+func TestStoreContextPropagation(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = store.ProductCount(cancelled, NewProductQuery())
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+
+	_, err = store.CategoryList(expired, NewCategoryQuery())
+
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected *TimeoutError for an expired context, got %v", err)
+	}
+
+	if _, err := store.ProductCount(context.Background(), NewProductQuery()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+}
+
+func TestStoreDiscountCreate_DuplicateCode(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	first := NewDiscount().SetCode("WELCOME10")
+	if err := store.DiscountCreate(ctx, first); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	err = store.DiscountCreate(ctx, NewDiscount().SetCode("WELCOME10"))
+	if !errors.Is(err, ErrDiscountCodeExists) {
+		t.Fatalf("expected ErrDiscountCodeExists, got %v", err)
+	}
+
+	second := NewDiscount().SetCode("WELCOME20")
+	if err := store.DiscountCreate(ctx, second); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	second.SetCode("WELCOME10")
+	if err := store.DiscountUpdate(ctx, second); !errors.Is(err, ErrDiscountCodeExists) {
+		t.Fatalf("expected ErrDiscountCodeExists on update, got %v", err)
+	}
+
+	// the code of a soft deleted discount can be reused
+	if err := store.DiscountSoftDelete(ctx, first); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.DiscountCreate(ctx, NewDiscount().SetCode("WELCOME10")); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+}
@@ -0,0 +1,156 @@
+package shopstore
+
+import (
+	"context"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// TaxCalculator calculates the tax of the line items of an order. Set
+// NewStoreOptions.TaxCalculator to plug in another implementation, like a
+// tax service client. The default is a TableTaxCalculator.
+type TaxCalculator interface {
+	// CalculateTax returns the tax of each line of the request, in the
+	// order of the lines
+	CalculateTax(ctx context.Context, request TaxRequest) ([]TaxLineResult, error)
+}
+
+// TaxRequest describes the lines of an order to calculate the tax of
+type TaxRequest struct {
+	// Address is the shipping address of the order, or its billing address
+	// when it has no shipping address
+	Address OrderAddress
+
+	// PricesIncludeTax tells that the line amounts include the tax
+	PricesIncludeTax bool
+
+	Lines []TaxLine
+}
+
+// TaxLine is a line of a TaxRequest
+type TaxLine struct {
+	ProductID string
+
+	// TaxClass is the tax class of the product
+	TaxClass string
+
+	// Amount is the taxable amount of the line: its unit price times its
+	// quantity, less its share of the order discount
+	Amount float64
+}
+
+// TaxLineResult is the tax of a TaxLine
+type TaxLineResult struct {
+	// Rate is the rate applied, in percent
+	Rate float64
+
+	// Amount is the tax of the whole line, rounded to the cent
+	Amount float64
+}
+
+// TableTaxCalculator is the default TaxCalculator. It applies the tax
+// rates of the tax rate table: the rate of the tax class for the region of
+// the address if there is one, else the rate of the tax class for its
+// country. A line without a matching rate is not taxed.
+type TableTaxCalculator struct {
+	store StoreInterface
+}
+
+var _ TaxCalculator = (*TableTaxCalculator)(nil)
+
+// NewTableTaxCalculator creates a TaxCalculator reading the rates from the
+// tax rate table of the store
+func NewTableTaxCalculator(store StoreInterface) *TableTaxCalculator {
+	return &TableTaxCalculator{store: store}
+}
+
+// CalculateTax returns the tax of each line of the request, in the order of the lines
+func (calculator *TableTaxCalculator) CalculateTax(ctx context.Context, request TaxRequest) ([]TaxLineResult, error) {
+	results := make([]TaxLineResult, len(request.Lines))
+
+	countryCode := strings.ToUpper(strings.TrimSpace(request.Address.CountryCode))
+	if countryCode == "" || len(request.Lines) == 0 {
+		return results, nil
+	}
+
+	taxClasses := lo.Uniq(lo.Map(request.Lines, func(line TaxLine, _ int) string {
+		return line.TaxClass
+	}))
+
+	taxRates, err := calculator.store.TaxRateList(ctx, NewTaxRateQuery().
+		SetCountryCode(countryCode).
+		SetTaxClassIn(taxClasses).
+		AddSort(COLUMN_CREATED_AT, SORT_DIRECTION_ASC).
+		AddSort(COLUMN_ID, SORT_DIRECTION_ASC))
+	if err != nil {
+		return nil, err
+	}
+
+	region := strings.TrimSpace(request.Address.Region)
+
+	rates := map[string]float64{}
+	for _, taxClass := range taxClasses {
+		regionRate, regionFound := lo.Find(taxRates, func(taxRate TaxRateInterface) bool {
+			return taxRate.GetTaxClass() == taxClass && region != "" && strings.EqualFold(taxRate.GetRegion(), region)
+		})
+
+		countryRate, countryFound := lo.Find(taxRates, func(taxRate TaxRateInterface) bool {
+			return taxRate.GetTaxClass() == taxClass && taxRate.GetRegion() == ""
+		})
+
+		if regionFound {
+			rates[taxClass] = regionRate.GetRateFloat()
+		} else if countryFound {
+			rates[taxClass] = countryRate.GetRateFloat()
+		}
+	}
+
+	for i, line := range request.Lines {
+		rate := rates[line.TaxClass]
+		results[i] = TaxLineResult{
+			Rate:   rate,
+			Amount: taxOf(line.Amount, rate, request.PricesIncludeTax),
+		}
+	}
+
+	return results, nil
+}
+
+// taxOf returns the tax, rounded to the cent, of an amount at the rate in
+// percent. An amount including the tax holds rate/(100+rate) of tax, an
+// amount excluding it bears rate/100 on top.
+func taxOf(amount float64, rate float64, inclusive bool) float64 {
+	if inclusive {
+		return roundPrice(amount * rate / (100 + rate))
+	}
+
+	return roundPrice(amount * rate / 100)
+}
+
+// allocateDiscount splits the discount over the amounts, in proportion to
+// them. The shares are rounded to the cent, the last one taking the
+// rounding difference, so they add up to the discount.
+func allocateDiscount(amounts []float64, discount float64) []float64 {
+	shares := make([]float64, len(amounts))
+
+	total := lo.Sum(amounts)
+	if discount <= 0 || total <= 0 {
+		return shares
+	}
+
+	discount = min(discount, total)
+
+	allocated := 0.0
+	for i, amount := range amounts {
+		if i == len(amounts)-1 {
+			shares[i] = roundPrice(discount - allocated)
+			break
+		}
+
+		shares[i] = roundPrice(discount * amount / total)
+		allocated += shares[i]
+	}
+
+	return shares
+}
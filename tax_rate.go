@@ -0,0 +1,326 @@
+package shopstore
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/dracory/dataobject"
+	"github.com/dromara/carbon/v2"
+	"github.com/spf13/cast"
+)
+
+// == CONSTANTS ==============================================================
+
+// DEFAULT_TAX_RATE_TABLE_NAME is the table the tax rates are stored in
+// when NewStoreOptions.TaxRateTableName is not set
+const DEFAULT_TAX_RATE_TABLE_NAME = "shop_tax_rate"
+
+// == CLASS ==================================================================
+
+// TaxRate represents the rate of a tax class in a country, or in a region
+// of a country. A rate without a region applies to the whole country,
+// except the regions with a rate of their own.
+type TaxRate struct {
+	dataobject.DataObject
+}
+
+// == INTERFACES =============================================================
+
+// Compile-time interface compliance check
+var _ TaxRateInterface = (*TaxRate)(nil)
+
+// == CONSTRUCTORS ===========================================================
+
+// NewTaxRate creates a new tax rate with default values:
+// - TaxClass: standard
+// - CountryCode, Region: empty
+// - Rate: 0 (percent)
+// - Name: empty
+// - Memo: empty
+// - CreatedAt: current UTC time
+// - UpdatedAt: current UTC time
+// - SoftDeletedAt: max datetime (not deleted)
+// - Metas: empty map
+func NewTaxRate() TaxRateInterface {
+	o := (&TaxRate{}).
+		SetID(GenerateShortID()).
+		SetTaxClass(TAX_CLASS_STANDARD).
+		SetCountryCode("").
+		SetRegion("").
+		SetRateFloat(0).
+		SetName("").
+		SetMemo("").
+		SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
+		SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
+		SetSoftDeletedAt(MAX_DATETIME).
+		SetVersion(1)
+
+	_ = o.SetMetas(map[string]string{})
+
+	return o
+}
+
+// NewTaxRateFromExistingData creates a tax rate from existing data map.
+// Used when hydrating from database or external sources.
+func NewTaxRateFromExistingData(data map[string]string) TaxRateInterface {
+	o := &TaxRate{}
+	o.Hydrate(data)
+	return o
+}
+
+// == METHODS ================================================================
+
+// IsSoftDeleted returns true if the tax rate is soft deleted.
+func (taxRate *TaxRate) IsSoftDeleted() bool {
+	return taxRate.GetSoftDeletedAt() != MAX_DATETIME
+}
+
+// == SETTERS AND GETTERS ====================================================
+
+// GetCountryCode returns the ISO 3166-1 alpha-2 country code, like "GB".
+func (taxRate *TaxRate) GetCountryCode() string {
+	return taxRate.Get(COLUMN_COUNTRY_CODE)
+}
+
+// SetCountryCode sets the ISO 3166-1 alpha-2 country code, trimmed and uppercased.
+func (taxRate *TaxRate) SetCountryCode(countryCode string) TaxRateInterface {
+	taxRate.Set(COLUMN_COUNTRY_CODE, strings.ToUpper(strings.TrimSpace(countryCode)))
+	return taxRate
+}
+
+// GetCreatedAt returns the creation timestamp as a string.
+func (taxRate *TaxRate) GetCreatedAt() string {
+	return taxRate.Get(COLUMN_CREATED_AT)
+}
+
+// GetCreatedAtCarbon returns the creation timestamp as a Carbon instance.
+func (taxRate *TaxRate) GetCreatedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(taxRate.GetCreatedAt())
+}
+
+// SetCreatedAt sets the creation timestamp.
+func (taxRate *TaxRate) SetCreatedAt(createdAt string) TaxRateInterface {
+	taxRate.Set(COLUMN_CREATED_AT, createdAt)
+	return taxRate
+}
+
+// GetID returns the unique identifier.
+func (taxRate *TaxRate) GetID() string {
+	return taxRate.Get(COLUMN_ID)
+}
+
+// SetID sets the unique identifier.
+func (taxRate *TaxRate) SetID(id string) TaxRateInterface {
+	taxRate.Set(COLUMN_ID, id)
+	return taxRate
+}
+
+// GetMemo returns the internal memo.
+func (taxRate *TaxRate) GetMemo() string {
+	return taxRate.Get(COLUMN_MEMO)
+}
+
+// SetMemo sets the internal memo.
+func (taxRate *TaxRate) SetMemo(memo string) TaxRateInterface {
+	taxRate.Set(COLUMN_MEMO, memo)
+	return taxRate
+}
+
+// GetMeta returns a specific metadata value by name. Returns empty string if not found.
+func (taxRate *TaxRate) GetMeta(name string) string {
+	metas, err := taxRate.GetMetas()
+
+	if err != nil {
+		return ""
+	}
+
+	if value, exists := metas[name]; exists {
+		return value
+	}
+
+	return ""
+}
+
+// MetaRemove removes a single metadata entry.
+func (taxRate *TaxRate) MetaRemove(name string) error {
+	metas, err := taxRate.GetMetas()
+
+	if err != nil {
+		return err
+	}
+
+	delete(metas, name)
+
+	return taxRate.SetMetas(metas)
+}
+
+// SetMeta sets a single metadata value.
+func (taxRate *TaxRate) SetMeta(name string, value string) error {
+	return taxRate.MetasUpsert(map[string]string{name: value})
+}
+
+// GetMetas returns all metadata as a map. Returns empty map if no metas stored.
+func (taxRate *TaxRate) GetMetas() (map[string]string, error) {
+	metasStr := taxRate.Get(COLUMN_METAS)
+
+	if metasStr == "" {
+		metasStr = "{}"
+	}
+
+	metasJson := map[string]string{}
+	errJson := json.Unmarshal([]byte(metasStr), &metasJson)
+	if errJson != nil {
+		return map[string]string{}, errJson
+	}
+
+	if metasJson == nil {
+		metasJson = map[string]string{}
+	}
+
+	return metasJson, nil
+}
+
+// MetasRemove removes multiple metadata entries.
+func (taxRate *TaxRate) MetasRemove(names []string) error {
+	for _, name := range names {
+		err := taxRate.MetaRemove(name)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MetasUpsert merges the provided metadata with existing values.
+func (taxRate *TaxRate) MetasUpsert(metas map[string]string) error {
+	currentMetas, err := taxRate.GetMetas()
+
+	if err != nil {
+		return err
+	}
+
+	for k, v := range metas {
+		currentMetas[k] = v
+	}
+
+	return taxRate.SetMetas(currentMetas)
+}
+
+// SetMetas replaces all metadata with the provided map.
+// Warning: this overwrites any existing metadata.
+func (taxRate *TaxRate) SetMetas(metas map[string]string) error {
+	mapString, err := json.Marshal(metas)
+
+	if err != nil {
+		return err
+	}
+
+	taxRate.Set(COLUMN_METAS, string(mapString))
+
+	return nil
+}
+
+// GetName returns the name of the tax shown to shoppers, like "VAT".
+func (taxRate *TaxRate) GetName() string {
+	return taxRate.Get(COLUMN_NAME)
+}
+
+// SetName sets the name of the tax shown to shoppers.
+func (taxRate *TaxRate) SetName(name string) TaxRateInterface {
+	taxRate.Set(COLUMN_NAME, name)
+	return taxRate
+}
+
+// GetRate returns the rate in percent as a string.
+func (taxRate *TaxRate) GetRate() string {
+	return taxRate.Get(COLUMN_RATE)
+}
+
+// SetRate sets the rate in percent from a string.
+func (taxRate *TaxRate) SetRate(rate string) TaxRateInterface {
+	taxRate.Set(COLUMN_RATE, rate)
+	return taxRate
+}
+
+// GetRateFloat returns the rate in percent as a float64, like 20 for 20%.
+func (taxRate *TaxRate) GetRateFloat() float64 {
+	return cast.ToFloat64(taxRate.GetRate())
+}
+
+// SetRateFloat sets the rate in percent from a float64.
+func (taxRate *TaxRate) SetRateFloat(rate float64) TaxRateInterface {
+	taxRate.SetRate(cast.ToString(rate))
+	return taxRate
+}
+
+// GetRegion returns the region the rate applies to, empty for the whole country.
+func (taxRate *TaxRate) GetRegion() string {
+	return taxRate.Get(COLUMN_REGION)
+}
+
+// SetRegion sets the region the rate applies to, trimmed. Empty applies to the whole country.
+func (taxRate *TaxRate) SetRegion(region string) TaxRateInterface {
+	taxRate.Set(COLUMN_REGION, strings.TrimSpace(region))
+	return taxRate
+}
+
+// GetSoftDeletedAt returns the soft deletion timestamp.
+func (taxRate *TaxRate) GetSoftDeletedAt() string {
+	return taxRate.Get(COLUMN_SOFT_DELETED_AT)
+}
+
+// GetSoftDeletedAtCarbon returns the soft deletion timestamp as a Carbon instance.
+func (taxRate *TaxRate) GetSoftDeletedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(taxRate.GetSoftDeletedAt())
+}
+
+// SetSoftDeletedAt sets the soft deletion timestamp.
+func (taxRate *TaxRate) SetSoftDeletedAt(deletedAt string) TaxRateInterface {
+	taxRate.Set(COLUMN_SOFT_DELETED_AT, deletedAt)
+	return taxRate
+}
+
+// GetTaxClass returns the tax class the rate applies to.
+func (taxRate *TaxRate) GetTaxClass() string {
+	return taxRate.Get(COLUMN_TAX_CLASS)
+}
+
+// SetTaxClass sets the tax class the rate applies to.
+func (taxRate *TaxRate) SetTaxClass(taxClass string) TaxRateInterface {
+	taxRate.Set(COLUMN_TAX_CLASS, taxClass)
+	return taxRate
+}
+
+// GetUpdatedAt returns the last update timestamp.
+func (taxRate *TaxRate) GetUpdatedAt() string {
+	return taxRate.Get(COLUMN_UPDATED_AT)
+}
+
+// GetUpdatedAtCarbon returns the last update timestamp as a Carbon instance.
+func (taxRate *TaxRate) GetUpdatedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(taxRate.GetUpdatedAt())
+}
+
+// SetUpdatedAt sets the last update timestamp.
+func (taxRate *TaxRate) SetUpdatedAt(updatedAt string) TaxRateInterface {
+	taxRate.Set(COLUMN_UPDATED_AT, updatedAt)
+	return taxRate
+}
+
+// GetVersion returns the version of the row the entity was loaded at, 0 if unknown.
+func (taxRate *TaxRate) GetVersion() int64 {
+	return cast.ToInt64(taxRate.Get(COLUMN_VERSION))
+}
+
+// SetVersion sets the version of the row the entity was loaded at.
+func (taxRate *TaxRate) SetVersion(version int64) TaxRateInterface {
+	taxRate.Set(COLUMN_VERSION, cast.ToString(version))
+	return taxRate
+}
+
+// MarkAsNotDirty resets the dirty state, clearing all change tracking.
+func (taxRate *TaxRate) MarkAsNotDirty() {
+	taxRate.DataObject.MarkAsNotDirty()
+}
@@ -0,0 +1,465 @@
+package shopstore
+
+import "errors"
+
+type TaxRateQueryInterface interface {
+	Validate() error
+
+	Columns() []string
+	SetColumns(columns []string) TaxRateQueryInterface
+
+	HasCountOnly() bool
+	IsCountOnly() bool
+	SetCountOnly(countOnly bool) TaxRateQueryInterface
+
+	HasCreatedAtGte() bool
+	CreatedAtGte() string
+	SetCreatedAtGte(createdAtGte string) TaxRateQueryInterface
+
+	HasCreatedAtLte() bool
+	CreatedAtLte() string
+	SetCreatedAtLte(createdAtLte string) TaxRateQueryInterface
+
+	HasCountryCode() bool
+	CountryCode() string
+	SetCountryCode(countryCode string) TaxRateQueryInterface
+
+	HasID() bool
+	ID() string
+	SetID(id string) TaxRateQueryInterface
+
+	HasIDIn() bool
+	IDIn() []string
+	SetIDIn(idIn []string) TaxRateQueryInterface
+
+	HasLimit() bool
+	Limit() int
+	SetLimit(limit int) TaxRateQueryInterface
+
+	HasOffset() bool
+	Offset() int
+	SetOffset(offset int) TaxRateQueryInterface
+
+	HasOrderBy() bool
+	OrderBy() string
+	SetOrderBy(orderBy string) TaxRateQueryInterface
+
+	HasSortDirection() bool
+	SortDirection() string
+	SetSortDirection(sortDirection string) TaxRateQueryInterface
+
+	// AddSort adds a sort key. Keys are applied in the order they are added,
+	// after the order_by column if one is set. Only the entity's sortable
+	// columns and the asc/desc directions are accepted by Validate.
+	HasSorts() bool
+	Sorts() []QuerySort
+	AddSort(column string, direction string) TaxRateQueryInterface
+
+	// AfterCursor continues a keyset paginated list right after the row the
+	// cursor points at. Cursors are returned as NextCursor by the *ListPage methods.
+	HasAfterCursor() bool
+	AfterCursor() string
+	SetAfterCursor(cursor string) TaxRateQueryInterface
+
+	HasSoftDeletedIncluded() bool
+	SoftDeletedIncluded() bool
+	SetSoftDeletedIncluded(softDeletedIncluded bool) TaxRateQueryInterface
+
+	HasRegion() bool
+	Region() string
+	SetRegion(region string) TaxRateQueryInterface
+
+	HasTaxClass() bool
+	TaxClass() string
+	SetTaxClass(taxClass string) TaxRateQueryInterface
+
+	HasTaxClassIn() bool
+	TaxClassIn() []string
+	SetTaxClassIn(taxClassIn []string) TaxRateQueryInterface
+
+	hasProperty(name string) bool
+}
+
+func NewTaxRateQuery() TaxRateQueryInterface {
+	return &taxRateQueryImplementation{
+		properties: make(map[string]any),
+	}
+}
+
+type taxRateQueryImplementation struct {
+	properties map[string]any
+}
+
+func (c *taxRateQueryImplementation) Validate() error {
+
+	if c.HasCreatedAtGte() && c.CreatedAtGte() == "" {
+		return errors.New("tax rate query. created_at_gte cannot be empty")
+	}
+
+	if c.HasCreatedAtLte() && c.CreatedAtLte() == "" {
+		return errors.New("tax rate query. created_at_lte cannot be empty")
+	}
+
+	if c.HasCountryCode() && c.CountryCode() == "" {
+		return errors.New("tax rate query. country_code cannot be empty")
+	}
+
+	if c.HasID() && c.ID() == "" {
+		return errors.New("tax rate query. id cannot be empty")
+	}
+
+	if c.HasIDIn() && len(c.IDIn()) == 0 {
+		return errors.New("tax rate query. id_in cannot be empty")
+	}
+
+	if c.HasTaxClass() && c.TaxClass() == "" {
+		return errors.New("tax rate query. tax_class cannot be empty")
+	}
+
+	if c.HasTaxClassIn() && len(c.TaxClassIn()) == 0 {
+		return errors.New("tax rate query. tax_class_in cannot be empty")
+	}
+
+	if c.HasSortDirection() && c.SortDirection() == "" {
+		return errors.New("tax rate query. sort_direction cannot be empty")
+	}
+
+	if c.HasLimit() && c.Limit() <= 0 {
+		return errors.New("tax rate query. limit must be greater than 0")
+	}
+
+	if c.HasOffset() && c.Offset() < 0 {
+		return errors.New("tax rate query. offset must be greater than or equal to 0")
+	}
+
+	if c.HasOrderBy() && c.OrderBy() == "" {
+		return errors.New("tax rate query. order_by cannot be empty")
+	}
+
+	if err := validateSort(c, taxRateSortableColumns); err != nil {
+		return errors.New("tax rate query. " + err.Error())
+	}
+
+	if c.HasAfterCursor() {
+		if c.AfterCursor() == "" {
+			return errors.New("tax rate query. after_cursor cannot be empty")
+		}
+
+		if c.HasOffset() {
+			return errors.New("tax rate query. after_cursor cannot be combined with offset")
+		}
+
+		if err := validateCursor(c.AfterCursor(), keysetSort(c)); err != nil {
+			return errors.New("tax rate query. after_cursor " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (c *taxRateQueryImplementation) Columns() []string {
+	if !c.hasProperty("columns") {
+		return []string{}
+	}
+
+	return c.properties["columns"].([]string)
+}
+
+func (c *taxRateQueryImplementation) SetColumns(columns []string) TaxRateQueryInterface {
+	c.properties["columns"] = columns
+
+	return c
+}
+
+func (c *taxRateQueryImplementation) HasCountOnly() bool {
+	return c.hasProperty("count_only")
+}
+
+func (c *taxRateQueryImplementation) IsCountOnly() bool {
+	if !c.HasCountOnly() {
+		return false
+	}
+
+	return c.properties["count_only"].(bool)
+}
+
+func (c *taxRateQueryImplementation) SetCountOnly(countOnly bool) TaxRateQueryInterface {
+	c.properties["count_only"] = countOnly
+
+	return c
+}
+
+func (c *taxRateQueryImplementation) HasCountryCode() bool {
+	return c.hasProperty("country_code")
+}
+
+func (c *taxRateQueryImplementation) CountryCode() string {
+	if !c.HasCountryCode() {
+		return ""
+	}
+
+	return c.properties["country_code"].(string)
+}
+
+func (c *taxRateQueryImplementation) SetCountryCode(countryCode string) TaxRateQueryInterface {
+	c.properties["country_code"] = countryCode
+
+	return c
+}
+
+func (c *taxRateQueryImplementation) HasCreatedAtGte() bool {
+	return c.hasProperty("created_at_gte")
+}
+
+func (c *taxRateQueryImplementation) CreatedAtGte() string {
+	if !c.HasCreatedAtGte() {
+		return ""
+	}
+
+	return c.properties["created_at_gte"].(string)
+}
+
+func (c *taxRateQueryImplementation) SetCreatedAtGte(createdAtGte string) TaxRateQueryInterface {
+	c.properties["created_at_gte"] = createdAtGte
+
+	return c
+}
+
+func (c *taxRateQueryImplementation) HasCreatedAtLte() bool {
+	return c.hasProperty("created_at_lte")
+}
+
+func (c *taxRateQueryImplementation) CreatedAtLte() string {
+	if !c.HasCreatedAtLte() {
+		return ""
+	}
+
+	return c.properties["created_at_lte"].(string)
+}
+
+func (c *taxRateQueryImplementation) SetCreatedAtLte(createdAtLte string) TaxRateQueryInterface {
+	c.properties["created_at_lte"] = createdAtLte
+
+	return c
+}
+
+func (c *taxRateQueryImplementation) HasID() bool {
+	return c.hasProperty("id")
+}
+
+func (c *taxRateQueryImplementation) ID() string {
+	if !c.HasID() {
+		return ""
+	}
+
+	return c.properties["id"].(string)
+}
+
+func (c *taxRateQueryImplementation) SetID(id string) TaxRateQueryInterface {
+	c.properties["id"] = id
+
+	return c
+}
+
+func (c *taxRateQueryImplementation) HasIDIn() bool {
+	return c.hasProperty("id_in")
+}
+
+func (c *taxRateQueryImplementation) IDIn() []string {
+	if !c.HasIDIn() {
+		return []string{}
+	}
+
+	return c.properties["id_in"].([]string)
+}
+
+func (c *taxRateQueryImplementation) SetIDIn(idIn []string) TaxRateQueryInterface {
+	c.properties["id_in"] = idIn
+
+	return c
+}
+
+func (c *taxRateQueryImplementation) HasLimit() bool {
+	return c.hasProperty("limit")
+}
+
+func (c *taxRateQueryImplementation) Limit() int {
+	if !c.HasLimit() {
+		return 0
+	}
+
+	return c.properties["limit"].(int)
+}
+
+func (c *taxRateQueryImplementation) SetLimit(limit int) TaxRateQueryInterface {
+	c.properties["limit"] = limit
+
+	return c
+}
+
+func (c *taxRateQueryImplementation) HasOffset() bool {
+	return c.hasProperty("offset")
+}
+
+func (c *taxRateQueryImplementation) Offset() int {
+	if !c.HasOffset() {
+		return 0
+	}
+
+	return c.properties["offset"].(int)
+}
+
+func (c *taxRateQueryImplementation) SetOffset(offset int) TaxRateQueryInterface {
+	c.properties["offset"] = offset
+
+	return c
+}
+
+func (c *taxRateQueryImplementation) HasOrderBy() bool {
+	return c.hasProperty("order_by")
+}
+
+func (c *taxRateQueryImplementation) OrderBy() string {
+	if !c.HasOrderBy() {
+		return ""
+	}
+
+	return c.properties["order_by"].(string)
+}
+
+func (c *taxRateQueryImplementation) SetOrderBy(orderBy string) TaxRateQueryInterface {
+	c.properties["order_by"] = orderBy
+
+	return c
+}
+
+func (c *taxRateQueryImplementation) HasSortDirection() bool {
+	return c.hasProperty("sort_direction")
+}
+
+func (c *taxRateQueryImplementation) SortDirection() string {
+	if !c.HasSortDirection() {
+		return ""
+	}
+
+	return c.properties["sort_direction"].(string)
+}
+
+func (c *taxRateQueryImplementation) SetSortDirection(sortDirection string) TaxRateQueryInterface {
+	c.properties["sort_direction"] = sortDirection
+
+	return c
+}
+
+func (c *taxRateQueryImplementation) HasSorts() bool {
+	return c.hasProperty("sorts")
+}
+
+func (c *taxRateQueryImplementation) Sorts() []QuerySort {
+	if !c.HasSorts() {
+		return []QuerySort{}
+	}
+
+	return c.properties["sorts"].([]QuerySort)
+}
+
+func (c *taxRateQueryImplementation) AddSort(column string, direction string) TaxRateQueryInterface {
+	c.properties["sorts"] = appendSort(c.Sorts(), column, direction)
+
+	return c
+}
+
+func (c *taxRateQueryImplementation) HasSoftDeletedIncluded() bool {
+	return c.hasProperty("soft_deleted_included")
+}
+
+func (c *taxRateQueryImplementation) SoftDeletedIncluded() bool {
+	if !c.HasSoftDeletedIncluded() {
+		return false
+	}
+
+	return c.properties["soft_deleted_included"].(bool)
+}
+
+func (c *taxRateQueryImplementation) SetSoftDeletedIncluded(softDeletedIncluded bool) TaxRateQueryInterface {
+	c.properties["soft_deleted_included"] = softDeletedIncluded
+
+	return c
+}
+
+func (c *taxRateQueryImplementation) HasRegion() bool {
+	return c.hasProperty("region")
+}
+
+func (c *taxRateQueryImplementation) Region() string {
+	if !c.HasRegion() {
+		return ""
+	}
+
+	return c.properties["region"].(string)
+}
+
+func (c *taxRateQueryImplementation) SetRegion(region string) TaxRateQueryInterface {
+	c.properties["region"] = region
+
+	return c
+}
+
+func (c *taxRateQueryImplementation) HasTaxClass() bool {
+	return c.hasProperty("tax_class")
+}
+
+func (c *taxRateQueryImplementation) TaxClass() string {
+	if !c.HasTaxClass() {
+		return ""
+	}
+
+	return c.properties["tax_class"].(string)
+}
+
+func (c *taxRateQueryImplementation) SetTaxClass(taxClass string) TaxRateQueryInterface {
+	c.properties["tax_class"] = taxClass
+
+	return c
+}
+
+func (c *taxRateQueryImplementation) HasTaxClassIn() bool {
+	return c.hasProperty("tax_class_in")
+}
+
+func (c *taxRateQueryImplementation) TaxClassIn() []string {
+	if !c.HasTaxClassIn() {
+		return []string{}
+	}
+
+	return c.properties["tax_class_in"].([]string)
+}
+
+func (c *taxRateQueryImplementation) SetTaxClassIn(taxClassIn []string) TaxRateQueryInterface {
+	c.properties["tax_class_in"] = taxClassIn
+
+	return c
+}
+
+func (c *taxRateQueryImplementation) HasAfterCursor() bool {
+	return c.hasProperty("after_cursor")
+}
+
+func (c *taxRateQueryImplementation) AfterCursor() string {
+	if !c.HasAfterCursor() {
+		return ""
+	}
+
+	return c.properties["after_cursor"].(string)
+}
+
+func (c *taxRateQueryImplementation) SetAfterCursor(cursor string) TaxRateQueryInterface {
+	c.properties["after_cursor"] = cursor
+
+	return c
+}
+
+func (c *taxRateQueryImplementation) hasProperty(name string) bool {
+	_, ok := c.properties[name]
+	return ok
+}
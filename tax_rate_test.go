@@ -0,0 +1,94 @@
+package shopstore
+
+import "testing"
+
+func TestNewTaxRateDefaults(t *testing.T) {
+	taxRate := NewTaxRate()
+	if taxRate == nil {
+		t.Fatal("NewTaxRate returned nil")
+	}
+
+	if taxRate.GetID() == "" {
+		t.Fatal("expected generated ID to be non-empty")
+	}
+
+	if taxRate.GetTaxClass() != TAX_CLASS_STANDARD {
+		t.Fatalf("expected tax class %q, got %q", TAX_CLASS_STANDARD, taxRate.GetTaxClass())
+	}
+
+	if taxRate.GetRateFloat() != 0 {
+		t.Fatalf("expected rate 0, got %f", taxRate.GetRateFloat())
+	}
+
+	if taxRate.GetCountryCode() != "" || taxRate.GetRegion() != "" {
+		t.Fatalf("expected no country and region, got %q and %q", taxRate.GetCountryCode(), taxRate.GetRegion())
+	}
+
+	if taxRate.GetSoftDeletedAt() != MAX_DATETIME {
+		t.Fatalf("expected soft deleted at %q, got %q", MAX_DATETIME, taxRate.GetSoftDeletedAt())
+	}
+
+	if taxRate.IsSoftDeleted() {
+		t.Fatal("expected a new tax rate not to be soft deleted")
+	}
+
+	if taxRate.GetVersion() != 1 {
+		t.Fatalf("expected version 1, got %d", taxRate.GetVersion())
+	}
+}
+
+func TestTaxRateSetters(t *testing.T) {
+	taxRate := NewTaxRate().
+		SetCountryCode(" us ").
+		SetRegion(" New York ").
+		SetTaxClass("reduced").
+		SetRateFloat(8.875).
+		SetName("NY sales tax")
+
+	if taxRate.GetCountryCode() != "US" {
+		t.Fatalf("expected country code %q, got %q", "US", taxRate.GetCountryCode())
+	}
+
+	if taxRate.GetRegion() != "New York" {
+		t.Fatalf("expected region %q, got %q", "New York", taxRate.GetRegion())
+	}
+
+	if taxRate.GetTaxClass() != "reduced" || taxRate.GetName() != "NY sales tax" {
+		t.Fatalf("unexpected tax class or name: %v", taxRate.Data())
+	}
+
+	if taxRate.GetRateFloat() != 8.875 || taxRate.GetRate() != "8.875" {
+		t.Fatalf("expected rate 8.875, got %q", taxRate.GetRate())
+	}
+}
+
+func TestTaxOf(t *testing.T) {
+	if tax := taxOf(100, 20, false); tax != 20 {
+		t.Fatalf("expected 20 on top of 100, got %v", tax)
+	}
+
+	if tax := taxOf(120, 20, true); tax != 20 {
+		t.Fatalf("expected 20 within 120, got %v", tax)
+	}
+
+	if tax := taxOf(9.99, 8.875, false); tax != 0.89 {
+		t.Fatalf("expected 0.89, got %v", tax)
+	}
+}
+
+func TestAllocateDiscount(t *testing.T) {
+	shares := allocateDiscount([]float64{10, 10, 10}, 10)
+	if shares[0] != 3.33 || shares[1] != 3.33 || shares[2] != 3.34 {
+		t.Fatalf("expected the last share to take the remainder, got %v", shares)
+	}
+
+	shares = allocateDiscount([]float64{30, 10}, 100)
+	if shares[0] != 30 || shares[1] != 10 {
+		t.Fatalf("expected the discount capped at the total, got %v", shares)
+	}
+
+	shares = allocateDiscount([]float64{30, 10}, 0)
+	if shares[0] != 0 || shares[1] != 0 {
+		t.Fatalf("expected no shares without a discount, got %v", shares)
+	}
+}
@@ -0,0 +1,77 @@
+package shopstore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+)
+
+// DEFAULT_OPERATION_TIMEOUT is the time a single store operation may take
+// when NewStoreOptions.OperationTimeout is not set
+const DEFAULT_OPERATION_TIMEOUT = 30 * time.Second
+
+// TimeoutError is returned when a store operation does not complete before
+// its deadline, either the one of the passed in context or the store's
+// operation timeout. It unwraps to context.DeadlineExceeded.
+type TimeoutError struct {
+	Operation string
+	Timeout   time.Duration
+	Err       error
+}
+
+func (e *TimeoutError) Error() string {
+	return "shop store: " + e.Operation + " timed out: " + e.Err.Error()
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// operationContext bounds ctx by the store's operation timeout. The returned
+// cancel function must be called once the operation completes.
+func (store *Store) operationContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if store.operationTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, store.operationTimeout)
+}
+
+// query starts a new query that runs with ctx, so that cancellation and
+// deadlines are honoured by the database driver
+func (store *Store) query(ctx context.Context) contractsorm.Query {
+	return queryWithContext(store.db.Query(), ctx)
+}
+
+// queryWithContext binds the query q to ctx, when its driver supports it
+func queryWithContext(q contractsorm.Query, ctx context.Context) contractsorm.Query {
+	if withContext, ok := q.(contractsorm.QueryWithContext); ok {
+		return withContext.WithContext(ctx)
+	}
+
+	return q
+}
+
+// operationError converts a missed deadline into a *TimeoutError and returns
+// any other error unchanged
+func (store *Store) operationError(operation string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &TimeoutError{
+			Operation: operation,
+			Timeout:   store.operationTimeout,
+			Err:       err,
+		}
+	}
+
+	return err
+}
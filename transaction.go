@@ -0,0 +1,110 @@
+package shopstore
+
+import (
+	"context"
+
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+	"github.com/samber/lo"
+)
+
+// statement returns a copy of the transaction query tx to run a single
+// statement with. Queries collect their clauses in place, so the
+// transaction query itself must not be used for more than one statement.
+func statement(tx contractsorm.Query) contractsorm.Query {
+	if cloner, ok := tx.(interface{ Clone() contractsorm.Query }); ok {
+		return cloner.Clone()
+	}
+
+	return tx
+}
+
+// changeRecords are the rows recorded about a change: its audit log
+// entries and the outbox events it causes
+type changeRecords struct {
+	auditLogs []AuditLogInterface
+	events    []OutboxEventInterface
+}
+
+// changesRecorded reports whether changes are recorded at all, in the
+// audit log or in the outbox
+func (store *Store) changesRecorded() bool {
+	return store.auditLogEnabled || store.outboxEnabled
+}
+
+// changeTransaction runs write, which returns the records of its change,
+// and inserts the records in the same transaction (see recordChanges).
+// With neither the audit log nor the outbox enabled write runs on its own,
+// outside of a transaction.
+func (store *Store) changeTransaction(ctx context.Context, write func(tx contractsorm.Query) (changeRecords, error)) error {
+	if !store.changesRecorded() {
+		_, err := write(store.query(ctx))
+		return err
+	}
+
+	return store.query(ctx).Transaction(func(tx contractsorm.Query) error {
+		records, err := write(tx)
+		if err != nil {
+			return err
+		}
+
+		return store.recordChanges(tx, records)
+	})
+}
+
+// recordChanges inserts the records of a change within its transaction tx,
+// with multi-row statements (see bulkInsert). Audit log entries are only
+// kept with the audit log enabled and events with the outbox enabled.
+func (store *Store) recordChanges(tx contractsorm.Query, records changeRecords) error {
+	if store.auditLogEnabled && len(records.auditLogs) > 0 {
+		rows := lo.Map(records.auditLogs, func(entry AuditLogInterface, _ int) map[string]string {
+			return entry.Data()
+		})
+
+		if err := bulkInsert(tx, store.auditLogTableName, rows); err != nil {
+			return err
+		}
+
+		lo.ForEach(records.auditLogs, func(entry AuditLogInterface, _ int) {
+			entry.MarkAsNotDirty()
+		})
+	}
+
+	if store.outboxEnabled && len(records.events) > 0 {
+		rows := lo.Map(records.events, func(event OutboxEventInterface, _ int) map[string]string {
+			return event.Data()
+		})
+
+		if err := bulkInsert(tx, store.outboxTableName, rows); err != nil {
+			return err
+		}
+
+		lo.ForEach(records.events, func(event OutboxEventInterface, _ int) {
+			event.MarkAsNotDirty()
+		})
+	}
+
+	return nil
+}
+
+// storedValues reads the stored values of the given columns, or of all the
+// columns when none are given, of the row with the given ID, locking the
+// row until the end of the transaction tx. Returns nil without reading when
+// changes are not recorded, as only the records need the previous values.
+func (store *Store) storedValues(tx contractsorm.Query, tableName string, id string, columns []string) (map[string]string, error) {
+	if !store.changesRecorded() {
+		return nil, nil
+	}
+
+	q := statement(tx).Table(tableName)
+	if len(columns) > 0 {
+		q = q.Select(columns)
+	}
+
+	var results []map[string]any
+	err := q.Where(COLUMN_ID+" = ?", id).LockForUpdate().Get(&results)
+	if err != nil || len(results) == 0 {
+		return map[string]string{}, err
+	}
+
+	return mapAnyToString(results[0]), nil
+}